@@ -0,0 +1,19 @@
+DROP INDEX IF EXISTS idx_products_search_vector;
+
+DROP TRIGGER IF EXISTS trg_categories_search_vector ON public.categories;
+DROP TRIGGER IF EXISTS trg_variant_options_search_vector ON public.variant_options;
+DROP TRIGGER IF EXISTS trg_products_search_vector ON public.products;
+
+DROP FUNCTION IF EXISTS categories_search_vector_trigger();
+DROP FUNCTION IF EXISTS variant_options_search_vector_trigger();
+DROP FUNCTION IF EXISTS products_search_vector_trigger();
+DROP FUNCTION IF EXISTS product_search_document(TEXT, TEXT, BIGINT, BIGINT);
+
+ALTER TABLE public.products DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE public.shops
+    DROP CONSTRAINT IF EXISTS shops_search_language_check,
+    DROP COLUMN IF EXISTS search_language;
+
+DROP TEXT SEARCH CONFIGURATION IF EXISTS en_unaccent;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS es_unaccent;
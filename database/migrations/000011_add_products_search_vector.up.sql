@@ -0,0 +1,118 @@
+-- Full-text search over products
+-- search_vector is maintained by triggers because it aggregates columns from
+-- categories and variant_options, which a generated column cannot reference
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Accent-insensitive text search configurations ("cafe" matches "café")
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'es_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION es_unaccent (COPY = spanish);
+        ALTER TEXT SEARCH CONFIGURATION es_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, spanish_stem;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'en_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION en_unaccent (COPY = english);
+        ALTER TEXT SEARCH CONFIGURATION en_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, english_stem;
+    END IF;
+END
+$$;
+
+-- Shop setting: default language used to parse search queries ('es' or 'en')
+ALTER TABLE public.shops
+    ADD COLUMN IF NOT EXISTS search_language text NOT NULL DEFAULT 'es',
+    ADD CONSTRAINT shops_search_language_check CHECK (search_language IN ('es', 'en'));
+
+ALTER TABLE public.products
+    ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+-- Builds the weighted document of a product
+-- Both Spanish and English stems are indexed so either query language matches
+-- Weights: A = name, B = category name, C = variant option names, D = description
+CREATE OR REPLACE FUNCTION product_search_document(
+    p_name TEXT,
+    p_description TEXT,
+    p_category_id BIGINT,
+    p_product_id BIGINT
+) RETURNS tsvector AS $$
+DECLARE
+    v_category TEXT;
+    v_options TEXT;
+BEGIN
+    SELECT c.name INTO v_category FROM categories c WHERE c.id = p_category_id;
+
+    SELECT string_agg(vo.name, ' ') INTO v_options
+    FROM variant_options vo
+    INNER JOIN product_variants pv ON vo.variant_id = pv.id
+    WHERE pv.product_id = p_product_id;
+
+    RETURN setweight(to_tsvector('es_unaccent', COALESCE(p_name, '')), 'A')
+        || setweight(to_tsvector('en_unaccent', COALESCE(p_name, '')), 'A')
+        || setweight(to_tsvector('es_unaccent', COALESCE(v_category, '')), 'B')
+        || setweight(to_tsvector('en_unaccent', COALESCE(v_category, '')), 'B')
+        || setweight(to_tsvector('es_unaccent', COALESCE(v_options, '')), 'C')
+        || setweight(to_tsvector('en_unaccent', COALESCE(v_options, '')), 'C')
+        || setweight(to_tsvector('es_unaccent', COALESCE(p_description, '')), 'D')
+        || setweight(to_tsvector('en_unaccent', COALESCE(p_description, '')), 'D');
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+-- Product rows: recompute when searchable columns change
+CREATE OR REPLACE FUNCTION products_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := product_search_document(NEW.name, NEW.description, NEW.category_id, NEW.id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_products_search_vector
+    BEFORE INSERT OR UPDATE OF name, description, category_id ON public.products
+    FOR EACH ROW EXECUTE FUNCTION products_search_vector_trigger();
+
+-- Variant options: refresh the owning product (options are inserted after the product)
+CREATE OR REPLACE FUNCTION variant_options_search_vector_trigger() RETURNS trigger AS $$
+DECLARE
+    v_variant_id BIGINT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        v_variant_id := OLD.variant_id;
+    ELSE
+        v_variant_id := NEW.variant_id;
+    END IF;
+
+    UPDATE products p
+    SET search_vector = product_search_document(p.name, p.description, p.category_id, p.id)
+    FROM product_variants pv
+    WHERE pv.id = v_variant_id AND p.id = pv.product_id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_variant_options_search_vector
+    AFTER INSERT OR UPDATE OF name OR DELETE ON public.variant_options
+    FOR EACH ROW EXECUTE FUNCTION variant_options_search_vector_trigger();
+
+-- Categories: a rename changes the document of every product in the category
+CREATE OR REPLACE FUNCTION categories_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    UPDATE products p
+    SET search_vector = product_search_document(p.name, p.description, p.category_id, p.id)
+    WHERE p.category_id = NEW.id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_categories_search_vector
+    AFTER UPDATE OF name ON public.categories
+    FOR EACH ROW EXECUTE FUNCTION categories_search_vector_trigger();
+
+-- Backfill existing products
+UPDATE public.products p
+SET search_vector = product_search_document(p.name, p.description, p.category_id, p.id);
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON public.products USING GIN (search_vector);
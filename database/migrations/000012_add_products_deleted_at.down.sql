@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS trg_products_reject_deleted_edit ON public.products;
+DROP FUNCTION IF EXISTS products_reject_deleted_edit();
+
+DROP INDEX IF EXISTS idx_products_deleted_at;
+DROP INDEX IF EXISTS idx_products_shop_id_live;
+
+ALTER TABLE public.products DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,29 @@
+-- Soft delete for products
+-- Deleted products keep their images, variants and options until purged,
+-- so historical references stay valid
+
+ALTER TABLE public.products
+    ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone null;
+
+-- Listings only read live products
+CREATE INDEX IF NOT EXISTS idx_products_shop_id_live ON public.products (shop_id, id) WHERE deleted_at IS NULL;
+
+-- Purge job scans soft-deleted products by deletion date
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON public.products (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Deleted products cannot be edited until restored. update_product always sets
+-- these catalog columns, so the guard covers every version of the procedure, while
+-- stock, version and restore updates of deleted products keep working
+-- (prices are left out: columns listed here cannot change type later)
+CREATE OR REPLACE FUNCTION products_reject_deleted_edit() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'product_not_found' USING ERRCODE = 'no_data_found';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_products_reject_deleted_edit
+    BEFORE UPDATE OF name, description, is_active, is_highlighted, is_promotional, category_id
+    ON public.products
+    FOR EACH ROW
+    WHEN (OLD.deleted_at IS NOT NULL)
+    EXECUTE FUNCTION products_reject_deleted_edit();
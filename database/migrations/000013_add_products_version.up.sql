@@ -0,0 +1,5 @@
+-- Optimistic concurrency control for product edits
+-- Every update_product call bumps the version, exposed to clients as the ETag
+
+ALTER TABLE public.products
+    ADD COLUMN IF NOT EXISTS version integer not null default 1;
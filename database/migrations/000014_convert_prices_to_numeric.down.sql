@@ -0,0 +1,6 @@
+ALTER TABLE public.variant_options
+    ALTER COLUMN price TYPE double precision USING price::double precision;
+
+ALTER TABLE public.products
+    ALTER COLUMN price TYPE double precision USING price::double precision,
+    ALTER COLUMN promotional_price TYPE double precision USING promotional_price::double precision;
@@ -0,0 +1,8 @@
+-- Prices are stored as exact decimals; money is handled as integer cents in Go
+
+ALTER TABLE public.products
+    ALTER COLUMN price TYPE numeric(12,2) USING round(price::numeric, 2),
+    ALTER COLUMN promotional_price TYPE numeric(12,2) USING round(promotional_price::numeric, 2);
+
+ALTER TABLE public.variant_options
+    ALTER COLUMN price TYPE numeric(12,2) USING round(price::numeric, 2);
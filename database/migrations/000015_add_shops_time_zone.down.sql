@@ -0,0 +1 @@
+ALTER TABLE public.shops DROP COLUMN IF EXISTS time_zone;
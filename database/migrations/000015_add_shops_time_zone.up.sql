@@ -0,0 +1,3 @@
+-- IANA time zone of the shop; promotion schedules are evaluated in it
+ALTER TABLE public.shops
+    ADD COLUMN IF NOT EXISTS time_zone text not null default 'America/Argentina/Buenos_Aires';
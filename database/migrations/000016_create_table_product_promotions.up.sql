@@ -0,0 +1,20 @@
+-- Scheduled promotional prices
+-- starts_at and ends_at are wall clock times in the shop time zone (no offset),
+-- recurring promotions only apply on weekdays (0 = Sunday) between start_time and end_time
+create table public.product_promotions (
+                                           id bigint generated by default as identity not null,
+                                           product_id bigint not null,
+                                           price numeric(12,2) not null,
+                                           starts_at timestamp without time zone not null,
+                                           ends_at timestamp without time zone null,
+                                           weekdays smallint[] null,
+                                           start_time time null,
+                                           end_time time null,
+                                           created_at timestamp with time zone not null default now(),
+                                           constraint product_promotions_pkey primary key (id),
+                                           constraint product_promotions_product_id_fkey foreign KEY (product_id) references products (id) on update CASCADE on delete CASCADE,
+                                           constraint product_promotions_price_check check (price > 0),
+                                           constraint product_promotions_window_check check (ends_at is null or ends_at > starts_at)
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_product_promotions_product_id ON public.product_promotions (product_id);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_stock_movements_append_only ON public.stock_movements;
+DROP FUNCTION IF EXISTS stock_movements_reject_update();
+DROP TABLE IF EXISTS stock_movements
@@ -0,0 +1,36 @@
+-- Append-only inventory ledger
+-- Every stock change is recorded with its signed quantity and the resulting stock;
+-- rows are never updated, they are only removed together with their product
+create table public.stock_movements (
+                                        id bigint generated by default as identity not null,
+                                        product_id bigint not null,
+                                        reason text not null,
+                                        quantity integer not null,
+                                        stock_after integer not null,
+                                        actor text null,
+                                        reference text null,
+                                        created_at timestamp with time zone not null default now(),
+                                        constraint stock_movements_pkey primary key (id),
+                                        constraint stock_movements_product_id_fkey foreign KEY (product_id) references products (id) on update CASCADE on delete CASCADE,
+                                        constraint stock_movements_reason_check check (reason in ('initial', 'restock', 'sale', 'return', 'adjustment')),
+                                        constraint stock_movements_quantity_check check (quantity <> 0),
+                                        constraint stock_movements_stock_after_check check (stock_after >= 0)
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id_id ON public.stock_movements (product_id, id DESC);
+
+CREATE OR REPLACE FUNCTION stock_movements_reject_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements_is_append_only' USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_stock_movements_append_only
+    BEFORE UPDATE ON public.stock_movements
+    FOR EACH ROW EXECUTE FUNCTION stock_movements_reject_update();
+
+-- Open the ledger of existing products with their current stock
+INSERT INTO public.stock_movements (product_id, reason, quantity, stock_after, reference)
+SELECT id, 'initial', stock, stock, 'ledger_backfill'
+FROM public.products
+WHERE stock > 0;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_products_low_stock;
+
+ALTER TABLE public.products
+    DROP COLUMN IF EXISTS low_stock_alerted_at;
@@ -0,0 +1,7 @@
+-- Low-stock alerts are sent once when stock falls to or below minimum_stock;
+-- low_stock_alerted_at is cleared when stock recovers so the next drop alerts again
+ALTER TABLE public.products
+    ADD COLUMN IF NOT EXISTS low_stock_alerted_at timestamp with time zone NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_low_stock ON public.products (shop_id)
+    WHERE deleted_at IS NULL AND stock <= COALESCE(minimum_stock, 0);
@@ -0,0 +1,4 @@
+ALTER TABLE public.variant_options
+    DROP CONSTRAINT IF EXISTS variant_options_stock_check,
+    DROP COLUMN IF EXISTS is_available,
+    DROP COLUMN IF EXISTS stock;
@@ -0,0 +1,5 @@
+-- Options can track their own stock (NULL = not tracked) and be switched off by hand
+ALTER TABLE public.variant_options
+    ADD COLUMN IF NOT EXISTS stock integer NULL,
+    ADD COLUMN IF NOT EXISTS is_available boolean NOT NULL DEFAULT true,
+    ADD CONSTRAINT variant_options_stock_check CHECK (stock IS NULL OR stock >= 0);
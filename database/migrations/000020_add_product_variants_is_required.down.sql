@@ -0,0 +1,2 @@
+ALTER TABLE public.product_variants
+    DROP COLUMN IF EXISTS is_required;
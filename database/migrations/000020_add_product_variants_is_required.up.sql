@@ -0,0 +1,3 @@
+-- Required variants need at least one option chosen when a product is quoted or ordered
+ALTER TABLE public.product_variants
+    ADD COLUMN IF NOT EXISTS is_required boolean NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+ALTER TABLE public.product_variants
+    DROP CONSTRAINT IF EXISTS product_variants_rules_is_array,
+    DROP COLUMN IF EXISTS rules;
@@ -0,0 +1,5 @@
+-- Declarative rules of custom variants (min/max, free selections, exclusive and required options)
+-- The domain validates each rule; the database only guarantees a JSON array
+ALTER TABLE public.product_variants
+    ADD COLUMN IF NOT EXISTS rules jsonb NOT NULL DEFAULT '[]'::jsonb,
+    ADD CONSTRAINT product_variants_rules_is_array CHECK (jsonb_typeof(rules) = 'array');
@@ -0,0 +1,3 @@
+ALTER TABLE public.product_images
+    DROP CONSTRAINT IF EXISTS product_images_renditions_is_object,
+    DROP COLUMN IF EXISTS renditions;
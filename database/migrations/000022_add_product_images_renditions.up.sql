@@ -0,0 +1,5 @@
+-- Resized renditions of each product image, keyed by name ({"thumb": "...", "medium": "..."})
+-- url keeps pointing at the normalized original
+ALTER TABLE public.product_images
+    ADD COLUMN IF NOT EXISTS renditions jsonb NOT NULL DEFAULT '{}'::jsonb,
+    ADD CONSTRAINT product_images_renditions_is_object CHECK (jsonb_typeof(renditions) = 'object');
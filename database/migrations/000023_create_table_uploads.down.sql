@@ -0,0 +1 @@
+DROP TABLE IF EXISTS uploads
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- Images clients put straight into the object storage through a signed URL
+-- awaiting_content: URL issued; pending: verified and stored, waiting for a product;
+-- attached: referenced by a product. Uploads not attached by expires_at are purged
+create table public.uploads (
+                                id text not null,
+                                status text not null default 'awaiting_content',
+                                content_type text not null,
+                                size bigint not null,
+                                url text null,
+                                renditions jsonb not null default '{}'::jsonb,
+                                expires_at timestamp with time zone not null,
+                                created_at timestamp with time zone not null default now(),
+                                attached_at timestamp with time zone null,
+                                constraint uploads_pkey primary key (id),
+                                constraint uploads_status_check check (status in ('awaiting_content', 'pending', 'attached')),
+                                constraint uploads_size_check check (size > 0),
+                                constraint uploads_renditions_is_object check (jsonb_typeof(renditions) = 'object')
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_uploads_expires_at ON public.uploads (expires_at) WHERE status <> 'attached';
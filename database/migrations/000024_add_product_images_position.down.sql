@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS public.idx_product_images_primary;
+DROP INDEX IF EXISTS public.idx_product_images_product_id_position;
+
+ALTER TABLE public.product_images
+    DROP COLUMN IF EXISTS is_primary,
+    DROP COLUMN IF EXISTS position;
@@ -0,0 +1,19 @@
+-- Order of the images of a product and the one shown first (e.g. on product cards)
+ALTER TABLE public.product_images
+    ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS is_primary BOOLEAN NOT NULL DEFAULT false;
+
+-- Existing images keep the order they were listed in (by id) and the first one becomes primary
+UPDATE public.product_images pi
+SET position = ordered.position,
+    is_primary = ordered.position = 0
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY id) - 1 AS position
+    FROM public.product_images
+) ordered
+WHERE pi.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_product_images_product_id_position ON public.product_images (product_id, position);
+
+-- A product has at most one primary image
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_images_primary ON public.product_images (product_id) WHERE is_primary;
@@ -0,0 +1,38 @@
+-- Server-side shopping carts, one per shop and owner
+-- A cart belongs to a customer or to an anonymous cart token, never both;
+-- carts not touched by expires_at are invisible to clients and get purged
+create table public.carts (
+                              id bigint generated by default as identity not null,
+                              shop_id bigint not null,
+                              customer_id bigint null,
+                              token text null,
+                              expires_at timestamp with time zone not null,
+                              created_at timestamp with time zone not null default now(),
+                              updated_at timestamp with time zone not null default now(),
+                              constraint carts_pkey primary key (id),
+                              constraint carts_shop_id_fkey foreign KEY (shop_id) references shops (id) on update CASCADE on delete CASCADE,
+                              constraint carts_customer_id_fkey foreign KEY (customer_id) references users (id) on update CASCADE on delete CASCADE,
+                              constraint carts_owner_check check ((customer_id is null) <> (token is null))
+) TABLESPACE pg_default;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_carts_shop_id_customer_id ON public.carts (shop_id, customer_id) WHERE customer_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_carts_shop_id_token ON public.carts (shop_id, token) WHERE token IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_carts_expires_at ON public.carts (expires_at);
+
+-- Line items keep what the customer chose; prices are recomputed on every read
+create table public.cart_lines (
+                                   id bigint generated by default as identity not null,
+                                   cart_id bigint not null,
+                                   product_id bigint not null,
+                                   selections jsonb not null default '[]'::jsonb,
+                                   quantity integer not null,
+                                   created_at timestamp with time zone not null default now(),
+                                   updated_at timestamp with time zone not null default now(),
+                                   constraint cart_lines_pkey primary key (id),
+                                   constraint cart_lines_cart_id_fkey foreign KEY (cart_id) references carts (id) on update CASCADE on delete CASCADE,
+                                   constraint cart_lines_product_id_fkey foreign KEY (product_id) references products (id) on update CASCADE on delete CASCADE,
+                                   constraint cart_lines_quantity_check check (quantity > 0),
+                                   constraint cart_lines_selections_is_array check (jsonb_typeof(selections) = 'array')
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_cart_lines_cart_id ON public.cart_lines (cart_id, id);
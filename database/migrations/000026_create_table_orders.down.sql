@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_order_items_immutable ON public.order_items;
+DROP FUNCTION IF EXISTS order_items_reject_update();
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders
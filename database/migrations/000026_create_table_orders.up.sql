@@ -0,0 +1,57 @@
+-- Orders placed in a shop, from a cart or a direct payload
+-- Amounts are the prices the customer accepted when the order was placed
+create table public.orders (
+                               id bigint generated by default as identity not null,
+                               shop_id bigint not null,
+                               customer_id bigint null,
+                               status text not null default 'pending',
+                               subtotal numeric(12,2) not null,
+                               total numeric(12,2) not null,
+                               item_count integer not null,
+                               notes text null,
+                               created_at timestamp with time zone not null default now(),
+                               updated_at timestamp with time zone not null default now(),
+                               constraint orders_pkey primary key (id),
+                               constraint orders_shop_id_fkey foreign KEY (shop_id) references shops (id) on update CASCADE on delete CASCADE,
+                               constraint orders_customer_id_fkey foreign KEY (customer_id) references users (id) on update CASCADE on delete SET NULL,
+                               constraint orders_item_count_check check (item_count > 0)
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_orders_shop_id_id ON public.orders (shop_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id_id ON public.orders (customer_id, id DESC) WHERE customer_id IS NOT NULL;
+
+-- Snapshot of each ordered product: its name, chosen options and prices at purchase time
+-- Items never change after the order is placed, product edits or purges do not rewrite them
+create table public.order_items (
+                                    id bigint generated by default as identity not null,
+                                    order_id bigint not null,
+                                    product_id bigint null,
+                                    product_name text not null,
+                                    selections jsonb not null default '[]'::jsonb,
+                                    options jsonb not null default '[]'::jsonb,
+                                    quantity integer not null,
+                                    base_price numeric(12,2) not null,
+                                    unit_price numeric(12,2) not null,
+                                    total numeric(12,2) not null,
+                                    constraint order_items_pkey primary key (id),
+                                    constraint order_items_order_id_fkey foreign KEY (order_id) references orders (id) on update CASCADE on delete CASCADE,
+                                    constraint order_items_product_id_fkey foreign KEY (product_id) references products (id) on update CASCADE on delete SET NULL,
+                                    constraint order_items_quantity_check check (quantity > 0),
+                                    constraint order_items_options_is_array check (jsonb_typeof(options) = 'array')
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON public.order_items (order_id, id);
+
+-- Only the product reference may change (set to NULL when the product is purged)
+CREATE OR REPLACE FUNCTION order_items_reject_update() RETURNS trigger AS $$
+BEGIN
+    IF (to_jsonb(NEW) - 'product_id') <> (to_jsonb(OLD) - 'product_id') THEN
+        RAISE EXCEPTION 'order_items_are_immutable' USING ERRCODE = 'restrict_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_order_items_immutable
+    BEFORE UPDATE ON public.order_items
+    FOR EACH ROW EXECUTE FUNCTION order_items_reject_update();
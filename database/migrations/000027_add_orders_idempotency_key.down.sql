@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS public.idx_orders_shop_id_idempotency_key;
+
+ALTER TABLE public.orders
+    DROP COLUMN IF EXISTS request_fingerprint,
+    DROP COLUMN IF EXISTS idempotency_key;
@@ -0,0 +1,7 @@
+-- Checkouts retried with the same Idempotency-Key return the order they already placed
+-- The fingerprint of the request tells a retry apart from a key reused for another checkout
+ALTER TABLE public.orders
+    ADD COLUMN IF NOT EXISTS idempotency_key TEXT NULL,
+    ADD COLUMN IF NOT EXISTS request_fingerprint TEXT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_shop_id_idempotency_key ON public.orders (shop_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS public.order_status_history;
+
+ALTER TABLE public.orders
+    DROP CONSTRAINT IF EXISTS orders_status_check;
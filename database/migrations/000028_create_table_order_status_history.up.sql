@@ -0,0 +1,18 @@
+-- Orders move through pending, confirmed, preparing, ready, out_for_delivery and delivered,
+-- or branch off to cancelled or refunded. The allowed transitions are checked by the domain
+ALTER TABLE public.orders
+    ADD CONSTRAINT orders_status_check check (status in ('pending', 'confirmed', 'preparing', 'ready', 'out_for_delivery', 'delivered', 'cancelled', 'refunded'));
+
+-- Every status change of an order, with who made it and when
+create table public.order_status_history (
+                                             id bigint generated by default as identity not null,
+                                             order_id bigint not null,
+                                             from_status text not null,
+                                             to_status text not null,
+                                             actor text null,
+                                             created_at timestamp with time zone not null default now(),
+                                             constraint order_status_history_pkey primary key (id),
+                                             constraint order_status_history_order_id_fkey foreign KEY (order_id) references orders (id) on update CASCADE on delete CASCADE
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON public.order_status_history (order_id, id);
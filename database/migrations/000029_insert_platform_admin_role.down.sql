@@ -0,0 +1,2 @@
+DELETE FROM public.user_roles WHERE role_id IN (SELECT id FROM public.roles WHERE name = 'platform_admin');
+DELETE FROM public.roles WHERE name = 'platform_admin';
@@ -0,0 +1,5 @@
+-- Platform admins run the maintenance endpoints across every shop (purges)
+-- Signup grants "admin" to every shop owner, so this role is only granted by hand
+INSERT INTO public.roles (name, description)
+SELECT 'platform_admin', 'runs maintenance tasks across every shop'
+WHERE NOT EXISTS (SELECT 1 FROM public.roles WHERE name = 'platform_admin');
@@ -0,0 +1,297 @@
+-- Rollback: Restore create_product and update_product without variant is_required
+
+CREATE OR REPLACE FUNCTION create_product(
+    p_name VARCHAR(255),
+    p_description TEXT,
+    p_price DECIMAL(10,2),
+    p_stock INTEGER,
+    p_minimum_stock INTEGER,
+    p_is_active BOOLEAN,
+    p_is_highlighted BOOLEAN,
+    p_is_promotional BOOLEAN,
+    p_promotional_price DECIMAL(10,2),
+    p_category_id INTEGER,
+    p_shop_id INTEGER,
+    p_images TEXT[],
+    p_variants JSONB
+) RETURNS INTEGER AS $$
+DECLARE
+    v_product_id INTEGER;
+    v_variant JSONB;
+    v_variant_id INTEGER;
+BEGIN
+    -- 1. Insert product
+    INSERT INTO products (
+        name, description, price, stock, minimum_stock,
+        is_active, is_highlighted, is_promotional, promotional_price,
+        category_id, shop_id
+    ) VALUES (
+        p_name, p_description, p_price, p_stock, p_minimum_stock,
+        p_is_active, p_is_highlighted, p_is_promotional, p_promotional_price,
+        p_category_id, p_shop_id
+    ) RETURNING id INTO v_product_id;
+
+    -- Open the inventory ledger with the initial stock
+    IF COALESCE(p_stock, 0) <> 0 THEN
+        INSERT INTO stock_movements (product_id, reason, quantity, stock_after)
+        VALUES (v_product_id, 'initial', p_stock, p_stock);
+    END IF;
+
+    -- 2. Insert images (batch with UNNEST)
+    IF p_images IS NOT NULL AND COALESCE(array_length(p_images, 1), 0) > 0 THEN
+        INSERT INTO product_images (url, product_id)
+        SELECT unnest(p_images), v_product_id;
+    END IF;
+
+    -- 3. Insert variants and their options (avoid O(n²) array concatenation)
+    -- Strategy: Loop variants (few items), batch insert options per variant
+    -- Example: 3 variants + 15 options = 3 variant INSERTs + 3 batch option INSERTs
+    IF COALESCE(jsonb_array_length(p_variants), 0) > 0 THEN
+        -- Loop through variants (typically 2-5 items)
+        FOR v_variant IN SELECT * FROM jsonb_array_elements(p_variants)
+        LOOP
+            -- Insert variant and get ID
+            INSERT INTO product_variants (
+                name, "order", selection_type, max_selections, product_id
+            ) VALUES (
+                v_variant->>'name',
+                (v_variant->>'order')::INTEGER,
+                v_variant->>'selection_type',
+                (v_variant->>'max_selections')::INTEGER,
+                v_product_id
+            ) RETURNING id INTO v_variant_id;
+
+            -- Batch insert options for THIS variant immediately (avoids array accumulation)
+            IF COALESCE(jsonb_array_length(v_variant->'options'), 0) > 0 THEN
+                INSERT INTO variant_options (name, price, "order", stock, is_available, variant_id)
+                SELECT
+                    opt->>'name',
+                    (opt->>'price')::DECIMAL,
+                    (opt->>'order')::INTEGER,
+                    (opt->>'stock')::INTEGER,
+                    COALESCE((opt->>'is_available')::BOOLEAN, true),
+                    v_variant_id
+                FROM jsonb_array_elements(v_variant->'options') opt
+                WHERE opt->>'name' IS NOT NULL  -- Validation: skip options without name
+                  AND opt->>'price' IS NOT NULL  -- Validation: skip options without price
+                  AND jsonb_typeof(opt) = 'object';
+            END IF;
+        END LOOP;
+    END IF;
+
+    RETURN v_product_id;
+
+EXCEPTION
+    WHEN OTHERS THEN
+        RAISE EXCEPTION 'Error creating product: %', SQLERRM;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function documentation
+COMMENT ON FUNCTION create_product IS
+'Creates a product with images, variants and options in a single call.
+Performance optimizations:
+- Product: 1 INSERT
+- Images: 1 batch INSERT (all at once)
+- Variants: N INSERTs (loop - typically 2-5 items)
+- Options: N batch INSERTs (one per variant, avoids O(n²) array concatenation)
+- COALESCE() for NULL safety on all length checks
+- Data validation: skips options without name or price
+- Exception handling for validation errors
+Example: 3 variants + 15 options = 6 total INSERTs (3 variants + 3 batch option INSERTs)
+Avoids O(n²) array_cat() overhead with 100+ options.
+Reduces 5+ Go round trips to 1 stored procedure call.
+The initial stock opens the inventory ledger (stock_movements).
+Options may carry their own stock (NULL = not tracked) and is_available (default true).';
+
+
+CREATE OR REPLACE FUNCTION update_product(
+    p_product_id INTEGER,
+    p_name VARCHAR(255),
+    p_description TEXT,
+    p_price DECIMAL(10,2),
+    p_stock INTEGER,
+    p_minimum_stock INTEGER,
+    p_is_active BOOLEAN,
+    p_is_highlighted BOOLEAN,
+    p_is_promotional BOOLEAN,
+    p_promotional_price DECIMAL(10,2),
+    p_category_id INTEGER,
+    p_images JSONB,      -- [{"id": 1, "url": "..."}, {"url": "new"}] or NULL to keep current images
+    p_variants JSONB,    -- [{"id": 1, "name": "...", "options": [...]}, {"name": "new", ...}] or NULL to keep current variants
+    p_expected_version INTEGER DEFAULT NULL -- version read by the client (If-Match) or NULL to skip the check
+) RETURNS VOID AS $$
+DECLARE
+    v_variant JSONB;
+    v_variant_id INTEGER;
+    v_previous_stock INTEGER;
+BEGIN
+    -- 0. Lock the row and read the stock so edits can be recorded in the ledger
+    SELECT stock INTO v_previous_stock
+    FROM products
+    WHERE id = p_product_id AND deleted_at IS NULL
+    FOR UPDATE;
+
+    -- 1. UPDATE basic product fields (optimistic lock on version)
+    UPDATE products
+    SET name = p_name,
+        description = p_description,
+        price = p_price,
+        stock = p_stock,
+        minimum_stock = p_minimum_stock,
+        is_active = p_is_active,
+        is_highlighted = p_is_highlighted,
+        is_promotional = p_is_promotional,
+        promotional_price = p_promotional_price,
+        category_id = p_category_id,
+        version = version + 1
+    WHERE id = p_product_id
+      AND deleted_at IS NULL
+      AND (p_expected_version IS NULL OR version = p_expected_version);
+
+    IF NOT FOUND THEN
+        IF EXISTS (SELECT 1 FROM products WHERE id = p_product_id AND deleted_at IS NULL) THEN
+            RAISE EXCEPTION 'product_version_mismatch' USING ERRCODE = 'serialization_failure';
+        END IF;
+        RAISE EXCEPTION 'product_not_found' USING ERRCODE = 'no_data_found';
+    END IF;
+
+    -- Stock overwritten by an edit is recorded as an adjustment
+    IF v_previous_stock IS DISTINCT FROM p_stock THEN
+        INSERT INTO stock_movements (product_id, reason, quantity, stock_after, reference)
+        VALUES (p_product_id, 'adjustment', COALESCE(p_stock, 0) - COALESCE(v_previous_stock, 0), COALESCE(p_stock, 0), 'product_update');
+    END IF;
+
+    -- 2. UPSERT images (optimized with != ALL for better index usage)
+    -- NULL means the caller did not send images (partial update): keep them as they are
+    IF p_images IS NULL THEN
+        NULL;
+    ELSIF COALESCE(jsonb_array_length(p_images), 0) = 0 THEN
+        DELETE FROM product_images WHERE product_id = p_product_id;
+    ELSE
+        -- Delete images NOT in the incoming list (using != ALL for performance)
+        DELETE FROM product_images
+        WHERE product_id = p_product_id
+          AND id != ALL (
+            SELECT (img->>'id')::INTEGER
+            FROM jsonb_array_elements(p_images) img
+            WHERE img->>'id' IS NOT NULL AND img->>'id' != ''
+          );
+
+        -- Insert new images (batch)
+        INSERT INTO product_images (url, product_id)
+        SELECT img->>'url', p_product_id
+        FROM jsonb_array_elements(p_images) img
+        WHERE img->>'id' IS NULL OR img->>'id' = '';
+    END IF;
+
+    -- 3. UPSERT variants and options
+    -- NULL means the caller did not send variants (partial update): keep them as they are
+    IF p_variants IS NULL THEN
+        NULL;
+    ELSIF COALESCE(jsonb_array_length(p_variants), 0) = 0 THEN
+        DELETE FROM product_variants WHERE product_id = p_product_id;
+    ELSE
+        -- 3a. Delete variants NOT in the incoming list (cascade deletes options)
+        DELETE FROM product_variants
+        WHERE product_id = p_product_id
+          AND id != ALL (
+            SELECT (v->>'id')::INTEGER
+            FROM jsonb_array_elements(p_variants) v
+            WHERE v->>'id' IS NOT NULL AND v->>'id' != ''
+          );
+
+        -- 3b. Update or insert each variant (loop needed - typically 2-5 variants)
+        FOR v_variant IN SELECT * FROM jsonb_array_elements(p_variants)
+        LOOP
+            IF v_variant->>'id' IS NOT NULL AND v_variant->>'id' != '' THEN
+                -- UPDATE existing variant
+                v_variant_id := (v_variant->>'id')::INTEGER;
+
+                UPDATE product_variants
+                SET name = v_variant->>'name',
+                    "order" = (v_variant->>'order')::INTEGER,
+                    selection_type = v_variant->>'selection_type',
+                    max_selections = (v_variant->>'max_selections')::INTEGER
+                WHERE id = v_variant_id;
+            ELSE
+                -- INSERT new variant
+                INSERT INTO product_variants (name, "order", selection_type, max_selections, product_id)
+                VALUES (
+                    v_variant->>'name',
+                    (v_variant->>'order')::INTEGER,
+                    v_variant->>'selection_type',
+                    (v_variant->>'max_selections')::INTEGER,
+                    p_product_id
+                ) RETURNING id INTO v_variant_id;
+            END IF;
+
+            -- 3c. UPSERT options for this variant (optimized with CTE to avoid reparsing JSONB)
+            IF v_variant->'options' IS NOT NULL AND COALESCE(jsonb_array_length(v_variant->'options'), 0) > 0 THEN
+                -- Parse JSONB once and reuse (avoids 3x parsing overhead)
+                WITH option_data AS (
+                    SELECT
+                        (opt->>'id')::INTEGER AS id,
+                        opt->>'name' AS name,
+                        (opt->>'price')::DECIMAL AS price,
+                        (opt->>'order')::INTEGER AS "order",
+                        (opt->>'stock')::INTEGER AS stock,
+                        COALESCE((opt->>'is_available')::BOOLEAN, true) AS is_available
+                    FROM jsonb_array_elements(v_variant->'options') opt
+                ),
+                deleted AS (
+                    -- Delete options NOT in the incoming list (using != ALL for performance)
+                    DELETE FROM variant_options
+                    WHERE variant_id = v_variant_id
+                      AND id != ALL (SELECT id FROM option_data WHERE id IS NOT NULL)
+                ),
+                updated AS (
+                    -- Batch UPDATE existing options
+                    UPDATE variant_options vo
+                    SET name = od.name,
+                        price = od.price,
+                        "order" = od."order",
+                        stock = od.stock,
+                        is_available = od.is_available
+                    FROM option_data od
+                    WHERE vo.id = od.id AND vo.variant_id = v_variant_id
+                )
+                -- Batch INSERT new options
+                INSERT INTO variant_options (name, price, "order", stock, is_available, variant_id)
+                SELECT name, price, "order", stock, is_available, v_variant_id
+                FROM option_data
+                WHERE id IS NULL;
+            ELSE
+                -- No options provided, delete all for this variant
+                DELETE FROM variant_options WHERE variant_id = v_variant_id;
+            END IF;
+        END LOOP;
+    END IF;
+
+EXCEPTION
+    WHEN OTHERS THEN
+        -- Keep the SQLSTATE so callers can tell conflicts and missing products apart
+        RAISE EXCEPTION 'Error updating product (ID: %): %', p_product_id, SQLERRM USING ERRCODE = SQLSTATE;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function documentation
+COMMENT ON FUNCTION update_product IS
+'Updates a product with images, variants and options in a single database call.
+Strategy:
+- Product: 1 UPDATE
+- Images: DELETE + batch INSERT (2 queries)
+- Variants: DELETE + loop for UPDATE/INSERT (N queries for N variants)
+- Options: CTE with DELETE + batch UPDATE + batch INSERT per variant (1 query × N variants)
+Optimizations applied:
+- != ALL() instead of NOT IN for better index usage and NULL handling
+- CTE for option_data to parse JSONB once (avoids 3x parsing overhead)
+- COALESCE() for NULL safety
+Clean approach: Loop variants (few items), batch options per variant (many items).
+Reduces ~10+ Go round trips to 1 stored procedure call.
+NULL images or variants are left untouched (used by JSON Merge Patch updates).
+Optimistic lock: a non NULL p_expected_version must match the stored version,
+otherwise serialization_failure (40001) is raised; missing products raise no_data_found (P0002).
+Stock changes are recorded in stock_movements as adjustments (reference product_update).
+Option stock and is_available are replaced with the incoming values (is_available defaults to true).
+Exception handling included for validation errors.';
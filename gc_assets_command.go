@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/mlgaray/ecommerce_api/internal/application/services"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/asset"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/assets"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/repositories/postgresql"
+)
+
+const gcAssetsCommand = "gc-assets"
+
+// runGCAssets collects the orphaned assets once and prints the report as JSON
+// Usage: ecommerce_api gc-assets [-dry-run] [-grace 24h]
+func runGCAssets(args []string) int {
+	flags := flag.NewFlagSet(gcAssetsCommand, flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report the orphaned assets without deleting them")
+	grace := flags.Duration("grace", 24*time.Hour, "only collect assets stored longer ago than this")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	var collectOrphanedAssets ports.CollectOrphanedAssetsUseCase
+	app := fx.New(
+		fx.Provide(
+			fx.Annotate(asset.NewCollectOrphanedAssetsUseCase, fx.As(new(ports.CollectOrphanedAssetsUseCase))),
+			fx.Annotate(services.NewAssetGCService, fx.As(new(ports.AssetGCService))),
+			fx.Annotate(postgresql.NewAssetReferenceRepository, fx.As(new(ports.AssetReferenceRepository))),
+			assets.NewAssetService,
+			fx.Annotate(postgresql.NewDataBaseConnection, fx.As(new(postgresql.DataBaseConnection))),
+		),
+		fx.Invoke(InitializeLogger),
+		fx.Populate(&collectOrphanedAssets),
+		fx.NopLogger,
+	)
+	if err := app.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", gcAssetsCommand, err)
+		return 1
+	}
+
+	report, err := collectOrphanedAssets.Execute(context.Background(), *grace, *dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", gcAssetsCommand, err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", gcAssetsCommand, err)
+		return 1
+	}
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
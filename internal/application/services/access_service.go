@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type AccessService struct {
+	shopRepository ports.ShopRepository
+}
+
+func NewAccessService(shopRepository ports.ShopRepository) *AccessService {
+	return &AccessService{
+		shopRepository: shopRepository,
+	}
+}
+
+// AuthorizeShopStaff checks that the user runs the shop
+// Business rule: the owner of a shop is its staff
+func (s *AccessService) AuthorizeShopStaff(ctx context.Context, user *models.User, shopID int) error {
+	if user == nil {
+		return &errors.AuthorizationError{Message: errors.Forbidden}
+	}
+
+	owned, err := s.shopRepository.IsOwnedBy(ctx, shopID, user.ID)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return &errors.AuthorizationError{Message: errors.Forbidden}
+	}
+	return nil
+}
+
+// AuthorizeProductStaff checks that the user runs the shop selling the product
+// A missing product is reported as such to the staff check callers
+func (s *AccessService) AuthorizeProductStaff(ctx context.Context, user *models.User, productID int) error {
+	if user == nil {
+		return &errors.AuthorizationError{Message: errors.Forbidden}
+	}
+
+	ownerID, err := s.shopRepository.GetOwnerIDByProductID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if ownerID != user.ID {
+		return &errors.AuthorizationError{Message: errors.Forbidden}
+	}
+	return nil
+}
+
+// AuthorizePlatformAdmin checks that the user may run maintenance tasks across every shop
+func (s *AccessService) AuthorizePlatformAdmin(_ context.Context, user *models.User) error {
+	if user == nil || !user.HasRole(models.PlatformAdminRole) {
+		return &errors.AuthorizationError{Message: errors.Forbidden}
+	}
+	return nil
+}
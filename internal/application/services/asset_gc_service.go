@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Asset GC service log field constants
+const (
+	AssetGCServiceField         = "asset_gc_service"
+	CollectOrphansFunctionField = "collect_orphans"
+	DeleteOrphanSubFuncField    = "delete_orphan"
+)
+
+type AssetGCService struct {
+	assetService             ports.AssetService
+	assetReferenceRepository ports.AssetReferenceRepository
+}
+
+func NewAssetGCService(assetService ports.AssetService, assetReferenceRepository ports.AssetReferenceRepository) *AssetGCService {
+	return &AssetGCService{
+		assetService:             assetService,
+		assetReferenceRepository: assetReferenceRepository,
+	}
+}
+
+// CollectOrphans deletes the stored assets nothing references anymore, such as the
+// images update_product drops, and reports them; a dry run only reports them
+//
+// Only assets older than the grace period are collected: images are stored before
+// the product (or upload) referencing them is saved. The storage is listed before the
+// references are read, so an asset stored in between is always too recent to collect.
+// An old orphan uploaded again (and so deduplicated) while the job runs is the one
+// case the grace period does not cover
+func (s *AssetGCService) CollectOrphans(ctx context.Context, grace time.Duration, dryRun bool) (*models.AssetGCReport, error) {
+	// Business rule: a grace period protects the images of requests in progress
+	if grace <= 0 {
+		return nil, &errors.ValidationError{Message: errors.GracePeriodMustBePositive}
+	}
+
+	report := &models.AssetGCReport{
+		DryRun:      dryRun,
+		GracePeriod: grace.String(),
+		StartedAt:   time.Now(),
+		Orphaned:    []*models.Asset{},
+	}
+
+	assets, err := s.assetService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Scanned = len(assets)
+
+	urls, err := s.assetReferenceRepository.GetReferencedURLs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys are content hashes, so URLs are matched by key whatever base URL (or CDN)
+	// they were stored with
+	referenced := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		referenced[path.Base(url)] = true
+	}
+
+	cutoff := report.StartedAt.Add(-grace)
+	for _, asset := range assets {
+		if referenced[asset.Key] {
+			report.Referenced++
+			continue
+		}
+		if asset.ModifiedAt.After(cutoff) {
+			continue
+		}
+		report.Orphaned = append(report.Orphaned, asset)
+	}
+
+	if !dryRun {
+		s.deleteOrphans(ctx, report)
+	}
+
+	logs.WithFields(map[string]interface{}{
+		"file":        AssetGCServiceField,
+		"function":    CollectOrphansFunctionField,
+		"dry_run":     report.DryRun,
+		"scanned":     report.Scanned,
+		"orphaned":    len(report.Orphaned),
+		"deleted":     report.Deleted,
+		"failed":      report.Failed,
+		"freed_bytes": report.FreedBytes,
+	}).Info("Orphaned assets collected")
+
+	return report, nil
+}
+
+// deleteOrphans deletes the orphaned assets of the report, counting what was freed
+// A failed deletion is retried by the next collection, so it does not stop the others
+func (s *AssetGCService) deleteOrphans(ctx context.Context, report *models.AssetGCReport) {
+	for _, asset := range report.Orphaned {
+		if err := s.assetService.Delete(ctx, asset.Key); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     AssetGCServiceField,
+				"function": CollectOrphansFunctionField,
+				"sub_func": DeleteOrphanSubFuncField,
+				"key":      asset.Key,
+				"error":    err.Error(),
+			}).Error("Error deleting orphaned asset")
+			report.Failed++
+			continue
+		}
+		report.Deleted++
+		report.FreedBytes += asset.Size
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	stdErrors "errors"
+	"slices"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type CartService struct {
+	cartRepository    ports.CartRepository
+	productRepository ports.ProductRepository
+}
+
+func NewCartService(cartRepository ports.CartRepository, productRepository ports.ProductRepository) *CartService {
+	return &CartService{
+		cartRepository:    cartRepository,
+		productRepository: productRepository,
+	}
+}
+
+// Get returns the cart of the owner with every line priced at the current instant
+func (s *CartService) Get(ctx context.Context, shopID int, owner models.CartOwner) (*models.Cart, error) {
+	cart, err := s.getCart(ctx, shopID, owner, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.price(ctx, cart)
+}
+
+// AddLine adds the configured product to the cart, opening the cart on the first line
+// Adding a configuration the cart already holds increases the quantity of that line
+func (s *CartService) AddLine(ctx context.Context, shopID int, owner models.CartOwner, line *models.CartLine) (*models.Cart, error) {
+	now := time.Now()
+	cart, err := s.findCart(ctx, shopID, owner, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing *models.CartLine
+	if cart != nil {
+		existing = cart.FindMatchingLine(line.ProductID, line.Selections)
+	}
+
+	// Business rule: the resulting line must be buyable right now
+	candidate := *line
+	if existing != nil {
+		candidate = *existing
+		candidate.Quantity += line.Quantity
+	}
+	if err := s.ensureCanBuy(ctx, &candidate, now); err != nil {
+		return nil, err
+	}
+
+	if cart == nil {
+		if cart, err = s.openCart(ctx, shopID, owner, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if existing != nil {
+		if err := s.cartRepository.UpdateLine(ctx, cart.ID, &candidate); err != nil {
+			return nil, err
+		}
+		*existing = candidate
+	} else {
+		if err := s.cartRepository.AddLine(ctx, cart, &candidate); err != nil {
+			return nil, err
+		}
+		cart.Lines = append(cart.Lines, &candidate)
+	}
+
+	return s.touch(ctx, cart, now)
+}
+
+// UpdateLine changes the quantity of a line and, when selections is not nil, its options
+func (s *CartService) UpdateLine(ctx context.Context, shopID int, owner models.CartOwner, lineID, quantity int, selections []models.VariantSelection) (*models.Cart, error) {
+	now := time.Now()
+	cart, err := s.getCart(ctx, shopID, owner, now)
+	if err != nil {
+		return nil, err
+	}
+
+	line, err := cart.FindLine(lineID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidate := *line
+	candidate.Quantity = quantity
+	if selections != nil {
+		candidate.Selections = selections
+	}
+	if err := s.ensureCanBuy(ctx, &candidate, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.UpdateLine(ctx, cart.ID, &candidate); err != nil {
+		return nil, err
+	}
+	*line = candidate
+
+	return s.touch(ctx, cart, now)
+}
+
+func (s *CartService) RemoveLine(ctx context.Context, shopID int, owner models.CartOwner, lineID int) (*models.Cart, error) {
+	now := time.Now()
+	cart, err := s.getCart(ctx, shopID, owner, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := cart.FindLine(lineID); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.RemoveLine(ctx, cart.ID, lineID); err != nil {
+		return nil, err
+	}
+	cart.Lines = slices.DeleteFunc(cart.Lines, func(line *models.CartLine) bool {
+		return line.ID == lineID
+	})
+
+	return s.touch(ctx, cart, now)
+}
+
+// Clear removes every line; the empty cart keeps its token and expiration window
+func (s *CartService) Clear(ctx context.Context, shopID int, owner models.CartOwner) (*models.Cart, error) {
+	now := time.Now()
+	cart, err := s.getCart(ctx, shopID, owner, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.Clear(ctx, cart.ID); err != nil {
+		return nil, err
+	}
+	cart.Lines = []*models.CartLine{}
+
+	return s.touch(ctx, cart, now)
+}
+
+// PurgeExpired deletes the carts without activity for models.CartTTL
+func (s *CartService) PurgeExpired(ctx context.Context) (int, error) {
+	return s.cartRepository.DeleteExpired(ctx, time.Now())
+}
+
+// getCart returns the cart of the owner; requests without an owner have no cart
+func (s *CartService) getCart(ctx context.Context, shopID int, owner models.CartOwner, now time.Time) (*models.Cart, error) {
+	if !owner.IsKnown() {
+		return nil, &errors.RecordNotFoundError{Message: errors.CartNotFound}
+	}
+	return s.cartRepository.GetByOwner(ctx, shopID, owner, now)
+}
+
+// findCart returns the cart of the owner or nil when the owner has none yet
+func (s *CartService) findCart(ctx context.Context, shopID int, owner models.CartOwner, now time.Time) (*models.Cart, error) {
+	cart, err := s.getCart(ctx, shopID, owner, now)
+	var notFoundErr *errors.RecordNotFoundError
+	if stdErrors.As(err, &notFoundErr) {
+		return nil, nil
+	}
+	return cart, err
+}
+
+// openCart creates the cart of the owner; anonymous owners always get a fresh token,
+// so a client cannot pick the token of a cart it does not hold
+func (s *CartService) openCart(ctx context.Context, shopID int, owner models.CartOwner, now time.Time) (*models.Cart, error) {
+	if owner.IsAnonymous() {
+		token, err := newCartToken()
+		if err != nil {
+			return nil, err
+		}
+		owner.Token = token
+	}
+
+	cart := models.NewCart(shopID, owner, now)
+	if err := s.cartRepository.Create(ctx, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// ensureCanBuy quotes the line with the current product, validating options and stock
+func (s *CartService) ensureCanBuy(ctx context.Context, line *models.CartLine, now time.Time) error {
+	product, err := s.productRepository.GetByID(ctx, line.ProductID)
+	if err != nil {
+		return err
+	}
+
+	_, err = product.Quote(line.Selections, line.Quantity, now)
+	return err
+}
+
+// touch records the activity, pushing the expiration back, and prices the cart
+func (s *CartService) touch(ctx context.Context, cart *models.Cart, now time.Time) (*models.Cart, error) {
+	cart.Touch(now)
+	if err := s.cartRepository.Touch(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return s.price(ctx, cart)
+}
+
+// price recomputes the lines with the current products; removed products leave their lines unavailable
+func (s *CartService) price(ctx context.Context, cart *models.Cart) (*models.Cart, error) {
+	products := make(map[int]*models.Product, len(cart.Lines))
+	for _, line := range cart.Lines {
+		if _, loaded := products[line.ProductID]; loaded {
+			continue
+		}
+
+		product, err := s.productRepository.GetByID(ctx, line.ProductID)
+		var notFoundErr *errors.RecordNotFoundError
+		if err != nil && !stdErrors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		products[line.ProductID] = product
+	}
+
+	cart.Price(products, time.Now())
+	return cart, nil
+}
+
+// newCartToken returns a random token, so anonymous carts cannot be guessed
+func newCartToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Inventory service log field constants
+const (
+	InventoryServiceField          = "inventory_service"
+	CheckLowStockFunctionField     = "check_low_stock"
+	ClaimLowStockAlertSubFuncField = "claim_low_stock_alert"
+	NotifyLowStockSubFuncField     = "notify_low_stock"
+	ReleaseLowStockSubFuncField    = "release_low_stock_alert"
+)
+
+// lowStockAlertTimeout bounds the delivery of an alert, which outlives the request
+const lowStockAlertTimeout = 30 * time.Second
+
+type InventoryService struct {
+	stockRepository ports.StockRepository
+	notifier        ports.Notifier
+}
+
+func NewInventoryService(stockRepository ports.StockRepository, notifier ports.Notifier) *InventoryService {
+	return &InventoryService{
+		stockRepository: stockRepository,
+		notifier:        notifier,
+	}
+}
+
+func (s *InventoryService) GetLowStockReport(ctx context.Context, shopID int) ([]*models.LowStockProduct, error) {
+	return s.stockRepository.GetLowStockByShopID(ctx, shopID)
+}
+
+// CheckLowStock sends a low-stock alert when the product stock crossed its minimum
+// Alerts are deduplicated until the stock recovers. The claim runs with the request
+// but the delivery runs in the background, so failures are logged instead of
+// slowing down or failing the request
+func (s *InventoryService) CheckLowStock(ctx context.Context, productID int) {
+	product, err := s.stockRepository.ClaimLowStockAlert(ctx, productID)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       InventoryServiceField,
+			"function":   CheckLowStockFunctionField,
+			"sub_func":   ClaimLowStockAlertSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error checking low stock")
+		return
+	}
+	if product == nil {
+		return
+	}
+
+	go s.deliverLowStockAlert(context.WithoutCancel(ctx), product)
+}
+
+// deliverLowStockAlert sends a claimed alert, releasing the claim when it cannot be delivered
+func (s *InventoryService) deliverLowStockAlert(ctx context.Context, product *models.LowStockProduct) {
+	ctx, cancel := context.WithTimeout(ctx, lowStockAlertTimeout)
+	defer cancel()
+
+	if err := s.notifier.NotifyLowStock(ctx, product); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       InventoryServiceField,
+			"function":   CheckLowStockFunctionField,
+			"sub_func":   NotifyLowStockSubFuncField,
+			"product_id": product.ProductID,
+			"error":      err.Error(),
+		}).Error("Error delivering low stock alert")
+
+		// Release the claim so the next stock change retries the alert
+		if err := s.stockRepository.ReleaseLowStockAlert(ctx, product.ProductID); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":       InventoryServiceField,
+				"function":   CheckLowStockFunctionField,
+				"sub_func":   ReleaseLowStockSubFuncField,
+				"product_id": product.ProductID,
+				"error":      err.Error(),
+			}).Error("Error releasing low stock alert")
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package services
+
+// applyMergePatch merges a JSON Merge Patch (RFC 7396) onto a decoded JSON document
+// Objects are merged recursively, null removes a member and any other value replaces it
+func applyMergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	merged := make(map[string]interface{}, len(targetObject))
+	for key, value := range targetObject {
+		merged[key] = value
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = applyMergePatch(merged[key], value)
+	}
+
+	return merged
+}
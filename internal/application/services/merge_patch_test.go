@@ -0,0 +1,57 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeJSON is a helper to build documents from the RFC 7396 examples
+func decodeJSON(t *testing.T, raw string) interface{} {
+	t.Helper()
+	var value interface{}
+	assert.NoError(t, json.Unmarshal([]byte(raw), &value))
+	return value
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		target   string
+		patch    string
+		expected string
+	}{
+		{"when member is replaced then keeps the others", `{"a":"b","c":"d"}`, `{"a":"z"}`, `{"a":"z","c":"d"}`},
+		{"when member is null then removes it", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"when nested object is patched then merges recursively", `{"a":{"b":"c","d":"e"}}`, `{"a":{"b":null,"f":"g"}}`, `{"a":{"d":"e","f":"g"}}`},
+		{"when array is given then replaces the whole array", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{"when target member is not an object then patch builds one", `{"a":"foo"}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{"when patch is empty then target is unchanged", `{"a":"b"}`, `{}`, `{"a":"b"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			target := decodeJSON(t, tc.target)
+			patch := decodeJSON(t, tc.patch)
+
+			// Act
+			result := applyMergePatch(target, patch)
+
+			// Assert
+			assert.Equal(t, decodeJSON(t, tc.expected), result)
+		})
+	}
+
+	t.Run("when patching then target document is not mutated", func(t *testing.T) {
+		// Arrange
+		target := decodeJSON(t, `{"a":{"b":"c"}}`)
+
+		// Act
+		applyMergePatch(target, decodeJSON(t, `{"a":{"b":"z"}}`))
+
+		// Assert
+		assert.Equal(t, decodeJSON(t, `{"a":{"b":"c"}}`), target)
+	})
+}
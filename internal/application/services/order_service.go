@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	stdErrors "errors"
+	"strconv"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Order service log field constants
+const (
+	OrderServiceField            = "order_service"
+	CreateOrderFunctionField     = "create"
+	ClearOrderedCartSubFuncField = "clear_cart"
+)
+
+type OrderService struct {
+	orderRepository   ports.OrderRepository
+	cartRepository    ports.CartRepository
+	productRepository ports.ProductRepository
+	paginationService ports.PaginationService[*models.Order]
+	inventoryService  ports.InventoryService
+	accessService     ports.AccessService
+}
+
+func NewOrderService(orderRepository ports.OrderRepository, cartRepository ports.CartRepository, productRepository ports.ProductRepository, paginationService ports.PaginationService[*models.Order], inventoryService ports.InventoryService, accessService ports.AccessService) *OrderService {
+	return &OrderService{
+		orderRepository:   orderRepository,
+		cartRepository:    cartRepository,
+		productRepository: productRepository,
+		paginationService: paginationService,
+		inventoryService:  inventoryService,
+		accessService:     accessService,
+	}
+}
+
+// Create places an order from the lines of the draft or from the cart of its owner
+// Every item is quoted with the current product and stored as a snapshot, and the
+// stock is taken when the order is stored. A checkout retried with the same
+// idempotency key returns the order it already placed
+func (s *OrderService) Create(ctx context.Context, shopID int, draft *models.OrderDraft) (*models.Order, error) {
+	if draft.IdempotencyKey != "" {
+		order, err := s.findPlaced(ctx, shopID, draft)
+		if order != nil || err != nil {
+			return order, err
+		}
+	}
+
+	now := time.Now()
+	lines := draft.Lines
+
+	var cart *models.Cart
+	if draft.Cart != nil {
+		var err error
+		if cart, err = s.getCart(ctx, shopID, *draft.Cart, now); err != nil {
+			return nil, err
+		}
+		lines = make([]models.OrderLine, 0, len(cart.Lines))
+		for _, line := range cart.Lines {
+			lines = append(lines, line.ToOrderLine())
+		}
+	}
+
+	order := models.NewOrder(shopID, draft.CustomerID, draft.Notes, now)
+	for _, line := range lines {
+		product, err := s.productRepository.GetByID(ctx, line.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if err := order.AddItem(product, line, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+
+	if draft.IdempotencyKey != "" {
+		order.IdempotencyKey = draft.IdempotencyKey
+		order.RequestFingerprint = draft.Fingerprint()
+	}
+
+	if err := s.orderRepository.Create(ctx, order); err != nil {
+		// A concurrent retry placed the order first
+		var duplicateErr *errors.DuplicateRecordError
+		if stdErrors.As(err, &duplicateErr) && duplicateErr.Message == errors.IdempotencyKeyInUse {
+			if placed, findErr := s.findPlaced(ctx, shopID, draft); placed != nil || findErr != nil {
+				return placed, findErr
+			}
+		}
+		return nil, err
+	}
+
+	if cart != nil {
+		s.clearCart(ctx, cart)
+	}
+	for _, productID := range order.ProductIDs() {
+		s.inventoryService.CheckLowStock(ctx, productID)
+	}
+	return order, nil
+}
+
+// GetByID returns the order to the customer that placed it or to the staff of its shop
+func (s *OrderService) GetByID(ctx context.Context, orderID int, requester *models.User) (*models.Order, error) {
+	order, err := s.orderRepository.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if requester != nil && order.BelongsTo(requester.ID) {
+		return order, nil
+	}
+
+	// Orders the requester cannot see are reported as missing, so their IDs are not disclosed
+	err = s.accessService.AuthorizeShopStaff(ctx, requester, order.ShopID)
+	var authorizationErr *errors.AuthorizationError
+	if stdErrors.As(err, &authorizationErr) {
+		return nil, &errors.RecordNotFoundError{Message: errors.OrderNotFound}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Transition moves the order to the next status of its lifecycle on behalf of the shop staff
+func (s *OrderService) Transition(ctx context.Context, orderID int, staff *models.User, next models.OrderStatus) (*models.Order, error) {
+	order, err := s.orderRepository.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cancelling restocks and refunds settle payments, so customers and anonymous callers are forbidden
+	if err := s.accessService.AuthorizeShopStaff(ctx, staff, order.ShopID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.orderRepository.Transition(ctx, order, next, staff.Actor()); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *OrderService) GetAllByShopID(ctx context.Context, shopID, limit, cursor int) ([]*models.Order, string, bool, error) {
+	orders, err := s.orderRepository.GetAllByShopID(ctx, shopID, limit, cursor)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return s.paginate(orders, limit)
+}
+
+func (s *OrderService) GetAllByCustomerID(ctx context.Context, customerID, limit, cursor int) ([]*models.Order, string, bool, error) {
+	orders, err := s.orderRepository.GetAllByCustomerID(ctx, customerID, limit, cursor)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return s.paginate(orders, limit)
+}
+
+// paginate builds the cursor of orders listed newest first, which is the ID of the oldest one
+func (s *OrderService) paginate(orders []*models.Order, limit int) ([]*models.Order, string, bool, error) {
+	nextCursor, hasMore := s.paginationService.BuildCursorPagination(orders, limit)
+	if !hasMore {
+		return orders, "", false, nil
+	}
+	return orders, strconv.Itoa(nextCursor), true, nil
+}
+
+// findPlaced returns the order placed with the idempotency key of the draft, or nil when there is none
+// Business rule: a key identifies a single checkout, so it cannot be reused for a different request
+func (s *OrderService) findPlaced(ctx context.Context, shopID int, draft *models.OrderDraft) (*models.Order, error) {
+	order, err := s.orderRepository.GetByIdempotencyKey(ctx, shopID, draft.IdempotencyKey)
+	var notFoundErr *errors.RecordNotFoundError
+	if stdErrors.As(err, &notFoundErr) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if order.RequestFingerprint != draft.Fingerprint() {
+		return nil, &errors.BusinessRuleError{Message: errors.IdempotencyKeyReused}
+	}
+	order.Replayed = true
+	return order, nil
+}
+
+// getCart returns the cart the order is placed from, which needs at least one line
+func (s *OrderService) getCart(ctx context.Context, shopID int, owner models.CartOwner, now time.Time) (*models.Cart, error) {
+	if !owner.IsKnown() {
+		return nil, &errors.RecordNotFoundError{Message: errors.CartNotFound}
+	}
+
+	cart, err := s.cartRepository.GetByOwner(ctx, shopID, owner, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Business rule: an empty cart cannot be ordered
+	if len(cart.Lines) == 0 {
+		return nil, &errors.BusinessRuleError{Message: errors.CartIsEmpty}
+	}
+	return cart, nil
+}
+
+// clearCart empties the ordered cart. The order is already stored,
+// so failures are logged instead of failing the request
+func (s *OrderService) clearCart(ctx context.Context, cart *models.Cart) {
+	if err := s.cartRepository.Clear(ctx, cart.ID); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderServiceField,
+			"function": CreateOrderFunctionField,
+			"sub_func": ClearOrderedCartSubFuncField,
+			"cart_id":  cart.ID,
+			"error":    err.Error(),
+		}).Error("Error clearing ordered cart")
+	}
+}
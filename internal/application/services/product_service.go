@@ -1,8 +1,14 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"io"
+	"time"
 
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
 	"github.com/mlgaray/ecommerce_api/internal/core/ports"
 )
@@ -10,94 +16,392 @@ import (
 type ProductService struct {
 	productRepository ports.ProductRepository
 	paginationService ports.PaginationService[*models.Product]
-	// TODO: Add AssetService injection when ready
-	// assetService 1ports.AssetService
+	searchPagination  ports.PaginationService[*models.ProductSearchResult]
+	inventoryService  ports.InventoryService
+	assetService      ports.AssetService
+	imageProcessor    ports.ImageProcessor
+	uploadService     ports.UploadService
+	accessService     ports.AccessService
 }
 
-func NewProductService(productRepository ports.ProductRepository, paginationService ports.PaginationService[*models.Product]) *ProductService {
+func NewProductService(productRepository ports.ProductRepository, paginationService ports.PaginationService[*models.Product], searchPagination ports.PaginationService[*models.ProductSearchResult], inventoryService ports.InventoryService, assetService ports.AssetService, imageProcessor ports.ImageProcessor, uploadService ports.UploadService, accessService ports.AccessService) *ProductService {
 	return &ProductService{
 		productRepository: productRepository,
 		paginationService: paginationService,
+		searchPagination:  searchPagination,
+		inventoryService:  inventoryService,
+		assetService:      assetService,
+		imageProcessor:    imageProcessor,
+		uploadService:     uploadService,
+		accessService:     accessService,
 	}
 }
 
-func (s *ProductService) Create(ctx context.Context, product *models.Product, imageBuffers [][]byte, shopID int) (*models.Product, error) {
+func (s *ProductService) Create(ctx context.Context, product *models.Product, uploads ports.ImageUploads, shopID int) (*models.Product, error) {
 	// Validate business rules (domain validation)
 	if err := product.Validate(); err != nil {
 		return nil, err
 	}
 
-	// TODO: Upload images using AssetService and set URLs in product
-	// For now, we'll create a placeholder for where image URLs would be stored
-	//
-	// Example when AssetService is ready:
-	// imageURLs := make([]string, len(imageBuffers))
-	// for i, buffer := range imageBuffers {
-	//     uploadResult, err := s.assetService.UploadImage(ctx, buffer)
-	//     if err != nil {
-	//         return nil, err
-	//     }
-	//     imageURLs[i] = uploadResult.SecureURL
-	// }
-	// product.Images = imageURLs
-
-	// For now, just set placeholder URLs
-	placeholderImages := make([]models.ProductImage, len(imageBuffers))
-	for i := range imageBuffers {
-		placeholderImages[i] = models.ProductImage{
-			URL: "https://placeholder.com/image_" + string(rune(i+1)),
-			// ID is 0 (omitted) - Repository will assign it on INSERT
-		}
+	// Upload the images and keep their URLs; validation runs first so an invalid
+	// product never reads (or stores) its images
+	// ID is 0 (omitted) - Repository will assign it on INSERT
+	images, err := s.uploadImages(ctx, uploads)
+	if err != nil {
+		return nil, err
 	}
-	product.Images = placeholderImages
+
+	// Images sent as upload references were stored beforehand; other entries are ignored
+	product.Images = referencedUploads(product.Images)
+	uploadIDs, err := s.uploadService.Attach(ctx, product.Images)
+	if err != nil {
+		return nil, err
+	}
+	// Streamed images follow the referenced ones, in the order they were sent
+	product.ArrangeImages(images)
 
 	// Create product with shop association (uses stored procedures for optimal performance)
-	return s.productRepository.Create(ctx, product, shopID)
+	created, err := s.productRepository.Create(ctx, product, shopID)
+	if err != nil {
+		s.uploadService.Release(ctx, uploadIDs)
+		return nil, err
+	}
+	return created, nil
+}
+
+// referencedUploads keeps the images that reference a direct upload
+func referencedUploads(images []models.ProductImage) []models.ProductImage {
+	var referenced []models.ProductImage
+	for _, image := range images {
+		if image.UploadID != "" {
+			referenced = append(referenced, image)
+		}
+	}
+	return referenced
 }
 
-func (s *ProductService) GetAllByShopID(ctx context.Context, shopID, limit, cursor int) ([]*models.Product, int, bool, error) {
+func (s *ProductService) GetAllByShopID(ctx context.Context, shopID int, filter *models.ProductFilter, requester *models.User) ([]*models.Product, string, bool, error) {
+	// Validate filter business rules (price range, sort, cursor consistency)
+	if err := filter.Validate(); err != nil {
+		return nil, "", false, err
+	}
+
+	// Business rule: inactive products are only listed to the staff of the shop;
+	// everyone else gets the public listing whatever they ask for
+	if filter.IsActive == nil || !*filter.IsActive {
+		err := s.accessService.AuthorizeShopStaff(ctx, requester, shopID)
+		var authorizationErr *errors.AuthorizationError
+		if stdErrors.As(err, &authorizationErr) {
+			active := true
+			filter.IsActive = &active
+		} else if err != nil {
+			return nil, "", false, err
+		}
+	}
+
 	// Get products from repository
-	products, err := s.productRepository.GetAllByShopID(ctx, shopID, limit, cursor)
+	products, err := s.productRepository.GetAllByShopID(ctx, shopID, filter)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	now := time.Now()
+	for _, product := range products {
+		product.ApplyPricing(now)
+		product.ApplyAvailability()
+		product.ApplyPrimaryImage()
+	}
+
+	_, hasMore := s.paginationService.BuildCursorPagination(products, filter.Limit)
+	if !hasMore {
+		return products, "", false, nil
+	}
+
+	// Cursor encodes the sort key and the ID of the last product (keyset pagination)
+	nextCursor := models.NewProductCursor(filter, products[len(products)-1]).Encode()
+
+	return products, nextCursor, true, nil
+}
+
+func (s *ProductService) Search(ctx context.Context, shopID int, search *models.ProductSearch) ([]*models.ProductSearchResult, string, bool, error) {
+	// Validate search business rules (query terms, language)
+	if err := search.Validate(); err != nil {
+		return nil, "", false, err
+	}
+
+	results, err := s.productRepository.Search(ctx, shopID, search)
 	if err != nil {
-		return nil, 0, false, err
+		return nil, "", false, err
 	}
 
-	nextCursor, hasMore := s.paginationService.BuildCursorPagination(products, limit)
+	now := time.Now()
+	for _, result := range results {
+		result.Product.ApplyPricing(now)
+		result.Product.ApplyAvailability()
+		result.Product.ApplyPrimaryImage()
+	}
 
-	return products, nextCursor, hasMore, nil
+	_, hasMore := s.searchPagination.BuildCursorPagination(results, search.Limit)
+	if !hasMore {
+		return results, "", false, nil
+	}
+
+	// Cursor encodes rank and ID of the last result so ties keep a stable order
+	last := results[len(results)-1]
+	nextCursor := (&models.ProductSearchCursor{Rank: last.Rank, ID: last.Product.ID}).Encode()
+
+	return results, nextCursor, true, nil
 }
 
 func (s *ProductService) GetByID(ctx context.Context, productID int) (*models.Product, error) {
 	// Get product from repository
-	return s.productRepository.GetByID(ctx, productID)
+	product, err := s.productRepository.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report the price active right now and when its promotion ends
+	product.ApplyPricing(time.Now())
+	product.ApplyAvailability()
+	return product, nil
 }
 
-func (s *ProductService) Update(ctx context.Context, productID int, product *models.Product, newImageBuffers [][]byte) error {
+func (s *ProductService) Update(ctx context.Context, productID int, product *models.Product, newUploads ports.ImageUploads) error {
 	// Validate business rules (domain validation)
 	if err := product.Validate(); err != nil {
 		return err
 	}
 
-	// Process new images (upload when AssetService is ready)
-	// TODO: When AssetService is implemented:
-	// for i, buffer := range newImageBuffers {
-	//     uploadResult, err := s.assetService.UploadImage(ctx, buffer)
-	//     if err != nil {
-	//         return err
-	//     }
-	//     product.Images = append(product.Images, models.ProductImage{
-	//         URL: uploadResult.SecureURL,
-	//     })
-	// }
+	// Upload new images; ID is 0 (omitted) - Repository will INSERT these
+	newImages, err := s.uploadImages(ctx, newUploads)
+	if err != nil {
+		return err
+	}
 
-	// For now, create placeholders for new images
-	for i := range newImageBuffers {
-		product.Images = append(product.Images, models.ProductImage{
-			URL: "https://placeholder.com/new_image_" + string(rune(i+1)),
-			// ID is 0 (omitted) - Repository will INSERT these
-		})
+	// Upload references become new images; ID is 0 as well
+	uploadIDs, err := s.uploadService.Attach(ctx, product.Images)
+	if err != nil {
+		return err
 	}
+	// New streamed images follow the ones sent in the product
+	product.ArrangeImages(newImages)
 
 	// Update product via repository (uses stored procedures for optimal performance)
-	return s.productRepository.Update(ctx, productID, product)
+	if err := s.productRepository.Update(ctx, productID, product); err != nil {
+		s.uploadService.Release(ctx, uploadIDs)
+		return err
+	}
+
+	// Edits may overwrite stock or minimum stock
+	s.inventoryService.CheckLowStock(ctx, productID)
+	return nil
+}
+
+// uploadImages streams each upload through the image processor into its renditions,
+// stores them and returns them as product images pointing to the original rendition
+// Images are read one at a time, so memory does not grow with the number of images
+func (s *ProductService) uploadImages(ctx context.Context, uploads ports.ImageUploads) ([]models.ProductImage, error) {
+	var images []models.ProductImage
+	if uploads == nil {
+		return images, nil
+	}
+
+	for {
+		upload, err := uploads.Next()
+		if err == io.EOF {
+			return images, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		renditions, err := s.imageProcessor.Process(ctx, upload.Content)
+		if err != nil {
+			// A stream cut short (e.g. over its size limit) explains the failure better than the decoder
+			if uploadErr := uploads.Err(); uploadErr != nil {
+				return nil, uploadErr
+			}
+			return nil, err
+		}
+
+		image, err := storeRenditions(ctx, s.assetService, renditions)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+}
+
+// storeRenditions stores each rendition and returns the product image pointing to the original
+func storeRenditions(ctx context.Context, assetService ports.AssetService, renditions []*models.ImageRendition) (models.ProductImage, error) {
+	image := models.ProductImage{Renditions: make(map[string]string, len(renditions))}
+	for _, rendition := range renditions {
+		asset, err := assetService.Upload(ctx, bytes.NewReader(rendition.Content), int64(len(rendition.Content)), rendition.ContentType)
+		if err != nil {
+			return models.ProductImage{}, err
+		}
+		image.Renditions[rendition.Name] = asset.URL
+	}
+	image.URL = image.Renditions[models.OriginalRendition]
+	return image, nil
+}
+
+// productReadOnlyFields cannot be changed through a merge patch
+var productReadOnlyFields = []string{"id", "created_at", "version"}
+
+// productPricingFields are managed through the promotions endpoints or computed on read
+var productPricingFields = []string{"promotions", "active_price", "promotion_ends_at"}
+
+// Patch applies a JSON Merge Patch (RFC 7396) onto the stored product
+// Images and variants are only replaced when the patch contains them
+// A positive expectedVersion must match the stored version (optimistic lock)
+func (s *ProductService) Patch(ctx context.Context, productID int, patch map[string]interface{}, expectedVersion int) (*models.Product, error) {
+	for _, field := range productReadOnlyFields {
+		if _, ok := patch[field]; ok {
+			return nil, &errors.ValidationError{Message: errors.ProductFieldIsReadOnly}
+		}
+	}
+	for _, field := range productPricingFields {
+		if _, ok := patch[field]; ok {
+			return nil, &errors.ValidationError{Message: errors.ProductPricingFieldIsReadOnly}
+		}
+	}
+
+	current, err := s.productRepository.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fail fast; the stored procedure checks again to close the race window
+	if expectedVersion > 0 && current.Version != expectedVersion {
+		return nil, &errors.PreconditionFailedError{Message: errors.ProductVersionMismatch}
+	}
+
+	product, err := mergeProductPatch(current, patch)
+	if err != nil {
+		return nil, err
+	}
+	product.ID = current.ID
+	product.Version = expectedVersion
+
+	// Validate business rules on the merged result (domain validation)
+	if err := product.Validate(); err != nil {
+		return nil, err
+	}
+
+	if product.Category == nil || product.Category.ID <= 0 {
+		return nil, &errors.ValidationError{Message: errors.ProductCategoryRequired}
+	}
+
+	_, replaceImages := patch["images"]
+	if replaceImages {
+		if err := validatePatchedImages(current.Images, product.Images); err != nil {
+			return nil, err
+		}
+		product.ArrangeImages(nil)
+	}
+	_, replaceVariants := patch["variants"]
+
+	if err := s.productRepository.Patch(ctx, productID, product, replaceImages, replaceVariants); err != nil {
+		return nil, err
+	}
+
+	_, stockPatched := patch["stock"]
+	_, minimumStockPatched := patch["minimum_stock"]
+	if stockPatched || minimumStockPatched {
+		s.inventoryService.CheckLowStock(ctx, productID)
+	}
+
+	// Read back so generated IDs of new variants and options are returned
+	return s.GetByID(ctx, productID)
+}
+
+// mergeProductPatch merges the patch onto the JSON representation of the product
+func mergeProductPatch(current *models.Product, patch map[string]interface{}) (*models.Product, error) {
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(currentJSON, &document); err != nil {
+		return nil, err
+	}
+
+	mergedJSON, err := json.Marshal(applyMergePatch(document, patch))
+	if err != nil {
+		return nil, err
+	}
+
+	// Type mismatches (e.g. a string price) surface here
+	var merged models.Product
+	if err := json.Unmarshal(mergedJSON, &merged); err != nil {
+		return nil, &errors.ValidationError{Message: errors.InvalidMergePatchDocument}
+	}
+
+	return &merged, nil
+}
+
+// validatePatchedImages ensures a patch only keeps or reorders stored images
+// Uploading new images requires the multipart update endpoint
+func validatePatchedImages(current, patched []models.ProductImage) error {
+	if len(patched) == 0 {
+		return &errors.ValidationError{Message: errors.ProductImageRequired}
+	}
+
+	stored := make(map[int]bool, len(current))
+	for _, image := range current {
+		stored[image.ID] = true
+	}
+
+	for _, image := range patched {
+		if !stored[image.ID] {
+			return &errors.ValidationError{Message: errors.PatchImageMustExist}
+		}
+	}
+
+	return nil
+}
+
+// ReorderImages stores a new order and primary image for the images of the product
+// The order must list every image of the product, once
+func (s *ProductService) ReorderImages(ctx context.Context, productID int, order *models.ImageOrder, expectedVersion int) (*models.Product, error) {
+	// Validate business rules (domain validation)
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepository.ReorderImages(ctx, productID, order.Images(), expectedVersion); err != nil {
+		return nil, err
+	}
+
+	// Read back so the new version is returned
+	return s.GetByID(ctx, productID)
+}
+
+// Quote prices the product configured with the selected options at the current instant
+func (s *ProductService) Quote(ctx context.Context, productID int, selections []models.VariantSelection, quantity int) (*models.Quote, error) {
+	product, err := s.productRepository.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return product.Quote(selections, quantity, time.Now())
+}
+
+func (s *ProductService) Delete(ctx context.Context, productID int) error {
+	return s.productRepository.SoftDelete(ctx, productID)
+}
+
+func (s *ProductService) Restore(ctx context.Context, productID int) error {
+	return s.productRepository.Restore(ctx, productID)
+}
+
+func (s *ProductService) PurgeDeleted(ctx context.Context, retention time.Duration) (int, error) {
+	// Business rule: purging must leave a retention window to restore products
+	if retention <= 0 {
+		return 0, &errors.ValidationError{Message: errors.RetentionDaysMustBePositive}
+	}
+
+	return s.productRepository.PurgeDeleted(ctx, time.Now().Add(-retention))
 }
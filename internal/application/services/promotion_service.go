@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type PromotionService struct {
+	productRepository   ports.ProductRepository
+	promotionRepository ports.PromotionRepository
+}
+
+func NewPromotionService(productRepository ports.ProductRepository, promotionRepository ports.PromotionRepository) *PromotionService {
+	return &PromotionService{
+		productRepository:   productRepository,
+		promotionRepository: promotionRepository,
+	}
+}
+
+// Create schedules a promotion after validating it against the product price
+// and the promotions already scheduled for the product
+func (s *PromotionService) Create(ctx context.Context, productID int, promotion *models.Promotion) (*models.Promotion, error) {
+	product, err := s.productRepository.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fail fast; the repository checks again with the product locked to close the race window
+	if err := promotion.ValidateSchedule(product.Price, product.Promotions); err != nil {
+		return nil, err
+	}
+
+	return s.promotionRepository.Create(ctx, productID, promotion)
+}
+
+func (s *PromotionService) Delete(ctx context.Context, productID, promotionID int) error {
+	return s.promotionRepository.Delete(ctx, productID, promotionID)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type StockService struct {
+	stockRepository   ports.StockRepository
+	paginationService ports.PaginationService[*models.StockMovement]
+	inventoryService  ports.InventoryService
+}
+
+func NewStockService(stockRepository ports.StockRepository, paginationService ports.PaginationService[*models.StockMovement], inventoryService ports.InventoryService) *StockService {
+	return &StockService{
+		stockRepository:   stockRepository,
+		paginationService: paginationService,
+		inventoryService:  inventoryService,
+	}
+}
+
+// Adjust records a stock movement and updates the product stock atomically
+func (s *StockService) Adjust(ctx context.Context, productID int, movement *models.StockMovement) (*models.StockMovement, error) {
+	// Validate business rules (domain validation)
+	if err := movement.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Insufficient stock is checked by the repository against the locked row
+	movement, err := s.stockRepository.ApplyStockMovement(ctx, productID, movement)
+	if err != nil {
+		return nil, err
+	}
+
+	s.inventoryService.CheckLowStock(ctx, productID)
+	return movement, nil
+}
+
+func (s *StockService) GetMovements(ctx context.Context, productID, limit, cursor int) ([]*models.StockMovement, string, bool, error) {
+	movements, err := s.stockRepository.GetMovements(ctx, productID, limit, cursor)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	// Movements are listed newest first, so the cursor is the ID of the oldest one
+	nextCursor, hasMore := s.paginationService.BuildCursorPagination(movements, limit)
+	if !hasMore {
+		return movements, "", false, nil
+	}
+
+	return movements, strconv.Itoa(nextCursor), true, nil
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	stdErrors "errors"
+	"io"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Upload service log field constants
+const (
+	UploadServiceField              = "upload_service"
+	FinalizeUploadFunctionField     = "finalize"
+	PurgeUploadsFunctionField       = "purge_expired"
+	ReleaseUploadsFunctionField     = "release"
+	DeleteStagingObjectSubFuncField = "delete_staging_object"
+)
+
+type UploadService struct {
+	uploadRepository ports.UploadRepository
+	assetService     ports.AssetService
+	imageProcessor   ports.ImageProcessor
+}
+
+func NewUploadService(uploadRepository ports.UploadRepository, assetService ports.AssetService, imageProcessor ports.ImageProcessor) *UploadService {
+	return &UploadService{
+		uploadRepository: uploadRepository,
+		assetService:     assetService,
+		imageProcessor:   imageProcessor,
+	}
+}
+
+// Create registers an upload and signs the URL the client puts the file at
+// The signature binds the declared type and size, so the storage rejects any other file
+func (s *UploadService) Create(ctx context.Context, contentType string, size int64) (*models.Upload, *models.SignedURL, error) {
+	id, err := newUploadID()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	upload := models.NewUpload(id, contentType, size, time.Now())
+	// Validate business rules (domain validation)
+	if err := upload.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	signedURL, err := s.assetService.PresignUpload(ctx, upload.StagingKey(), upload.ContentType, upload.Size, models.UploadURLTTL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.uploadRepository.Create(ctx, upload); err != nil {
+		return nil, nil, err
+	}
+
+	return upload, signedURL, nil
+}
+
+// Finalize verifies the file the client put in the storage, stores its renditions
+// like a multipart image and leaves the upload pending until a product attaches it
+func (s *UploadService) Finalize(ctx context.Context, uploadID string) (*models.Upload, error) {
+	upload, err := s.uploadRepository.GetByID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := upload.CanFinalize(time.Now()); err != nil {
+		return nil, err
+	}
+
+	object, err := s.assetService.Open(ctx, upload.StagingKey())
+	if err != nil {
+		var notFoundErr *errors.RecordNotFoundError
+		if stdErrors.As(err, &notFoundErr) {
+			return nil, &errors.BusinessRuleError{Message: errors.UploadContentMissing}
+		}
+		return nil, err
+	}
+	defer object.Close()
+
+	// The signed Content-Length already bounds the file; it is not trusted to be the declared one
+	content := &io.LimitedReader{R: object, N: upload.Size + 1}
+	renditions, err := s.imageProcessor.Process(ctx, content)
+	if _, drainErr := io.Copy(io.Discard, content); drainErr == nil && content.N == 0 {
+		return nil, &errors.ValidationError{Message: errors.UploadContentTooLarge}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := storeRenditions(ctx, s.assetService, renditions)
+	if err != nil {
+		return nil, err
+	}
+
+	upload.Finalize(image)
+	if err := s.uploadRepository.Finalize(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	// The renditions are stored, so the staging object is no longer needed
+	if err := s.assetService.Delete(ctx, upload.StagingKey()); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":      UploadServiceField,
+			"function":  FinalizeUploadFunctionField,
+			"sub_func":  DeleteStagingObjectSubFuncField,
+			"upload_id": upload.ID,
+			"error":     err.Error(),
+		}).Error("Error deleting staging object")
+	}
+
+	return upload, nil
+}
+
+// Attach resolves the images referencing an upload into the stored image and claims
+// the uploads, so no other product can attach them. It returns the claimed IDs,
+// to be released if the product is not saved
+func (s *UploadService) Attach(ctx context.Context, images []models.ProductImage) ([]string, error) {
+	var uploadIDs []string
+	referenced := make(map[string]bool)
+	for _, image := range images {
+		if image.UploadID == "" {
+			continue
+		}
+		if referenced[image.UploadID] {
+			return nil, &errors.ValidationError{Message: errors.DuplicateUploadReference}
+		}
+		referenced[image.UploadID] = true
+		uploadIDs = append(uploadIDs, image.UploadID)
+	}
+	if len(uploadIDs) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	claimed, err := s.uploadRepository.Claim(ctx, uploadIDs, now)
+	if err != nil {
+		return nil, err
+	}
+
+	uploads := make(map[string]*models.Upload, len(claimed))
+	for _, upload := range claimed {
+		uploads[upload.ID] = upload
+	}
+
+	if len(uploads) != len(uploadIDs) {
+		claimedIDs := make([]string, 0, len(claimed))
+		for id := range uploads {
+			claimedIDs = append(claimedIDs, id)
+		}
+		s.Release(ctx, claimedIDs)
+		return nil, s.attachError(ctx, uploadIDs, uploads, now)
+	}
+
+	// The image keeps the position and primary flag it was sent with
+	for i := range images {
+		if upload, ok := uploads[images[i].UploadID]; ok {
+			image := upload.ToProductImage()
+			image.Position, image.IsPrimary = images[i].Position, images[i].IsPrimary
+			images[i] = image
+		}
+	}
+	return uploadIDs, nil
+}
+
+// attachError explains why the first unclaimed upload cannot be attached
+func (s *UploadService) attachError(ctx context.Context, uploadIDs []string, claimed map[string]*models.Upload, now time.Time) error {
+	for _, id := range uploadIDs {
+		if claimed[id] != nil {
+			continue
+		}
+		upload, err := s.uploadRepository.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := upload.CanAttach(now); err != nil {
+			return err
+		}
+	}
+	// A concurrent request claimed it in the meantime
+	return &errors.BusinessRuleError{Message: errors.UploadAlreadyAttached}
+}
+
+// Release returns uploads claimed for a product that could not be saved
+// The request already failed, so errors are logged instead of returned
+func (s *UploadService) Release(ctx context.Context, uploadIDs []string) {
+	if len(uploadIDs) == 0 {
+		return
+	}
+
+	if err := s.uploadRepository.Release(ctx, uploadIDs); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       UploadServiceField,
+			"function":   ReleaseUploadsFunctionField,
+			"upload_ids": uploadIDs,
+			"error":      err.Error(),
+		}).Error("Error releasing uploads")
+	}
+}
+
+// PurgeExpired deletes the uploads never attached to a product and their staging objects
+// Renditions are content-addressed and may be shared with other images, so they are kept
+func (s *UploadService) PurgeExpired(ctx context.Context) (int, error) {
+	expired, err := s.uploadRepository.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, upload := range expired {
+		if upload.Status != models.UploadAwaitingContent {
+			continue
+		}
+		if err := s.assetService.Delete(ctx, upload.StagingKey()); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":      UploadServiceField,
+				"function":  PurgeUploadsFunctionField,
+				"sub_func":  DeleteStagingObjectSubFuncField,
+				"upload_id": upload.ID,
+				"error":     err.Error(),
+			}).Error("Error deleting staging object")
+		}
+	}
+
+	return len(expired), nil
+}
+
+// newUploadID returns a random ID, so uploads cannot be guessed and attached by others
+func newUploadID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
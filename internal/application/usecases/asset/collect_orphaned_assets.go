@@ -0,0 +1,24 @@
+package asset
+
+import (
+	"context"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type CollectOrphanedAssetsUseCase struct {
+	assetGCService ports.AssetGCService
+}
+
+func NewCollectOrphanedAssetsUseCase(assetGCService ports.AssetGCService) ports.CollectOrphanedAssetsUseCase {
+	return &CollectOrphanedAssetsUseCase{
+		assetGCService: assetGCService,
+	}
+}
+
+func (uc *CollectOrphanedAssetsUseCase) Execute(ctx context.Context, grace time.Duration, dryRun bool) (*models.AssetGCReport, error) {
+	// Runs from the admin endpoint, the gc-assets command and the schedule alike
+	return uc.assetGCService.CollectOrphans(ctx, grace, dryRun)
+}
@@ -25,12 +25,12 @@ func (uc *SignInUseCase) Execute(ctx context.Context, user *models.User) (string
 		return "", err
 	}
 
-	authenticatedUser, err := uc.userService.ValidateCredentials(ctx, user, _user.Password)
-	if err != nil {
+	if _, err := uc.userService.ValidateCredentials(ctx, user, _user.Password); err != nil {
 		return "", err
 	}
 
-	token, err := uc.tokenService.Generate(ctx, authenticatedUser)
+	// The token is issued to the stored user, which carries the ID and roles requests are authorized with
+	token, err := uc.tokenService.Generate(ctx, _user)
 	if err != nil {
 		return "", err
 	}
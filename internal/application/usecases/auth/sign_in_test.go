@@ -42,7 +42,7 @@ func TestSignInUseCase_Execute(t *testing.T) {
 
 		userServiceMock.EXPECT().GetByEmail(ctx, email).Return(storedUser, nil)
 		userServiceMock.EXPECT().ValidateCredentials(ctx, inputUser, hashedPassword).Return(authenticatedUser, nil)
-		tokenServiceMock.EXPECT().Generate(ctx, authenticatedUser).Return(expectedToken, nil)
+		tokenServiceMock.EXPECT().Generate(ctx, storedUser).Return(expectedToken, nil)
 
 		useCase := NewSignInUseCase(userServiceMock, tokenServiceMock)
 
@@ -146,7 +146,7 @@ func TestSignInUseCase_Execute(t *testing.T) {
 
 		userServiceMock.EXPECT().GetByEmail(ctx, email).Return(storedUser, nil)
 		userServiceMock.EXPECT().ValidateCredentials(ctx, inputUser, hashedPassword).Return(authenticatedUser, nil)
-		tokenServiceMock.EXPECT().Generate(ctx, authenticatedUser).Return("", expectedError)
+		tokenServiceMock.EXPECT().Generate(ctx, storedUser).Return("", expectedError)
 
 		useCase := NewSignInUseCase(userServiceMock, tokenServiceMock)
 
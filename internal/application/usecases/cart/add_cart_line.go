@@ -0,0 +1,22 @@
+package cart
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type AddCartLineUseCase struct {
+	cartService ports.CartService
+}
+
+func NewAddCartLineUseCase(cartService ports.CartService) ports.AddCartLineUseCase {
+	return &AddCartLineUseCase{
+		cartService: cartService,
+	}
+}
+
+func (uc *AddCartLineUseCase) Execute(ctx context.Context, shopID int, owner models.CartOwner, line *models.CartLine) (*models.Cart, error) {
+	return uc.cartService.AddLine(ctx, shopID, owner, line)
+}
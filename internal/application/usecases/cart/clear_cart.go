@@ -0,0 +1,22 @@
+package cart
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type ClearCartUseCase struct {
+	cartService ports.CartService
+}
+
+func NewClearCartUseCase(cartService ports.CartService) ports.ClearCartUseCase {
+	return &ClearCartUseCase{
+		cartService: cartService,
+	}
+}
+
+func (uc *ClearCartUseCase) Execute(ctx context.Context, shopID int, owner models.CartOwner) (*models.Cart, error) {
+	return uc.cartService.Clear(ctx, shopID, owner)
+}
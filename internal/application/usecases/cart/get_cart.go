@@ -0,0 +1,22 @@
+package cart
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type GetCartUseCase struct {
+	cartService ports.CartService
+}
+
+func NewGetCartUseCase(cartService ports.CartService) ports.GetCartUseCase {
+	return &GetCartUseCase{
+		cartService: cartService,
+	}
+}
+
+func (uc *GetCartUseCase) Execute(ctx context.Context, shopID int, owner models.CartOwner) (*models.Cart, error) {
+	return uc.cartService.Get(ctx, shopID, owner)
+}
@@ -0,0 +1,22 @@
+package cart
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type PurgeCartsUseCase struct {
+	cartService ports.CartService
+}
+
+func NewPurgeCartsUseCase(cartService ports.CartService) ports.PurgeCartsUseCase {
+	return &PurgeCartsUseCase{
+		cartService: cartService,
+	}
+}
+
+func (uc *PurgeCartsUseCase) Execute(ctx context.Context) (int, error) {
+	// Carts without activity for models.CartTTL expire
+	return uc.cartService.PurgeExpired(ctx)
+}
@@ -0,0 +1,22 @@
+package cart
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type RemoveCartLineUseCase struct {
+	cartService ports.CartService
+}
+
+func NewRemoveCartLineUseCase(cartService ports.CartService) ports.RemoveCartLineUseCase {
+	return &RemoveCartLineUseCase{
+		cartService: cartService,
+	}
+}
+
+func (uc *RemoveCartLineUseCase) Execute(ctx context.Context, shopID int, owner models.CartOwner, lineID int) (*models.Cart, error) {
+	return uc.cartService.RemoveLine(ctx, shopID, owner, lineID)
+}
@@ -0,0 +1,22 @@
+package cart
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type UpdateCartLineUseCase struct {
+	cartService ports.CartService
+}
+
+func NewUpdateCartLineUseCase(cartService ports.CartService) ports.UpdateCartLineUseCase {
+	return &UpdateCartLineUseCase{
+		cartService: cartService,
+	}
+}
+
+func (uc *UpdateCartLineUseCase) Execute(ctx context.Context, shopID int, owner models.CartOwner, lineID, quantity int, selections []models.VariantSelection) (*models.Cart, error) {
+	return uc.cartService.UpdateLine(ctx, shopID, owner, lineID, quantity, selections)
+}
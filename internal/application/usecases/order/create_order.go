@@ -0,0 +1,22 @@
+package order
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type CreateOrderUseCase struct {
+	orderService ports.OrderService
+}
+
+func NewCreateOrderUseCase(orderService ports.OrderService) ports.CreateOrderUseCase {
+	return &CreateOrderUseCase{
+		orderService: orderService,
+	}
+}
+
+func (uc *CreateOrderUseCase) Execute(ctx context.Context, shopID int, draft *models.OrderDraft) (*models.Order, error) {
+	return uc.orderService.Create(ctx, shopID, draft)
+}
@@ -0,0 +1,22 @@
+package order
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type GetCustomerOrdersUseCase struct {
+	orderService ports.OrderService
+}
+
+func NewGetCustomerOrdersUseCase(orderService ports.OrderService) ports.GetCustomerOrdersUseCase {
+	return &GetCustomerOrdersUseCase{
+		orderService: orderService,
+	}
+}
+
+func (uc *GetCustomerOrdersUseCase) Execute(ctx context.Context, customerID, limit, cursor int) ([]*models.Order, string, bool, error) {
+	return uc.orderService.GetAllByCustomerID(ctx, customerID, limit, cursor)
+}
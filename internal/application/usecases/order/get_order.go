@@ -0,0 +1,22 @@
+package order
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type GetOrderUseCase struct {
+	orderService ports.OrderService
+}
+
+func NewGetOrderUseCase(orderService ports.OrderService) ports.GetOrderUseCase {
+	return &GetOrderUseCase{
+		orderService: orderService,
+	}
+}
+
+func (uc *GetOrderUseCase) Execute(ctx context.Context, orderID int, requester *models.User) (*models.Order, error) {
+	return uc.orderService.GetByID(ctx, orderID, requester)
+}
@@ -0,0 +1,22 @@
+package order
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type GetShopOrdersUseCase struct {
+	orderService ports.OrderService
+}
+
+func NewGetShopOrdersUseCase(orderService ports.OrderService) ports.GetShopOrdersUseCase {
+	return &GetShopOrdersUseCase{
+		orderService: orderService,
+	}
+}
+
+func (uc *GetShopOrdersUseCase) Execute(ctx context.Context, shopID, limit, cursor int) ([]*models.Order, string, bool, error) {
+	return uc.orderService.GetAllByShopID(ctx, shopID, limit, cursor)
+}
@@ -0,0 +1,22 @@
+package order
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type TransitionOrderUseCase struct {
+	orderService ports.OrderService
+}
+
+func NewTransitionOrderUseCase(orderService ports.OrderService) ports.TransitionOrderUseCase {
+	return &TransitionOrderUseCase{
+		orderService: orderService,
+	}
+}
+
+func (uc *TransitionOrderUseCase) Execute(ctx context.Context, orderID int, staff *models.User, next models.OrderStatus) (*models.Order, error) {
+	return uc.orderService.Transition(ctx, orderID, staff, next)
+}
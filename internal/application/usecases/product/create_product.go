@@ -17,7 +17,7 @@ func NewCreateProductUseCase(productService ports.ProductService) ports.CreatePr
 	}
 }
 
-func (uc *CreateProductUseCase) Execute(ctx context.Context, product *models.Product, imageBuffers [][]byte, shopID int) (*models.Product, error) {
+func (uc *CreateProductUseCase) Execute(ctx context.Context, product *models.Product, images ports.ImageUploads, shopID int) (*models.Product, error) {
 	// Uses stored procedure for optimal performance (single DB round trip)
-	return uc.productService.Create(ctx, product, imageBuffers, shopID)
+	return uc.productService.Create(ctx, product, images, shopID)
 }
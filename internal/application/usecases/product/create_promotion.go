@@ -0,0 +1,23 @@
+package product
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type CreatePromotionUseCase struct {
+	promotionService ports.PromotionService
+}
+
+func NewCreatePromotionUseCase(promotionService ports.PromotionService) ports.CreatePromotionUseCase {
+	return &CreatePromotionUseCase{
+		promotionService: promotionService,
+	}
+}
+
+func (uc *CreatePromotionUseCase) Execute(ctx context.Context, productID int, promotion *models.Promotion) (*models.Promotion, error) {
+	// The promotion is validated against the product price and its other promotions
+	return uc.promotionService.Create(ctx, productID, promotion)
+}
@@ -0,0 +1,22 @@
+package product
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type DeleteProductUseCase struct {
+	productService ports.ProductService
+}
+
+func NewDeleteProductUseCase(productService ports.ProductService) ports.DeleteProductUseCase {
+	return &DeleteProductUseCase{
+		productService: productService,
+	}
+}
+
+func (uc *DeleteProductUseCase) Execute(ctx context.Context, productID int) error {
+	// Soft delete: the product is hidden from reads but kept until purged
+	return uc.productService.Delete(ctx, productID)
+}
@@ -0,0 +1,21 @@
+package product
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type DeletePromotionUseCase struct {
+	promotionService ports.PromotionService
+}
+
+func NewDeletePromotionUseCase(promotionService ports.PromotionService) ports.DeletePromotionUseCase {
+	return &DeletePromotionUseCase{
+		promotionService: promotionService,
+	}
+}
+
+func (uc *DeletePromotionUseCase) Execute(ctx context.Context, productID, promotionID int) error {
+	return uc.promotionService.Delete(ctx, productID, promotionID)
+}
@@ -17,6 +17,6 @@ func NewGetAllByShopIDUseCase(productService ports.ProductService) ports.GetAllB
 	}
 }
 
-func (uc *GetAllByShopIDUseCase) Execute(ctx context.Context, shopID, limit, cursor int) ([]*models.Product, int, bool, error) {
-	return uc.productService.GetAllByShopID(ctx, shopID, limit, cursor)
+func (uc *GetAllByShopIDUseCase) Execute(ctx context.Context, shopID int, filter *models.ProductFilter, requester *models.User) ([]*models.Product, string, bool, error) {
+	return uc.productService.GetAllByShopID(ctx, shopID, filter, requester)
 }
@@ -0,0 +1,23 @@
+package product
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type PatchProductUseCase struct {
+	productService ports.ProductService
+}
+
+func NewPatchProductUseCase(productService ports.ProductService) ports.PatchProductUseCase {
+	return &PatchProductUseCase{
+		productService: productService,
+	}
+}
+
+func (uc *PatchProductUseCase) Execute(ctx context.Context, productID int, patch map[string]interface{}, expectedVersion int) (*models.Product, error) {
+	// Images and variants are only replaced when the patch contains them
+	return uc.productService.Patch(ctx, productID, patch, expectedVersion)
+}
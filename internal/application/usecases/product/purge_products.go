@@ -0,0 +1,23 @@
+package product
+
+import (
+	"context"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type PurgeProductsUseCase struct {
+	productService ports.ProductService
+}
+
+func NewPurgeProductsUseCase(productService ports.ProductService) ports.PurgeProductsUseCase {
+	return &PurgeProductsUseCase{
+		productService: productService,
+	}
+}
+
+func (uc *PurgeProductsUseCase) Execute(ctx context.Context, retention time.Duration) (int, error) {
+	// Hard delete cascades to images, variants and options
+	return uc.productService.PurgeDeleted(ctx, retention)
+}
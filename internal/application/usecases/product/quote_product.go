@@ -0,0 +1,22 @@
+package product
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type QuoteProductUseCase struct {
+	productService ports.ProductService
+}
+
+func NewQuoteProductUseCase(productService ports.ProductService) ports.QuoteProductUseCase {
+	return &QuoteProductUseCase{
+		productService: productService,
+	}
+}
+
+func (uc *QuoteProductUseCase) Execute(ctx context.Context, productID int, selections []models.VariantSelection, quantity int) (*models.Quote, error) {
+	return uc.productService.Quote(ctx, productID, selections, quantity)
+}
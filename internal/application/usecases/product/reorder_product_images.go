@@ -0,0 +1,22 @@
+package product
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type ReorderProductImagesUseCase struct {
+	productService ports.ProductService
+}
+
+func NewReorderProductImagesUseCase(productService ports.ProductService) ports.ReorderProductImagesUseCase {
+	return &ReorderProductImagesUseCase{
+		productService: productService,
+	}
+}
+
+func (uc *ReorderProductImagesUseCase) Execute(ctx context.Context, productID int, order *models.ImageOrder, expectedVersion int) (*models.Product, error) {
+	return uc.productService.ReorderImages(ctx, productID, order, expectedVersion)
+}
@@ -0,0 +1,21 @@
+package product
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type RestoreProductUseCase struct {
+	productService ports.ProductService
+}
+
+func NewRestoreProductUseCase(productService ports.ProductService) ports.RestoreProductUseCase {
+	return &RestoreProductUseCase{
+		productService: productService,
+	}
+}
+
+func (uc *RestoreProductUseCase) Execute(ctx context.Context, productID int) error {
+	return uc.productService.Restore(ctx, productID)
+}
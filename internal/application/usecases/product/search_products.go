@@ -0,0 +1,22 @@
+package product
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type SearchProductsUseCase struct {
+	productService ports.ProductService
+}
+
+func NewSearchProductsUseCase(productService ports.ProductService) ports.SearchProductsUseCase {
+	return &SearchProductsUseCase{
+		productService: productService,
+	}
+}
+
+func (uc *SearchProductsUseCase) Execute(ctx context.Context, shopID int, search *models.ProductSearch) ([]*models.ProductSearchResult, string, bool, error) {
+	return uc.productService.Search(ctx, shopID, search)
+}
@@ -0,0 +1,22 @@
+package stock
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type AdjustStockUseCase struct {
+	stockService ports.StockService
+}
+
+func NewAdjustStockUseCase(stockService ports.StockService) ports.AdjustStockUseCase {
+	return &AdjustStockUseCase{
+		stockService: stockService,
+	}
+}
+
+func (uc *AdjustStockUseCase) Execute(ctx context.Context, productID int, movement *models.StockMovement) (*models.StockMovement, error) {
+	return uc.stockService.Adjust(ctx, productID, movement)
+}
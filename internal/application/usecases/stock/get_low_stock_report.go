@@ -0,0 +1,22 @@
+package stock
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type GetLowStockReportUseCase struct {
+	inventoryService ports.InventoryService
+}
+
+func NewGetLowStockReportUseCase(inventoryService ports.InventoryService) ports.GetLowStockReportUseCase {
+	return &GetLowStockReportUseCase{
+		inventoryService: inventoryService,
+	}
+}
+
+func (uc *GetLowStockReportUseCase) Execute(ctx context.Context, shopID int) ([]*models.LowStockProduct, error) {
+	return uc.inventoryService.GetLowStockReport(ctx, shopID)
+}
@@ -0,0 +1,22 @@
+package stock
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type GetStockMovementsUseCase struct {
+	stockService ports.StockService
+}
+
+func NewGetStockMovementsUseCase(stockService ports.StockService) ports.GetStockMovementsUseCase {
+	return &GetStockMovementsUseCase{
+		stockService: stockService,
+	}
+}
+
+func (uc *GetStockMovementsUseCase) Execute(ctx context.Context, productID, limit, cursor int) ([]*models.StockMovement, string, bool, error) {
+	return uc.stockService.GetMovements(ctx, productID, limit, cursor)
+}
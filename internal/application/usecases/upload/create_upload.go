@@ -0,0 +1,23 @@
+package upload
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type CreateUploadUseCase struct {
+	uploadService ports.UploadService
+}
+
+func NewCreateUploadUseCase(uploadService ports.UploadService) ports.CreateUploadUseCase {
+	return &CreateUploadUseCase{
+		uploadService: uploadService,
+	}
+}
+
+func (uc *CreateUploadUseCase) Execute(ctx context.Context, contentType string, size int64) (*models.Upload, *models.SignedURL, error) {
+	// The client puts the file straight into the storage through the signed URL
+	return uc.uploadService.Create(ctx, contentType, size)
+}
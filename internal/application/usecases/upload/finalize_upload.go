@@ -0,0 +1,23 @@
+package upload
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type FinalizeUploadUseCase struct {
+	uploadService ports.UploadService
+}
+
+func NewFinalizeUploadUseCase(uploadService ports.UploadService) ports.FinalizeUploadUseCase {
+	return &FinalizeUploadUseCase{
+		uploadService: uploadService,
+	}
+}
+
+func (uc *FinalizeUploadUseCase) Execute(ctx context.Context, uploadID string) (*models.Upload, error) {
+	// The file is verified and processed like a multipart image
+	return uc.uploadService.Finalize(ctx, uploadID)
+}
@@ -0,0 +1,22 @@
+package upload
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+type PurgeUploadsUseCase struct {
+	uploadService ports.UploadService
+}
+
+func NewPurgeUploadsUseCase(uploadService ports.UploadService) ports.PurgeUploadsUseCase {
+	return &PurgeUploadsUseCase{
+		uploadService: uploadService,
+	}
+}
+
+func (uc *PurgeUploadsUseCase) Execute(ctx context.Context) (int, error) {
+	// Uploads never attached to a product expire
+	return uc.uploadService.PurgeExpired(ctx)
+}
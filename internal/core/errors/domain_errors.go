@@ -48,10 +48,50 @@ func (e *AuthorizationError) Error() string {
 }
 
 // BusinessRuleError represents a violation of business rules
+// Fields optionally locates every failure when a rule breaks in several places (e.g. items[1])
 type BusinessRuleError struct {
 	Message string
+	Fields  []FieldError
 }
 
 func (e *BusinessRuleError) Error() string {
 	return e.Message
 }
+
+// PreconditionFailedError represents a domain error when a resource changed
+// since the version the caller based its modification on
+type PreconditionFailedError struct {
+	Message string
+}
+
+func (e *PreconditionFailedError) Error() string {
+	return e.Message
+}
+
+// FieldError is a validation failure located by the path of the field
+// (e.g. variants[1].options[0].price)
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldValidationError represents a domain validation error that reports every
+// invalid field at once instead of stopping at the first one
+type FieldValidationError struct {
+	Message string
+	Fields  []FieldError
+}
+
+func (e *FieldValidationError) Error() string {
+	return e.Message
+}
+
+// Add records a failure of the field at the given path
+func (e *FieldValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// HasErrors reports whether any field failed
+func (e *FieldValidationError) HasErrors() bool {
+	return len(e.Fields) > 0
+}
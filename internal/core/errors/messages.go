@@ -12,6 +12,8 @@ const (
 
 	// Product related error messages
 	ProductNotFound                               = "product_not_found"
+	DeletedProductNotFound                        = "deleted_product_not_found"
+	RetentionDaysMustBePositive                   = "retention_days_must_be_positive"
 	ProductAlreadyExists                          = "product_already_exists"
 	ProductPriceMustBePositive                    = "product_price_must_be_positive"
 	ProductStockCannotBeNegative                  = "product_stock_cannot_be_negative"
@@ -21,9 +23,136 @@ const (
 	PromotionalProductRequiresPromotionalPrice    = "promotional_product_requires_promotional_price"
 	PromotionalPriceMustBeLowerThanRegularPrice   = "promotional_price_must_be_lower_than_regular_price"
 	PromotionalPriceMustBePositiveWhenPromotional = "promotional_price_must_be_positive_when_promotional"
+	InvalidMoneyAmount                            = "invalid_money_amount"
 	QuantityMustBePositive                        = "quantity_must_be_positive"
 	InsufficientStock                             = "insufficient_stock"
 
+	// Variant related error messages
+	InvalidVariants                   = "invalid_variants"
+	VariantNameRequired               = "variant_name_is_required"
+	VariantNamesMustBeUnique          = "variant_names_must_be_unique"
+	InvalidSelectionType              = "invalid_selection_type_must_be_single_multiple_or_custom"
+	VariantMustHaveOptions            = "variant_must_have_at_least_one_option"
+	MaxSelectionsCannotBeNegative     = "max_selections_cannot_be_negative"
+	SingleSelectionMaxSelectionsIsOne = "single_selection_allows_max_selections_of_one"
+	MaxSelectionsExceedsOptionCount   = "max_selections_exceeds_option_count"
+
+	// Variant option related error messages
+	OptionNameRequired          = "option_name_is_required"
+	OptionNamesMustBeUnique     = "option_names_must_be_unique"
+	OptionPriceCannotBeNegative = "option_price_cannot_be_negative"
+	OptionOrdersMustBeUnique    = "option_orders_must_be_unique"
+	OptionStockCannotBeNegative = "option_stock_cannot_be_negative"
+	OptionUnavailable           = "option_unavailable"
+	OptionOutOfStock            = "option_out_of_stock"
+
+	// Variant selection related error messages
+	VariantNotFound                = "variant_not_found"
+	VariantSelectedMoreThanOnce    = "variant_selected_more_than_once"
+	VariantSelectionRequired       = "variant_selection_is_required"
+	SingleSelectionAllowsOneOption = "single_selection_allows_one_option"
+	MaxSelectionsExceeded          = "max_selections_exceeded"
+	OptionNotFoundInVariant        = "option_not_found_in_variant"
+	OptionSelectedMoreThanOnce     = "option_selected_more_than_once"
+	ProductNotAvailable            = "product_not_available"
+
+	// Variant rule related error messages
+	VariantRulesRequireCustomSelection      = "variant_rules_require_custom_selection_type"
+	InvalidVariantRuleType                  = "invalid_rule_type_must_be_selection_count_free_selections_mutually_exclusive_or_requires_option"
+	SelectionCountRuleRequiresMinOrMax      = "selection_count_rule_requires_min_or_max"
+	InvalidSelectionCountRule               = "invalid_selection_count_rule"
+	FreeSelectionsMustBePositive            = "free_selections_must_be_positive"
+	MutuallyExclusiveRuleRequiresTwoOptions = "mutually_exclusive_rule_requires_two_options"
+	RequiresOptionRuleIsIncomplete          = "requires_option_rule_needs_option_and_requires"
+	OptionCannotRequireItself               = "option_cannot_require_itself"
+	VariantRuleReferencesUnknownOption      = "variant_rule_references_unknown_option"
+	MinSelectionsNotReached                 = "min_selections_not_reached"
+	OptionsAreMutuallyExclusive             = "options_are_mutually_exclusive"
+	OptionRequiresAnotherOption             = "option_requires_another_option"
+
+	// Asset related error messages
+	AssetNotFound             = "asset_not_found"
+	InvalidAssetKey           = "invalid_asset_key"
+	UnsupportedAssetType      = "unsupported_asset_type"
+	InvalidImage              = "invalid_image"
+	ImageTooLarge             = "image_dimensions_too_large"
+	ImageTooSmall             = "image_dimensions_too_small"
+	UnsupportedImageFormat    = "unsupported_image_format"
+	GracePeriodMustBePositive = "grace_period_must_be_positive"
+
+	// Direct upload related error messages
+	UploadNotFound                    = "upload_not_found"
+	UploadSizeOutOfRange              = "upload_size_must_be_between_1_byte_and_3mb"
+	UploadExpired                     = "upload_expired"
+	UploadAlreadyFinalized            = "upload_already_finalized"
+	UploadNotFinalized                = "upload_not_finalized"
+	UploadAlreadyAttached             = "upload_already_attached"
+	UploadContentMissing              = "upload_content_not_found"
+	UploadContentTooLarge             = "upload_content_larger_than_declared"
+	DuplicateUploadReference          = "upload_referenced_more_than_once"
+	DirectUploadsRequireObjectStorage = "direct_uploads_require_object_storage"
+
+	// Product patch related error messages
+	InvalidMergePatchDocument     = "invalid_merge_patch_document"
+	ProductFieldIsReadOnly        = "id_created_at_and_version_are_read_only"
+	ProductPricingFieldIsReadOnly = "promotions_and_active_price_are_read_only"
+	ProductCategoryRequired       = "category_id_is_required"
+	ProductImageRequired          = "at_least_one_image_is_required"
+	PatchImageMustExist           = "patched_images_must_reference_existing_images"
+
+	// Product image order related error messages
+	OnlyOnePrimaryImage         = "only_one_image_can_be_primary"
+	ImageOrderRepeatsImage      = "image_order_cannot_repeat_images"
+	PrimaryImageMustBeOrdered   = "primary_image_must_be_in_the_image_order"
+	ImageOrderMustListAllImages = "image_order_must_list_every_product_image"
+
+	// Cart related error messages
+	CartNotFound     = "cart_not_found"
+	CartLineNotFound = "cart_line_not_found"
+	CartIsEmpty      = "cart_is_empty"
+
+	// Order related error messages
+	OrderNotFound        = "order_not_found"
+	OrderRequiresItems   = "order_requires_at_least_one_item"
+	IdempotencyKeyInUse  = "idempotency_key_in_use"
+	IdempotencyKeyReused = "idempotency_key_reused_with_a_different_request"
+
+	// Order status related error messages
+	InvalidOrderStatus              = "invalid_order_status"
+	OrderStatusTransitionNotAllowed = "order_status_transition_not_allowed"
+
+	// Product concurrency related error messages
+	ProductVersionMismatch = "product_was_modified_by_another_request"
+
+	// Stock related error messages
+	InvalidStockMovementReason        = "invalid_reason_must_be_restock_sale_return_or_adjustment"
+	StockMovementQuantityCannotBeZero = "quantity_cannot_be_zero"
+	StockMovementQuantitySignMismatch = "quantity_sign_does_not_match_reason"
+
+	// Product promotion related error messages
+	PromotionNotFound                    = "promotion_not_found"
+	PromotionStartsAtRequired            = "promotion_starts_at_is_required"
+	PromotionEndsAtMustBeAfterStartsAt   = "promotion_ends_at_must_be_after_starts_at"
+	PromotionPriceMustBePositive         = "promotion_price_must_be_positive"
+	PromotionRecurrenceRequiresWeekdays  = "promotion_recurrence_requires_weekdays"
+	InvalidPromotionWeekday              = "invalid_weekday_must_be_0_to_6_without_repeats"
+	PromotionEndTimeMustBeAfterStartTime = "promotion_end_time_must_be_after_start_time"
+	PromotionsCannotOverlap              = "promotions_cannot_overlap"
+	InvalidLocalDateTime                 = "invalid_date_time_format_must_be_yyyy_mm_ddthh_mm_ss"
+	InvalidClockTime                     = "invalid_time_of_day_format_must_be_hh_mm"
+
+	// Product listing related error messages
+	InvalidCursorFormat                 = "invalid_cursor_format"
+	InvalidProductSortField             = "invalid_sort_must_be_price_name_or_created_at"
+	InvalidSortDirection                = "invalid_order_must_be_asc_or_desc"
+	PriceFilterCannotBeNegative         = "price_filter_cannot_be_negative"
+	MinPriceCannotBeGreaterThanMaxPrice = "min_price_cannot_be_greater_than_max_price"
+
+	// Product search related error messages
+	SearchQueryRequired   = "search_query_is_required"
+	SearchQueryTooLong    = "search_query_too_long"
+	InvalidSearchLanguage = "invalid_lang_must_be_es_or_en"
+
 	// Category related error messages
 	CategoryNotFound = "category_not_found"
 
@@ -34,6 +163,7 @@ const (
 	TokenCannotBeEmpty      = "token_cannot_be_empty"
 	UnexpectedSigningMethod = "unexpected_signing_method"
 	CouldNotParseToken      = "could_not_parse_token"
+	AuthenticationRequired  = "authentication_required"
 
 	// Validation error messages
 	InvalidInput           = "invalid_input"
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Asset is a stored binary file, such as a product image
+// Key identifies the file in the storage and URL is where clients download it from
+type Asset struct {
+	Key         string `json:"key"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// ModifiedAt is when the file was stored, set when assets are listed
+	ModifiedAt time.Time `json:"modified_at,omitzero"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// AssetGCReport is the outcome of a garbage collection of the stored assets
+// Orphaned lists the assets no data references that are older than the grace period;
+// a dry run only reports them, otherwise they are deleted
+type AssetGCReport struct {
+	DryRun      bool      `json:"dry_run"`
+	GracePeriod string    `json:"grace_period"`
+	StartedAt   time.Time `json:"started_at"`
+	Scanned     int       `json:"scanned"`
+	Referenced  int       `json:"referenced"`
+	Orphaned    []*Asset  `json:"orphaned"`
+	Deleted     int       `json:"deleted"`
+	Failed      int       `json:"failed"`
+	FreedBytes  int64     `json:"freed_bytes"`
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+// CartTTL is how long a cart lives without activity before it expires
+const CartTTL = 7 * 24 * time.Hour
+
+// CartOwner identifies whose cart it is: a customer or the anonymous cart token
+type CartOwner struct {
+	CustomerID int
+	Token      string
+}
+
+// IsAnonymous reports whether the cart is only known by its token
+func (o CartOwner) IsAnonymous() bool {
+	return o.CustomerID == 0
+}
+
+// IsKnown reports whether the request identified a cart owner at all
+func (o CartOwner) IsKnown() bool {
+	return o.CustomerID > 0 || o.Token != ""
+}
+
+// Cart is the server-side shopping cart of an owner in one shop
+type Cart struct {
+	ID         int         `json:"id"`
+	ShopID     int         `json:"shop_id"`
+	CustomerID int         `json:"customer_id,omitempty"`
+	Token      string      `json:"token,omitempty"`
+	Lines      []*CartLine `json:"lines"`
+	// Subtotal and ItemCount are computed by Price for responses
+	Subtotal  Money     `json:"subtotal"`
+	ItemCount int       `json:"item_count"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CartLine is a product configured with its variant options and a quantity
+type CartLine struct {
+	ID         int                `json:"id"`
+	ProductID  int                `json:"product_id"`
+	Selections []VariantSelection `json:"selections"`
+	Quantity   int                `json:"quantity"`
+	// The fields below are computed by Price with the current product pricing
+	ProductName string       `json:"product_name,omitempty"`
+	UnitPrice   Money        `json:"unit_price"`
+	Total       Money        `json:"total"`
+	Breakdown   []*QuoteLine `json:"breakdown,omitempty"`
+	Available   bool         `json:"available"`
+	Issue       string       `json:"issue,omitempty"` // why the line cannot be bought right now
+}
+
+// NewCart opens an empty cart for the owner in the shop
+func NewCart(shopID int, owner CartOwner, now time.Time) *Cart {
+	cart := &Cart{
+		ShopID:     shopID,
+		CustomerID: owner.CustomerID,
+		Token:      owner.Token,
+		Lines:      []*CartLine{},
+		CreatedAt:  now,
+	}
+	cart.Touch(now)
+	return cart
+}
+
+// Touch records activity on the cart, pushing its expiration back
+func (c *Cart) Touch(now time.Time) {
+	c.UpdatedAt = now
+	c.ExpiresAt = now.Add(CartTTL)
+}
+
+func (c *Cart) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// FindLine returns the line of the cart with the given ID
+func (c *Cart) FindLine(lineID int) (*CartLine, error) {
+	for _, line := range c.Lines {
+		if line.ID == lineID {
+			return line, nil
+		}
+	}
+	return nil, &errors.RecordNotFoundError{Message: errors.CartLineNotFound}
+}
+
+// FindMatchingLine returns the line holding the product configured with the same options
+// Adding the same configuration again increases that line instead of repeating it
+func (c *Cart) FindMatchingLine(productID int, selections []VariantSelection) *CartLine {
+	for _, line := range c.Lines {
+		if line.ProductID == productID && sameSelections(line.Selections, selections) {
+			return line
+		}
+	}
+	return nil
+}
+
+// Price recomputes every line with the current products and sums the available ones
+// products holds the products of the lines by ID; missing products make their lines unavailable
+func (c *Cart) Price(products map[int]*Product, at time.Time) {
+	c.Subtotal = Money{}
+	c.ItemCount = 0
+	for _, line := range c.Lines {
+		line.Price(products[line.ProductID], at)
+		if !line.Available {
+			continue
+		}
+		c.Subtotal = c.Subtotal.Add(line.Total)
+		c.ItemCount += line.Quantity
+	}
+}
+
+// Price quotes the line with the product pricing and option prices at the instant
+// Lines whose product, options or stock are no longer available keep an issue instead of a price
+func (l *CartLine) Price(product *Product, at time.Time) {
+	l.UnitPrice, l.Total, l.Breakdown = Money{}, Money{}, nil
+	if product == nil {
+		l.Available = false
+		l.Issue = errors.ProductNotAvailable
+		return
+	}
+
+	l.ProductName = product.Name
+	quote, err := product.Quote(l.Selections, l.Quantity, at)
+	if err != nil {
+		l.Available = false
+		l.Issue = err.Error()
+		return
+	}
+
+	l.UnitPrice = quote.UnitPrice
+	l.Total = quote.Total
+	l.Breakdown = quote.Lines
+	l.Available = true
+	l.Issue = ""
+}
+
+// sameSelections compares two selections ignoring the order of variants and options
+func sameSelections(a, b []VariantSelection) bool {
+	left, right := normalizeSelections(a), normalizeSelections(b)
+	if len(left) != len(right) {
+		return false
+	}
+	for variantID, optionIDs := range left {
+		if !slices.Equal(optionIDs, right[variantID]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeSelections indexes the sorted option IDs by variant, dropping empty choices
+func normalizeSelections(selections []VariantSelection) map[int][]int {
+	normalized := make(map[int][]int, len(selections))
+	for _, selection := range selections {
+		if len(selection.OptionIDs) == 0 {
+			continue
+		}
+		optionIDs := slices.Clone(selection.OptionIDs)
+		slices.Sort(optionIDs)
+		normalized[selection.VariantID] = optionIDs
+	}
+	return normalized
+}
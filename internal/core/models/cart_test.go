@@ -0,0 +1,111 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func TestNewCart(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	cart := NewCart(1, CartOwner{Token: "abc"}, now)
+
+	assert.Equal(t, "abc", cart.Token)
+	assert.Empty(t, cart.Lines)
+	assert.Equal(t, now.Add(CartTTL), cart.ExpiresAt)
+	assert.False(t, cart.IsExpired(now.Add(CartTTL-time.Second)))
+	assert.True(t, cart.IsExpired(now.Add(CartTTL)))
+}
+
+func TestCart_FindMatchingLine(t *testing.T) {
+	cart := &Cart{Lines: []*CartLine{
+		{ID: 1, ProductID: 1, Selections: []VariantSelection{{VariantID: 1, OptionIDs: []int{2}}, {VariantID: 2, OptionIDs: []int{3, 4}}}},
+		{ID: 2, ProductID: 2},
+	}}
+
+	t.Run("when the configuration matches in another order then returns the line", func(t *testing.T) {
+		selections := []VariantSelection{{VariantID: 2, OptionIDs: []int{4, 3}}, {VariantID: 1, OptionIDs: []int{2}}}
+		assert.Equal(t, 1, cart.FindMatchingLine(1, selections).ID)
+	})
+
+	t.Run("when empty choices are sent then they are ignored", func(t *testing.T) {
+		selections := []VariantSelection{{VariantID: 3, OptionIDs: []int{}}}
+		assert.Equal(t, 2, cart.FindMatchingLine(2, selections).ID)
+	})
+
+	t.Run("when the options differ then returns nil", func(t *testing.T) {
+		selections := []VariantSelection{{VariantID: 1, OptionIDs: []int{2}}, {VariantID: 2, OptionIDs: []int{3}}}
+		assert.Nil(t, cart.FindMatchingLine(1, selections))
+	})
+}
+
+func TestCart_FindLine(t *testing.T) {
+	cart := &Cart{Lines: []*CartLine{{ID: 1}}}
+
+	_, err := cart.FindLine(2)
+
+	assert.Equal(t, &errors.RecordNotFoundError{Message: errors.CartLineNotFound}, err)
+}
+
+func TestCart_Price(t *testing.T) {
+	at := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	t.Run("when every line is available then sums the lines", func(t *testing.T) {
+		// Arrange
+		cart := &Cart{Lines: []*CartLine{
+			{ID: 1, ProductID: 1, Quantity: 2, Selections: []VariantSelection{{VariantID: 1, OptionIDs: []int{2}}, {VariantID: 2, OptionIDs: []int{3}}}},
+			{ID: 2, ProductID: 1, Quantity: 1, Selections: []VariantSelection{{VariantID: 1, OptionIDs: []int{1}}}},
+		}}
+
+		// Act
+		cart.Price(map[int]*Product{1: quotedBurger()}, at)
+
+		// Assert
+		assert.Equal(t, "14.50", cart.Lines[0].UnitPrice.String())
+		assert.Equal(t, "29.00", cart.Lines[0].Total.String())
+		assert.Len(t, cart.Lines[0].Breakdown, 3)
+		assert.Equal(t, "10.00", cart.Lines[1].Total.String())
+		assert.Equal(t, "39.00", cart.Subtotal.String())
+		assert.Equal(t, 3, cart.ItemCount)
+	})
+
+	t.Run("when the product has a promotion then the line uses the effective price", func(t *testing.T) {
+		// Arrange
+		product := quotedBurger()
+		product.IsPromotional = true
+		product.PromotionalPrice = MoneyFromFloat(8)
+		cart := &Cart{Lines: []*CartLine{{ID: 1, ProductID: 1, Quantity: 1, Selections: []VariantSelection{{VariantID: 1, OptionIDs: []int{1}}}}}}
+
+		// Act
+		cart.Price(map[int]*Product{1: product}, at)
+
+		// Assert
+		assert.Equal(t, "8.00", cart.Subtotal.String())
+	})
+
+	t.Run("when a line cannot be bought then it keeps the issue and is left out of the subtotal", func(t *testing.T) {
+		// Arrange
+		product := quotedBurger()
+		product.Stock = 1
+		cart := &Cart{Lines: []*CartLine{
+			{ID: 1, ProductID: 1, Quantity: 2, Selections: []VariantSelection{{VariantID: 1, OptionIDs: []int{1}}}},
+			{ID: 2, ProductID: 9, Quantity: 1},
+		}}
+
+		// Act
+		cart.Price(map[int]*Product{1: product}, at)
+
+		// Assert
+		assert.False(t, cart.Lines[0].Available)
+		assert.Equal(t, errors.InsufficientStock, cart.Lines[0].Issue)
+		assert.Equal(t, "Classic Burger", cart.Lines[0].ProductName)
+		assert.False(t, cart.Lines[1].Available)
+		assert.Equal(t, errors.ProductNotAvailable, cart.Lines[1].Issue)
+		assert.True(t, cart.Subtotal.IsZero())
+		assert.Zero(t, cart.ItemCount)
+	})
+}
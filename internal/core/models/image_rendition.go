@@ -0,0 +1,13 @@
+package models
+
+// OriginalRendition is the full size rendition every processed image has
+const OriginalRendition = "original"
+
+// ImageRendition is one encoded size of an uploaded image
+type ImageRendition struct {
+	Name        string
+	Content     []byte
+	ContentType string
+	Width       int
+	Height      int
+}
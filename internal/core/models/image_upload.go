@@ -0,0 +1,9 @@
+package models
+
+import "io"
+
+// ImageUpload is one uploaded image, read as a stream so it is never held in memory whole
+type ImageUpload struct {
+	Field   string // form field the image came from, e.g. images[0]
+	Content io.Reader
+}
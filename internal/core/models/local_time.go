@@ -0,0 +1,161 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+
+	// Embedded zone database so shop time zones resolve on minimal images
+	_ "time/tzdata"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+// DefaultTimeZone is used when a shop has no (or an unknown) time zone
+const DefaultTimeZone = "America/Argentina/Buenos_Aires"
+
+// LoadTimeZone resolves an IANA time zone name, falling back to DefaultTimeZone
+func LoadTimeZone(name string) *time.Location {
+	if name != "" {
+		if location, err := time.LoadLocation(name); err == nil {
+			return location
+		}
+	}
+	location, err := time.LoadLocation(DefaultTimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// localDateTimeLayouts are the accepted wall clock formats, most precise first
+var localDateTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+// LocalDateTime is a wall clock date and time without offset (e.g. "2026-11-01T00:00:00")
+// It is interpreted in the shop time zone, so "midnight" stays midnight across DST changes
+type LocalDateTime struct {
+	wall time.Time // always UTC, only the clock fields are meaningful
+}
+
+// NewLocalDateTime builds a wall clock date and time
+func NewLocalDateTime(year int, month time.Month, day, hour, minute int) LocalDateTime {
+	return LocalDateTime{wall: time.Date(year, month, day, hour, minute, 0, 0, time.UTC)}
+}
+
+// LocalDateTimeOf returns the wall clock of an instant in its own location
+func LocalDateTimeOf(at time.Time) LocalDateTime {
+	return LocalDateTime{wall: time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), at.Second(), 0, time.UTC)}
+}
+
+// ParseLocalDateTime parses "2006-01-02T15:04:05", "2006-01-02T15:04" or "2006-01-02"
+func ParseLocalDateTime(value string) (LocalDateTime, error) {
+	for _, layout := range localDateTimeLayouts {
+		if wall, err := time.Parse(layout, value); err == nil {
+			return LocalDateTime{wall: wall}, nil
+		}
+	}
+	return LocalDateTime{}, &errors.ValidationError{Message: errors.InvalidLocalDateTime}
+}
+
+// In returns the instant of the wall clock in the given location
+func (d LocalDateTime) In(location *time.Location) time.Time {
+	return time.Date(d.wall.Year(), d.wall.Month(), d.wall.Day(), d.wall.Hour(), d.wall.Minute(), d.wall.Second(), 0, location)
+}
+
+// IsZero reports whether the value was never set
+func (d LocalDateTime) IsZero() bool {
+	return d.wall.IsZero()
+}
+
+// Before reports whether d is earlier than other
+func (d LocalDateTime) Before(other LocalDateTime) bool {
+	return d.wall.Before(other.wall)
+}
+
+// String formats the value as "2006-01-02T15:04:05"
+func (d LocalDateTime) String() string {
+	return d.wall.Format(localDateTimeLayouts[0])
+}
+
+// MarshalJSON encodes the wall clock without offset
+func (d LocalDateTime) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON decodes any of the accepted wall clock formats
+func (d *LocalDateTime) UnmarshalJSON(data []byte) error {
+	value, err := strconv.Unquote(string(data))
+	if err != nil {
+		return &errors.ValidationError{Message: errors.InvalidLocalDateTime}
+	}
+	parsed, err := ParseLocalDateTime(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value stores the wall clock in a timestamp without time zone column
+func (d LocalDateTime) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// ClockTime is a time of day in minutes since midnight, encoded as "HH:MM"
+type ClockTime int
+
+// ParseClockTime parses "HH:MM" (24h); "24:00" is accepted as the end of the day
+func ParseClockTime(value string) (ClockTime, error) {
+	if len(value) != 5 || value[2] != ':' {
+		return 0, &errors.ValidationError{Message: errors.InvalidClockTime}
+	}
+	hours, hoursErr := strconv.Atoi(value[:2])
+	minutes, minutesErr := strconv.Atoi(value[3:])
+	if hoursErr != nil || minutesErr != nil || minutes < 0 || minutes > 59 || hours < 0 || hours > 24 || hours == 24 && minutes != 0 {
+		return 0, &errors.ValidationError{Message: errors.InvalidClockTime}
+	}
+	return ClockTime(hours*60 + minutes), nil
+}
+
+// ClockTimeOf returns the time of day of an instant in its own location
+func ClockTimeOf(at time.Time) ClockTime {
+	return ClockTime(at.Hour()*60 + at.Minute())
+}
+
+// On returns the instant of the time of day on the date of day, in day's location
+func (c ClockTime) On(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, int(c), 0, 0, day.Location())
+}
+
+// String formats the time of day as "HH:MM"
+func (c ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// MarshalJSON encodes the time of day as "HH:MM"
+func (c ClockTime) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(c.String())), nil
+}
+
+// UnmarshalJSON decodes "HH:MM"; PostgreSQL time values ("18:00:00") are accepted too
+func (c *ClockTime) UnmarshalJSON(data []byte) error {
+	value, err := strconv.Unquote(string(data))
+	if err != nil {
+		return &errors.ValidationError{Message: errors.InvalidClockTime}
+	}
+	if len(value) == 8 && value[5:] == ":00" {
+		value = value[:5]
+	}
+	parsed, err := ParseClockTime(value)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// Value stores the time of day in a time column
+func (c ClockTime) Value() (driver.Value, error) {
+	return c.String(), nil
+}
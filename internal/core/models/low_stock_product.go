@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// LowStockProduct is a product whose stock is at or below its minimum stock
+// It is both a row of the low-stock report and the payload of a low-stock alert
+type LowStockProduct struct {
+	ProductID    int        `json:"product_id"`
+	ShopID       int        `json:"shop_id"`
+	Name         string     `json:"name"`
+	Stock        int        `json:"stock"`
+	MinimumStock int        `json:"minimum_stock"`
+	AlertedAt    *time.Time `json:"alerted_at,omitempty"` // nil until an alert was delivered
+}
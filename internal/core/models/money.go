@@ -0,0 +1,265 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+// moneyMinorUnits is the number of decimals of every amount; shops sell in pesos
+// and no column stores a currency, so amounts carry none either
+const moneyMinorUnits = 2
+
+// Money is an exact amount in pesos stored as integer minor units (cents)
+// The zero value is zero
+type Money struct {
+	amount int64
+}
+
+// NewMoney creates an amount from minor units (e.g. 1999 is 19.99)
+func NewMoney(minorUnits int64) Money {
+	return Money{amount: minorUnits}
+}
+
+// ParseMoney parses a decimal string such as "19.99"
+// Extra decimals are rounded half away from zero, the only rounding rule for money
+func ParseMoney(value string) (Money, error) {
+	value = strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(value, "-")
+	if negative || strings.HasPrefix(value, "+") {
+		value = value[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, &errors.ValidationError{Message: errors.InvalidMoneyAmount}
+	}
+
+	digits := moneyMinorUnits
+	roundUp := false
+	if len(fraction) > digits {
+		roundUp = fraction[digits] >= '5'
+		fraction = fraction[:digits]
+	}
+	fraction += strings.Repeat("0", digits-len(fraction))
+
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, &errors.ValidationError{Message: errors.InvalidMoneyAmount}
+	}
+	if roundUp {
+		if amount == math.MaxInt64 {
+			return Money{}, &errors.ValidationError{Message: errors.InvalidMoneyAmount}
+		}
+		amount++
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return NewMoney(amount), nil
+}
+
+// MoneyFromFloat converts a float amount
+// The shortest decimal representation is used so 0.1 stays 0.10 and not 0.0999...
+func MoneyFromFloat(value float64) Money {
+	money, err := ParseMoney(strconv.FormatFloat(value, 'f', -1, 64))
+	if err != nil {
+		return Money{}
+	}
+	return money
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MinorUnits returns the amount in minor units (cents)
+func (m Money) MinorUnits() int64 {
+	return m.amount
+}
+
+// IsZero reports whether the amount is zero (used by omitzero JSON tags)
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+// IsNegative reports whether the amount is lower than zero
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Cmp returns -1, 0 or +1 comparing the amounts
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.amount < other.amount:
+		return -1
+	case m.amount > other.amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Add returns the sum of both amounts
+func (m Money) Add(other Money) Money {
+	return NewMoney(m.amount + other.amount)
+}
+
+// Multiply returns the amount times a quantity
+func (m Money) Multiply(quantity int) Money {
+	return NewMoney(m.amount * int64(quantity))
+}
+
+// String formats the amount as a plain decimal with two decimals (e.g. "19.90")
+func (m Money) String() string {
+	digits := moneyMinorUnits
+	amount := m.amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	text := strconv.FormatInt(amount, 10)
+	if len(text) <= digits {
+		text = strings.Repeat("0", digits-len(text)+1) + text
+	}
+	return sign + text[:len(text)-digits] + "." + text[len(text)-digits:]
+}
+
+// Float64 returns the amount in major units; only for display or metrics
+func (m Money) Float64() float64 {
+	value, _ := strconv.ParseFloat(m.String(), 64)
+	return value
+}
+
+// MarshalJSON encodes the amount as a JSON number in major units (e.g. 19.9),
+// the same shape clients received when prices were float64
+func (m Money) MarshalJSON() ([]byte, error) {
+	text := m.String()
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	return []byte(text), nil
+}
+
+// UnmarshalJSON decodes a JSON number or numeric string without going through float64
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		*m = Money{}
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	// Accept exponent notation (1.5e2) by shifting the decimal point, never through float64
+	if strings.ContainsAny(text, "eE") {
+		plain, ok := expandExponent(text)
+		if !ok {
+			return &errors.ValidationError{Message: errors.InvalidMoneyAmount}
+		}
+		text = plain
+	}
+
+	money, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
+
+// maxMoneyExponent bounds the exponents accepted in JSON amounts
+const maxMoneyExponent = 18
+
+// expandExponent rewrites a number such as "-1.25e2" as the plain decimal "-125"
+func expandExponent(value string) (string, bool) {
+	mantissa, exponentText, _ := strings.Cut(strings.ToLower(value), "e")
+	exponent, err := strconv.Atoi(exponentText)
+	if err != nil || exponent > maxMoneyExponent || exponent < -maxMoneyExponent {
+		return "", false
+	}
+
+	sign := ""
+	if strings.HasPrefix(mantissa, "-") || strings.HasPrefix(mantissa, "+") {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return "", false
+	}
+
+	digits := whole + fraction
+	point := len(whole) + exponent
+	switch {
+	case point <= 0:
+		return sign + "0." + strings.Repeat("0", -point) + digits, true
+	case point >= len(digits):
+		return sign + digits + strings.Repeat("0", point-len(digits)), true
+	default:
+		return sign + digits[:point] + "." + digits[point:], true
+	}
+}
+
+// Value stores the amount as an exact decimal (numeric columns)
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads numeric, float and integer columns; NULL is zero
+func (m *Money) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*m = Money{}
+		return nil
+	case []byte:
+		return m.scanString(string(value))
+	case string:
+		return m.scanString(value)
+	case float64:
+		*m = MoneyFromFloat(value)
+		return nil
+	case int64:
+		*m = NewMoney(value * pow10(moneyMinorUnits))
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
+
+func (m *Money) scanString(value string) error {
+	money, err := ParseMoney(value)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into Money", value)
+	}
+	*m = money
+	return nil
+}
+
+func pow10(exponent int) int64 {
+	result := int64(1)
+	for i := 0; i < exponent; i++ {
+		result *= 10
+	}
+	return result
+}
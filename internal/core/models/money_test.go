@@ -0,0 +1,115 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected int64
+	}{
+		{"when amount has two decimals then keeps them exactly", "19.99", 1999},
+		{"when amount has no decimals then pads them", "20", 2000},
+		{"when third decimal is five then rounds half away from zero", "0.125", 13},
+		{"when negative amount is rounded then rounds away from zero", "-0.125", -13},
+		{"when third decimal is below five then truncates", "10.994", 1099},
+		{"when amount starts with a dot then reads the fraction", ".5", 50},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			money, err := ParseMoney(tc.value)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, money.MinorUnits())
+		})
+	}
+
+	t.Run("when amount is not a decimal then returns validation error", func(t *testing.T) {
+		for _, value := range []string{"", "abc", "1.2.3", "1,5", "."} {
+			_, err := ParseMoney(value)
+			assert.Error(t, err, value)
+		}
+	})
+
+	t.Run("when rounding overflows the amount then returns validation error", func(t *testing.T) {
+		// Act
+		_, err := ParseMoney("92233720368547758.075")
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestMoney_JSON(t *testing.T) {
+	t.Run("when marshaling then emits a number without trailing zeros", func(t *testing.T) {
+		// Arrange
+		price := NewMoney(1990)
+
+		// Act
+		data, err := json.Marshal(price)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "19.9", string(data))
+	})
+
+	t.Run("when unmarshaling numbers or strings then does not drift", func(t *testing.T) {
+		for raw, expected := range map[string]int64{`0.1`: 10, `"0.3"`: 30, `1e2`: 10000, `null`: 0} {
+			var money Money
+			assert.NoError(t, json.Unmarshal([]byte(raw), &money), raw)
+			assert.Equal(t, expected, money.MinorUnits(), raw)
+		}
+	})
+
+	t.Run("when unmarshaling exponents then shifts the decimal point exactly", func(t *testing.T) {
+		for raw, expected := range map[string]int64{`1.5E2`: 15000, `-2.5e-1`: -25, `1999e-2`: 1999, `12345e-6`: 1, `9007199254740993e-2`: 9007199254740993} {
+			var money Money
+			assert.NoError(t, json.Unmarshal([]byte(raw), &money), raw)
+			assert.Equal(t, expected, money.MinorUnits(), raw)
+		}
+	})
+
+	t.Run("when unmarshaling invalid exponents then fails", func(t *testing.T) {
+		for _, raw := range []string{`1e400`, `"1e"`, `"e2"`, `"1.2.3e2"`} {
+			var money Money
+			assert.Error(t, json.Unmarshal([]byte(raw), &money), raw)
+		}
+	})
+
+	t.Run("when adding amounts that drift as floats then result is exact", func(t *testing.T) {
+		// Arrange
+		a := MoneyFromFloat(0.1)
+		b := MoneyFromFloat(0.2)
+
+		// Act
+		sum := a.Add(b)
+
+		// Assert
+		assert.Equal(t, 0, sum.Cmp(MoneyFromFloat(0.3)))
+		assert.Equal(t, "0.30", sum.String())
+	})
+}
+
+func TestMoney_Scan(t *testing.T) {
+	t.Run("when scanning database values then reads every numeric representation", func(t *testing.T) {
+		for _, src := range []interface{}{[]byte("12.50"), "12.5", 12.5} {
+			var money Money
+			assert.NoError(t, money.Scan(src))
+			assert.Equal(t, int64(1250), money.MinorUnits())
+		}
+	})
+
+	t.Run("when scanning NULL then amount is zero", func(t *testing.T) {
+		money := NewMoney(100)
+		assert.NoError(t, money.Scan(nil))
+		assert.True(t, money.IsZero())
+	})
+}
@@ -1,8 +1,57 @@
 package models
 
+import (
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
 type Option struct {
-	ID    int     `json:"id,omitempty"`
-	Name  string  `json:"name,omitempty"`
-	Price float64 `json:"price,omitempty"`
-	Order int     `json:"order,omitempty"`
+	ID    int    `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Price Money  `json:"price,omitzero"`
+	Order int    `json:"order,omitempty"`
+	// Stock is nil when the option is not tracked on its own (e.g. a sauce that never runs out)
+	Stock *int `json:"stock,omitempty"`
+	// IsAvailable lets the shop switch an option off by hand; nil means available
+	IsAvailable *bool `json:"is_available,omitempty"`
+	// SoldOut is computed by ApplyAvailability for storefront responses
+	SoldOut bool `json:"sold_out,omitempty"`
+}
+
+// validate records every invalid field of the option found at path (e.g. variants[1].options[0])
+func (o *Option) validate(path string, result *errors.FieldValidationError) {
+	if strings.TrimSpace(o.Name) == "" {
+		result.Add(path+".name", errors.OptionNameRequired)
+	}
+
+	if o.Price.IsNegative() {
+		result.Add(path+".price", errors.OptionPriceCannotBeNegative)
+	}
+
+	// Business rule: tracked stock cannot be negative
+	if o.Stock != nil && *o.Stock < 0 {
+		result.Add(path+".stock", errors.OptionStockCannotBeNegative)
+	}
+}
+
+// IsSelectable reports whether customers can pick the option right now
+func (o *Option) IsSelectable() bool {
+	if o.IsAvailable != nil && !*o.IsAvailable {
+		return false
+	}
+	return o.Stock == nil || *o.Stock > 0
+}
+
+// EnsureAvailable checks that quantity units of the option can be quoted or ordered
+func (o *Option) EnsureAvailable(quantity int) error {
+	if o.IsAvailable != nil && !*o.IsAvailable {
+		return &errors.BusinessRuleError{Message: errors.OptionUnavailable}
+	}
+
+	if o.Stock != nil && *o.Stock < quantity {
+		return &errors.BusinessRuleError{Message: errors.OptionOutOfStock}
+	}
+
+	return nil
 }
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func TestOption_EnsureAvailable(t *testing.T) {
+	stock := func(units int) *int { return &units }
+	unavailable := false
+
+	t.Run("when the option is not stock tracked then any quantity is available", func(t *testing.T) {
+		assert.NoError(t, (&Option{Name: "Ketchup"}).EnsureAvailable(100))
+	})
+
+	t.Run("when the tracked stock is lower than the quantity then the option is out of stock", func(t *testing.T) {
+		// Arrange
+		option := &Option{Name: "Bacon", Stock: stock(1)}
+
+		// Act
+		err := option.EnsureAvailable(2)
+
+		// Assert
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.OptionOutOfStock}, err)
+	})
+
+	t.Run("when the option was switched off then it is unavailable", func(t *testing.T) {
+		err := (&Option{Name: "XL", Stock: stock(5), IsAvailable: &unavailable}).EnsureAvailable(1)
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.OptionUnavailable}, err)
+	})
+}
+
+func TestProduct_ApplyAvailability(t *testing.T) {
+	t.Run("when an option has no stock left then it is flagged as sold out", func(t *testing.T) {
+		// Arrange
+		empty := 0
+		bacon := &Option{Name: "Bacon", Stock: &empty}
+		cheese := &Option{Name: "Cheese"}
+		product := &Product{Variants: []*Variant{{Name: "Extras", Options: []*Option{bacon, cheese}}}}
+
+		// Act
+		product.ApplyAvailability()
+
+		// Assert
+		assert.True(t, bacon.SoldOut)
+		assert.False(t, cheese.SoldOut)
+	})
+
+	t.Run("when an option has negative stock then validation rejects the product", func(t *testing.T) {
+		// Arrange
+		negative := -1
+		product := &Product{
+			Price:    MoneyFromFloat(10),
+			Variants: []*Variant{{Name: "Extras", SelectionType: Multiple, Options: []*Option{{Name: "Bacon", Stock: &negative}}}},
+		}
+
+		// Act
+		err := product.Validate()
+
+		// Assert
+		assert.Equal(t, &errors.FieldValidationError{
+			Message: errors.InvalidVariants,
+			Fields:  []errors.FieldError{{Field: "variants[0].options[0].stock", Message: errors.OptionStockCannotBeNegative}},
+		}, err)
+	})
+}
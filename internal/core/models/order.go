@@ -0,0 +1,345 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+// OrderStatus is the stage of an order
+type OrderStatus string
+
+const (
+	OrderPending        OrderStatus = "pending" // placed, waiting for the shop
+	OrderConfirmed      OrderStatus = "confirmed"
+	OrderPreparing      OrderStatus = "preparing"
+	OrderReady          OrderStatus = "ready" // ready to be picked up or sent
+	OrderOutForDelivery OrderStatus = "out_for_delivery"
+	OrderDelivered      OrderStatus = "delivered"
+	OrderCancelled      OrderStatus = "cancelled" // dropped before delivery, its items go back to the stock
+	OrderRefunded       OrderStatus = "refunded"  // delivered and paid back
+)
+
+// orderTransitions lists the statuses every status can move to
+// Cancelled and refunded orders are final
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderPending:        {OrderConfirmed, OrderCancelled},
+	OrderConfirmed:      {OrderPreparing, OrderCancelled},
+	OrderPreparing:      {OrderReady, OrderCancelled},
+	OrderReady:          {OrderOutForDelivery, OrderDelivered, OrderCancelled},
+	OrderOutForDelivery: {OrderDelivered},
+	OrderDelivered:      {OrderRefunded},
+	OrderCancelled:      {},
+	OrderRefunded:       {},
+}
+
+// IsValid reports whether the status is a stage of the order lifecycle
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether an order can move from the status to the next one
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	return slices.Contains(orderTransitions[s], next)
+}
+
+// OrderStatusChange is an entry of the status history of an order
+type OrderStatusChange struct {
+	ID        int         `json:"id,omitempty"`
+	OrderID   int         `json:"order_id"`
+	From      OrderStatus `json:"from"`
+	To        OrderStatus `json:"to"`
+	Actor     string      `json:"actor,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// ReturnsStock reports whether the change puts the ordered items back in the stock
+func (c *OrderStatusChange) ReturnsStock() bool {
+	return c.To == OrderCancelled
+}
+
+// OrderLine is a product requested for an order, from a direct payload or a cart line
+type OrderLine struct {
+	ProductID  int                `json:"product_id"`
+	Selections []VariantSelection `json:"selections"`
+	Quantity   int                `json:"quantity"`
+}
+
+// OrderDraft is what an order is placed from: the lines of a payload or the cart of an owner
+type OrderDraft struct {
+	CustomerID int
+	Notes      string
+	Lines      []OrderLine
+	// Cart is set when the order is placed from the cart of the owner
+	Cart *CartOwner
+	// IdempotencyKey identifies the checkout across client retries; empty when not sent
+	IdempotencyKey string
+}
+
+// Fingerprint identifies what the draft asks for, so a retried key can be told
+// apart from a key reused for a different checkout
+func (d *OrderDraft) Fingerprint() string {
+	payload, _ := json.Marshal(struct {
+		CustomerID int         `json:"customer_id"`
+		Notes      string      `json:"notes"`
+		Lines      []OrderLine `json:"lines"`
+		Cart       *CartOwner  `json:"cart"`
+	}{d.CustomerID, d.Notes, d.Lines, d.Cart})
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Order is a purchase placed in a shop
+// Its items are snapshots, so later product edits do not change what was ordered
+type Order struct {
+	ID         int          `json:"id"`
+	ShopID     int          `json:"shop_id"`
+	CustomerID int          `json:"customer_id,omitempty"`
+	Status     OrderStatus  `json:"status"`
+	Items      []*OrderItem `json:"items"`
+	Subtotal   Money        `json:"subtotal"`
+	Total      Money        `json:"total"`
+	ItemCount  int          `json:"item_count"`
+	Notes      string       `json:"notes,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+
+	IdempotencyKey     string `json:"-"`
+	RequestFingerprint string `json:"-"`
+	// Replayed is set when a retried checkout returns the order it already placed
+	Replayed bool `json:"-"`
+}
+
+// StockLevels is the stock of products and of the options that track their own stock, by ID
+type StockLevels struct {
+	Products map[int]int
+	Options  map[int]int
+}
+
+// OrderItem is the snapshot of an ordered product with the options and prices of the purchase
+type OrderItem struct {
+	ID          int                `json:"id"`
+	ProductID   int                `json:"product_id,omitempty"` // zero once the product is purged
+	ProductName string             `json:"product_name"`
+	Selections  []VariantSelection `json:"selections"`
+	Options     []OrderItemOption  `json:"options"`
+	Quantity    int                `json:"quantity"`
+	BasePrice   Money              `json:"base_price"` // product price without options
+	UnitPrice   Money              `json:"unit_price"` // one product with its options
+	Total       Money              `json:"total"`
+}
+
+// OrderItemOption is a chosen option with the name and price it had at purchase time
+type OrderItemOption struct {
+	VariantID int    `json:"variant_id"`
+	OptionID  int    `json:"option_id"`
+	Name      string `json:"name"` // e.g. "Size: Double"
+	Price     Money  `json:"price"`
+}
+
+// NewOrder opens an empty pending order for the customer (zero for guests)
+func NewOrder(shopID, customerID int, notes string, now time.Time) *Order {
+	return &Order{
+		ShopID:     shopID,
+		CustomerID: customerID,
+		Status:     OrderPending,
+		Items:      []*OrderItem{},
+		Notes:      notes,
+		CreatedAt:  now,
+	}
+}
+
+// GetID implements Identifiable interface for pagination
+func (o *Order) GetID() int {
+	return o.ID
+}
+
+// AddItem quotes the line with the product at the instant and keeps a snapshot of it
+// Unavailable products, options or stock fail like a quote does
+func (o *Order) AddItem(product *Product, line OrderLine, at time.Time) error {
+	quote, err := product.Quote(line.Selections, line.Quantity, at)
+	if err != nil {
+		return err
+	}
+
+	item := &OrderItem{
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		Selections:  line.Selections,
+		Options:     []OrderItemOption{},
+		Quantity:    quote.Quantity,
+		BasePrice:   quote.Lines[0].UnitPrice,
+		UnitPrice:   quote.UnitPrice,
+		Total:       quote.Total,
+	}
+	for _, option := range quote.Lines[1:] {
+		item.Options = append(item.Options, OrderItemOption{
+			VariantID: option.VariantID,
+			OptionID:  option.OptionID,
+			Name:      option.Description,
+			Price:     option.UnitPrice,
+		})
+	}
+
+	o.Items = append(o.Items, item)
+	o.Subtotal = o.Subtotal.Add(item.Total)
+	o.Total = o.Subtotal
+	o.ItemCount += item.Quantity
+	return nil
+}
+
+// Validate validates business rules of an order about to be placed
+func (o *Order) Validate() error {
+	// Business rule: an order needs at least one item
+	if len(o.Items) == 0 {
+		return &errors.ValidationError{Message: errors.OrderRequiresItems}
+	}
+	return nil
+}
+
+// BelongsTo reports whether the customer placed the order
+func (o *Order) BelongsTo(customerID int) bool {
+	return o.CustomerID == customerID
+}
+
+// TransitionTo moves the order to the next status and returns the change to record
+func (o *Order) TransitionTo(next OrderStatus, actor string, at time.Time) (*OrderStatusChange, error) {
+	// Business rule: only the statuses of the lifecycle exist
+	if !next.IsValid() {
+		return nil, &errors.ValidationError{Message: errors.InvalidOrderStatus}
+	}
+
+	// Business rule: orders move along the transition table only
+	if !o.Status.CanTransitionTo(next) {
+		return nil, &errors.BusinessRuleError{Message: errors.OrderStatusTransitionNotAllowed}
+	}
+
+	change := &OrderStatusChange{OrderID: o.ID, From: o.Status, To: next, Actor: actor, CreatedAt: at}
+	o.Status = next
+	return change, nil
+}
+
+// ProductIDs returns the distinct ordered products in ascending order,
+// the order their rows are locked in to avoid deadlocks between checkouts
+// Items of purged products are left out
+func (o *Order) ProductIDs() []int {
+	ids := []int{}
+	for _, item := range o.Items {
+		if item.ProductID != 0 {
+			ids = append(ids, item.ProductID)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// OptionIDs returns the distinct ordered options in ascending order
+func (o *Order) OptionIDs() []int {
+	ids := []int{}
+	for _, item := range o.Items {
+		for _, option := range item.Options {
+			ids = append(ids, option.OptionID)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// Reserve takes the ordered units from the locked stock levels and returns the
+// sale movements of every product. Lines are served in order; every line the
+// remaining stock cannot cover is reported and nothing is taken
+func (o *Order) Reserve(levels *StockLevels) ([]*StockMovement, error) {
+	products := maps.Clone(levels.Products)
+	options := maps.Clone(levels.Options)
+	shortage := &errors.BusinessRuleError{Message: errors.InsufficientStock}
+
+	for i, item := range o.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		if products[item.ProductID] < item.Quantity {
+			shortage.Fields = append(shortage.Fields, errors.FieldError{Field: field, Message: errors.InsufficientStock})
+			continue
+		}
+
+		available := true
+		for _, option := range item.Options {
+			// Options without a level do not track their stock
+			if stock, tracked := options[option.OptionID]; tracked && stock < item.Quantity {
+				available = false
+			}
+		}
+		if !available {
+			shortage.Fields = append(shortage.Fields, errors.FieldError{Field: field, Message: errors.OptionOutOfStock})
+			continue
+		}
+
+		products[item.ProductID] -= item.Quantity
+		for _, option := range item.Options {
+			if _, tracked := options[option.OptionID]; tracked {
+				options[option.OptionID] -= item.Quantity
+			}
+		}
+	}
+	if len(shortage.Fields) > 0 {
+		return nil, shortage
+	}
+
+	movements := []*StockMovement{}
+	for _, productID := range o.ProductIDs() {
+		movements = append(movements, &StockMovement{
+			ProductID:  productID,
+			Reason:     StockMovementSale,
+			Quantity:   products[productID] - levels.Products[productID],
+			StockAfter: products[productID],
+			Reference:  fmt.Sprintf("order:%d", o.ID),
+		})
+	}
+	levels.Products, levels.Options = products, options
+	return movements, nil
+}
+
+// Restock puts the ordered units back in the locked stock levels and returns the
+// return movements of every product. Products or options missing from the levels
+// (purged, or options that do not track their stock) are skipped
+func (o *Order) Restock(levels *StockLevels, actor string) []*StockMovement {
+	before := maps.Clone(levels.Products)
+	for _, item := range o.Items {
+		if _, locked := levels.Products[item.ProductID]; !locked {
+			continue
+		}
+		levels.Products[item.ProductID] += item.Quantity
+		for _, option := range item.Options {
+			if _, tracked := levels.Options[option.OptionID]; tracked {
+				levels.Options[option.OptionID] += item.Quantity
+			}
+		}
+	}
+
+	movements := []*StockMovement{}
+	for _, productID := range o.ProductIDs() {
+		stock, locked := levels.Products[productID]
+		if !locked {
+			continue
+		}
+		movements = append(movements, &StockMovement{
+			ProductID:  productID,
+			Reason:     StockMovementReturn,
+			Quantity:   stock - before[productID],
+			StockAfter: stock,
+			Actor:      actor,
+			Reference:  fmt.Sprintf("order:%d", o.ID),
+		})
+	}
+	return movements
+}
+
+// ToOrderLine returns the line an order is placed with
+func (l *CartLine) ToOrderLine() OrderLine {
+	return OrderLine{ProductID: l.ProductID, Selections: l.Selections, Quantity: l.Quantity}
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func TestOrder_AddItem(t *testing.T) {
+	at := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	t.Run("when the line can be bought then keeps a snapshot of names and prices", func(t *testing.T) {
+		// Arrange
+		order := NewOrder(1, 7, "", at)
+		product := quotedBurger()
+		line := OrderLine{ProductID: 1, Quantity: 2, Selections: []VariantSelection{{VariantID: 1, OptionIDs: []int{2}}, {VariantID: 2, OptionIDs: []int{4}}}}
+
+		// Act
+		err := order.AddItem(product, line, at)
+		product.Name = "Renamed Burger"
+		product.Price = MoneyFromFloat(99)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, order.Items, 1)
+		item := order.Items[0]
+		assert.Equal(t, "Classic Burger", item.ProductName)
+		assert.Equal(t, "10.00", item.BasePrice.String())
+		assert.Equal(t, "14.00", item.UnitPrice.String())
+		assert.Equal(t, "28.00", item.Total.String())
+		assert.Equal(t, []OrderItemOption{
+			{VariantID: 1, OptionID: 2, Name: "Size: Double", Price: MoneyFromFloat(3)},
+			{VariantID: 2, OptionID: 4, Name: "Extras: Cheese", Price: MoneyFromFloat(1)},
+		}, item.Options)
+		assert.Equal(t, "28.00", order.Total.String())
+		assert.Equal(t, 2, order.ItemCount)
+	})
+
+	t.Run("when items are added then sums them", func(t *testing.T) {
+		order := NewOrder(1, 0, "", at)
+		simple := []VariantSelection{{VariantID: 1, OptionIDs: []int{1}}}
+
+		assert.NoError(t, order.AddItem(quotedBurger(), OrderLine{ProductID: 1, Quantity: 1, Selections: simple}, at))
+		assert.NoError(t, order.AddItem(quotedBurger(), OrderLine{ProductID: 1, Quantity: 3, Selections: simple}, at))
+
+		assert.Equal(t, "40.00", order.Subtotal.String())
+		assert.Equal(t, 4, order.ItemCount)
+	})
+
+	t.Run("when the stock is not enough then returns the quote error", func(t *testing.T) {
+		order := NewOrder(1, 0, "", at)
+		line := OrderLine{ProductID: 1, Quantity: 11, Selections: []VariantSelection{{VariantID: 1, OptionIDs: []int{1}}}}
+
+		err := order.AddItem(quotedBurger(), line, at)
+
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.InsufficientStock}, err)
+		assert.Empty(t, order.Items)
+	})
+}
+
+func TestOrder_Validate(t *testing.T) {
+	order := NewOrder(1, 0, "", time.Now())
+
+	assert.Equal(t, &errors.ValidationError{Message: errors.OrderRequiresItems}, order.Validate())
+}
+
+func TestOrder_Reserve(t *testing.T) {
+	at := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	double := []VariantSelection{{VariantID: 1, OptionIDs: []int{2}}}
+	orderOf := func(quantities ...int) *Order {
+		order := NewOrder(1, 0, "", at)
+		order.ID = 9
+		for _, quantity := range quantities {
+			_ = order.AddItem(quotedBurger(), OrderLine{ProductID: 1, Quantity: quantity, Selections: double}, at)
+		}
+		return order
+	}
+
+	t.Run("when the stock covers every line then returns the sale of each product", func(t *testing.T) {
+		// Arrange
+		order := orderOf(2, 3)
+		levels := &StockLevels{Products: map[int]int{1: 10}, Options: map[int]int{2: 6}}
+
+		// Act
+		movements, err := order.Reserve(levels)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []*StockMovement{{ProductID: 1, Reason: StockMovementSale, Quantity: -5, StockAfter: 5, Reference: "order:9"}}, movements)
+		assert.Equal(t, map[int]int{1: 5}, levels.Products)
+		assert.Equal(t, map[int]int{2: 1}, levels.Options)
+	})
+
+	t.Run("when the locked stock is short then reports every line it cannot cover", func(t *testing.T) {
+		// Arrange
+		order := orderOf(2, 3, 1)
+		levels := &StockLevels{Products: map[int]int{1: 4}, Options: map[int]int{}}
+
+		// Act
+		_, err := order.Reserve(levels)
+
+		// Assert
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.InsufficientStock, Fields: []errors.FieldError{
+			{Field: "items[1]", Message: errors.InsufficientStock},
+		}}, err)
+		assert.Equal(t, map[int]int{1: 4}, levels.Products)
+	})
+
+	t.Run("when a tracked option is short then reports the line", func(t *testing.T) {
+		order := orderOf(2)
+		levels := &StockLevels{Products: map[int]int{1: 10}, Options: map[int]int{2: 1}}
+
+		_, err := order.Reserve(levels)
+
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.InsufficientStock, Fields: []errors.FieldError{
+			{Field: "items[0]", Message: errors.OptionOutOfStock},
+		}}, err)
+	})
+}
+
+func TestOrder_TransitionTo(t *testing.T) {
+	at := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	t.Run("when the transition is in the table then moves the order and returns the change", func(t *testing.T) {
+		// Arrange
+		order := NewOrder(1, 0, "", at)
+		order.ID = 9
+
+		// Act
+		change, err := order.TransitionTo(OrderConfirmed, "staff:ana", at)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &OrderStatusChange{OrderID: 9, From: OrderPending, To: OrderConfirmed, Actor: "staff:ana", CreatedAt: at}, change)
+		assert.Equal(t, OrderConfirmed, order.Status)
+	})
+
+	tests := []struct {
+		name string
+		from OrderStatus
+		to   OrderStatus
+		err  error
+	}{
+		{"pending orders cannot skip to delivered", OrderPending, OrderDelivered, &errors.BusinessRuleError{Message: errors.OrderStatusTransitionNotAllowed}},
+		{"orders out for delivery cannot be cancelled", OrderOutForDelivery, OrderCancelled, &errors.BusinessRuleError{Message: errors.OrderStatusTransitionNotAllowed}},
+		{"pending orders cannot be refunded", OrderPending, OrderRefunded, &errors.BusinessRuleError{Message: errors.OrderStatusTransitionNotAllowed}},
+		{"cancelled orders are final", OrderCancelled, OrderPending, &errors.BusinessRuleError{Message: errors.OrderStatusTransitionNotAllowed}},
+		{"unknown statuses are rejected", OrderPending, OrderStatus("shipped"), &errors.ValidationError{Message: errors.InvalidOrderStatus}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{Status: tt.from}
+
+			_, err := order.TransitionTo(tt.to, "", at)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.from, order.Status)
+		})
+	}
+}
+
+func TestOrder_Restock(t *testing.T) {
+	at := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	double := []VariantSelection{{VariantID: 1, OptionIDs: []int{2}}}
+
+	t.Run("when cancelled then returns the units of every locked product and tracked option", func(t *testing.T) {
+		// Arrange
+		order := NewOrder(1, 0, "", at)
+		order.ID = 9
+		_ = order.AddItem(quotedBurger(), OrderLine{ProductID: 1, Quantity: 2, Selections: double}, at)
+		_ = order.AddItem(quotedBurger(), OrderLine{ProductID: 1, Quantity: 1, Selections: []VariantSelection{{VariantID: 1, OptionIDs: []int{1}}}}, at)
+		levels := &StockLevels{Products: map[int]int{1: 4}, Options: map[int]int{2: 0}}
+
+		// Act
+		movements := order.Restock(levels, "staff:ana")
+
+		// Assert
+		assert.Equal(t, []*StockMovement{{ProductID: 1, Reason: StockMovementReturn, Quantity: 3, StockAfter: 7, Actor: "staff:ana", Reference: "order:9"}}, movements)
+		assert.Equal(t, map[int]int{1: 7}, levels.Products)
+		assert.Equal(t, map[int]int{2: 2}, levels.Options)
+	})
+
+	t.Run("when the product was purged then skips its items", func(t *testing.T) {
+		order := &Order{ID: 9, Items: []*OrderItem{{ProductName: "Classic Burger", Quantity: 2}}}
+		levels := &StockLevels{Products: map[int]int{}, Options: map[int]int{}}
+
+		movements := order.Restock(levels, "")
+
+		assert.Empty(t, movements)
+	})
+}
+
+func TestOrderDraft_Fingerprint(t *testing.T) {
+	draft := OrderDraft{CustomerID: 7, Lines: []OrderLine{{ProductID: 1, Quantity: 2}}, IdempotencyKey: "a"}
+	retry := draft
+	retry.IdempotencyKey = "b"
+	other := draft
+	other.Lines = []OrderLine{{ProductID: 1, Quantity: 3}}
+
+	assert.Equal(t, draft.Fingerprint(), retry.Fingerprint())
+	assert.NotEqual(t, draft.Fingerprint(), other.Fingerprint())
+}
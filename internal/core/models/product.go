@@ -1,21 +1,37 @@
 package models
 
-import "github.com/mlgaray/ecommerce_api/internal/core/errors"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
 
 type Product struct {
 	ID               int            `json:"id,omitempty"`
 	Name             string         `json:"name,omitempty"`
 	Description      string         `json:"description,omitempty"`
-	Price            float64        `json:"price,omitempty"`
+	Price            Money          `json:"price,omitzero"`
 	Images           []ProductImage `json:"images,omitempty"`
 	Category         *Category      `json:"category,omitempty"`
 	Variants         []*Variant     `json:"variants"`
 	IsActive         bool           `json:"is_active"`
 	IsPromotional    bool           `json:"is_promotional"`
-	PromotionalPrice float64        `json:"promotional_price,omitempty"`
+	PromotionalPrice Money          `json:"promotional_price,omitzero"`
 	IsHighlighted    bool           `json:"is_highlighted"`
 	Stock            int            `json:"stock"`
 	MinimumStock     int            `json:"minimum_stock,omitempty"`
+	CreatedAt        time.Time      `json:"created_at,omitzero"`
+	Version          int            `json:"version,omitempty"`
+	Promotions       []*Promotion   `json:"promotions,omitempty"`
+	// ActivePrice and PromotionEndsAt are computed by ApplyPricing for responses
+	ActivePrice     Money      `json:"active_price,omitzero"`
+	PromotionEndsAt *time.Time `json:"promotion_ends_at,omitempty"`
+	// PrimaryImage is computed by ApplyPrimaryImage for list responses
+	PrimaryImage *ProductImage `json:"primary_image,omitempty"`
+	// TimeZone of the shop, used to evaluate promotion schedules
+	TimeZone string `json:"-"`
 }
 
 // GetID implements Identifiable interface for pagination
@@ -37,13 +53,25 @@ func (p *Product) Validate() error {
 		return err
 	}
 
+	if err := p.validatePromotions(); err != nil {
+		return err
+	}
+
+	if err := p.validateVariants(); err != nil {
+		return err
+	}
+
+	if err := p.validateImages(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // validatePriceAndStock validates basic price and stock business rules
 func (p *Product) validatePriceAndStock() error {
 	// Business rule: price must be positive
-	if p.Price <= 0 {
+	if !p.Price.IsPositive() {
 		return &errors.ValidationError{
 			Message: errors.ProductPriceMustBePositive,
 		}
@@ -88,14 +116,14 @@ func (p *Product) validateMinimumStock() error {
 // validatePromotionalPrice validates promotional price business rules
 func (p *Product) validatePromotionalPrice() error {
 	// Business rule: if promotional, must have promotional price
-	if p.IsPromotional && p.PromotionalPrice <= 0 {
+	if p.IsPromotional && !p.PromotionalPrice.IsPositive() {
 		return &errors.ValidationError{
 			Message: errors.PromotionalProductRequiresPromotionalPrice,
 		}
 	}
 
 	// Business rule: promotional price must be lower than regular price
-	if p.IsPromotional && p.PromotionalPrice >= p.Price {
+	if p.IsPromotional && p.PromotionalPrice.Cmp(p.Price) >= 0 {
 		return &errors.ValidationError{
 			Message: errors.PromotionalPriceMustBeLowerThanRegularPrice,
 		}
@@ -104,6 +132,48 @@ func (p *Product) validatePromotionalPrice() error {
 	return nil
 }
 
+// validatePromotions validates each scheduled promotion and rejects overlapping ones
+func (p *Product) validatePromotions() error {
+	for i, promotion := range p.Promotions {
+		if err := promotion.Validate(p.Price); err != nil {
+			return err
+		}
+
+		// Business rule: only one scheduled promotion can apply at a time
+		for _, other := range p.Promotions[i+1:] {
+			if promotion.Overlaps(other) {
+				return &errors.ValidationError{Message: errors.PromotionsCannotOverlap}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateVariants validates every variant, option and variant rule
+// All the invalid fields are reported together, each one with its path
+func (p *Product) validateVariants() error {
+	result := &errors.FieldValidationError{Message: errors.InvalidVariants}
+
+	names := make(map[string]bool, len(p.Variants))
+	for i, variant := range p.Variants {
+		path := fmt.Sprintf("variants[%d]", i)
+		variant.validate(path, result)
+
+		// Business rule: variant names identify a single variant of the product
+		name := strings.ToLower(strings.TrimSpace(variant.Name))
+		if name != "" && names[name] {
+			result.Add(path+".name", errors.VariantNamesMustBeUnique)
+		}
+		names[name] = true
+	}
+
+	if result.HasErrors() {
+		return result
+	}
+	return nil
+}
+
 // CanBeSold checks if the product can be sold (business logic)
 func (p *Product) CanBeSold() bool {
 	return p.IsActive && p.Stock > 0
@@ -114,14 +184,44 @@ func (p *Product) IsLowStock() bool {
 	return p.Stock <= p.MinimumStock
 }
 
-// GetEffectivePrice returns the current price considering promotions (business logic)
-func (p *Product) GetEffectivePrice() float64 {
+// ActivePromotion returns the scheduled promotion applying at the instant and when it ends
+func (p *Product) ActivePromotion(at time.Time) (*Promotion, *time.Time) {
+	location := LoadTimeZone(p.TimeZone)
+	for _, promotion := range p.Promotions {
+		if active, endsAt := promotion.ActiveAt(at, location); active {
+			return promotion, endsAt
+		}
+	}
+	return nil, nil
+}
+
+// GetEffectivePrice returns the price at the instant considering promotions (business logic)
+// A scheduled promotion wins over the manual promotional price
+func (p *Product) GetEffectivePrice(at time.Time) Money {
+	if promotion, _ := p.ActivePromotion(at); promotion != nil {
+		return promotion.Price
+	}
 	if p.IsPromotional {
 		return p.PromotionalPrice
 	}
 	return p.Price
 }
 
+// ApplyPricing sets the price active at the instant and when its promotion ends
+func (p *Product) ApplyPricing(at time.Time) {
+	p.ActivePrice = p.GetEffectivePrice(at)
+	_, p.PromotionEndsAt = p.ActivePromotion(at)
+}
+
+// ApplyAvailability flags the options customers cannot pick right now
+func (p *Product) ApplyAvailability() {
+	for _, variant := range p.Variants {
+		for _, option := range variant.Options {
+			option.SoldOut = !option.IsSelectable()
+		}
+	}
+}
+
 // DecrementStock reduces stock by given quantity (business logic with validation)
 func (p *Product) DecrementStock(quantity int) error {
 	if quantity <= 0 {
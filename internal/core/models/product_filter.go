@@ -0,0 +1,166 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+type ProductSortField string
+
+const (
+	ProductSortByDefault   ProductSortField = ""           // Newest first by product ID
+	ProductSortByPrice     ProductSortField = "price"      // Regular price
+	ProductSortByName      ProductSortField = "name"       // Alphabetical by name
+	ProductSortByCreatedAt ProductSortField = "created_at" // Creation timestamp
+)
+
+type SortDirection string
+
+const (
+	SortAscending  SortDirection = "asc"
+	SortDescending SortDirection = "desc"
+)
+
+// ProductFilter holds the optional criteria used to list products of a shop
+// Nil pointer fields mean "do not filter by this attribute"
+type ProductFilter struct {
+	CategoryID    *int
+	IsActive      *bool
+	IsPromotional *bool
+	IsHighlighted *bool
+	InStock       *bool
+	IsLowStock    *bool
+	MinPrice      *Money
+	MaxPrice      *Money
+	SortBy        ProductSortField
+	SortDirection SortDirection
+	Limit         int
+	Cursor        *ProductCursor
+}
+
+// ProductCursor identifies the position of the last product of a page
+// It stores the sort key together with the product ID so keyset pagination
+// stays stable when several products share the same sort value
+type ProductCursor struct {
+	SortBy        ProductSortField `json:"s,omitempty"`
+	SortDirection SortDirection    `json:"d"`
+	Value         string           `json:"v,omitempty"`
+	ID            int              `json:"id"`
+}
+
+// Validate validates business rules for the ProductFilter
+func (f *ProductFilter) Validate() error {
+	if !isValidProductSortField(f.SortBy) {
+		return &errors.ValidationError{Message: errors.InvalidProductSortField}
+	}
+
+	if f.SortDirection != SortAscending && f.SortDirection != SortDescending {
+		return &errors.ValidationError{Message: errors.InvalidSortDirection}
+	}
+
+	if (f.MinPrice != nil && f.MinPrice.IsNegative()) || (f.MaxPrice != nil && f.MaxPrice.IsNegative()) {
+		return &errors.ValidationError{Message: errors.PriceFilterCannotBeNegative}
+	}
+
+	if f.MinPrice != nil && f.MaxPrice != nil && f.MinPrice.Cmp(*f.MaxPrice) > 0 {
+		return &errors.ValidationError{Message: errors.MinPriceCannotBeGreaterThanMaxPrice}
+	}
+
+	// Business rule: a cursor is only meaningful for the ordering that produced it
+	if f.Cursor != nil && (f.Cursor.SortBy != f.SortBy || f.Cursor.SortDirection != f.SortDirection) {
+		return &errors.ValidationError{Message: errors.InvalidCursorFormat}
+	}
+
+	return nil
+}
+
+// DefaultSortDirection returns the natural direction for a sort field
+// Newest first for dates and IDs, ascending for prices and names
+func DefaultSortDirection(sortBy ProductSortField) SortDirection {
+	if sortBy == ProductSortByDefault || sortBy == ProductSortByCreatedAt {
+		return SortDescending
+	}
+	return SortAscending
+}
+
+func isValidProductSortField(sortBy ProductSortField) bool {
+	switch sortBy {
+	case ProductSortByDefault, ProductSortByPrice, ProductSortByName, ProductSortByCreatedAt:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewProductCursor builds the cursor pointing right after the given product
+func NewProductCursor(filter *ProductFilter, product *Product) *ProductCursor {
+	cursor := &ProductCursor{
+		SortBy:        filter.SortBy,
+		SortDirection: filter.SortDirection,
+		ID:            product.ID,
+	}
+
+	switch filter.SortBy {
+	case ProductSortByPrice:
+		cursor.Value = product.Price.String()
+	case ProductSortByName:
+		cursor.Value = product.Name
+	case ProductSortByCreatedAt:
+		cursor.Value = product.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	return cursor
+}
+
+// Encode serializes the cursor into an opaque URL-safe token
+func (c *ProductCursor) Encode() string {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// PriceValue returns the cursor sort key as a price
+func (c *ProductCursor) PriceValue() (Money, error) {
+	return ParseMoney(c.Value)
+}
+
+// TimeValue returns the cursor sort key as a timestamp
+func (c *ProductCursor) TimeValue() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, c.Value)
+}
+
+// DecodeProductCursor parses a token produced by ProductCursor.Encode
+func DecodeProductCursor(token string) (*ProductCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, &errors.ValidationError{Message: errors.InvalidCursorFormat}
+	}
+
+	var cursor ProductCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, &errors.ValidationError{Message: errors.InvalidCursorFormat}
+	}
+
+	if cursor.ID <= 0 || !isValidProductSortField(cursor.SortBy) {
+		return nil, &errors.ValidationError{Message: errors.InvalidCursorFormat}
+	}
+
+	// The sort key must be parseable for the ordering it belongs to
+	switch cursor.SortBy {
+	case ProductSortByPrice:
+		if _, err := cursor.PriceValue(); err != nil {
+			return nil, &errors.ValidationError{Message: errors.InvalidCursorFormat}
+		}
+	case ProductSortByCreatedAt:
+		if _, err := cursor.TimeValue(); err != nil {
+			return nil, &errors.ValidationError{Message: errors.InvalidCursorFormat}
+		}
+	}
+
+	return &cursor, nil
+}
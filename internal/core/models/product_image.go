@@ -1,6 +1,109 @@
 package models
 
+import (
+	"sort"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
 type ProductImage struct {
 	ID  int    `json:"id,omitempty"`
-	URL string `json:"url"`
+	URL string `json:"url"` // the original rendition
+	// Renditions maps each rendition name (e.g. thumb, medium, original) to its URL
+	Renditions map[string]string `json:"renditions,omitempty"`
+	// UploadID references a finalized direct upload to attach in place of a multipart file
+	UploadID string `json:"upload_id,omitempty"`
+	// Position orders the images of a product from 0; IsPrimary marks the one shown on cards
+	Position  int  `json:"position"`
+	IsPrimary bool `json:"is_primary"`
+}
+
+// ImageOrder is a new order for the stored images of a product
+// PrimaryImageID defaults to the first image when zero
+type ImageOrder struct {
+	ImageIDs       []int
+	PrimaryImageID int
+}
+
+// Validate validates business rules for the image order
+func (o *ImageOrder) Validate() error {
+	listed := make(map[int]bool, len(o.ImageIDs))
+	for _, id := range o.ImageIDs {
+		if listed[id] {
+			return &errors.ValidationError{Message: errors.ImageOrderRepeatsImage}
+		}
+		listed[id] = true
+	}
+
+	// Business rule: the primary image is one of the ordered images
+	if o.PrimaryImageID != 0 && !listed[o.PrimaryImageID] {
+		return &errors.ValidationError{Message: errors.PrimaryImageMustBeOrdered}
+	}
+	return nil
+}
+
+// Images returns the ordered images with their position and primary flag
+func (o *ImageOrder) Images() []ProductImage {
+	primaryID := o.PrimaryImageID
+	if primaryID == 0 && len(o.ImageIDs) > 0 {
+		primaryID = o.ImageIDs[0]
+	}
+
+	images := make([]ProductImage, len(o.ImageIDs))
+	for i, id := range o.ImageIDs {
+		images[i] = ProductImage{ID: id, Position: i, IsPrimary: id == primaryID}
+	}
+	return images
+}
+
+// validateImages validates the image business rules of the product
+func (p *Product) validateImages() error {
+	// Business rule: a single image is shown first on product cards
+	primary := 0
+	for _, image := range p.Images {
+		if image.IsPrimary {
+			primary++
+		}
+	}
+	if primary > 1 {
+		return &errors.ValidationError{Message: errors.OnlyOnePrimaryImage}
+	}
+	return nil
+}
+
+// ArrangeImages sorts the images by position, adds the appended ones after them and
+// numbers the positions from 0. The first image becomes primary when none was chosen
+// Images without a position keep the order they were sent in
+func (p *Product) ArrangeImages(appended []ProductImage) {
+	sort.SliceStable(p.Images, func(i, j int) bool {
+		return p.Images[i].Position < p.Images[j].Position
+	})
+	p.Images = append(p.Images, appended...)
+
+	hasPrimary := false
+	for i := range p.Images {
+		p.Images[i].Position = i
+		hasPrimary = hasPrimary || p.Images[i].IsPrimary
+	}
+	if !hasPrimary && len(p.Images) > 0 {
+		p.Images[0].IsPrimary = true
+	}
+}
+
+// ApplyPrimaryImage sets the image shown on product cards: the primary one, or the
+// first one for images stored without a primary flag
+func (p *Product) ApplyPrimaryImage() {
+	p.PrimaryImage = nil
+	if len(p.Images) == 0 {
+		return
+	}
+
+	primary := p.Images[0]
+	for _, image := range p.Images {
+		if image.IsPrimary {
+			primary = image
+			break
+		}
+	}
+	p.PrimaryImage = &primary
 }
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func TestProduct_ArrangeImages(t *testing.T) {
+	t.Run("when images have positions then sorts them and appends the streamed ones", func(t *testing.T) {
+		// Arrange
+		product := &Product{Images: []ProductImage{
+			{ID: 1, Position: 2},
+			{ID: 2, Position: 0, IsPrimary: true},
+			{ID: 3, Position: 1},
+		}}
+
+		// Act
+		product.ArrangeImages([]ProductImage{{URL: "/assets/new.jpg"}})
+
+		// Assert
+		assert.Equal(t, []ProductImage{
+			{ID: 2, Position: 0, IsPrimary: true},
+			{ID: 3, Position: 1},
+			{ID: 1, Position: 2},
+			{URL: "/assets/new.jpg", Position: 3},
+		}, product.Images)
+	})
+
+	t.Run("when no image is primary then the first one becomes primary", func(t *testing.T) {
+		// Arrange
+		product := &Product{Images: []ProductImage{{ID: 1}, {ID: 2}}}
+
+		// Act
+		product.ArrangeImages(nil)
+
+		// Assert
+		assert.Equal(t, []ProductImage{{ID: 1, Position: 0, IsPrimary: true}, {ID: 2, Position: 1}}, product.Images)
+	})
+}
+
+func TestProduct_ApplyPrimaryImage(t *testing.T) {
+	t.Run("when an image is primary then it is the primary image", func(t *testing.T) {
+		product := &Product{Images: []ProductImage{{ID: 1}, {ID: 2, IsPrimary: true}}}
+		product.ApplyPrimaryImage()
+		assert.Equal(t, &ProductImage{ID: 2, IsPrimary: true}, product.PrimaryImage)
+	})
+
+	t.Run("when no image is primary then the first one is used", func(t *testing.T) {
+		product := &Product{Images: []ProductImage{{ID: 1}, {ID: 2}}}
+		product.ApplyPrimaryImage()
+		assert.Equal(t, &ProductImage{ID: 1}, product.PrimaryImage)
+	})
+
+	t.Run("when there are no images then there is no primary image", func(t *testing.T) {
+		product := &Product{}
+		product.ApplyPrimaryImage()
+		assert.Nil(t, product.PrimaryImage)
+	})
+}
+
+func TestImageOrder_Validate(t *testing.T) {
+	t.Run("when an image is repeated then returns validation error", func(t *testing.T) {
+		order := &ImageOrder{ImageIDs: []int{1, 2, 1}}
+		assert.Equal(t, &errors.ValidationError{Message: errors.ImageOrderRepeatsImage}, order.Validate())
+	})
+
+	t.Run("when the primary image is not ordered then returns validation error", func(t *testing.T) {
+		order := &ImageOrder{ImageIDs: []int{1, 2}, PrimaryImageID: 3}
+		assert.Equal(t, &errors.ValidationError{Message: errors.PrimaryImageMustBeOrdered}, order.Validate())
+	})
+}
+
+func TestImageOrder_Images(t *testing.T) {
+	t.Run("when no primary image is chosen then the first one is primary", func(t *testing.T) {
+		order := &ImageOrder{ImageIDs: []int{3, 1}}
+		assert.Equal(t, []ProductImage{{ID: 3, Position: 0, IsPrimary: true}, {ID: 1, Position: 1}}, order.Images())
+	})
+
+	t.Run("when a primary image is chosen then only it is primary", func(t *testing.T) {
+		order := &ImageOrder{ImageIDs: []int{3, 1}, PrimaryImageID: 1}
+		assert.Equal(t, []ProductImage{{ID: 3, Position: 0}, {ID: 1, Position: 1, IsPrimary: true}}, order.Images())
+	})
+}
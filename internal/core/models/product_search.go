@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+type SearchLanguage string
+
+const (
+	SearchLanguageShopDefault SearchLanguage = ""   // Use the language configured in the shop settings
+	SearchLanguageSpanish     SearchLanguage = "es" // Spanish stemming
+	SearchLanguageEnglish     SearchLanguage = "en" // English stemming
+)
+
+const (
+	searchQueryMaxLength = 100
+	searchQueryMaxTerms  = 8
+)
+
+// ProductSearch holds a full-text search over the products of a shop
+type ProductSearch struct {
+	Query    string
+	Language SearchLanguage
+	Limit    int
+	Cursor   *ProductSearchCursor
+}
+
+// ProductSearchCursor identifies the last result of a page by relevance and ID
+type ProductSearchCursor struct {
+	Rank float64 `json:"r"`
+	ID   int     `json:"id"`
+}
+
+// ProductSearchResult is a product matched by a search with its relevance
+type ProductSearchResult struct {
+	Product              *Product `json:"product"`
+	Rank                 float64  `json:"rank"`
+	NameHighlight        string   `json:"name_highlight,omitempty"`
+	DescriptionHighlight string   `json:"description_highlight,omitempty"`
+}
+
+// GetID implements Identifiable interface for pagination
+func (r *ProductSearchResult) GetID() int {
+	return r.Product.ID
+}
+
+// Validate validates business rules for the ProductSearch
+func (s *ProductSearch) Validate() error {
+	if len(s.Terms()) == 0 {
+		return &errors.ValidationError{Message: errors.SearchQueryRequired}
+	}
+
+	if len([]rune(s.Query)) > searchQueryMaxLength {
+		return &errors.ValidationError{Message: errors.SearchQueryTooLong}
+	}
+
+	switch s.Language {
+	case SearchLanguageShopDefault, SearchLanguageSpanish, SearchLanguageEnglish:
+	default:
+		return &errors.ValidationError{Message: errors.InvalidSearchLanguage}
+	}
+
+	return nil
+}
+
+// Terms splits the query into lowercase words, dropping punctuation and operators
+// Only the first searchQueryMaxTerms words are kept
+func (s *ProductSearch) Terms() []string {
+	terms := strings.FieldsFunc(strings.ToLower(s.Query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(terms) > searchQueryMaxTerms {
+		terms = terms[:searchQueryMaxTerms]
+	}
+	return terms
+}
+
+// Encode serializes the cursor into an opaque URL-safe token
+func (c *ProductSearchCursor) Encode() string {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeProductSearchCursor parses a token produced by ProductSearchCursor.Encode
+func DecodeProductSearchCursor(token string) (*ProductSearchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, &errors.ValidationError{Message: errors.InvalidCursorFormat}
+	}
+
+	var cursor ProductSearchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 || cursor.Rank < 0 {
+		return nil, &errors.ValidationError{Message: errors.InvalidCursorFormat}
+	}
+
+	return &cursor, nil
+}
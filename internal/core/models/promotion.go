@@ -0,0 +1,166 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+// Promotion is a promotional price scheduled in the shop time zone
+// Without recurrence it applies during the whole [StartsAt, EndsAt) window,
+// with recurrence only on the given weekdays between StartTime and EndTime
+type Promotion struct {
+	ID         int                  `json:"id,omitempty"`
+	Price      Money                `json:"price,omitzero"`
+	StartsAt   LocalDateTime        `json:"starts_at,omitzero"`
+	EndsAt     *LocalDateTime       `json:"ends_at,omitempty"` // nil means no scheduled end
+	Recurrence *PromotionRecurrence `json:"recurrence,omitempty"`
+}
+
+// PromotionRecurrence repeats a promotion every week (e.g. happy hour on weekdays 18-20h)
+type PromotionRecurrence struct {
+	Weekdays  []time.Weekday `json:"weekdays"` // 0 is Sunday
+	StartTime ClockTime      `json:"start_time"`
+	EndTime   ClockTime      `json:"end_time"`
+}
+
+// Validate validates business rules of a promotion for a product with the given regular price
+func (p *Promotion) Validate(regularPrice Money) error {
+	// Business rule: the schedule must have a start
+	if p.StartsAt.IsZero() {
+		return &errors.ValidationError{Message: errors.PromotionStartsAtRequired}
+	}
+
+	// Business rule: the schedule cannot end before it starts
+	if p.EndsAt != nil && !p.StartsAt.Before(*p.EndsAt) {
+		return &errors.ValidationError{Message: errors.PromotionEndsAtMustBeAfterStartsAt}
+	}
+
+	// Business rule: promotional price must be positive and lower than the regular price
+	if !p.Price.IsPositive() {
+		return &errors.ValidationError{Message: errors.PromotionPriceMustBePositive}
+	}
+	if p.Price.Cmp(regularPrice) >= 0 {
+		return &errors.ValidationError{Message: errors.PromotionalPriceMustBeLowerThanRegularPrice}
+	}
+
+	if p.Recurrence != nil {
+		return p.Recurrence.Validate()
+	}
+
+	return nil
+}
+
+// ValidateSchedule validates the promotion for a product with the given regular price
+// against the promotions already scheduled for that product
+func (p *Promotion) ValidateSchedule(regularPrice Money, scheduled []*Promotion) error {
+	if err := p.Validate(regularPrice); err != nil {
+		return err
+	}
+
+	// Business rule: only one scheduled promotion can apply at a time
+	for _, other := range scheduled {
+		if p.Overlaps(other) {
+			return &errors.ValidationError{Message: errors.PromotionsCannotOverlap}
+		}
+	}
+
+	return nil
+}
+
+// Validate validates the weekly window of a recurring promotion
+func (r *PromotionRecurrence) Validate() error {
+	if len(r.Weekdays) == 0 {
+		return &errors.ValidationError{Message: errors.PromotionRecurrenceRequiresWeekdays}
+	}
+
+	seen := make(map[time.Weekday]bool, len(r.Weekdays))
+	for _, weekday := range r.Weekdays {
+		if weekday < time.Sunday || weekday > time.Saturday || seen[weekday] {
+			return &errors.ValidationError{Message: errors.InvalidPromotionWeekday}
+		}
+		seen[weekday] = true
+	}
+
+	// Windows crossing midnight are expressed as two promotions
+	if r.StartTime >= r.EndTime {
+		return &errors.ValidationError{Message: errors.PromotionEndTimeMustBeAfterStartTime}
+	}
+
+	return nil
+}
+
+// Overlaps reports whether both promotions could apply at the same instant
+// A recurring and a non recurring promotion overlap whenever their date ranges do
+func (p *Promotion) Overlaps(other *Promotion) bool {
+	if !p.dateRangeOverlaps(other) {
+		return false
+	}
+	if p.Recurrence == nil || other.Recurrence == nil {
+		return true
+	}
+	return p.Recurrence.overlaps(other.Recurrence)
+}
+
+// dateRangeOverlaps compares the half-open [StartsAt, EndsAt) ranges
+func (p *Promotion) dateRangeOverlaps(other *Promotion) bool {
+	startsBeforeOtherEnds := other.EndsAt == nil || p.StartsAt.Before(*other.EndsAt)
+	otherStartsBeforeEnd := p.EndsAt == nil || other.StartsAt.Before(*p.EndsAt)
+	return startsBeforeOtherEnds && otherStartsBeforeEnd
+}
+
+func (r *PromotionRecurrence) overlaps(other *PromotionRecurrence) bool {
+	if r.StartTime >= other.EndTime || other.StartTime >= r.EndTime {
+		return false
+	}
+	for _, weekday := range r.Weekdays {
+		if other.includesWeekday(weekday) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *PromotionRecurrence) includesWeekday(weekday time.Weekday) bool {
+	for _, day := range r.Weekdays {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveAt reports whether the promotion applies at the instant and until when
+// The end is nil when the promotion has no scheduled end
+func (p *Promotion) ActiveAt(at time.Time, location *time.Location) (bool, *time.Time) {
+	local := at.In(location)
+	wall := LocalDateTimeOf(local)
+	if wall.Before(p.StartsAt) {
+		return false, nil
+	}
+
+	var endsAt *time.Time
+	if p.EndsAt != nil {
+		if !wall.Before(*p.EndsAt) {
+			return false, nil
+		}
+		end := p.EndsAt.In(location)
+		endsAt = &end
+	}
+
+	if p.Recurrence == nil {
+		return true, endsAt
+	}
+
+	clock := ClockTimeOf(local)
+	if !p.Recurrence.includesWeekday(local.Weekday()) || clock < p.Recurrence.StartTime || clock >= p.Recurrence.EndTime {
+		return false, nil
+	}
+
+	// Today's window closes first unless the whole schedule ends earlier
+	windowEnd := p.Recurrence.EndTime.On(local)
+	if endsAt == nil || windowEnd.Before(*endsAt) {
+		endsAt = &windowEnd
+	}
+	return true, endsAt
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func localDateTime(t *testing.T, value string) *LocalDateTime {
+	t.Helper()
+	parsed, err := ParseLocalDateTime(value)
+	assert.NoError(t, err)
+	return &parsed
+}
+
+func happyHour(t *testing.T) *Promotion {
+	return &Promotion{
+		Price:    MoneyFromFloat(7.5),
+		StartsAt: *localDateTime(t, "2026-01-01"),
+		Recurrence: &PromotionRecurrence{
+			Weekdays:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+			StartTime: 18 * 60,
+			EndTime:   20 * 60,
+		},
+	}
+}
+
+func TestPromotion_ActiveAt(t *testing.T) {
+	location := LoadTimeZone(DefaultTimeZone)
+
+	t.Run("when instant is inside the weekday window then ends with the window", func(t *testing.T) {
+		// Arrange
+		promotion := happyHour(t)
+		at := time.Date(2026, 3, 4, 19, 30, 0, 0, location) // Wednesday
+
+		// Act
+		active, endsAt := promotion.ActiveAt(at, location)
+
+		// Assert
+		assert.True(t, active)
+		assert.Equal(t, time.Date(2026, 3, 4, 20, 0, 0, 0, location), *endsAt)
+	})
+
+	t.Run("when instant is on a weekend then promotion is not active", func(t *testing.T) {
+		active, _ := happyHour(t).ActiveAt(time.Date(2026, 3, 7, 19, 0, 0, 0, location), location)
+		assert.False(t, active)
+	})
+
+	t.Run("when instant is given in UTC then evaluates the shop wall clock", func(t *testing.T) {
+		// 22:30 UTC is 19:30 in Buenos Aires
+		active, _ := happyHour(t).ActiveAt(time.Date(2026, 3, 4, 22, 30, 0, 0, time.UTC), location)
+		assert.True(t, active)
+	})
+
+	t.Run("when window end is exclusive then promotion ends at ends_at", func(t *testing.T) {
+		// Arrange
+		promotion := &Promotion{
+			Price:    MoneyFromFloat(7.5),
+			StartsAt: *localDateTime(t, "2026-11-01"),
+			EndsAt:   localDateTime(t, "2026-11-30"),
+		}
+
+		// Act
+		before, endsAt := promotion.ActiveAt(time.Date(2026, 11, 29, 23, 59, 0, 0, location), location)
+		after, _ := promotion.ActiveAt(time.Date(2026, 11, 30, 0, 0, 0, 0, location), location)
+
+		// Assert
+		assert.True(t, before)
+		assert.Equal(t, time.Date(2026, 11, 30, 0, 0, 0, 0, location), *endsAt)
+		assert.False(t, after)
+	})
+}
+
+func TestPromotion_ValidateSchedule(t *testing.T) {
+	t.Run("when the promotion overlaps a scheduled one then returns validation error", func(t *testing.T) {
+		// Arrange
+		late := happyHour(t)
+		late.Recurrence.StartTime = 19 * 60
+		late.Recurrence.EndTime = 22 * 60
+
+		// Act
+		err := happyHour(t).ValidateSchedule(MoneyFromFloat(10), []*Promotion{late})
+
+		// Assert
+		assert.Equal(t, &errors.ValidationError{Message: errors.PromotionsCannotOverlap}, err)
+	})
+
+	t.Run("when the price is not below the regular price then returns validation error", func(t *testing.T) {
+		// Act
+		err := happyHour(t).ValidateSchedule(MoneyFromFloat(7.5), nil)
+
+		// Assert
+		assert.Equal(t, &errors.ValidationError{Message: errors.PromotionalPriceMustBeLowerThanRegularPrice}, err)
+	})
+}
+
+func TestPromotion_Overlaps(t *testing.T) {
+	t.Run("when recurring windows share no weekday then they do not overlap", func(t *testing.T) {
+		// Arrange
+		weekend := happyHour(t)
+		weekend.Recurrence.Weekdays = []time.Weekday{time.Saturday, time.Sunday}
+
+		// Act & Assert
+		assert.False(t, happyHour(t).Overlaps(weekend))
+	})
+
+	t.Run("when recurring windows share a weekday and hours then they overlap", func(t *testing.T) {
+		// Arrange
+		late := happyHour(t)
+		late.Recurrence.StartTime = 19 * 60
+		late.Recurrence.EndTime = 22 * 60
+
+		// Act & Assert
+		assert.True(t, happyHour(t).Overlaps(late))
+	})
+
+	t.Run("when date ranges only touch then they do not overlap", func(t *testing.T) {
+		// Arrange
+		november := &Promotion{StartsAt: *localDateTime(t, "2026-11-01"), EndsAt: localDateTime(t, "2026-12-01")}
+		december := &Promotion{StartsAt: *localDateTime(t, "2026-12-01")}
+
+		// Act & Assert
+		assert.False(t, november.Overlaps(december))
+		assert.True(t, december.Overlaps(happyHour(t)))
+	})
+}
+
+func TestProduct_GetEffectivePrice(t *testing.T) {
+	location := LoadTimeZone(DefaultTimeZone)
+
+	t.Run("when a scheduled promotion is active then it wins over the manual promotional price", func(t *testing.T) {
+		// Arrange
+		product := &Product{
+			Price:            MoneyFromFloat(10),
+			IsPromotional:    true,
+			PromotionalPrice: MoneyFromFloat(9),
+			Promotions:       []*Promotion{happyHour(t)},
+		}
+
+		// Act & Assert
+		assert.Equal(t, "7.50", product.GetEffectivePrice(time.Date(2026, 3, 4, 19, 0, 0, 0, location)).String())
+		assert.Equal(t, "9.00", product.GetEffectivePrice(time.Date(2026, 3, 4, 21, 0, 0, 0, location)).String())
+	})
+
+	t.Run("when promotions overlap then validation rejects the product", func(t *testing.T) {
+		// Arrange
+		product := &Product{
+			Price:      MoneyFromFloat(10),
+			Promotions: []*Promotion{happyHour(t), happyHour(t)},
+		}
+
+		// Act
+		err := product.Validate()
+
+		// Assert
+		assert.Equal(t, &errors.ValidationError{Message: errors.PromotionsCannotOverlap}, err)
+	})
+}
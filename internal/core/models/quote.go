@@ -0,0 +1,119 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+// VariantSelection holds the options chosen for one variant of a product
+type VariantSelection struct {
+	VariantID int   `json:"variant_id"`
+	OptionIDs []int `json:"option_ids"`
+}
+
+// QuoteLine is one priced element of a quote: the product itself or a chosen option
+type QuoteLine struct {
+	Description string `json:"description"`
+	VariantID   int    `json:"variant_id,omitempty"`
+	OptionID    int    `json:"option_id,omitempty"`
+	UnitPrice   Money  `json:"unit_price"`
+	Quantity    int    `json:"quantity"`
+	Total       Money  `json:"total"`
+}
+
+// Quote is the itemized price of a configured product
+type Quote struct {
+	ProductID     int          `json:"product_id"`
+	Quantity      int          `json:"quantity"`
+	RegularPrice  Money        `json:"regular_price"`
+	IsPromotional bool         `json:"is_promotional"` // the product line uses a promotional price
+	Lines         []*QuoteLine `json:"lines"`
+	UnitPrice     Money        `json:"unit_price"` // one product with its options
+	Total         Money        `json:"total"`
+}
+
+// Quote prices quantity units of the product configured with the selections at the instant
+// Selections are validated against the variant rules; unavailable products or options
+// fail with a business rule error
+func (p *Product) Quote(selections []VariantSelection, quantity int, at time.Time) (*Quote, error) {
+	if quantity <= 0 {
+		return nil, &errors.ValidationError{Message: errors.QuantityMustBePositive}
+	}
+
+	// Business rule: only active products with enough stock can be quoted
+	if !p.CanBeSold() {
+		return nil, &errors.BusinessRuleError{Message: errors.ProductNotAvailable}
+	}
+	if p.Stock < quantity {
+		return nil, &errors.BusinessRuleError{Message: errors.InsufficientStock}
+	}
+
+	chosen, err := p.groupSelections(selections)
+	if err != nil {
+		return nil, err
+	}
+
+	unitPrice := p.GetEffectivePrice(at)
+	quote := &Quote{
+		ProductID:     p.ID,
+		Quantity:      quantity,
+		RegularPrice:  p.Price,
+		IsPromotional: unitPrice.Cmp(p.Price) < 0,
+		Lines: []*QuoteLine{{
+			Description: p.Name,
+			UnitPrice:   unitPrice,
+			Quantity:    quantity,
+			Total:       unitPrice.Multiply(quantity),
+		}},
+	}
+
+	for _, variant := range p.Variants {
+		options, err := variant.SelectOptions(chosen[variant.ID])
+		if err != nil {
+			return nil, err
+		}
+
+		prices := variant.OptionPrices(options)
+		for i, option := range options {
+			if err := option.EnsureAvailable(quantity); err != nil {
+				return nil, err
+			}
+
+			unitPrice = unitPrice.Add(prices[i])
+			quote.Lines = append(quote.Lines, &QuoteLine{
+				Description: variant.Name + ": " + option.Name,
+				VariantID:   variant.ID,
+				OptionID:    option.ID,
+				UnitPrice:   prices[i],
+				Quantity:    quantity,
+				Total:       prices[i].Multiply(quantity),
+			})
+		}
+	}
+
+	quote.UnitPrice = unitPrice
+	quote.Total = unitPrice.Multiply(quantity)
+	return quote, nil
+}
+
+// groupSelections indexes the chosen option IDs by variant, rejecting unknown or repeated variants
+func (p *Product) groupSelections(selections []VariantSelection) (map[int][]int, error) {
+	known := make(map[int]bool, len(p.Variants))
+	for _, variant := range p.Variants {
+		known[variant.ID] = true
+	}
+
+	chosen := make(map[int][]int, len(selections))
+	for _, selection := range selections {
+		if !known[selection.VariantID] {
+			return nil, &errors.ValidationError{Message: errors.VariantNotFound}
+		}
+		if _, repeated := chosen[selection.VariantID]; repeated {
+			return nil, &errors.ValidationError{Message: errors.VariantSelectedMoreThanOnce}
+		}
+		chosen[selection.VariantID] = selection.OptionIDs
+	}
+
+	return chosen, nil
+}
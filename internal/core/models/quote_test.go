@@ -0,0 +1,84 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func quotedBurger() *Product {
+	return &Product{
+		ID:       1,
+		Name:     "Classic Burger",
+		Price:    MoneyFromFloat(10),
+		IsActive: true,
+		Stock:    10,
+		Variants: []*Variant{
+			{ID: 1, Name: "Size", SelectionType: Single, IsRequired: true, Options: []*Option{
+				{ID: 1, Name: "Simple", Price: MoneyFromFloat(0)},
+				{ID: 2, Name: "Double", Price: MoneyFromFloat(3)},
+			}},
+			{ID: 2, Name: "Extras", SelectionType: Multiple, MaxSelections: 2, Options: []*Option{
+				{ID: 3, Name: "Bacon", Price: MoneyFromFloat(1.5)},
+				{ID: 4, Name: "Cheese", Price: MoneyFromFloat(1)},
+				{ID: 5, Name: "Egg", Price: MoneyFromFloat(1)},
+			}},
+		},
+	}
+}
+
+func TestProduct_Quote(t *testing.T) {
+	at := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	t.Run("when the selection is valid then returns an itemized breakdown", func(t *testing.T) {
+		// Arrange
+		product := quotedBurger()
+		product.IsPromotional = true
+		product.PromotionalPrice = MoneyFromFloat(9)
+		selections := []VariantSelection{{VariantID: 1, OptionIDs: []int{2}}, {VariantID: 2, OptionIDs: []int{3, 4}}}
+
+		// Act
+		quote, err := product.Quote(selections, 2, at)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, quote.IsPromotional)
+		assert.Len(t, quote.Lines, 4)
+		assert.Equal(t, "9.00", quote.Lines[0].UnitPrice.String())
+		assert.Equal(t, "14.50", quote.UnitPrice.String())
+		assert.Equal(t, "29.00", quote.Total.String())
+	})
+
+	t.Run("when a required variant is missing then returns a validation error", func(t *testing.T) {
+		_, err := quotedBurger().Quote(nil, 1, at)
+		assert.Equal(t, &errors.ValidationError{Message: errors.VariantSelectionRequired}, err)
+	})
+
+	t.Run("when a single variant gets two options then returns a validation error", func(t *testing.T) {
+		_, err := quotedBurger().Quote([]VariantSelection{{VariantID: 1, OptionIDs: []int{1, 2}}}, 1, at)
+		assert.Equal(t, &errors.ValidationError{Message: errors.SingleSelectionAllowsOneOption}, err)
+	})
+
+	t.Run("when more options than max selections are chosen then returns a validation error", func(t *testing.T) {
+		selections := []VariantSelection{{VariantID: 1, OptionIDs: []int{1}}, {VariantID: 2, OptionIDs: []int{3, 4, 5}}}
+		_, err := quotedBurger().Quote(selections, 1, at)
+		assert.Equal(t, &errors.ValidationError{Message: errors.MaxSelectionsExceeded}, err)
+	})
+
+	t.Run("when a chosen option is out of stock then returns a business rule error", func(t *testing.T) {
+		// Arrange
+		product := quotedBurger()
+		empty := 0
+		product.Variants[1].Options[0].Stock = &empty
+		selections := []VariantSelection{{VariantID: 1, OptionIDs: []int{1}}, {VariantID: 2, OptionIDs: []int{3}}}
+
+		// Act
+		_, err := product.Quote(selections, 1, at)
+
+		// Assert
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.OptionOutOfStock}, err)
+	})
+}
@@ -1,5 +1,9 @@
 package models
 
+// PlatformAdminRole runs maintenance tasks across every shop
+// Signup grants "admin" to every shop owner, so platform tasks need their own role
+const PlatformAdminRole = "platform_admin"
+
 type Role struct {
 	ID          int    `json:"id,omitempty"`
 	Name        string `json:"name,omitempty"`
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// SignedURL lets a client send a request straight to the storage until it expires
+// The request must carry the listed headers, as they are part of the signature
+type SignedURL struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+// StockMovementReason explains why the stock of a product changed
+type StockMovementReason string
+
+const (
+	StockMovementInitial    StockMovementReason = "initial"    // stock the product was created with
+	StockMovementRestock    StockMovementReason = "restock"    // goods received, always positive
+	StockMovementSale       StockMovementReason = "sale"       // goods sold, always negative
+	StockMovementReturn     StockMovementReason = "return"     // goods returned by a customer, always positive
+	StockMovementAdjustment StockMovementReason = "adjustment" // manual correction (counts, damages, product edits)
+)
+
+// StockMovement is an entry of the append-only inventory ledger
+// The sum of the quantities of a product equals its current stock
+type StockMovement struct {
+	ID         int                 `json:"id,omitempty"`
+	ProductID  int                 `json:"product_id"`
+	Reason     StockMovementReason `json:"reason"`
+	Quantity   int                 `json:"quantity"` // signed delta applied to the stock
+	StockAfter int                 `json:"stock_after"`
+	Actor      string              `json:"actor,omitempty"`
+	Reference  string              `json:"reference,omitempty"` // e.g. an order or delivery note number
+	CreatedAt  time.Time           `json:"created_at,omitzero"`
+}
+
+// GetID implements Identifiable interface for pagination
+func (m *StockMovement) GetID() int {
+	return m.ID
+}
+
+// Validate validates business rules of a stock adjustment requested by a user
+func (m *StockMovement) Validate() error {
+	// Business rule: a movement must change the stock
+	if m.Quantity == 0 {
+		return &errors.ValidationError{Message: errors.StockMovementQuantityCannotBeZero}
+	}
+
+	// Business rule: the sign of the quantity follows the reason
+	// Initial movements are only written when a product is created
+	switch m.Reason {
+	case StockMovementRestock, StockMovementReturn:
+		if m.Quantity < 0 {
+			return &errors.ValidationError{Message: errors.StockMovementQuantitySignMismatch}
+		}
+	case StockMovementSale:
+		if m.Quantity > 0 {
+			return &errors.ValidationError{Message: errors.StockMovementQuantitySignMismatch}
+		}
+	case StockMovementAdjustment:
+	default:
+		return &errors.ValidationError{Message: errors.InvalidStockMovementReason}
+	}
+
+	return nil
+}
+
+// ApplyTo changes the product stock by the movement quantity and records the resulting stock
+func (m *StockMovement) ApplyTo(product *Product) error {
+	var err error
+	if m.Quantity > 0 {
+		err = product.IncrementStock(m.Quantity)
+	} else {
+		err = product.DecrementStock(-m.Quantity)
+	}
+	if err != nil {
+		return err
+	}
+
+	m.ProductID = product.ID
+	m.StockAfter = product.Stock
+	return nil
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func TestStockMovement_Validate(t *testing.T) {
+	t.Run("when a sale adds stock then the sign does not match the reason", func(t *testing.T) {
+		// Arrange
+		movement := &StockMovement{Reason: StockMovementSale, Quantity: 2}
+
+		// Act
+		err := movement.Validate()
+
+		// Assert
+		assert.Equal(t, &errors.ValidationError{Message: errors.StockMovementQuantitySignMismatch}, err)
+	})
+
+	t.Run("when an adjustment removes stock then it is valid", func(t *testing.T) {
+		assert.NoError(t, (&StockMovement{Reason: StockMovementAdjustment, Quantity: -2}).Validate())
+	})
+
+	t.Run("when the reason is initial then it cannot be requested", func(t *testing.T) {
+		err := (&StockMovement{Reason: StockMovementInitial, Quantity: 2}).Validate()
+		assert.Equal(t, &errors.ValidationError{Message: errors.InvalidStockMovementReason}, err)
+	})
+}
+
+func TestStockMovement_ApplyTo(t *testing.T) {
+	t.Run("when the stock is enough then records the resulting stock", func(t *testing.T) {
+		// Arrange
+		product := &Product{ID: 3, Stock: 10}
+		movement := &StockMovement{Reason: StockMovementSale, Quantity: -4}
+
+		// Act
+		err := movement.ApplyTo(product)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 6, product.Stock)
+		assert.Equal(t, 6, movement.StockAfter)
+		assert.Equal(t, 3, movement.ProductID)
+	})
+
+	t.Run("when the stock is not enough then returns a business rule error", func(t *testing.T) {
+		err := (&StockMovement{Reason: StockMovementSale, Quantity: -4}).ApplyTo(&Product{Stock: 3})
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.InsufficientStock}, err)
+	})
+}
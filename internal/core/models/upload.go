@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+// UploadStatus is the stage of a direct-to-storage image upload
+type UploadStatus string
+
+const (
+	// UploadAwaitingContent means the signed URL was issued and the file was not finalized yet
+	UploadAwaitingContent UploadStatus = "awaiting_content"
+	// UploadPending means the image was verified and stored, waiting to be attached to a product
+	UploadPending UploadStatus = "pending"
+	// UploadAttached means a product references the image
+	UploadAttached UploadStatus = "attached"
+)
+
+const (
+	// MaxUploadSize matches the limit of the images sent through the product form
+	MaxUploadSize = 3 * 1024 * 1024
+	// UploadURLTTL is how long the signed URL accepts the file
+	UploadURLTTL = 15 * time.Minute
+	// UploadTTL is how long an upload waits for a product before it expires
+	UploadTTL = 24 * time.Hour
+)
+
+// uploadContentTypes are the image types a client can declare for an upload
+var uploadContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// Upload is an image a client sends straight to the storage through a signed URL,
+// registered first and referenced by its ID when the product is created or updated
+type Upload struct {
+	ID          string            `json:"upload_id"`
+	Status      UploadStatus      `json:"status"`
+	ContentType string            `json:"content_type"`
+	Size        int64             `json:"size"`
+	URL         string            `json:"url,omitempty"`
+	Renditions  map[string]string `json:"renditions,omitempty"`
+	ExpiresAt   time.Time         `json:"expires_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// NewUpload registers the upload of a file the client declared
+func NewUpload(id, contentType string, size int64, now time.Time) *Upload {
+	return &Upload{
+		ID:          id,
+		Status:      UploadAwaitingContent,
+		ContentType: contentType,
+		Size:        size,
+		ExpiresAt:   now.Add(UploadTTL),
+		CreatedAt:   now,
+	}
+}
+
+// Validate checks the declared file, which the signed URL then enforces
+func (u *Upload) Validate() error {
+	if !uploadContentTypes[u.ContentType] {
+		return &errors.ValidationError{Message: errors.UnsupportedImageFormat}
+	}
+	if u.Size <= 0 || u.Size > MaxUploadSize {
+		return &errors.ValidationError{Message: errors.UploadSizeOutOfRange}
+	}
+	return nil
+}
+
+// StagingKey is where the client puts the file until it is finalized
+func (u *Upload) StagingKey() string {
+	return "uploads/" + u.ID
+}
+
+func (u *Upload) IsExpired(now time.Time) bool {
+	return !now.Before(u.ExpiresAt)
+}
+
+// CanFinalize checks the file can still be verified and stored
+func (u *Upload) CanFinalize(now time.Time) error {
+	if u.Status != UploadAwaitingContent {
+		return &errors.BusinessRuleError{Message: errors.UploadAlreadyFinalized}
+	}
+	if u.IsExpired(now) {
+		return &errors.BusinessRuleError{Message: errors.UploadExpired}
+	}
+	return nil
+}
+
+// Finalize records the stored image; the upload then waits for a product
+func (u *Upload) Finalize(image ProductImage) {
+	u.Status = UploadPending
+	u.URL = image.URL
+	u.Renditions = image.Renditions
+}
+
+// CanAttach checks the upload is a verified image no product references yet
+func (u *Upload) CanAttach(now time.Time) error {
+	switch u.Status {
+	case UploadAwaitingContent:
+		return &errors.BusinessRuleError{Message: errors.UploadNotFinalized}
+	case UploadAttached:
+		return &errors.BusinessRuleError{Message: errors.UploadAlreadyAttached}
+	}
+	if u.IsExpired(now) {
+		return &errors.BusinessRuleError{Message: errors.UploadExpired}
+	}
+	return nil
+}
+
+// ToProductImage returns the image a product stores for the upload
+func (u *Upload) ToProductImage() ProductImage {
+	return ProductImage{URL: u.URL, Renditions: u.Renditions}
+}
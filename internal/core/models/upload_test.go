@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func TestUpload_Validate(t *testing.T) {
+	now := time.Now()
+
+	t.Run("when the image is within the limit then it is valid", func(t *testing.T) {
+		assert.NoError(t, NewUpload("id", "image/webp", MaxUploadSize, now).Validate())
+	})
+
+	t.Run("when the type is not an allowed image then returns unsupported format", func(t *testing.T) {
+		err := NewUpload("id", "image/gif", 100, now).Validate()
+		assert.Equal(t, &errors.ValidationError{Message: errors.UnsupportedImageFormat}, err)
+	})
+
+	t.Run("when the size is over the limit then returns out of range", func(t *testing.T) {
+		err := NewUpload("id", "image/png", MaxUploadSize+1, now).Validate()
+		assert.Equal(t, &errors.ValidationError{Message: errors.UploadSizeOutOfRange}, err)
+	})
+}
+
+func TestUpload_CanFinalize(t *testing.T) {
+	now := time.Now()
+
+	t.Run("when the content is awaited then it can be finalized", func(t *testing.T) {
+		assert.NoError(t, NewUpload("id", "image/png", 100, now).CanFinalize(now))
+	})
+
+	t.Run("when it was finalized then returns already finalized", func(t *testing.T) {
+		// Arrange
+		upload := NewUpload("id", "image/png", 100, now)
+		upload.Finalize(ProductImage{URL: "/assets/a.jpg"})
+
+		// Act
+		err := upload.CanFinalize(now)
+
+		// Assert
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.UploadAlreadyFinalized}, err)
+	})
+
+	t.Run("when it expired then returns expired", func(t *testing.T) {
+		err := NewUpload("id", "image/png", 100, now).CanFinalize(now.Add(UploadTTL))
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.UploadExpired}, err)
+	})
+}
+
+func TestUpload_CanAttach(t *testing.T) {
+	now := time.Now()
+	finalized := func() *Upload {
+		upload := NewUpload("id", "image/png", 100, now)
+		upload.Finalize(ProductImage{URL: "/assets/a.jpg", Renditions: map[string]string{OriginalRendition: "/assets/a.jpg"}})
+		return upload
+	}
+
+	t.Run("when it is pending then it can be attached as its image", func(t *testing.T) {
+		upload := finalized()
+
+		assert.NoError(t, upload.CanAttach(now))
+		assert.Equal(t, "/assets/a.jpg", upload.ToProductImage().URL)
+	})
+
+	t.Run("when the content is still awaited then returns not finalized", func(t *testing.T) {
+		err := NewUpload("id", "image/png", 100, now).CanAttach(now)
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.UploadNotFinalized}, err)
+	})
+
+	t.Run("when a product attached it then returns already attached", func(t *testing.T) {
+		upload := finalized()
+		upload.Status = UploadAttached
+
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.UploadAlreadyAttached}, upload.CanAttach(now))
+	})
+
+	t.Run("when it expired then returns expired", func(t *testing.T) {
+		err := finalized().CanAttach(now.Add(UploadTTL + time.Second))
+		assert.Equal(t, &errors.BusinessRuleError{Message: errors.UploadExpired}, err)
+	})
+}
@@ -1,5 +1,7 @@
 package models
 
+import "fmt"
+
 type User struct {
 	ID       int    `json:"id,omitempty"`
 	Name     string `json:"name,omitempty"`
@@ -11,3 +13,18 @@ type User struct {
 	// Token string  `json:"token,omitempty" json:"token"`
 	Roles []*Role `json:"roles,omitempty"`
 }
+
+// HasRole reports whether the user was granted the role
+func (u *User) HasRole(name string) bool {
+	for _, role := range u.Roles {
+		if role != nil && role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor identifies the user in the audit trails (e.g. order status history)
+func (u *User) Actor() string {
+	return fmt.Sprintf("user:%d", u.ID)
+}
@@ -1,18 +1,173 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
 type SelectionType string
 
 const (
 	Single   SelectionType = "single"   // User can select only 1 option
 	Multiple SelectionType = "multiple" // User can select multiple options (up to max_selections)
-	Custom   SelectionType = "custom"   // Custom selection logic defined by user
+	Custom   SelectionType = "custom"   // Selection logic defined by the variant rules
 )
 
 type Variant struct {
-	ID            int           `json:"id,omitempty"`
-	Name          string        `json:"name,omitempty"`
-	Order         int           `json:"order,omitempty"`
-	SelectionType SelectionType `json:"selection_type,omitempty"`
-	MaxSelections int           `json:"max_selections,omitempty"`
-	Options       []*Option     `json:"options,omitempty"`
+	ID            int            `json:"id,omitempty"`
+	Name          string         `json:"name,omitempty"`
+	Order         int            `json:"order,omitempty"`
+	SelectionType SelectionType  `json:"selection_type,omitempty"`
+	MaxSelections int            `json:"max_selections,omitempty"`
+	IsRequired    bool           `json:"is_required,omitempty"` // at least one option must be chosen
+	Options       []*Option      `json:"options,omitempty"`
+	Rules         []*VariantRule `json:"rules,omitempty"` // only custom variants have rules
+}
+
+// validate records every invalid field of the variant found at path (e.g. variants[1])
+func (v *Variant) validate(path string, result *errors.FieldValidationError) {
+	if strings.TrimSpace(v.Name) == "" {
+		result.Add(path+".name", errors.VariantNameRequired)
+	}
+
+	switch v.SelectionType {
+	case Single, Multiple, Custom:
+	default:
+		result.Add(path+".selection_type", errors.InvalidSelectionType)
+	}
+
+	// Business rule: max_selections must fit the selection type and the available options
+	switch {
+	case v.MaxSelections < 0:
+		result.Add(path+".max_selections", errors.MaxSelectionsCannotBeNegative)
+	case v.SelectionType == Single && v.MaxSelections > 1:
+		result.Add(path+".max_selections", errors.SingleSelectionMaxSelectionsIsOne)
+	case v.MaxSelections > len(v.Options):
+		result.Add(path+".max_selections", errors.MaxSelectionsExceedsOptionCount)
+	}
+
+	if len(v.Options) == 0 {
+		result.Add(path+".options", errors.VariantMustHaveOptions)
+	}
+
+	// Business rule: option names and orders identify a single option of the variant
+	names := make(map[string]bool, len(v.Options))
+	orders := make(map[int]bool, len(v.Options))
+	for i, option := range v.Options {
+		optionPath := fmt.Sprintf("%s.options[%d]", path, i)
+		option.validate(optionPath, result)
+
+		name := strings.ToLower(strings.TrimSpace(option.Name))
+		if name != "" && names[name] {
+			result.Add(optionPath+".name", errors.OptionNamesMustBeUnique)
+		}
+		names[name] = true
+
+		if orders[option.Order] {
+			result.Add(optionPath+".order", errors.OptionOrdersMustBeUnique)
+		}
+		orders[option.Order] = true
+	}
+
+	// Business rule: rules define the behavior of custom variants only
+	if len(v.Rules) > 0 && v.SelectionType != Custom {
+		result.Add(path+".rules", errors.VariantRulesRequireCustomSelection)
+		return
+	}
+	for i, rule := range v.Rules {
+		if err := rule.Validate(v); err != nil {
+			result.Add(fmt.Sprintf("%s.rules[%d]", path, i), err.Error())
+		}
+	}
+}
+
+// ensureOptionNames checks that every name references an option of the variant
+func (v *Variant) ensureOptionNames(names []string) error {
+	for _, name := range names {
+		found := false
+		for _, option := range v.Options {
+			if option.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &errors.ValidationError{Message: errors.VariantRuleReferencesUnknownOption}
+		}
+	}
+	return nil
+}
+
+// FindOption returns the option of the variant with the given ID
+func (v *Variant) FindOption(optionID int) *Option {
+	for _, option := range v.Options {
+		if option.ID == optionID {
+			return option
+		}
+	}
+	return nil
+}
+
+// SelectOptions validates the option IDs chosen for the variant against its selection rules
+// and returns the chosen options in the requested order
+func (v *Variant) SelectOptions(optionIDs []int) ([]*Option, error) {
+	// Business rule: required variants need a choice
+	if len(optionIDs) == 0 && v.IsRequired {
+		return nil, &errors.ValidationError{Message: errors.VariantSelectionRequired}
+	}
+
+	// Business rule: single variants take exactly one option, the rest honor max_selections
+	if v.SelectionType == Single && len(optionIDs) > 1 {
+		return nil, &errors.ValidationError{Message: errors.SingleSelectionAllowsOneOption}
+	}
+	if v.MaxSelections > 0 && len(optionIDs) > v.MaxSelections {
+		return nil, &errors.ValidationError{Message: errors.MaxSelectionsExceeded}
+	}
+
+	selected := make([]*Option, 0, len(optionIDs))
+	seen := make(map[int]bool, len(optionIDs))
+	for _, optionID := range optionIDs {
+		if seen[optionID] {
+			return nil, &errors.ValidationError{Message: errors.OptionSelectedMoreThanOnce}
+		}
+		seen[optionID] = true
+
+		option := v.FindOption(optionID)
+		if option == nil {
+			return nil, &errors.ValidationError{Message: errors.OptionNotFoundInVariant}
+		}
+		selected = append(selected, option)
+	}
+
+	// Business rule: custom variants also honor their own rules
+	if v.SelectionType == Custom {
+		for _, rule := range v.Rules {
+			if err := rule.Check(selected); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, nil
+	}
+	return selected, nil
+}
+
+// OptionPrices returns the price charged for each selected option, after the pricing rules
+func (v *Variant) OptionPrices(selected []*Option) []Money {
+	prices := make([]Money, len(selected))
+	for i, option := range selected {
+		prices[i] = option.Price
+	}
+
+	if v.SelectionType == Custom {
+		for _, rule := range v.Rules {
+			rule.Price(selected, prices)
+		}
+	}
+
+	return prices
 }
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"sort"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+type VariantRuleType string
+
+const (
+	SelectionCountRule    VariantRuleType = "selection_count"    // At least Min and at most Max options
+	FreeSelectionsRule    VariantRuleType = "free_selections"    // The cheapest Free options are not charged
+	MutuallyExclusiveRule VariantRuleType = "mutually_exclusive" // At most one of Options can be chosen
+	RequiresOptionRule    VariantRuleType = "requires_option"    // Choosing Option needs every option in Requires
+)
+
+// VariantRule is a declarative rule of a custom variant
+// Options are referenced by name, since new options have no ID until they are stored
+type VariantRule struct {
+	Type     VariantRuleType `json:"type"`
+	Min      *int            `json:"min,omitempty"`
+	Max      *int            `json:"max,omitempty"`
+	Free     int             `json:"free,omitempty"`
+	Options  []string        `json:"options,omitempty"`
+	Option   string          `json:"option,omitempty"`
+	Requires []string        `json:"requires,omitempty"`
+}
+
+// Validate validates the rule against the options of the variant it belongs to
+func (r *VariantRule) Validate(variant *Variant) error {
+	switch r.Type {
+	case SelectionCountRule:
+		if r.Min == nil && r.Max == nil {
+			return &errors.ValidationError{Message: errors.SelectionCountRuleRequiresMinOrMax}
+		}
+		if (r.Min != nil && *r.Min < 0) || (r.Max != nil && *r.Max <= 0) {
+			return &errors.ValidationError{Message: errors.InvalidSelectionCountRule}
+		}
+		if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+			return &errors.ValidationError{Message: errors.InvalidSelectionCountRule}
+		}
+	case FreeSelectionsRule:
+		if r.Free <= 0 {
+			return &errors.ValidationError{Message: errors.FreeSelectionsMustBePositive}
+		}
+	case MutuallyExclusiveRule:
+		if len(r.Options) < 2 {
+			return &errors.ValidationError{Message: errors.MutuallyExclusiveRuleRequiresTwoOptions}
+		}
+		return variant.ensureOptionNames(r.Options)
+	case RequiresOptionRule:
+		if r.Option == "" || len(r.Requires) == 0 {
+			return &errors.ValidationError{Message: errors.RequiresOptionRuleIsIncomplete}
+		}
+		for _, required := range r.Requires {
+			if required == r.Option {
+				return &errors.ValidationError{Message: errors.OptionCannotRequireItself}
+			}
+		}
+		return variant.ensureOptionNames(append([]string{r.Option}, r.Requires...))
+	default:
+		return &errors.ValidationError{Message: errors.InvalidVariantRuleType}
+	}
+
+	return nil
+}
+
+// Check evaluates the selection rules against the chosen options
+// Pricing rules such as free selections always pass and are applied by Price
+func (r *VariantRule) Check(selected []*Option) error {
+	chosen := make(map[string]bool, len(selected))
+	for _, option := range selected {
+		chosen[option.Name] = true
+	}
+
+	switch r.Type {
+	case SelectionCountRule:
+		if r.Min != nil && len(selected) < *r.Min {
+			return &errors.ValidationError{Message: errors.MinSelectionsNotReached}
+		}
+		if r.Max != nil && len(selected) > *r.Max {
+			return &errors.ValidationError{Message: errors.MaxSelectionsExceeded}
+		}
+	case MutuallyExclusiveRule:
+		count := 0
+		for _, name := range r.Options {
+			if chosen[name] {
+				count++
+			}
+		}
+		if count > 1 {
+			return &errors.ValidationError{Message: errors.OptionsAreMutuallyExclusive}
+		}
+	case RequiresOptionRule:
+		if !chosen[r.Option] {
+			return nil
+		}
+		for _, required := range r.Requires {
+			if !chosen[required] {
+				return &errors.ValidationError{Message: errors.OptionRequiresAnotherOption}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Price applies pricing rules to the prices charged for the chosen options, in the same order
+// With a free selections rule the cheapest options are the free ones, so the
+// total does not depend on the order the customer picked them
+func (r *VariantRule) Price(selected []*Option, prices []Money) {
+	if r.Type != FreeSelectionsRule {
+		return
+	}
+
+	byPrice := make([]int, len(selected))
+	for i := range byPrice {
+		byPrice[i] = i
+	}
+	sort.SliceStable(byPrice, func(a, b int) bool {
+		return prices[byPrice[a]].Cmp(prices[byPrice[b]]) < 0
+	})
+
+	for i := 0; i < r.Free && i < len(byPrice); i++ {
+		index := byPrice[i]
+		prices[index] = Money{}
+	}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+// toppings is a custom variant: 1 to 3 toppings, the first 2 free, bacon and tofu
+// exclude each other and double cheese needs cheese
+func toppings() *Variant {
+	return &Variant{ID: 3, Name: "Toppings", SelectionType: Custom, Options: []*Option{
+		{ID: 6, Name: "Cheese", Price: MoneyFromFloat(1), Order: 1},
+		{ID: 7, Name: "Double cheese", Price: MoneyFromFloat(1.5), Order: 2},
+		{ID: 8, Name: "Bacon", Price: MoneyFromFloat(2), Order: 3},
+		{ID: 9, Name: "Tofu", Price: MoneyFromFloat(2.5), Order: 4},
+	}, Rules: []*VariantRule{
+		{Type: SelectionCountRule, Min: intPtr(1), Max: intPtr(3)},
+		{Type: FreeSelectionsRule, Free: 2},
+		{Type: MutuallyExclusiveRule, Options: []string{"Bacon", "Tofu"}},
+		{Type: RequiresOptionRule, Option: "Double cheese", Requires: []string{"Cheese"}},
+	}}
+}
+
+func TestProduct_Validate_VariantRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(variant *Variant)
+		expected *errors.FieldError
+	}{
+		{
+			name:   "when the rules are valid then returns nil",
+			mutate: func(variant *Variant) {},
+		},
+		{
+			name:     "when a non custom variant has rules then returns a validation error",
+			mutate:   func(variant *Variant) { variant.SelectionType = Multiple },
+			expected: &errors.FieldError{Field: "variants[0].rules", Message: errors.VariantRulesRequireCustomSelection},
+		},
+		{
+			name:     "when the rule type is unknown then returns a validation error",
+			mutate:   func(variant *Variant) { variant.Rules[0].Type = "bogus" },
+			expected: &errors.FieldError{Field: "variants[0].rules[0]", Message: errors.InvalidVariantRuleType},
+		},
+		{
+			name:     "when min is greater than max then returns a validation error",
+			mutate:   func(variant *Variant) { variant.Rules[0].Min = intPtr(4) },
+			expected: &errors.FieldError{Field: "variants[0].rules[0]", Message: errors.InvalidSelectionCountRule},
+		},
+		{
+			name:     "when free selections is not positive then returns a validation error",
+			mutate:   func(variant *Variant) { variant.Rules[1].Free = 0 },
+			expected: &errors.FieldError{Field: "variants[0].rules[1]", Message: errors.FreeSelectionsMustBePositive},
+		},
+		{
+			name:     "when a rule references an unknown option then returns a validation error",
+			mutate:   func(variant *Variant) { variant.Rules[2].Options = []string{"Bacon", "Ham"} },
+			expected: &errors.FieldError{Field: "variants[0].rules[2]", Message: errors.VariantRuleReferencesUnknownOption},
+		},
+		{
+			name:     "when an option requires itself then returns a validation error",
+			mutate:   func(variant *Variant) { variant.Rules[3].Requires = []string{"Double cheese"} },
+			expected: &errors.FieldError{Field: "variants[0].rules[3]", Message: errors.OptionCannotRequireItself},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			variant := toppings()
+			tt.mutate(variant)
+			product := &Product{Price: MoneyFromFloat(10), Variants: []*Variant{variant}}
+
+			// Act
+			err := product.Validate()
+
+			// Assert
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, &errors.FieldValidationError{Message: errors.InvalidVariants, Fields: []errors.FieldError{*tt.expected}}, err)
+		})
+	}
+}
+
+func TestVariant_SelectOptions_CustomRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		optionIDs []int
+		expected  error
+	}{
+		{name: "when the selection honors every rule then returns nil", optionIDs: []int{6, 7, 8}},
+		{name: "when nothing is chosen below min then returns a validation error", optionIDs: nil, expected: &errors.ValidationError{Message: errors.MinSelectionsNotReached}},
+		{name: "when more than max are chosen then returns a validation error", optionIDs: []int{6, 7, 8, 9}, expected: &errors.ValidationError{Message: errors.MaxSelectionsExceeded}},
+		{name: "when exclusive options are chosen together then returns a validation error", optionIDs: []int{8, 9}, expected: &errors.ValidationError{Message: errors.OptionsAreMutuallyExclusive}},
+		{name: "when a required option is missing then returns a validation error", optionIDs: []int{7}, expected: &errors.ValidationError{Message: errors.OptionRequiresAnotherOption}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := toppings().SelectOptions(tt.optionIDs)
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestProduct_Quote_FreeSelections(t *testing.T) {
+	// Arrange
+	product := quotedBurger()
+	product.Variants = append(product.Variants, toppings())
+	selections := []VariantSelection{{VariantID: 1, OptionIDs: []int{1}}, {VariantID: 3, OptionIDs: []int{8, 6, 7}}}
+
+	// Act
+	quote, err := product.Quote(selections, 2, time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC))
+
+	// Assert: cheese and double cheese are the cheapest, so only bacon is charged
+	assert.NoError(t, err)
+	assert.Equal(t, "2.00", quote.Lines[2].UnitPrice.String())
+	assert.Equal(t, "0.00", quote.Lines[3].UnitPrice.String())
+	assert.Equal(t, "0.00", quote.Lines[4].UnitPrice.String())
+	assert.Equal(t, "12.00", quote.UnitPrice.String())
+	assert.Equal(t, "24.00", quote.Total.String())
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func TestProduct_Validate_Variants(t *testing.T) {
+	t.Run("when variants are consistent then returns nil", func(t *testing.T) {
+		// Arrange
+		product := &Product{Price: MoneyFromFloat(10), Variants: []*Variant{
+			{Name: "Size", SelectionType: Single, MaxSelections: 1, Options: []*Option{
+				{Name: "Simple", Price: MoneyFromFloat(0), Order: 0},
+				{Name: "Double", Price: MoneyFromFloat(3), Order: 1},
+			}},
+			{Name: "Extras", SelectionType: Multiple, MaxSelections: 2, Options: []*Option{
+				{Name: "Bacon", Price: MoneyFromFloat(1.5), Order: 0},
+				{Name: "Cheese", Price: MoneyFromFloat(1), Order: 1},
+			}},
+		}}
+
+		// Act
+		err := product.Validate()
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("when several fields are invalid then reports every one with its path", func(t *testing.T) {
+		// Arrange
+		product := &Product{Price: MoneyFromFloat(10), Variants: []*Variant{
+			{Name: "Size", SelectionType: Single, MaxSelections: 2, Options: []*Option{
+				{Name: "Simple", Price: MoneyFromFloat(0), Order: 1},
+				{Name: "Double", Price: MoneyFromFloat(3), Order: 2},
+			}},
+			{Name: " size ", SelectionType: Multiple, MaxSelections: 3, Options: []*Option{
+				{Name: "Bacon", Price: MoneyFromFloat(-1), Order: 1},
+				{Name: "bacon", Price: MoneyFromFloat(1), Order: 1},
+			}},
+			{Name: "", SelectionType: "any"},
+		}}
+
+		// Act
+		err := product.Validate()
+
+		// Assert
+		assert.Equal(t, &errors.FieldValidationError{
+			Message: errors.InvalidVariants,
+			Fields: []errors.FieldError{
+				{Field: "variants[0].max_selections", Message: errors.SingleSelectionMaxSelectionsIsOne},
+				{Field: "variants[1].max_selections", Message: errors.MaxSelectionsExceedsOptionCount},
+				{Field: "variants[1].options[0].price", Message: errors.OptionPriceCannotBeNegative},
+				{Field: "variants[1].options[1].name", Message: errors.OptionNamesMustBeUnique},
+				{Field: "variants[1].options[1].order", Message: errors.OptionOrdersMustBeUnique},
+				{Field: "variants[1].name", Message: errors.VariantNamesMustBeUnique},
+				{Field: "variants[2].name", Message: errors.VariantNameRequired},
+				{Field: "variants[2].selection_type", Message: errors.InvalidSelectionType},
+				{Field: "variants[2].options", Message: errors.VariantMustHaveOptions},
+			},
+		}, err)
+	})
+
+	t.Run("when product fields are invalid then returns them before the variants", func(t *testing.T) {
+		product := &Product{Price: MoneyFromFloat(0), Variants: []*Variant{{Name: ""}}}
+		assert.Equal(t, &errors.ValidationError{Message: errors.ProductPriceMustBePositive}, product.Validate())
+	})
+}
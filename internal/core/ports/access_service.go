@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type AccessService interface {
+	// AuthorizeShopStaff succeeds when the user runs the shop; anyone else, signed in or not, is forbidden
+	AuthorizeShopStaff(ctx context.Context, user *models.User, shopID int) error
+	// AuthorizeProductStaff succeeds when the user runs the shop of the product, deleted or not
+	AuthorizeProductStaff(ctx context.Context, user *models.User, productID int) error
+	// AuthorizePlatformAdmin succeeds when the user was granted the platform admin role
+	AuthorizePlatformAdmin(ctx context.Context, user *models.User) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type AdjustStockUseCase interface {
+	Execute(ctx context.Context, productID int, movement *models.StockMovement) (*models.StockMovement, error)
+}
@@ -0,0 +1,8 @@
+package ports
+
+import "net/http"
+
+type AssetHandler interface {
+	Serve(http.ResponseWriter, *http.Request)
+	CollectOrphans(http.ResponseWriter, *http.Request)
+}
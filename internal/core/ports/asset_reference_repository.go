@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// AssetReferenceRepository lists the asset URLs stored data points at
+type AssetReferenceRepository interface {
+	GetReferencedURLs(ctx context.Context) ([]string, error)
+}
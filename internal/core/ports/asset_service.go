@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+// AssetService stores binary assets and resolves the URLs they are served from
+type AssetService interface {
+	// Upload streams content of the given size and type into the storage
+	Upload(ctx context.Context, content io.Reader, size int64, contentType string) (*models.Asset, error)
+	Delete(ctx context.Context, key string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	URL(key string) string
+	// List returns every content-addressed asset in the storage
+	List(ctx context.Context) ([]*models.Asset, error)
+	// PresignUpload lets a client put a file of the given type and size at key
+	// without going through the API
+	PresignUpload(ctx context.Context, key, contentType string, size int64, expires time.Duration) (*models.SignedURL, error)
+}
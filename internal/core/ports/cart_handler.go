@@ -0,0 +1,12 @@
+package ports
+
+import "net/http"
+
+type CartHandler interface {
+	Get(http.ResponseWriter, *http.Request)
+	AddLine(http.ResponseWriter, *http.Request)
+	UpdateLine(http.ResponseWriter, *http.Request)
+	RemoveLine(http.ResponseWriter, *http.Request)
+	Clear(http.ResponseWriter, *http.Request)
+	PurgeExpired(http.ResponseWriter, *http.Request)
+}
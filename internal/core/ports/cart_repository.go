@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type CartRepository interface {
+	// GetByOwner returns the unexpired cart of the owner in the shop with its lines
+	GetByOwner(ctx context.Context, shopID int, owner models.CartOwner, now time.Time) (*models.Cart, error)
+	Create(ctx context.Context, cart *models.Cart) error
+	AddLine(ctx context.Context, cart *models.Cart, line *models.CartLine) error
+	UpdateLine(ctx context.Context, cartID int, line *models.CartLine) error
+	RemoveLine(ctx context.Context, cartID, lineID int) error
+	Clear(ctx context.Context, cartID int) error
+	Touch(ctx context.Context, cart *models.Cart) error
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
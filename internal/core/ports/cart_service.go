@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type CartService interface {
+	Get(ctx context.Context, shopID int, owner models.CartOwner) (*models.Cart, error)
+	AddLine(ctx context.Context, shopID int, owner models.CartOwner, line *models.CartLine) (*models.Cart, error)
+	// UpdateLine changes the quantity of a line and, when selections is not nil, its options
+	UpdateLine(ctx context.Context, shopID int, owner models.CartOwner, lineID, quantity int, selections []models.VariantSelection) (*models.Cart, error)
+	RemoveLine(ctx context.Context, shopID int, owner models.CartOwner, lineID int) (*models.Cart, error)
+	Clear(ctx context.Context, shopID int, owner models.CartOwner) (*models.Cart, error)
+	PurgeExpired(ctx context.Context) (int, error)
+}
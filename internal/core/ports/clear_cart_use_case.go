@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type ClearCartUseCase interface {
+	Execute(ctx context.Context, shopID int, owner models.CartOwner) (*models.Cart, error)
+}
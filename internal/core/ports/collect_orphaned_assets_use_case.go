@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type CollectOrphanedAssetsUseCase interface {
+	Execute(ctx context.Context, grace time.Duration, dryRun bool) (*models.AssetGCReport, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type CreateOrderUseCase interface {
+	Execute(ctx context.Context, shopID int, draft *models.OrderDraft) (*models.Order, error)
+}
@@ -7,5 +7,5 @@ import (
 )
 
 type CreateProductUseCase interface {
-	Execute(ctx context.Context, product *models.Product, imageBuffers [][]byte, shopID int) (*models.Product, error)
+	Execute(ctx context.Context, product *models.Product, images ImageUploads, shopID int) (*models.Product, error)
 }
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type CreatePromotionUseCase interface {
+	Execute(ctx context.Context, productID int, promotion *models.Promotion) (*models.Promotion, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type CreateUploadUseCase interface {
+	Execute(ctx context.Context, contentType string, size int64) (*models.Upload, *models.SignedURL, error)
+}
@@ -0,0 +1,7 @@
+package ports
+
+import "context"
+
+type DeletePromotionUseCase interface {
+	Execute(ctx context.Context, productID, promotionID int) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type FinalizeUploadUseCase interface {
+	Execute(ctx context.Context, uploadID string) (*models.Upload, error)
+}
@@ -7,5 +7,5 @@ import (
 )
 
 type GetAllByShopIDUseCase interface {
-	Execute(ctx context.Context, shopID, limit, cursor int) ([]*models.Product, int, bool, error)
+	Execute(ctx context.Context, shopID int, filter *models.ProductFilter, requester *models.User) ([]*models.Product, string, bool, error)
 }
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type GetCustomerOrdersUseCase interface {
+	Execute(ctx context.Context, customerID, limit, cursor int) ([]*models.Order, string, bool, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type GetLowStockReportUseCase interface {
+	Execute(ctx context.Context, shopID int) ([]*models.LowStockProduct, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type GetOrderUseCase interface {
+	Execute(ctx context.Context, orderID int, requester *models.User) (*models.Order, error)
+}
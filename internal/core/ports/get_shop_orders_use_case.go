@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type GetShopOrdersUseCase interface {
+	Execute(ctx context.Context, shopID, limit, cursor int) ([]*models.Order, string, bool, error)
+}
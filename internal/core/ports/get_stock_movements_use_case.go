@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type GetStockMovementsUseCase interface {
+	Execute(ctx context.Context, productID, limit, cursor int) ([]*models.StockMovement, string, bool, error)
+}
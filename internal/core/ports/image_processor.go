@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+// ImageProcessor turns an uploaded image into the renditions served to clients
+type ImageProcessor interface {
+	Process(ctx context.Context, content io.Reader) ([]*models.ImageRendition, error)
+}
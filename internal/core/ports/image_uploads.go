@@ -0,0 +1,11 @@
+package ports
+
+import "github.com/mlgaray/ecommerce_api/internal/core/models"
+
+// ImageUploads streams the images of a request one at a time
+// Next returns io.EOF after the last image; each image must be consumed before the next one is read
+type ImageUploads interface {
+	Next() (*models.ImageUpload, error)
+	// Err returns the error that cut the current image short (e.g. it exceeds the size limit), if any
+	Err() error
+}
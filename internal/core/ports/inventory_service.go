@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type InventoryService interface {
+	GetLowStockReport(ctx context.Context, shopID int) ([]*models.LowStockProduct, error)
+	CheckLowStock(ctx context.Context, productID int)
+}
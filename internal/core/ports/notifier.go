@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+// Notifier delivers inventory alerts to the shop owners
+type Notifier interface {
+	NotifyLowStock(ctx context.Context, product *models.LowStockProduct) error
+}
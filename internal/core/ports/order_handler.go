@@ -0,0 +1,11 @@
+package ports
+
+import "net/http"
+
+type OrderHandler interface {
+	Create(http.ResponseWriter, *http.Request)
+	GetByID(http.ResponseWriter, *http.Request)
+	GetShopOrders(http.ResponseWriter, *http.Request)
+	GetCustomerOrders(http.ResponseWriter, *http.Request)
+	Transition(http.ResponseWriter, *http.Request)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type OrderRepository interface {
+	// Create stores the order with its items and takes the ordered units from the stock
+	// in a single transaction, filling the generated IDs
+	Create(ctx context.Context, order *models.Order) error
+	// Transition moves the order to the next status, recording the change in its history
+	// and returning the units of cancelled orders to the stock, in a single transaction
+	Transition(ctx context.Context, order *models.Order, next models.OrderStatus, actor string) (*models.OrderStatusChange, error)
+	GetByID(ctx context.Context, orderID int) (*models.Order, error)
+	GetByIdempotencyKey(ctx context.Context, shopID int, key string) (*models.Order, error)
+	GetAllByShopID(ctx context.Context, shopID, limit, cursor int) ([]*models.Order, error)
+	GetAllByCustomerID(ctx context.Context, customerID, limit, cursor int) ([]*models.Order, error)
+}
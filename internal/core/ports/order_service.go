@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type OrderService interface {
+	Create(ctx context.Context, shopID int, draft *models.OrderDraft) (*models.Order, error)
+	// GetByID returns the order to the customer that placed it or to the staff of its shop
+	GetByID(ctx context.Context, orderID int, requester *models.User) (*models.Order, error)
+	// Transition moves the order to the next status on behalf of the staff of its shop
+	Transition(ctx context.Context, orderID int, staff *models.User, next models.OrderStatus) (*models.Order, error)
+	GetAllByShopID(ctx context.Context, shopID, limit, cursor int) ([]*models.Order, string, bool, error)
+	GetAllByCustomerID(ctx context.Context, customerID, limit, cursor int) ([]*models.Order, string, bool, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type PatchProductUseCase interface {
+	Execute(ctx context.Context, productID int, patch map[string]interface{}, expectedVersion int) (*models.Product, error)
+}
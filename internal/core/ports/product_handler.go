@@ -6,5 +6,12 @@ type ProductHandler interface {
 	Create(http.ResponseWriter, *http.Request)
 	GetAllByShopID(http.ResponseWriter, *http.Request)
 	GetByID(http.ResponseWriter, *http.Request)
+	Search(http.ResponseWriter, *http.Request)
+	Delete(http.ResponseWriter, *http.Request)
+	Restore(http.ResponseWriter, *http.Request)
+	PurgeDeleted(http.ResponseWriter, *http.Request)
 	Update(http.ResponseWriter, *http.Request)
+	Patch(http.ResponseWriter, *http.Request)
+	Quote(http.ResponseWriter, *http.Request)
+	ReorderImages(http.ResponseWriter, *http.Request)
 }
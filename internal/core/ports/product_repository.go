@@ -2,13 +2,20 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
 )
 
 type ProductRepository interface {
 	Create(ctx context.Context, product *models.Product, shopID int) (*models.Product, error)
-	GetAllByShopID(ctx context.Context, shopID, limit, cursor int) ([]*models.Product, error)
+	GetAllByShopID(ctx context.Context, shopID int, filter *models.ProductFilter) ([]*models.Product, error)
 	GetByID(ctx context.Context, productID int) (*models.Product, error)
+	Search(ctx context.Context, shopID int, search *models.ProductSearch) ([]*models.ProductSearchResult, error)
+	SoftDelete(ctx context.Context, productID int) error
+	Restore(ctx context.Context, productID int) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error)
 	Update(ctx context.Context, productID int, product *models.Product) error
+	Patch(ctx context.Context, productID int, product *models.Product, replaceImages, replaceVariants bool) error
+	ReorderImages(ctx context.Context, productID int, images []models.ProductImage, expectedVersion int) error
 }
@@ -2,13 +2,22 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
 )
 
 type ProductService interface {
-	Create(ctx context.Context, product *models.Product, imageBuffers [][]byte, shopID int) (*models.Product, error)
-	GetAllByShopID(ctx context.Context, shopID, limit, cursor int) ([]*models.Product, int, bool, error)
+	Create(ctx context.Context, product *models.Product, images ImageUploads, shopID int) (*models.Product, error)
+	// GetAllByShopID lists the products of the shop; inactive ones are only listed to its staff
+	GetAllByShopID(ctx context.Context, shopID int, filter *models.ProductFilter, requester *models.User) ([]*models.Product, string, bool, error)
 	GetByID(ctx context.Context, productID int) (*models.Product, error)
-	Update(ctx context.Context, productID int, product *models.Product, newImageBuffers [][]byte) error
+	Search(ctx context.Context, shopID int, search *models.ProductSearch) ([]*models.ProductSearchResult, string, bool, error)
+	Delete(ctx context.Context, productID int) error
+	Restore(ctx context.Context, productID int) error
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int, error)
+	Update(ctx context.Context, productID int, product *models.Product, newImages ImageUploads) error
+	Patch(ctx context.Context, productID int, patch map[string]interface{}, expectedVersion int) (*models.Product, error)
+	ReorderImages(ctx context.Context, productID int, order *models.ImageOrder, expectedVersion int) (*models.Product, error)
+	Quote(ctx context.Context, productID int, selections []models.VariantSelection, quantity int) (*models.Quote, error)
 }
@@ -0,0 +1,8 @@
+package ports
+
+import "net/http"
+
+type PromotionHandler interface {
+	Create(http.ResponseWriter, *http.Request)
+	Delete(http.ResponseWriter, *http.Request)
+}
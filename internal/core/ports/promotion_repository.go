@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type PromotionRepository interface {
+	Create(ctx context.Context, productID int, promotion *models.Promotion) (*models.Promotion, error)
+	Delete(ctx context.Context, productID, promotionID int) error
+}
@@ -0,0 +1,7 @@
+package ports
+
+import "context"
+
+type PurgeCartsUseCase interface {
+	Execute(ctx context.Context) (int, error)
+}
@@ -0,0 +1,10 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+type PurgeProductsUseCase interface {
+	Execute(ctx context.Context, retention time.Duration) (int, error)
+}
@@ -0,0 +1,7 @@
+package ports
+
+import "context"
+
+type PurgeUploadsUseCase interface {
+	Execute(ctx context.Context) (int, error)
+}
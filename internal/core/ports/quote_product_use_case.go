@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type QuoteProductUseCase interface {
+	Execute(ctx context.Context, productID int, selections []models.VariantSelection, quantity int) (*models.Quote, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type RemoveCartLineUseCase interface {
+	Execute(ctx context.Context, shopID int, owner models.CartOwner, lineID int) (*models.Cart, error)
+}
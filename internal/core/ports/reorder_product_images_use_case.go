@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type ReorderProductImagesUseCase interface {
+	Execute(ctx context.Context, productID int, order *models.ImageOrder, expectedVersion int) (*models.Product, error)
+}
@@ -0,0 +1,7 @@
+package ports
+
+import "context"
+
+type RestoreProductUseCase interface {
+	Execute(ctx context.Context, productID int) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type SearchProductsUseCase interface {
+	Execute(ctx context.Context, shopID int, search *models.ProductSearch) ([]*models.ProductSearchResult, string, bool, error)
+}
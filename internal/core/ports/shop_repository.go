@@ -8,4 +8,6 @@ import (
 
 type ShopRepository interface {
 	Create(ctx context.Context, shop *models.Shop) (*models.Shop, error)
+	IsOwnedBy(ctx context.Context, shopID, userID int) (bool, error)
+	GetOwnerIDByProductID(ctx context.Context, productID int) (int, error)
 }
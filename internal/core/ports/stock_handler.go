@@ -0,0 +1,9 @@
+package ports
+
+import "net/http"
+
+type StockHandler interface {
+	AdjustStock(http.ResponseWriter, *http.Request)
+	GetMovements(http.ResponseWriter, *http.Request)
+	GetLowStockReport(http.ResponseWriter, *http.Request)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type StockRepository interface {
+	ApplyStockMovement(ctx context.Context, productID int, movement *models.StockMovement) (*models.StockMovement, error)
+	GetMovements(ctx context.Context, productID, limit, cursor int) ([]*models.StockMovement, error)
+	GetLowStockByShopID(ctx context.Context, shopID int) ([]*models.LowStockProduct, error)
+	ClaimLowStockAlert(ctx context.Context, productID int) (*models.LowStockProduct, error)
+	ReleaseLowStockAlert(ctx context.Context, productID int) error
+}
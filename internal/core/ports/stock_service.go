@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type StockService interface {
+	Adjust(ctx context.Context, productID int, movement *models.StockMovement) (*models.StockMovement, error)
+	GetMovements(ctx context.Context, productID, limit, cursor int) ([]*models.StockMovement, string, bool, error)
+}
@@ -8,7 +8,8 @@ import (
 
 type TokenService interface {
 	Generate(ctx context.Context, user *models.User) (string, error)
-	// ValidateToken(ctx context.Context, token string) (*entities.User, error)
+	// VerifyToken returns the user a valid token was issued to
+	VerifyToken(ctx context.Context, token string) (*models.User, error)
 	// RefreshToken(ctx context.Context, token string) (string, error)
 	// GetTokenExpiration() time.Duration
 }
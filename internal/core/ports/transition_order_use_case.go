@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type TransitionOrderUseCase interface {
+	Execute(ctx context.Context, orderID int, staff *models.User, next models.OrderStatus) (*models.Order, error)
+}
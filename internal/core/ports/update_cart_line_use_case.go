@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type UpdateCartLineUseCase interface {
+	Execute(ctx context.Context, shopID int, owner models.CartOwner, lineID, quantity int, selections []models.VariantSelection) (*models.Cart, error)
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "net/http"
+
+type UploadHandler interface {
+	Create(http.ResponseWriter, *http.Request)
+	Finalize(http.ResponseWriter, *http.Request)
+	PurgeExpired(http.ResponseWriter, *http.Request)
+}
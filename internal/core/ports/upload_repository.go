@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type UploadRepository interface {
+	Create(ctx context.Context, upload *models.Upload) error
+	GetByID(ctx context.Context, uploadID string) (*models.Upload, error)
+	Finalize(ctx context.Context, upload *models.Upload) error
+	Claim(ctx context.Context, uploadIDs []string, now time.Time) ([]*models.Upload, error)
+	Release(ctx context.Context, uploadIDs []string) error
+	DeleteExpired(ctx context.Context, now time.Time) ([]*models.Upload, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type UploadService interface {
+	Create(ctx context.Context, contentType string, size int64) (*models.Upload, *models.SignedURL, error)
+	Finalize(ctx context.Context, uploadID string) (*models.Upload, error)
+	PurgeExpired(ctx context.Context) (int, error)
+	// Attach resolves the product images referencing an upload and claims the uploads
+	Attach(ctx context.Context, images []models.ProductImage) ([]string, error)
+	Release(ctx context.Context, uploadIDs []string)
+}
@@ -0,0 +1,46 @@
+package assets
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+// Default location of the local storage and the path it is served under
+const (
+	defaultAssetsDir     = "storage/assets"
+	defaultAssetsBaseURL = "/assets"
+)
+
+// NewAssetService stores assets in the S3-compatible bucket configured in
+// ASSETS_S3_BUCKET, falling back to the directory configured in ASSETS_DIR
+// ASSETS_BASE_URL overrides the public URL of the assets (e.g. a CDN in front of them)
+func NewAssetService() ports.AssetService {
+	baseURL := strings.TrimSpace(os.Getenv("ASSETS_BASE_URL"))
+
+	if bucket := strings.TrimSpace(os.Getenv("ASSETS_S3_BUCKET")); bucket != "" {
+		partSize, _ := strconv.Atoi(os.Getenv("ASSETS_S3_PART_SIZE"))
+		return NewS3AssetService(S3Config{
+			Endpoint:        strings.TrimSpace(os.Getenv("ASSETS_S3_ENDPOINT")),
+			Region:          strings.TrimSpace(os.Getenv("ASSETS_S3_REGION")),
+			Bucket:          bucket,
+			Prefix:          strings.TrimSpace(os.Getenv("ASSETS_S3_PREFIX")),
+			AccessKeyID:     os.Getenv("ASSETS_S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("ASSETS_S3_SECRET_ACCESS_KEY"),
+			BaseURL:         baseURL,
+			PartSize:        partSize,
+		})
+	}
+
+	dir := strings.TrimSpace(os.Getenv("ASSETS_DIR"))
+	if dir == "" {
+		dir = defaultAssetsDir
+	}
+	if baseURL == "" {
+		baseURL = defaultAssetsBaseURL
+	}
+
+	return NewLocalAssetService(dir, baseURL)
+}
@@ -0,0 +1,102 @@
+package assets
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+// extensionsByContentType lists the asset types the storage accepts
+var extensionsByContentType = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// contentKeyPattern matches the keys built by contentHasher, so a key can never
+// point outside the storage (e.g. ../../etc/passwd)
+var contentKeyPattern = regexp.MustCompile(`^[a-f0-9]{64}\.(jpg|png|webp|gif)$`)
+
+// stagingKeyPattern matches the keys direct uploads are put at until they are finalized
+var stagingKeyPattern = regexp.MustCompile(`^uploads/[a-f0-9]{32}$`)
+
+// sniffLen is the number of bytes http.DetectContentType looks at
+const sniffLen = 512
+
+// sniffContent checks that the content is of the declared type, which must be one
+// the storage accepts, and returns the extension of its key with a reader that
+// replays the sniffed bytes
+func sniffContent(content io.Reader, contentType string) (io.Reader, string, error) {
+	extension, ok := extensionsByContentType[contentType]
+	if !ok {
+		return nil, "", &errors.ValidationError{Message: errors.UnsupportedAssetType}
+	}
+
+	buffered := bufio.NewReaderSize(content, sniffLen)
+	head, err := buffered.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, "", err
+	}
+	if http.DetectContentType(head) != contentType {
+		return nil, "", &errors.ValidationError{Message: errors.UnsupportedAssetType}
+	}
+
+	return buffered, extension, nil
+}
+
+// contentHasher hashes and counts the content while it is streamed to the storage,
+// which addresses the content by its SHA-256 so uploading the same file twice stores it once
+type contentHasher struct {
+	reader io.Reader
+	hash   hash.Hash
+	size   int64
+}
+
+func newContentHasher(content io.Reader) *contentHasher {
+	return &contentHasher{reader: content, hash: sha256.New()}
+}
+
+func (h *contentHasher) Read(p []byte) (int, error) {
+	n, err := h.reader.Read(p)
+	h.hash.Write(p[:n])
+	h.size += int64(n)
+	return n, err
+}
+
+// key is the content key of the bytes read so far
+func (h *contentHasher) key(extension string) string {
+	return hex.EncodeToString(h.hash.Sum(nil)) + extension
+}
+
+// isContentKey reports whether the key addresses stored content, as opposed
+// to a direct upload waiting to be finalized
+func isContentKey(key string) bool {
+	return contentKeyPattern.MatchString(key)
+}
+
+// contentTypeOf returns the content type a content key was stored with
+func contentTypeOf(key string) string {
+	extension := path.Ext(key)
+	for contentType, candidate := range extensionsByContentType {
+		if candidate == extension {
+			return contentType
+		}
+	}
+	return ""
+}
+
+// validateKey rejects keys that were not built by contentHasher or for a direct upload
+func validateKey(key string) error {
+	if !contentKeyPattern.MatchString(key) && !stagingKeyPattern.MatchString(key) {
+		return &errors.ValidationError{Message: errors.InvalidAssetKey}
+	}
+	return nil
+}
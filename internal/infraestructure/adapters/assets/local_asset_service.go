@@ -0,0 +1,194 @@
+package assets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Asset service log field constants
+const (
+	LocalAssetServiceField = "local_asset_service"
+	UploadFunctionField    = "upload"
+	DeleteFunctionField    = "delete"
+	OpenFunctionField      = "open"
+	ListFunctionField      = "list"
+	WriteFileSubFuncField  = "write_file"
+)
+
+// LocalAssetService stores assets as content-addressed files in a directory
+// Files are sharded by the first two characters of their key to keep directories small
+type LocalAssetService struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalAssetService(dir, baseURL string) *LocalAssetService {
+	return &LocalAssetService{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Upload streams the content into a temporary file while hashing it, then moves
+// the file to its content key
+func (s *LocalAssetService) Upload(_ context.Context, content io.Reader, size int64, contentType string) (*models.Asset, error) {
+	content, extension, err := sniffContent(content, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.write(newContentHasher(content), size, extension)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     LocalAssetServiceField,
+			"function": UploadFunctionField,
+			"sub_func": WriteFileSubFuncField,
+			"size":     size,
+			"error":    err.Error(),
+		}).Error("Failed to store asset")
+		return nil, fmt.Errorf("asset storage failed")
+	}
+
+	return &models.Asset{
+		Key:         key,
+		URL:         s.URL(key),
+		ContentType: contentType,
+		Size:        size,
+	}, nil
+}
+
+// write stores the content through a temporary file, so readers never see a partial file
+// The temporary file is dropped when the same content was already stored
+func (s *LocalAssetService) write(content *contentHasher, size int64, extension string) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if content.size != size {
+		return "", fmt.Errorf("read %d bytes of the %d announced", content.size, size)
+	}
+
+	key := content.key(extension)
+	path := s.path(key)
+	if _, err := os.Stat(path); err == nil {
+		return key, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	return key, os.Rename(tmp.Name(), path)
+}
+
+// Delete removes the asset; deleting a missing asset is not an error
+func (s *LocalAssetService) Delete(_ context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		logs.WithFields(map[string]interface{}{
+			"file":     LocalAssetServiceField,
+			"function": DeleteFunctionField,
+			"key":      key,
+			"error":    err.Error(),
+		}).Error("Failed to delete asset")
+		return fmt.Errorf("asset storage failed")
+	}
+
+	return nil
+}
+
+func (s *LocalAssetService) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &errors.RecordNotFoundError{Message: errors.AssetNotFound}
+		}
+		logs.WithFields(map[string]interface{}{
+			"file":     LocalAssetServiceField,
+			"function": OpenFunctionField,
+			"key":      key,
+			"error":    err.Error(),
+		}).Error("Failed to open asset")
+		return nil, fmt.Errorf("asset storage failed")
+	}
+
+	return file, nil
+}
+
+func (s *LocalAssetService) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// List walks the shard directories; temporary files of writes in progress are skipped
+func (s *LocalAssetService) List(_ context.Context) ([]*models.Asset, error) {
+	var assets []*models.Asset
+	err := filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isContentKey(entry.Name()) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		assets = append(assets, &models.Asset{
+			Key:         entry.Name(),
+			URL:         s.URL(entry.Name()),
+			ContentType: contentTypeOf(entry.Name()),
+			Size:        info.Size(),
+			ModifiedAt:  info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		logs.WithFields(map[string]interface{}{
+			"file":     LocalAssetServiceField,
+			"function": ListFunctionField,
+			"error":    err.Error(),
+		}).Error("Failed to list assets")
+		return nil, fmt.Errorf("asset storage failed")
+	}
+
+	return assets, nil
+}
+
+// PresignUpload is not supported: clients can only reach the directory through the API
+func (s *LocalAssetService) PresignUpload(_ context.Context, _, _ string, _ int64, _ time.Duration) (*models.SignedURL, error) {
+	return nil, &errors.BusinessRuleError{Message: errors.DirectUploadsRequireObjectStorage}
+}
+
+func (s *LocalAssetService) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key)
+}
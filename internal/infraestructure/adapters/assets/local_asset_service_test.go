@@ -0,0 +1,162 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+func init() {
+	logs.Init()
+}
+
+func pngContent(t *testing.T) []byte {
+	var buffer bytes.Buffer
+	require.NoError(t, png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, 2, 2))))
+	return buffer.Bytes()
+}
+
+func upload(service *LocalAssetService, content []byte) (*models.Asset, error) {
+	return service.Upload(context.Background(), bytes.NewReader(content), int64(len(content)), "image/png")
+}
+
+func TestLocalAssetService_Upload(t *testing.T) {
+	t.Run("when the content is an image then stores it under its content hash", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		service := NewLocalAssetService(dir, "/assets/")
+		content := pngContent(t)
+
+		// Act
+		asset, err := upload(service, content)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, strings.HasSuffix(asset.Key, ".png"))
+		assert.Equal(t, "/assets/"+asset.Key, asset.URL)
+		assert.Equal(t, "image/png", asset.ContentType)
+		stored, err := os.ReadFile(filepath.Join(dir, asset.Key[:2], asset.Key))
+		require.NoError(t, err)
+		assert.Equal(t, content, stored)
+	})
+
+	t.Run("when the same content is uploaded twice then returns the same key", func(t *testing.T) {
+		// Arrange
+		service := NewLocalAssetService(t.TempDir(), "/assets")
+		content := pngContent(t)
+
+		// Act
+		first, err := upload(service, content)
+		require.NoError(t, err)
+		second, err := upload(service, content)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, first.Key, second.Key)
+	})
+
+	t.Run("when the content is not an image then returns a validation error", func(t *testing.T) {
+		service := NewLocalAssetService(t.TempDir(), "/assets")
+		_, err := upload(service, []byte("plain text"))
+		assert.Equal(t, &errors.ValidationError{Message: errors.UnsupportedAssetType}, err)
+	})
+
+	t.Run("when the content type is not accepted then returns a validation error", func(t *testing.T) {
+		service := NewLocalAssetService(t.TempDir(), "/assets")
+		content := []byte("plain text")
+		_, err := service.Upload(context.Background(), bytes.NewReader(content), int64(len(content)), "text/plain")
+		assert.Equal(t, &errors.ValidationError{Message: errors.UnsupportedAssetType}, err)
+	})
+
+	t.Run("when the content is shorter than announced then stores nothing", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		service := NewLocalAssetService(dir, "/assets")
+		content := pngContent(t)
+
+		// Act
+		_, err := service.Upload(context.Background(), bytes.NewReader(content), int64(len(content))+1, "image/png")
+
+		// Assert
+		assert.Error(t, err)
+		assets, err := service.List(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, assets)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestLocalAssetService_OpenAndDelete(t *testing.T) {
+	t.Run("when the asset exists then opens and deletes it", func(t *testing.T) {
+		// Arrange
+		service := NewLocalAssetService(t.TempDir(), "/assets")
+		content := pngContent(t)
+		asset, err := upload(service, content)
+		require.NoError(t, err)
+
+		// Act
+		file, err := service.Open(context.Background(), asset.Key)
+		require.NoError(t, err)
+		read, _ := io.ReadAll(file)
+		file.Close()
+
+		// Assert
+		assert.Equal(t, content, read)
+		assert.NoError(t, service.Delete(context.Background(), asset.Key))
+		assert.NoError(t, service.Delete(context.Background(), asset.Key))
+		_, err = service.Open(context.Background(), asset.Key)
+		assert.Equal(t, &errors.RecordNotFoundError{Message: errors.AssetNotFound}, err)
+	})
+
+	t.Run("when the key escapes the storage then returns a validation error", func(t *testing.T) {
+		service := NewLocalAssetService(t.TempDir(), "/assets")
+		_, err := service.Open(context.Background(), "../../etc/passwd")
+		assert.Equal(t, &errors.ValidationError{Message: errors.InvalidAssetKey}, err)
+	})
+}
+
+func TestLocalAssetService_List(t *testing.T) {
+	t.Run("when the storage has assets then lists them with their size and time", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		service := NewLocalAssetService(dir, "/assets")
+		asset, err := upload(service, pngContent(t))
+		require.NoError(t, err)
+		// Temporary files of writes in progress are not assets
+		require.NoError(t, os.WriteFile(filepath.Join(dir, asset.Key[:2], ".upload-123"), []byte("partial"), 0o644))
+
+		// Act
+		assets, err := service.List(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, assets, 1)
+		assert.Equal(t, asset.Key, assets[0].Key)
+		assert.Equal(t, asset.URL, assets[0].URL)
+		assert.Equal(t, asset.Size, assets[0].Size)
+		assert.Equal(t, "image/png", assets[0].ContentType)
+		assert.False(t, assets[0].ModifiedAt.IsZero())
+	})
+
+	t.Run("when the directory was never created then lists nothing", func(t *testing.T) {
+		assets, err := NewLocalAssetService(filepath.Join(t.TempDir(), "missing"), "/assets").List(context.Background())
+
+		assert.NoError(t, err)
+		assert.Empty(t, assets)
+	})
+}
@@ -0,0 +1,565 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// S3 asset service log field constants
+const (
+	S3AssetServiceField         = "s3_asset_service"
+	MultipartUploadSubFuncField = "multipart_upload"
+	AbortMultipartSubFuncField  = "abort_multipart_upload"
+	DeleteStagingSubFuncField   = "delete_staging"
+)
+
+const (
+	// defaultS3PartSize is the size of each part of a multipart upload;
+	// S3 requires at least 5 MiB for every part but the last one
+	defaultS3PartSize = 8 * 1024 * 1024
+	// assetCacheControl lets browsers and CDNs cache content-addressed objects forever
+	assetCacheControl = "public, max-age=31536000, immutable"
+	s3RequestTimeout  = 30 * time.Second
+	// s3StagingPrefix holds the objects being streamed until their content key is known
+	s3StagingPrefix = "staging/"
+)
+
+// S3Config locates the bucket of an S3-compatible storage
+// Requests use path-style URLs (endpoint/bucket/key), which AWS, MinIO and most stand-ins accept
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.us-east-1.amazonaws.com or http://minio:9000
+	Region          string
+	Bucket          string
+	Prefix          string // optional folder for the objects, e.g. images
+	AccessKeyID     string
+	SecretAccessKey string
+	BaseURL         string // public URL of the objects; defaults to endpoint/bucket/prefix
+	PartSize        int    // files larger than this use a multipart upload
+}
+
+// S3AssetService stores assets as content-addressed objects of an S3-compatible bucket
+type S3AssetService struct {
+	client   *http.Client
+	signer   *s3Signer
+	endpoint string
+	bucket   string
+	prefix   string
+	baseURL  string
+	partSize int
+}
+
+func NewS3AssetService(config S3Config) *S3AssetService {
+	endpoint := strings.TrimRight(config.Endpoint, "/")
+	prefix := strings.Trim(config.Prefix, "/")
+
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = endpoint + "/" + config.Bucket
+		if prefix != "" {
+			baseURL += "/" + prefix
+		}
+	}
+
+	region := config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	partSize := config.PartSize
+	if partSize <= 0 {
+		partSize = defaultS3PartSize
+	}
+
+	return &S3AssetService{
+		client: &http.Client{Timeout: s3RequestTimeout},
+		signer: &s3Signer{
+			accessKeyID:     config.AccessKeyID,
+			secretAccessKey: config.SecretAccessKey,
+			region:          region,
+		},
+		endpoint: endpoint,
+		bucket:   config.Bucket,
+		prefix:   prefix,
+		baseURL:  baseURL,
+		partSize: partSize,
+	}
+}
+
+// Upload streams the content into a staging object while hashing it, then copies
+// the object to its content key within the bucket, so the content is never held in memory
+func (s *S3AssetService) Upload(ctx context.Context, content io.Reader, size int64, contentType string) (*models.Asset, error) {
+	content, extension, err := sniffContent(content, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.stream(ctx, newContentHasher(content), size, contentType, extension)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     S3AssetServiceField,
+			"function": UploadFunctionField,
+			"size":     size,
+			"error":    err.Error(),
+		}).Error("Failed to store asset")
+		return nil, fmt.Errorf("asset storage failed")
+	}
+
+	return &models.Asset{
+		Key:         key,
+		URL:         s.URL(key),
+		ContentType: contentType,
+		Size:        size,
+	}, nil
+}
+
+// stream puts the content at a staging key and returns its content key once copied there
+// The staging object is deleted whatever the outcome
+func (s *S3AssetService) stream(ctx context.Context, content *contentHasher, size int64, contentType, extension string) (string, error) {
+	staging, err := newStagingKey()
+	if err != nil {
+		return "", err
+	}
+	defer s.deleteStaging(staging)
+
+	if size > int64(s.partSize) {
+		err = s.multipartUpload(ctx, staging, contentType, content)
+	} else {
+		err = s.putObject(ctx, staging, contentType, content, size)
+	}
+	if err != nil {
+		return "", err
+	}
+	if content.size != size {
+		return "", fmt.Errorf("read %d bytes of the %d announced", content.size, size)
+	}
+
+	key := content.key(extension)
+	// The same content was already stored
+	exists, err := s.exists(ctx, key)
+	if err != nil || exists {
+		return key, err
+	}
+	return key, s.copyObject(ctx, staging, key, contentType)
+}
+
+func newStagingKey() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return s3StagingPrefix + hex.EncodeToString(id), nil
+}
+
+// putObject streams the content in a single request; its size must be known
+// beforehand, and the payload is left unsigned as it is not read before being sent
+func (s *S3AssetService) putObject(ctx context.Context, key, contentType string, content io.Reader, size int64) error {
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	headers.Set("Cache-Control", assetCacheControl)
+
+	resp, err := s.sendStream(ctx, http.MethodPut, s.objectURL(key), nil, headers, content, size, unsignedPayload)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// multipartUpload sends the content in parts of partSize bytes, buffering one part at a time
+// A failed upload is aborted so the bucket does not keep (and bill) the orphan parts
+func (s *S3AssetService) multipartUpload(ctx context.Context, key, contentType string, content io.Reader) error {
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	headers.Set("Cache-Control", assetCacheControl)
+
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, headers, nil)
+	if err != nil {
+		return err
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err = xml.NewDecoder(resp.Body).Decode(&initiated)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := s.uploadParts(ctx, key, initiated.UploadID, content); err != nil {
+		s.abortMultipartUpload(key, initiated.UploadID)
+		return err
+	}
+	return nil
+}
+
+// s3CompletedPart is one part listed in a CompleteMultipartUpload request
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+func (s *S3AssetService) uploadParts(ctx context.Context, key, uploadID string, content io.Reader) error {
+	var parts []s3CompletedPart
+	buffer := make([]byte, s.partSize)
+	for {
+		n, err := io.ReadFull(content, buffer)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		partNumber := len(parts) + 1
+
+		query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+		resp, sendErr := s.do(ctx, http.MethodPut, key, query, nil, buffer[:n])
+		if sendErr != nil {
+			return sendErr
+		}
+		resp.Body.Close()
+
+		parts = append(parts, s3CompletedPart{PartNumber: partNumber, ETag: resp.Header.Get("ETag")})
+		// A short read is the last part
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, nil, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// S3 may answer 200 with an error document when completing fails
+	var completed struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&completed); err != nil {
+		return err
+	}
+	if completed.XMLName.Local == "Error" {
+		return fmt.Errorf("complete multipart upload failed: %s", completed.Code)
+	}
+	return nil
+}
+
+func (s *S3AssetService) abortMultipartUpload(key, uploadID string) {
+	// The request context may be the reason the upload failed
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+
+	resp, err := s.do(ctx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, nil)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":      S3AssetServiceField,
+			"function":  UploadFunctionField,
+			"sub_func":  AbortMultipartSubFuncField,
+			"key":       key,
+			"upload_id": uploadID,
+			"error":     err.Error(),
+		}).Error("Failed to abort multipart upload")
+		return
+	}
+	resp.Body.Close()
+}
+
+func (s *S3AssetService) exists(ctx context.Context, key string) (bool, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, nil, nil)
+	if err != nil {
+		if isS3NotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// copyObject copies the object within the bucket, replacing its metadata
+func (s *S3AssetService) copyObject(ctx context.Context, source, key, contentType string) error {
+	headers := http.Header{}
+	headers.Set("X-Amz-Copy-Source", "/"+uriEncode(s.bucket, true)+"/"+uriEncode(s.objectKey(source), false))
+	headers.Set("X-Amz-Metadata-Directive", "REPLACE")
+	headers.Set("Content-Type", contentType)
+	headers.Set("Cache-Control", assetCacheControl)
+
+	resp, err := s.do(ctx, http.MethodPut, key, nil, headers, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Like completing a multipart upload, a copy may answer 200 with an error document
+	var copied struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&copied); err != nil {
+		return err
+	}
+	if copied.XMLName.Local == "Error" {
+		return fmt.Errorf("copy object failed: %s", copied.Code)
+	}
+	return nil
+}
+
+func (s *S3AssetService) deleteStaging(key string) {
+	// The request context may be the reason the upload failed
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     S3AssetServiceField,
+			"function": UploadFunctionField,
+			"sub_func": DeleteStagingSubFuncField,
+			"key":      key,
+			"error":    err.Error(),
+		}).Error("Failed to delete staging object")
+		return
+	}
+	resp.Body.Close()
+}
+
+// Delete removes the object; S3 answers deletes of missing objects with success
+func (s *S3AssetService) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil
+		}
+		logs.WithFields(map[string]interface{}{
+			"file":     S3AssetServiceField,
+			"function": DeleteFunctionField,
+			"key":      key,
+			"error":    err.Error(),
+		}).Error("Failed to delete asset")
+		return fmt.Errorf("asset storage failed")
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3AssetService) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, &errors.RecordNotFoundError{Message: errors.AssetNotFound}
+		}
+		logs.WithFields(map[string]interface{}{
+			"file":     S3AssetServiceField,
+			"function": OpenFunctionField,
+			"key":      key,
+			"error":    err.Error(),
+		}).Error("Failed to open asset")
+		return nil, fmt.Errorf("asset storage failed")
+	}
+
+	return resp.Body, nil
+}
+
+func (s *S3AssetService) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// s3StatusError is a non successful answer of the storage
+type s3StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *s3StatusError) Error() string {
+	return fmt.Sprintf("s3 responded %d: %s", e.StatusCode, e.Body)
+}
+
+func isS3NotFound(err error) bool {
+	statusErr, ok := err.(*s3StatusError)
+	return ok && statusErr.StatusCode == http.StatusNotFound
+}
+
+// s3ListBucketResult is a page of a ListObjectsV2 answer
+type s3ListBucketResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+// List pages through the objects under the prefix (ListObjectsV2)
+// Direct uploads waiting to be finalized and foreign objects are skipped
+func (s *S3AssetService) List(ctx context.Context) ([]*models.Asset, error) {
+	bucketURL := s.endpoint + "/" + uriEncode(s.bucket, true)
+	prefix := ""
+	if s.prefix != "" {
+		prefix = s.prefix + "/"
+	}
+
+	var assets []*models.Asset
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		page, err := s.listPage(ctx, bucketURL, query)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     S3AssetServiceField,
+				"function": ListFunctionField,
+				"error":    err.Error(),
+			}).Error("Failed to list assets")
+			return nil, fmt.Errorf("asset storage failed")
+		}
+
+		for _, object := range page.Contents {
+			key := strings.TrimPrefix(object.Key, prefix)
+			if !isContentKey(key) {
+				continue
+			}
+			assets = append(assets, &models.Asset{
+				Key:         key,
+				URL:         s.URL(key),
+				ContentType: contentTypeOf(key),
+				Size:        object.Size,
+				ModifiedAt:  object.LastModified,
+			})
+		}
+
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return assets, nil
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+func (s *S3AssetService) listPage(ctx context.Context, bucketURL string, query url.Values) (*s3ListBucketResult, error) {
+	resp, err := s.send(ctx, http.MethodGet, bucketURL, query, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var page s3ListBucketResult
+	if err := xml.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// PresignUpload signs a PUT of the object the client sends straight to the bucket
+// Content-Type and Content-Length are signed, so the storage rejects any other file
+func (s *S3AssetService) PresignUpload(_ context.Context, key, contentType string, size int64, expires time.Duration) (*models.SignedURL, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	target, err := url.Parse(s.objectURL(key))
+	if err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	headers.Set("Content-Length", strconv.FormatInt(size, 10))
+
+	now := time.Now()
+	signed := &models.SignedURL{
+		URL:       s.signer.presign(http.MethodPut, target, headers, expires, now),
+		Method:    http.MethodPut,
+		Headers:   make(map[string]string, len(headers)),
+		ExpiresAt: now.Add(expires),
+	}
+	for name := range headers {
+		signed.Headers[name] = headers.Get(name)
+	}
+	return signed, nil
+}
+
+// objectKey is the key of the object in the bucket, under the prefix
+func (s *S3AssetService) objectKey(key string) string {
+	if s.prefix != "" {
+		return s.prefix + "/" + key
+	}
+	return key
+}
+
+// objectURL is the path-style URL of the object in the bucket
+func (s *S3AssetService) objectURL(key string) string {
+	return s.endpoint + "/" + uriEncode(s.bucket, true) + "/" + uriEncode(s.objectKey(key), false)
+}
+
+// do sends a signed request for the object and fails on any non 2xx answer
+func (s *S3AssetService) do(ctx context.Context, method, key string, query url.Values, headers http.Header, body []byte) (*http.Response, error) {
+	return s.send(ctx, method, s.objectURL(key), query, headers, body)
+}
+
+// send signs and sends a request to the target, an object or the bucket itself
+func (s *S3AssetService) send(ctx context.Context, method, target string, query url.Values, headers http.Header, body []byte) (*http.Response, error) {
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		payloadHash = hashHex(body)
+	}
+	return s.sendStream(ctx, method, target, query, headers, bytes.NewReader(body), int64(len(body)), payloadHash)
+}
+
+// sendStream signs and sends a request whose body of the given size is read as it is sent
+func (s *S3AssetService) sendStream(ctx context.Context, method, target string, query url.Values, headers http.Header, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	s.signer.sign(req, payloadHash, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &s3StatusError{StatusCode: resp.StatusCode, Body: string(message)}
+	}
+
+	return resp, nil
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 
-	"github.com/mlgaray/ecommerce_api/internal/core/entities"
 	"github.com/mlgaray/ecommerce_api/internal/core/errors"
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
 )
@@ -23,7 +22,10 @@ func (j *TokenService) Generate(ctx context.Context, user *models.User) (string,
 		return "", &errors.ValidationError{Message: errors.InvalidInput}
 	}
 
-	userJSON, err := json.Marshal(user)
+	// The token identifies the user; credentials never leave the server
+	claimed := *user
+	claimed.Password = ""
+	userJSON, err := json.Marshal(&claimed)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal user data: %w", err)
 	}
@@ -42,7 +44,7 @@ func (j *TokenService) Generate(ctx context.Context, user *models.User) (string,
 	return signedToken, nil
 }
 
-func (j *TokenService) VerifyToken(token string) (*entities.User, error) {
+func (j *TokenService) VerifyToken(_ context.Context, token string) (*models.User, error) {
 	if token == "" {
 		return nil, &errors.ValidationError{Message: errors.TokenCannotBeEmpty}
 	}
@@ -66,14 +68,20 @@ func (j *TokenService) VerifyToken(token string) (*entities.User, error) {
 		return nil, &errors.AuthenticationError{Message: errors.TokenInvalid}
 	}
 
-	// claims, ok := parse.Claims.(jwt.MapClaims)
-	/*
-		if !ok {
-			return nil, &errors.AuthenticationError{Message: "could not get claims"}
-		}*/
+	claims, ok := parse.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, &errors.AuthenticationError{Message: errors.TokenInvalid}
+	}
+	userJSON, ok := claims["user"].(string)
+	if !ok {
+		return nil, &errors.AuthenticationError{Message: errors.TokenInvalid}
+	}
 
-	// email := claims["email"].(string)
-	return nil, nil
+	user := &models.User{}
+	if err := json.Unmarshal([]byte(userJSON), user); err != nil || user.ID <= 0 {
+		return nil, &errors.AuthenticationError{Message: errors.TokenInvalid}
+	}
+	return user, nil
 }
 
 func NewTokenService() *TokenService {
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Asset handler log field constants
+const (
+	AssetHandlerField                  = "asset_handler"
+	ServeAssetFunctionField            = "serve"
+	CollectOrphanedAssetsFunctionField = "collect_orphans"
+)
+
+// defaultAssetGCGraceHours keeps the images of requests in progress when no grace period is given
+const defaultAssetGCGraceHours = 24
+
+type AssetHandler struct {
+	assetService          ports.AssetService
+	collectOrphanedAssets ports.CollectOrphanedAssetsUseCase
+}
+
+func NewAssetHandler(assetService ports.AssetService, collectOrphanedAssetsUseCase ports.CollectOrphanedAssetsUseCase) *AssetHandler {
+	return &AssetHandler{
+		assetService:          assetService,
+		collectOrphanedAssets: collectOrphanedAssetsUseCase,
+	}
+}
+
+// Serve streams a stored asset
+// Keys are content-addressed, so the response never changes and can be cached forever
+func (h *AssetHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+
+	content, err := h.assetService.Open(r.Context(), key)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(key)))
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     AssetHandlerField,
+			"function": ServeAssetFunctionField,
+			"sub_func": "io.Copy",
+			"key":      key,
+			"error":    err.Error(),
+		}).Error("Error writing asset")
+	}
+}
+
+// CollectOrphans deletes the stored assets no longer referenced, or only reports them on a dry run
+func (h *AssetHandler) CollectOrphans(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	graceHours := defaultAssetGCGraceHours
+	if graceStr := r.URL.Query().Get("grace_hours"); graceStr != "" {
+		parsed, err := strconv.Atoi(graceStr)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":        AssetHandlerField,
+				"function":    CollectOrphanedAssetsFunctionField,
+				"sub_func":    "strconv.Atoi",
+				"grace_hours": graceStr,
+				"error":       err.Error(),
+			}).Error("Invalid grace_hours parameter")
+			httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_grace_hours_format"})
+			return
+		}
+		graceHours = parsed
+	}
+
+	dryRun := false
+	if dryRunStr := r.URL.Query().Get("dry_run"); dryRunStr != "" {
+		parsed, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     AssetHandlerField,
+				"function": CollectOrphanedAssetsFunctionField,
+				"sub_func": "strconv.ParseBool",
+				"dry_run":  dryRunStr,
+				"error":    err.Error(),
+			}).Error("Invalid dry_run parameter")
+			httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_dry_run_format"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	report, err := h.collectOrphanedAssets.Execute(ctx, time.Duration(graceHours)*time.Hour, dryRun)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":        AssetHandlerField,
+			"function":    CollectOrphanedAssetsFunctionField,
+			"grace_hours": graceHours,
+			"dry_run":     dryRun,
+			"error":       err.Error(),
+		}).Error("Error collecting orphaned assets")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     AssetHandlerField,
+			"function": CollectOrphanedAssetsFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
@@ -0,0 +1,288 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/middleware"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Cart handler log field constants
+const (
+	CartHandlerField             = "cart_handler"
+	GetCartFunctionField         = "get"
+	AddCartLineFunctionField     = "add_line"
+	UpdateCartLineFunctionField  = "update_line"
+	RemoveCartLineFunctionField  = "remove_line"
+	ClearCartFunctionField       = "clear"
+	PurgeCartsFunctionField      = "purge_expired"
+	ParseCartRequestSubFuncField = "parse_cart_request"
+)
+
+// Cart owner header
+// Signed in customers are identified by their bearer token; anonymous clients
+// send back the X-Cart-Token they received when the cart was opened
+const CartTokenHeader = "X-Cart-Token"
+
+type CartHandler struct {
+	getCart        ports.GetCartUseCase
+	addCartLine    ports.AddCartLineUseCase
+	updateCartLine ports.UpdateCartLineUseCase
+	removeCartLine ports.RemoveCartLineUseCase
+	clearCart      ports.ClearCartUseCase
+	purgeCarts     ports.PurgeCartsUseCase
+}
+
+func NewCartHandler(getCartUseCase ports.GetCartUseCase, addCartLineUseCase ports.AddCartLineUseCase, updateCartLineUseCase ports.UpdateCartLineUseCase, removeCartLineUseCase ports.RemoveCartLineUseCase, clearCartUseCase ports.ClearCartUseCase, purgeCartsUseCase ports.PurgeCartsUseCase) *CartHandler {
+	return &CartHandler{
+		getCart:        getCartUseCase,
+		addCartLine:    addCartLineUseCase,
+		updateCartLine: updateCartLineUseCase,
+		removeCartLine: removeCartLineUseCase,
+		clearCart:      clearCartUseCase,
+		purgeCarts:     purgeCartsUseCase,
+	}
+}
+
+func (h *CartHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	shopID, owner, err := h.parseCartRequest(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	cart, err := h.getCart.Execute(ctx, shopID, owner)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartHandlerField,
+			"function": GetCartFunctionField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Error getting cart")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	h.writeCart(w, cart, GetCartFunctionField)
+}
+
+// AddLine adds a configured product to the cart, opening the cart on the first line
+func (h *CartHandler) AddLine(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	shopID, owner, err := h.parseCartRequest(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	var request contracts.CartLineAddRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartHandlerField,
+			"function": AddCartLineFunctionField,
+			"sub_func": "json.Decode",
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Error decoding cart line request")
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_json_format"})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	cart, err := h.addCartLine.Execute(ctx, shopID, owner, request.ToCartLine())
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       CartHandlerField,
+			"function":   AddCartLineFunctionField,
+			"shop_id":    shopID,
+			"product_id": request.ProductID,
+			"error":      err.Error(),
+		}).Error("Error adding cart line")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	h.writeCart(w, cart, AddCartLineFunctionField)
+}
+
+func (h *CartHandler) UpdateLine(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	shopID, owner, err := h.parseCartRequest(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	lineID, err := parsePathID(r, "line_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	var request contracts.CartLineUpdateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartHandlerField,
+			"function": UpdateCartLineFunctionField,
+			"sub_func": "json.Decode",
+			"shop_id":  shopID,
+			"line_id":  lineID,
+			"error":    err.Error(),
+		}).Error("Error decoding cart line request")
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_json_format"})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	cart, err := h.updateCartLine.Execute(ctx, shopID, owner, lineID, *request.Quantity, request.Selections)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartHandlerField,
+			"function": UpdateCartLineFunctionField,
+			"shop_id":  shopID,
+			"line_id":  lineID,
+			"error":    err.Error(),
+		}).Error("Error updating cart line")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	h.writeCart(w, cart, UpdateCartLineFunctionField)
+}
+
+func (h *CartHandler) RemoveLine(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	shopID, owner, err := h.parseCartRequest(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	lineID, err := parsePathID(r, "line_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	cart, err := h.removeCartLine.Execute(ctx, shopID, owner, lineID)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartHandlerField,
+			"function": RemoveCartLineFunctionField,
+			"shop_id":  shopID,
+			"line_id":  lineID,
+			"error":    err.Error(),
+		}).Error("Error removing cart line")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	h.writeCart(w, cart, RemoveCartLineFunctionField)
+}
+
+func (h *CartHandler) Clear(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	shopID, owner, err := h.parseCartRequest(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	cart, err := h.clearCart.Execute(ctx, shopID, owner)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartHandlerField,
+			"function": ClearCartFunctionField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Error clearing cart")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	h.writeCart(w, cart, ClearCartFunctionField)
+}
+
+func (h *CartHandler) PurgeExpired(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	purged, err := h.purgeCarts.Execute(ctx)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartHandlerField,
+			"function": PurgeCartsFunctionField,
+			"error":    err.Error(),
+		}).Error("Error purging expired carts")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(contracts.CartPurgeResponse{Purged: purged}); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartHandlerField,
+			"function": PurgeCartsFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+// parseCartRequest reads the shop of the cart and its owner
+func (h *CartHandler) parseCartRequest(r *http.Request) (int, models.CartOwner, error) {
+	shopID, err := parsePathID(r, "shop_id")
+	if err != nil {
+		return 0, models.CartOwner{}, err
+	}
+
+	owner, err := contracts.ParseCartOwner(middleware.UserFrom(r.Context()), r.Header.Get(CartTokenHeader))
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartHandlerField,
+			"function": ParseCartRequestSubFuncField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Invalid cart owner headers")
+		return 0, models.CartOwner{}, err
+	}
+
+	return shopID, owner, nil
+}
+
+// writeCart responds with the priced cart; anonymous carts echo their token so
+// the client can send it back on the next request
+func (h *CartHandler) writeCart(w http.ResponseWriter, cart *models.Cart, function string) {
+	if cart.Token != "" {
+		w.Header().Set(CartTokenHeader, cart.Token)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(cart); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartHandlerField,
+			"function": function,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
@@ -0,0 +1,91 @@
+package contracts
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+// cartTokenPattern matches the tokens issued for anonymous carts
+var cartTokenPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// CartLineAddRequest represents the HTTP request to add a configured product to the cart
+// quantity defaults to 1 when omitted
+type CartLineAddRequest struct {
+	ProductID  int                       `json:"product_id"`
+	Quantity   *int                      `json:"quantity,omitempty"`
+	Selections []models.VariantSelection `json:"selections"`
+}
+
+func (r *CartLineAddRequest) Validate() error {
+	// HTTP validation: required fields
+	if r.ProductID <= 0 {
+		return &httpErrors.BadRequestError{Message: "product_id_is_required"}
+	}
+
+	// Note: Business validations (selection rules, availability, quantity)
+	// are handled by Product.Quote() in the cart service
+	return validateSelections(r.Selections)
+}
+
+// ToCartLine converts the HTTP request into the domain line
+func (r *CartLineAddRequest) ToCartLine() *models.CartLine {
+	quantity := 1
+	if r.Quantity != nil {
+		quantity = *r.Quantity
+	}
+	return &models.CartLine{
+		ProductID:  r.ProductID,
+		Selections: r.Selections,
+		Quantity:   quantity,
+	}
+}
+
+// CartLineUpdateRequest represents the HTTP request to change a cart line
+// selections are kept when omitted
+type CartLineUpdateRequest struct {
+	Quantity   *int                      `json:"quantity"`
+	Selections []models.VariantSelection `json:"selections,omitempty"`
+}
+
+func (r *CartLineUpdateRequest) Validate() error {
+	// HTTP validation: required fields
+	if r.Quantity == nil {
+		return &httpErrors.BadRequestError{Message: "quantity_is_required"}
+	}
+
+	return validateSelections(r.Selections)
+}
+
+// validateSelections checks that every selection names its variant
+func validateSelections(selections []models.VariantSelection) error {
+	for _, selection := range selections {
+		if selection.VariantID <= 0 {
+			return &httpErrors.BadRequestError{Message: "variant_id_is_required"}
+		}
+	}
+	return nil
+}
+
+// ParseCartOwner reads the owner of the cart from the authenticated customer and the request headers
+// The signed in customer wins over the anonymous cart token; both may be missing
+// before the first line is added
+func ParseCartOwner(customer *models.User, token string) (models.CartOwner, error) {
+	if customer != nil {
+		return models.CartOwner{CustomerID: customer.ID}, nil
+	}
+
+	token = strings.TrimSpace(token)
+
+	if token != "" && !cartTokenPattern.MatchString(token) {
+		return models.CartOwner{}, &httpErrors.BadRequestError{Message: "invalid_cart_token_format"}
+	}
+	return models.CartOwner{Token: token}, nil
+}
+
+// CartPurgeResponse represents the HTTP response of the expired carts purge
+type CartPurgeResponse struct {
+	Purged int `json:"purged"`
+}
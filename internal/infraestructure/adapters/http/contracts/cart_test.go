@@ -0,0 +1,48 @@
+package contracts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+func TestParseCartOwner(t *testing.T) {
+	token := "0123456789abcdef0123456789abcdef"
+
+	t.Run("when the customer is signed in then it wins over the token", func(t *testing.T) {
+		owner, err := ParseCartOwner(&models.User{ID: 7}, token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.CartOwner{CustomerID: 7}, owner)
+	})
+
+	t.Run("when only the token is sent then returns an anonymous owner", func(t *testing.T) {
+		owner, err := ParseCartOwner(nil, token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.CartOwner{Token: token}, owner)
+	})
+
+	t.Run("when nothing is sent then returns an unknown owner", func(t *testing.T) {
+		owner, err := ParseCartOwner(nil, "")
+
+		assert.NoError(t, err)
+		assert.False(t, owner.IsKnown())
+	})
+
+	t.Run("when the token was not issued by the API then returns bad request error", func(t *testing.T) {
+		_, err := ParseCartOwner(nil, "not-a-token")
+
+		assert.Equal(t, &httpErrors.BadRequestError{Message: "invalid_cart_token_format"}, err)
+	})
+}
+
+func TestCartLineAddRequest_ToCartLine(t *testing.T) {
+	request := CartLineAddRequest{ProductID: 3}
+
+	assert.NoError(t, request.Validate())
+	assert.Equal(t, 1, request.ToCartLine().Quantity)
+}
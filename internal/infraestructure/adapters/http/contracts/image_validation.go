@@ -0,0 +1,69 @@
+package contracts
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg" // register decoders for DecodeConfig
+	_ "image/png"
+	"io"
+	"net/http"
+
+	_ "golang.org/x/image/webp"
+
+	domainErrors "github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+const (
+	maxImageBytes = 3 * 1024 * 1024
+	// minImageDimension keeps thumbnails and icons out of the catalog
+	minImageDimension = 100
+	// maxImageDimension and maxImagePixels reject decompression bombs: a few KB
+	// can declare a huge canvas that would take gigabytes once decoded
+	maxImageDimension = 10000
+	maxImagePixels    = 40_000_000
+)
+
+// imageFormats is the allow-list of sniffed content types and the decoder that must agree with each
+var imageFormats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// validateImageContent sniffs the magic bytes and decodes only the image header,
+// so the pixels are never allocated here. It returns the rejection code, empty if valid.
+// Images are checked by their content, never by the filename or the part Content-Type
+func validateImageContent(content io.Reader) (string, error) {
+	buffer := make([]byte, 512)
+	n, err := io.ReadFull(content, buffer)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	format, ok := imageFormats[http.DetectContentType(buffer[:n])]
+	if !ok {
+		return domainErrors.UnsupportedImageFormat, nil
+	}
+
+	config, decoded, err := image.DecodeConfig(io.MultiReader(bytes.NewReader(buffer[:n]), content))
+	if err != nil || decoded != format {
+		return domainErrors.InvalidImage, nil
+	}
+
+	switch {
+	case config.Width > maxImageDimension || config.Height > maxImageDimension,
+		config.Width*config.Height > maxImagePixels:
+		return domainErrors.ImageTooLarge, nil
+	case config.Width < minImageDimension || config.Height < minImageDimension:
+		return domainErrors.ImageTooSmall, nil
+	}
+
+	return "", nil
+}
+
+func imageFieldError(field, message string) error {
+	return &domainErrors.FieldValidationError{
+		Message: message,
+		Fields:  []domainErrors.FieldError{{Field: field, Message: message}},
+	}
+}
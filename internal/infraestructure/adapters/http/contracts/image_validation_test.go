@@ -0,0 +1,58 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainErrors "github.com/mlgaray/ecommerce_api/internal/core/errors"
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	var buffer bytes.Buffer
+	require.NoError(t, png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buffer.Bytes()
+}
+
+// pngHeader builds a PNG that only declares its size, as a decompression bomb does
+func pngHeader(width, height uint32) []byte {
+	chunk := []byte("IHDR")
+	chunk = binary.BigEndian.AppendUint32(chunk, width)
+	chunk = binary.BigEndian.AppendUint32(chunk, height)
+	chunk = append(chunk, 8, 6, 0, 0, 0)
+
+	content := []byte("\x89PNG\r\n\x1a\n")
+	content = binary.BigEndian.AppendUint32(content, uint32(len(chunk)-4))
+	content = append(content, chunk...)
+	return binary.BigEndian.AppendUint32(content, crc32.ChecksumIEEE(chunk))
+}
+
+func TestValidateImageContent(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  []byte
+		expected string
+	}{
+		{name: "when the image is within the limits then accepts it", content: pngImage(t, 100, 100), expected: ""},
+		{name: "when the header declares a huge canvas then returns too large", content: pngHeader(50000, 50000), expected: domainErrors.ImageTooLarge},
+		{name: "when a side is above the maximum then returns too large", content: pngHeader(maxImageDimension+1, 200), expected: domainErrors.ImageTooLarge},
+		{name: "when a side is below the minimum then returns too small", content: pngImage(t, 99, 300), expected: domainErrors.ImageTooSmall},
+		{name: "when the header is truncated then returns invalid image", content: []byte("\x89PNG\r\n\x1a\n"), expected: domainErrors.InvalidImage},
+		{name: "when the magic bytes are not allowed then returns unsupported format", content: []byte("GIF89a"), expected: domainErrors.UnsupportedImageFormat},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			message, err := validateImageContent(bytes.NewReader(tc.content))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, message)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package contracts
+
+// MessageResponse represents an HTTP response that only carries a message code
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,193 @@
+package contracts
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+const (
+	// MaxUploadRequestBytes bounds a whole product upload (e.g. 4 images of 3MB each + product data)
+	MaxUploadRequestBytes = 13 << 20
+	// maxFormFieldBytes bounds each text field, such as the product JSON
+	maxFormFieldBytes = 1 << 20
+)
+
+// ReadFormFields reads the text fields of a multipart request up to its first file.
+// Fields sent after the images are not read, so clients must send product and shop_id first.
+// The first file part is returned to be streamed by MultipartImageUploads
+func ReadFormFields(reader *multipart.Reader) (map[string]string, *multipart.Part, error) {
+	fields := make(map[string]string)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return fields, nil, nil
+		}
+		if err != nil {
+			return nil, nil, multipartError(err)
+		}
+		if part.FileName() != "" {
+			return fields, part, nil
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxFormFieldBytes+1))
+		if err != nil {
+			return nil, nil, multipartError(err)
+		}
+		if len(value) > maxFormFieldBytes {
+			return nil, nil, &httpErrors.BadRequestError{Message: "form_field_too_large"}
+		}
+		fields[part.FormName()] = string(value)
+	}
+}
+
+// MultipartImageUploads streams the images[i] file parts of a request straight from the
+// body: each image is validated by its header and read once by whoever consumes it
+type MultipartImageUploads struct {
+	reader   *multipart.Reader
+	pending  *multipart.Part
+	done     bool
+	required bool
+	count    int
+	current  *limitedImage
+}
+
+// NewMultipartImageUploads streams the images starting at the first file part, nil when
+// the body had none; when required, a request without images fails once the stream ends
+func NewMultipartImageUploads(reader *multipart.Reader, first *multipart.Part, required bool) *MultipartImageUploads {
+	return &MultipartImageUploads{reader: reader, pending: first, done: first == nil, required: required}
+}
+
+func (u *MultipartImageUploads) Next() (*models.ImageUpload, error) {
+	// Drain what the consumer left unread (e.g. bytes after the end of the image),
+	// so the size limit holds for the whole part
+	if u.current != nil {
+		if _, err := io.Copy(io.Discard, u.current); err != nil {
+			return nil, err
+		}
+		u.current = nil
+	}
+
+	for {
+		part, err := u.nextPart()
+		if err == io.EOF {
+			if u.required && u.count == 0 {
+				return nil, &httpErrors.BadRequestError{Message: "at_least_one_image_is_required"}
+			}
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Other parts are skipped without being buffered
+		if part.FileName() == "" || !strings.HasPrefix(part.FormName(), "images[") {
+			continue
+		}
+
+		u.count++
+		u.current = &limitedImage{field: part.FormName(), reader: part, remaining: maxImageBytes}
+		content, err := validateImageStream(u.current)
+		if err != nil {
+			return nil, err
+		}
+		return &models.ImageUpload{Field: part.FormName(), Content: content}, nil
+	}
+}
+
+// Err reports the size limit or body error that cut the current image short
+func (u *MultipartImageUploads) Err() error {
+	if u.current == nil {
+		return nil
+	}
+	return u.current.err
+}
+
+func (u *MultipartImageUploads) nextPart() (*multipart.Part, error) {
+	if u.done {
+		return nil, io.EOF
+	}
+	if u.pending != nil {
+		part := u.pending
+		u.pending = nil
+		return part, nil
+	}
+	part, err := u.reader.NextPart()
+	if err == io.EOF {
+		u.done = true
+	}
+	if err != nil && err != io.EOF {
+		return nil, multipartError(err)
+	}
+	return part, err
+}
+
+// validateImageStream checks the image header of the stream and returns a reader that
+// replays the bytes it consumed followed by the rest of the image
+func validateImageStream(image *limitedImage) (io.Reader, error) {
+	var header bytes.Buffer
+	message, err := validateImageContent(io.TeeReader(image, &header))
+	if image.err != nil {
+		return nil, image.err
+	}
+	if err != nil {
+		return nil, multipartError(err)
+	}
+	if message != "" {
+		return nil, imageFieldError(image.field, message)
+	}
+	return io.MultiReader(&header, image), nil
+}
+
+// limitedImage reads an image part and fails once it goes over the per file limit
+type limitedImage struct {
+	field     string
+	reader    io.Reader
+	remaining int64
+	err       error
+}
+
+func (l *limitedImage) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	if l.remaining <= 0 {
+		// Probe one byte to tell an image of exactly the limit from a larger one
+		var probe [1]byte
+		n, err := io.ReadFull(l.reader, probe[:])
+		if n > 0 {
+			l.err = imageFieldError(l.field, "image_size_too_large_max_3mb")
+			return 0, l.err
+		}
+		if err != io.EOF {
+			l.err = multipartError(err)
+			return 0, l.err
+		}
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.reader.Read(p)
+	l.remaining -= int64(n)
+	if err != nil && err != io.EOF {
+		l.err = multipartError(err)
+		return n, l.err
+	}
+	return n, err
+}
+
+// multipartError maps body read failures: an oversized request is 413, anything else a malformed form
+func multipartError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return &httpErrors.RequestEntityTooLargeError{Message: "request_too_large"}
+	}
+	return &httpErrors.BadRequestError{Message: "error_parsing_multipart_form"}
+}
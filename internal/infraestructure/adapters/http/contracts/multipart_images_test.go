@@ -0,0 +1,139 @@
+package contracts
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainErrors "github.com/mlgaray/ecommerce_api/internal/core/errors"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+// multipartBody writes the product fields followed by the contents as images[i] parts
+func multipartBody(t *testing.T, contents ...[]byte) *multipart.Reader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("product", `{"name":"Burger"}`))
+	require.NoError(t, writer.WriteField("shop_id", "1"))
+	for i, content := range contents {
+		part, err := writer.CreateFormFile(fmt.Sprintf("images[%d]", i), "image.png")
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	return multipart.NewReader(&body, writer.Boundary())
+}
+
+func imageUploads(t *testing.T, required bool, contents ...[]byte) *MultipartImageUploads {
+	reader := multipartBody(t, contents...)
+	_, first, err := ReadFormFields(reader)
+	require.NoError(t, err)
+	return NewMultipartImageUploads(reader, first, required)
+}
+
+func TestReadFormFields(t *testing.T) {
+	t.Run("when the fields precede the images then returns them and the first image part", func(t *testing.T) {
+		fields, first, err := ReadFormFields(multipartBody(t, pngImage(t, 100, 100)))
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"product": `{"name":"Burger"}`, "shop_id": "1"}, fields)
+		assert.Equal(t, "images[0]", first.FormName())
+	})
+}
+
+func TestMultipartImageUploads_Next(t *testing.T) {
+	t.Run("when the images are valid then streams each one with its content intact", func(t *testing.T) {
+		// Arrange
+		first, second := pngImage(t, 120, 100), pngImage(t, 300, 200)
+		uploads := imageUploads(t, true, first, second)
+
+		// Act & Assert
+		for i, expected := range [][]byte{first, second} {
+			upload, err := uploads.Next()
+			require.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("images[%d]", i), upload.Field)
+
+			content, err := io.ReadAll(upload.Content)
+			require.NoError(t, err)
+			assert.Equal(t, expected, content)
+		}
+		_, err := uploads.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("when an image is not an allowed format then names its field", func(t *testing.T) {
+		// Arrange
+		var gifContent bytes.Buffer
+		require.NoError(t, gif.Encode(&gifContent, image.NewRGBA(image.Rect(0, 0, 120, 120)), nil))
+		uploads := imageUploads(t, true, pngImage(t, 120, 120), gifContent.Bytes())
+
+		// Act
+		_, firstErr := uploads.Next()
+		_, err := uploads.Next()
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.Equal(t, &domainErrors.FieldValidationError{
+			Message: domainErrors.UnsupportedImageFormat,
+			Fields:  []domainErrors.FieldError{{Field: "images[1]", Message: domainErrors.UnsupportedImageFormat}},
+		}, err)
+	})
+
+	t.Run("when an image goes over the size limit then the stream fails and Err names its field", func(t *testing.T) {
+		// Arrange
+		oversized := append(pngImage(t, 100, 100), make([]byte, maxImageBytes)...)
+		uploads := imageUploads(t, true, oversized)
+
+		// Act
+		upload, err := uploads.Next()
+		require.NoError(t, err)
+		_, readErr := io.ReadAll(upload.Content)
+
+		// Assert
+		expected := imageFieldError("images[0]", "image_size_too_large_max_3mb")
+		assert.Equal(t, expected, readErr)
+		assert.Equal(t, expected, uploads.Err())
+	})
+
+	t.Run("when the request goes over its size limit then returns request too large", func(t *testing.T) {
+		// Arrange
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("images[0]", "image.png")
+		require.NoError(t, err)
+		_, err = part.Write(pngImage(t, 100, 100))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		limited := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(&body), 200)
+		reader := multipart.NewReader(limited, writer.Boundary())
+
+		// Act
+		_, first, err := ReadFormFields(reader)
+		require.NoError(t, err)
+		_, err = NewMultipartImageUploads(reader, first, true).Next()
+
+		// Assert
+		assert.Equal(t, &httpErrors.RequestEntityTooLargeError{Message: "request_too_large"}, err)
+	})
+
+	t.Run("when images are required and none was sent then fails at the end of the stream", func(t *testing.T) {
+		_, err := imageUploads(t, true).Next()
+		assert.Equal(t, &httpErrors.BadRequestError{Message: "at_least_one_image_is_required"}, err)
+	})
+
+	t.Run("when images are optional and none was sent then ends the stream", func(t *testing.T) {
+		_, err := imageUploads(t, false).Next()
+		assert.Equal(t, io.EOF, err)
+	})
+}
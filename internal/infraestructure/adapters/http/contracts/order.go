@@ -0,0 +1,125 @@
+package contracts
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+// MaxOrderNotesLength is the longest note a customer can leave with an order
+const MaxOrderNotesLength = 500
+
+// OrderCreateRequest represents the HTTP request to place an order
+// The order is placed either from the listed items or from the cart of the requester
+type OrderCreateRequest struct {
+	FromCart bool               `json:"from_cart"`
+	Items    []OrderItemRequest `json:"items"`
+	Notes    string             `json:"notes"`
+}
+
+// OrderItemRequest represents a configured product of a direct order
+// quantity defaults to 1 when omitted
+type OrderItemRequest struct {
+	ProductID  int                       `json:"product_id"`
+	Quantity   *int                      `json:"quantity,omitempty"`
+	Selections []models.VariantSelection `json:"selections"`
+}
+
+func (r *OrderCreateRequest) Validate() error {
+	// HTTP validation: exactly one source of items
+	if r.FromCart && len(r.Items) > 0 {
+		return &httpErrors.BadRequestError{Message: "items_and_from_cart_are_mutually_exclusive"}
+	}
+	if !r.FromCart && len(r.Items) == 0 {
+		return &httpErrors.BadRequestError{Message: "items_or_from_cart_is_required"}
+	}
+	if utf8.RuneCountInString(r.Notes) > MaxOrderNotesLength {
+		return &httpErrors.BadRequestError{Message: "notes_too_long"}
+	}
+
+	for _, item := range r.Items {
+		if item.ProductID <= 0 {
+			return &httpErrors.BadRequestError{Message: "product_id_is_required"}
+		}
+		if err := validateSelections(item.Selections); err != nil {
+			return err
+		}
+	}
+
+	// Note: Business validations (selection rules, availability, quantity)
+	// are handled by Product.Quote() in the order service
+	return nil
+}
+
+// ToOrderDraft converts the HTTP request into the domain draft of the customer
+// Orders from the cart are placed with the cart of the owner
+func (r *OrderCreateRequest) ToOrderDraft(owner models.CartOwner) *models.OrderDraft {
+	draft := &models.OrderDraft{
+		CustomerID: owner.CustomerID,
+		Notes:      r.Notes,
+	}
+	if r.FromCart {
+		draft.Cart = &owner
+		return draft
+	}
+
+	for _, item := range r.Items {
+		quantity := 1
+		if item.Quantity != nil {
+			quantity = *item.Quantity
+		}
+		draft.Lines = append(draft.Lines, models.OrderLine{
+			ProductID:  item.ProductID,
+			Selections: item.Selections,
+			Quantity:   quantity,
+		})
+	}
+	return draft
+}
+
+// OrderTransitionRequest represents the HTTP request to move an order to its next status
+// The actor of the change is the authenticated caller, never a field of the request
+type OrderTransitionRequest struct {
+	Status string `json:"status"`
+}
+
+func (r *OrderTransitionRequest) Validate() error {
+	// HTTP validation: required fields
+	if strings.TrimSpace(r.Status) == "" {
+		return &httpErrors.BadRequestError{Message: "status_is_required"}
+	}
+
+	// Note: Business validations (known statuses, allowed transitions)
+	// are handled by Order.TransitionTo()
+	return nil
+}
+
+// ToOrderStatus returns the requested status in the form the domain stores it
+func (r *OrderTransitionRequest) ToOrderStatus() models.OrderStatus {
+	return models.OrderStatus(strings.ToLower(strings.TrimSpace(r.Status)))
+}
+
+// ParseOrderCursor decodes the cursor of the order listings
+// The cursor is the ID of the last order of the previous page
+func ParseOrderCursor(cursor string) (int, error) {
+	if strings.TrimSpace(cursor) == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.Atoi(cursor)
+	if err != nil || id <= 0 {
+		return 0, &httpErrors.BadRequestError{Message: "invalid_cursor_format"}
+	}
+
+	return id, nil
+}
+
+// OrdersResponse represents the HTTP response for a paginated list of orders
+type OrdersResponse struct {
+	Orders     []*models.Order `json:"orders"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
@@ -0,0 +1,68 @@
+package contracts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+func TestOrderCreateRequest_Validate(t *testing.T) {
+	t.Run("when items and from_cart are both sent then returns bad request error", func(t *testing.T) {
+		request := OrderCreateRequest{FromCart: true, Items: []OrderItemRequest{{ProductID: 1}}}
+
+		assert.Equal(t, &httpErrors.BadRequestError{Message: "items_and_from_cart_are_mutually_exclusive"}, request.Validate())
+	})
+
+	t.Run("when neither items nor from_cart are sent then returns bad request error", func(t *testing.T) {
+		request := OrderCreateRequest{}
+
+		assert.Equal(t, &httpErrors.BadRequestError{Message: "items_or_from_cart_is_required"}, request.Validate())
+	})
+
+	t.Run("when the notes are too long then returns bad request error", func(t *testing.T) {
+		request := OrderCreateRequest{FromCart: true, Notes: strings.Repeat("a", MaxOrderNotesLength+1)}
+
+		assert.Equal(t, &httpErrors.BadRequestError{Message: "notes_too_long"}, request.Validate())
+	})
+}
+
+func TestOrderCreateRequest_ToOrderDraft(t *testing.T) {
+	t.Run("when items are sent then quantity defaults to 1", func(t *testing.T) {
+		request := OrderCreateRequest{Items: []OrderItemRequest{{ProductID: 3}}}
+
+		draft := request.ToOrderDraft(models.CartOwner{CustomerID: 7})
+
+		assert.Equal(t, 7, draft.CustomerID)
+		assert.Nil(t, draft.Cart)
+		assert.Equal(t, []models.OrderLine{{ProductID: 3, Quantity: 1}}, draft.Lines)
+	})
+
+	t.Run("when from_cart is sent then the draft uses the cart of the owner", func(t *testing.T) {
+		owner := models.CartOwner{Token: "0123456789abcdef0123456789abcdef"}
+		request := OrderCreateRequest{FromCart: true}
+
+		draft := request.ToOrderDraft(owner)
+
+		assert.Equal(t, &owner, draft.Cart)
+		assert.Zero(t, draft.CustomerID)
+	})
+}
+
+func TestOrderTransitionRequest(t *testing.T) {
+	t.Run("when the status is missing then returns bad request error", func(t *testing.T) {
+		request := OrderTransitionRequest{Status: " "}
+
+		assert.Equal(t, &httpErrors.BadRequestError{Message: "status_is_required"}, request.Validate())
+	})
+
+	t.Run("when the status is sent then normalizes it", func(t *testing.T) {
+		request := OrderTransitionRequest{Status: " Out_For_Delivery "}
+
+		assert.NoError(t, request.Validate())
+		assert.Equal(t, models.OrderOutForDelivery, request.ToOrderStatus())
+	})
+}
@@ -1,10 +1,6 @@
 package contracts
 
 import (
-	"bytes"
-	"io"
-	"mime/multipart"
-	"net/http"
 	"strings"
 
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
@@ -12,9 +8,9 @@ import (
 )
 
 type ProductCreateRequest struct {
-	Product models.Product          `json:"product"`
-	ShopID  int                     `json:"shop_id"`
-	Images  []*multipart.FileHeader `json:"-"` // Not part of JSON, set manually
+	Product models.Product         `json:"product"`
+	ShopID  int                    `json:"shop_id"`
+	Images  *MultipartImageUploads `json:"-"` // Streamed from the body, validated as they are read
 }
 
 func (r *ProductCreateRequest) Validate() error {
@@ -28,11 +24,17 @@ func (r *ProductCreateRequest) Validate() error {
 		return &httpErrors.BadRequestError{Message: "shop_id_is_required"}
 	}
 
-	// Validate images
-	if err := r.validateImages(); err != nil {
-		return err
+	// Images are validated one by one as they are streamed; the stream
+	// fails at its end if the request had neither files nor upload references
+	// (at_least_one_image_is_required)
+	for _, image := range r.Product.Images {
+		if image.UploadID == "" {
+			continue
+		}
+		if err := ValidateUploadID(image.UploadID); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
@@ -104,7 +106,7 @@ func (r *ProductCreateRequest) validateVariantOptions(variant *models.Variant, v
 		if strings.TrimSpace(option.Name) == "" {
 			return &httpErrors.BadRequestError{Message: "option_name_is_required"}
 		}
-		if option.Price < 0 {
+		if option.Price.IsNegative() {
 			return &httpErrors.BadRequestError{Message: "option_price_cannot_be_negative"}
 		}
 		if option.Order == 0 {
@@ -116,81 +118,3 @@ func (r *ProductCreateRequest) validateVariantOptions(variant *models.Variant, v
 	}
 	return nil
 }
-
-func (r *ProductCreateRequest) validateImages() error {
-	if len(r.Images) == 0 {
-		return &httpErrors.BadRequestError{Message: "at_least_one_image_is_required"}
-	}
-
-	// Validate each image
-	for _, imageHeader := range r.Images {
-		// Check file size (max 5MB per image)
-		if imageHeader.Size > 3*1024*1024 {
-			return &httpErrors.BadRequestError{Message: "image_size_too_large_max_3mb"}
-		}
-
-		// Open file to check MIME type
-		file, err := imageHeader.Open()
-		if err != nil {
-			return &httpErrors.BadRequestError{Message: "cannot_open_image_file"}
-		}
-		defer file.Close()
-
-		// Read first 512 bytes to detect MIME type
-		buffer := make([]byte, 512)
-		_, err = file.Read(buffer)
-		if err != nil && err != io.EOF {
-			return &httpErrors.BadRequestError{Message: "cannot_read_image_file"}
-		}
-
-		// Check MIME type
-		mimeType := http.DetectContentType(buffer)
-		if !isValidImageType(mimeType) {
-			return &httpErrors.BadRequestError{Message: "invalid_image_type_only_jpeg_png_allowed"}
-		}
-
-		// Reset file pointer for later use
-		if seeker, ok := file.(io.Seeker); ok {
-			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
-				return &httpErrors.BadRequestError{Message: "cannot_reset_file_pointer"}
-			}
-		}
-	}
-
-	return nil
-}
-
-func isValidImageType(mimeType string) bool {
-	validTypes := []string{
-		"image/jpeg",
-		"image/jpg",
-		"image/png",
-	}
-	for _, validType := range validTypes {
-		if mimeType == validType {
-			return true
-		}
-	}
-	return false
-}
-
-// ToImageBuffers converts FileHeaders to byte slices for upload service
-func (r *ProductCreateRequest) ToImageBuffers() ([][]byte, error) {
-	buffers := make([][]byte, len(r.Images))
-
-	for i, imageHeader := range r.Images {
-		file, err := imageHeader.Open()
-		if err != nil {
-			return nil, &httpErrors.BadRequestError{Message: "cannot_open_image_file"}
-		}
-		defer file.Close()
-
-		buffer := &bytes.Buffer{}
-		if _, err := io.Copy(buffer, file); err != nil {
-			return nil, &httpErrors.BadRequestError{Message: "cannot_read_image_file"}
-		}
-		buffers[i] = buffer.Bytes()
-	}
-
-	return buffers, nil
-}
@@ -0,0 +1,37 @@
+package contracts
+
+import (
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+// ProductImageOrderRequest represents the HTTP request to reorder the images of a product
+// primary_image_id defaults to the first image when omitted
+type ProductImageOrderRequest struct {
+	ImageIDs       []int `json:"image_ids"`
+	PrimaryImageID int   `json:"primary_image_id,omitempty"`
+}
+
+func (r *ProductImageOrderRequest) Validate() error {
+	// HTTP validation: required fields
+	if len(r.ImageIDs) == 0 {
+		return &httpErrors.BadRequestError{Message: "image_ids_are_required"}
+	}
+	for _, id := range r.ImageIDs {
+		if id <= 0 {
+			return &httpErrors.BadRequestError{Message: "invalid_image_id"}
+		}
+	}
+	if r.PrimaryImageID < 0 {
+		return &httpErrors.BadRequestError{Message: "invalid_primary_image_id"}
+	}
+
+	// Note: Business validations (repeated images, primary image listed)
+	// are handled by ImageOrder.Validate() in the service layer
+	return nil
+}
+
+// ToImageOrder returns the domain image order
+func (r *ProductImageOrderRequest) ToImageOrder() *models.ImageOrder {
+	return &models.ImageOrder{ImageIDs: r.ImageIDs, PrimaryImageID: r.PrimaryImageID}
+}
@@ -0,0 +1,85 @@
+package contracts
+
+import (
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+// ProductListRequest represents the query parameters accepted by the product listing endpoint
+type ProductListRequest struct {
+	Limit         int
+	Cursor        string
+	CategoryID    *int
+	IsActive      *bool
+	IsPromotional *bool
+	IsHighlighted *bool
+	InStock       *bool
+	IsLowStock    *bool
+	MinPrice      *models.Money
+	MaxPrice      *models.Money
+	Sort          string
+	Order         string
+}
+
+func (r *ProductListRequest) Validate() error {
+	if r.Limit <= 0 {
+		return &httpErrors.BadRequestError{Message: "invalid_limit_format"}
+	}
+
+	if r.CategoryID != nil && *r.CategoryID <= 0 {
+		return &httpErrors.BadRequestError{Message: "invalid_category_id_format"}
+	}
+
+	order := strings.ToLower(strings.TrimSpace(r.Order))
+	if order != "" && order != string(models.SortAscending) && order != string(models.SortDescending) {
+		return &httpErrors.BadRequestError{Message: "invalid_order_must_be_asc_or_desc"}
+	}
+
+	// Note: Business validations (sort field, price range, cursor consistency)
+	// are handled by ProductFilter.Validate() in the service layer
+	return nil
+}
+
+// ToFilter converts the HTTP query into the domain filter used by the service
+func (r *ProductListRequest) ToFilter() (*models.ProductFilter, error) {
+	sortBy := models.ProductSortField(strings.ToLower(strings.TrimSpace(r.Sort)))
+
+	direction := models.SortDirection(strings.ToLower(strings.TrimSpace(r.Order)))
+	if direction == "" {
+		direction = models.DefaultSortDirection(sortBy)
+	}
+
+	// Listings show active products unless explicitly requested;
+	// inactive ones are only listed to the staff of the shop
+	isActive := r.IsActive
+	if isActive == nil {
+		active := true
+		isActive = &active
+	}
+
+	filter := &models.ProductFilter{
+		CategoryID:    r.CategoryID,
+		IsActive:      isActive,
+		IsPromotional: r.IsPromotional,
+		IsHighlighted: r.IsHighlighted,
+		InStock:       r.InStock,
+		IsLowStock:    r.IsLowStock,
+		MinPrice:      r.MinPrice,
+		MaxPrice:      r.MaxPrice,
+		SortBy:        sortBy,
+		SortDirection: direction,
+		Limit:         r.Limit,
+	}
+
+	if strings.TrimSpace(r.Cursor) != "" {
+		cursor, err := models.DecodeProductCursor(r.Cursor)
+		if err != nil {
+			return nil, &httpErrors.BadRequestError{Message: "invalid_cursor_format"}
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,118 @@
+package contracts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+func TestProductListRequest_Validate(t *testing.T) {
+	t.Run("when all parameters are valid then returns no error", func(t *testing.T) {
+		// Arrange
+		categoryID := 2
+		request := ProductListRequest{
+			Limit:      20,
+			CategoryID: &categoryID,
+			Sort:       "price",
+			Order:      "ASC",
+		}
+
+		// Act
+		err := request.Validate()
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("when category id is not positive then returns bad request error", func(t *testing.T) {
+		// Arrange
+		categoryID := 0
+		request := ProductListRequest{Limit: 20, CategoryID: &categoryID}
+
+		// Act
+		err := request.Validate()
+
+		// Assert
+		assert.Error(t, err)
+		assert.IsType(t, &httpErrors.BadRequestError{}, err)
+		assert.Equal(t, "invalid_category_id_format", err.Error())
+	})
+
+	t.Run("when order is unknown then returns bad request error", func(t *testing.T) {
+		// Arrange
+		request := ProductListRequest{Limit: 20, Order: "sideways"}
+
+		// Act
+		err := request.Validate()
+
+		// Assert
+		assert.Error(t, err)
+		assert.IsType(t, &httpErrors.BadRequestError{}, err)
+		assert.Equal(t, "invalid_order_must_be_asc_or_desc", err.Error())
+	})
+}
+
+func TestProductListRequest_ToFilter(t *testing.T) {
+	t.Run("when is_active is omitted then only active products are requested", func(t *testing.T) {
+		// Arrange
+		request := ProductListRequest{Limit: 20}
+
+		// Act
+		filter, err := request.ToFilter()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, filter.IsActive)
+		assert.True(t, *filter.IsActive)
+		assert.Equal(t, models.ProductSortByDefault, filter.SortBy)
+		assert.Equal(t, models.SortDescending, filter.SortDirection)
+		assert.Nil(t, filter.Cursor)
+	})
+
+	t.Run("when sort is given without order then uses the natural direction", func(t *testing.T) {
+		// Arrange
+		request := ProductListRequest{Limit: 20, Sort: "name"}
+
+		// Act
+		filter, err := request.ToFilter()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, models.ProductSortByName, filter.SortBy)
+		assert.Equal(t, models.SortAscending, filter.SortDirection)
+	})
+
+	t.Run("when cursor was encoded by a previous page then it is decoded", func(t *testing.T) {
+		// Arrange
+		previous := &models.ProductFilter{SortBy: models.ProductSortByPrice, SortDirection: models.SortAscending}
+		token := models.NewProductCursor(previous, &models.Product{ID: 9, Price: models.MoneyFromFloat(12.5)}).Encode()
+		request := ProductListRequest{Limit: 20, Sort: "price", Cursor: token}
+
+		// Act
+		filter, err := request.ToFilter()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, filter.Cursor)
+		assert.Equal(t, 9, filter.Cursor.ID)
+		assert.Equal(t, "12.50", filter.Cursor.Value)
+		assert.NoError(t, filter.Validate())
+	})
+
+	t.Run("when cursor is not a valid token then returns bad request error", func(t *testing.T) {
+		// Arrange
+		request := ProductListRequest{Limit: 20, Cursor: "-1"}
+
+		// Act
+		filter, err := request.ToFilter()
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, filter)
+		assert.IsType(t, &httpErrors.BadRequestError{}, err)
+		assert.Equal(t, "invalid_cursor_format", err.Error())
+	})
+}
@@ -0,0 +1,7 @@
+package contracts
+
+// ProductPurgeResponse represents the HTTP response of the deleted products purge
+type ProductPurgeResponse struct {
+	Purged        int `json:"purged"`
+	RetentionDays int `json:"retention_days"`
+}
@@ -0,0 +1,34 @@
+package contracts
+
+import (
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+// ProductQuoteRequest represents the HTTP request to price a configured product
+// quantity defaults to 1 when omitted
+type ProductQuoteRequest struct {
+	Quantity   *int                      `json:"quantity,omitempty"`
+	Selections []models.VariantSelection `json:"selections"`
+}
+
+func (r *ProductQuoteRequest) Validate() error {
+	// HTTP validation: every selection must name its variant
+	for _, selection := range r.Selections {
+		if selection.VariantID <= 0 {
+			return &httpErrors.BadRequestError{Message: "variant_id_is_required"}
+		}
+	}
+
+	// Note: Business validations (selection rules, availability, quantity)
+	// are handled by Product.Quote() in the service layer
+	return nil
+}
+
+// GetQuantity returns the requested quantity or 1 when omitted
+func (r *ProductQuoteRequest) GetQuantity() int {
+	if r.Quantity == nil {
+		return 1
+	}
+	return *r.Quantity
+}
@@ -0,0 +1,56 @@
+package contracts
+
+import (
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+// ProductSearchRequest represents the query parameters of the product search endpoint
+type ProductSearchRequest struct {
+	Query    string
+	Language string
+	Limit    int
+	Cursor   string
+}
+
+func (r *ProductSearchRequest) Validate() error {
+	if strings.TrimSpace(r.Query) == "" {
+		return &httpErrors.BadRequestError{Message: "search_query_is_required"}
+	}
+
+	if r.Limit <= 0 {
+		return &httpErrors.BadRequestError{Message: "invalid_limit_format"}
+	}
+
+	// Note: Business validations (usable terms, length, language)
+	// are handled by ProductSearch.Validate() in the service layer
+	return nil
+}
+
+// ToSearch converts the HTTP query into the domain search used by the service
+func (r *ProductSearchRequest) ToSearch() (*models.ProductSearch, error) {
+	search := &models.ProductSearch{
+		Query:    strings.TrimSpace(r.Query),
+		Language: models.SearchLanguage(strings.ToLower(strings.TrimSpace(r.Language))),
+		Limit:    r.Limit,
+	}
+
+	if strings.TrimSpace(r.Cursor) != "" {
+		cursor, err := models.DecodeProductSearchCursor(r.Cursor)
+		if err != nil {
+			return nil, &httpErrors.BadRequestError{Message: "invalid_cursor_format"}
+		}
+		search.Cursor = cursor
+	}
+
+	return search, nil
+}
+
+// ProductSearchResponse represents the HTTP response for paginated search results
+type ProductSearchResponse struct {
+	Results    []*models.ProductSearchResult `json:"results"`
+	NextCursor string                        `json:"next_cursor,omitempty"`
+	HasMore    bool                          `json:"has_more"`
+}
@@ -1,10 +1,6 @@
 package contracts
 
 import (
-	"bytes"
-	"io"
-	"mime/multipart"
-	"net/http"
 	"strings"
 
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
@@ -12,9 +8,9 @@ import (
 )
 
 type ProductUpdateRequest struct {
-	Product   models.Product          `json:"product"`
-	ShopID    int                     `json:"shop_id"`
-	NewImages []*multipart.FileHeader `json:"-"` // Optional new images to upload
+	Product   models.Product         `json:"product"`
+	ShopID    int                    `json:"shop_id"`
+	NewImages *MultipartImageUploads `json:"-"` // Optional new images, streamed from the body
 }
 
 func (r *ProductUpdateRequest) Validate() error {
@@ -28,18 +24,9 @@ func (r *ProductUpdateRequest) Validate() error {
 		return &httpErrors.BadRequestError{Message: "shop_id_is_required"}
 	}
 
-	// CRITICAL: Validate that at least one image exists (existing OR new)
-	// User could delete all existing images, so we need at least one new image
-	if len(r.Product.Images) == 0 && len(r.NewImages) == 0 {
-		return &httpErrors.BadRequestError{Message: "at_least_one_image_is_required"}
-	}
-
-	// Validate new images (if any)
-	if len(r.NewImages) > 0 {
-		if err := r.validateNewImages(); err != nil {
-			return err
-		}
-	}
+	// CRITICAL: At least one image must exist (existing OR new)
+	// User could delete all existing images, so the new images stream is then
+	// required and fails at its end if it had none
 
 	// Validate existing images have valid data
 	if err := r.validateExistingImages(); err != nil {
@@ -108,7 +95,7 @@ func (r *ProductUpdateRequest) validateVariantOptions(variant *models.Variant, v
 		if strings.TrimSpace(option.Name) == "" {
 			return &httpErrors.BadRequestError{Message: "option_name_is_required"}
 		}
-		if option.Price < 0 {
+		if option.Price.IsNegative() {
 			return &httpErrors.BadRequestError{Message: "option_price_cannot_be_negative"}
 		}
 		if option.Order == 0 {
@@ -123,7 +110,14 @@ func (r *ProductUpdateRequest) validateVariantOptions(variant *models.Variant, v
 
 func (r *ProductUpdateRequest) validateExistingImages() error {
 	// Validate that existing images have valid IDs and URLs
+	// Images uploaded beforehand through /uploads are referenced by upload_id instead
 	for _, img := range r.Product.Images {
+		if img.UploadID != "" {
+			if err := ValidateUploadID(img.UploadID); err != nil {
+				return err
+			}
+			continue
+		}
 		if img.ID <= 0 {
 			return &httpErrors.BadRequestError{Message: "existing_image_must_have_valid_id"}
 		}
@@ -133,63 +127,3 @@ func (r *ProductUpdateRequest) validateExistingImages() error {
 	}
 	return nil
 }
-
-func (r *ProductUpdateRequest) validateNewImages() error {
-	// Validate each new image
-	for _, imageHeader := range r.NewImages {
-		// Check file size (max 3MB per image)
-		if imageHeader.Size > 3*1024*1024 {
-			return &httpErrors.BadRequestError{Message: "image_size_too_large_max_3mb"}
-		}
-
-		// Open file to check MIME type
-		file, err := imageHeader.Open()
-		if err != nil {
-			return &httpErrors.BadRequestError{Message: "cannot_open_image_file"}
-		}
-		defer file.Close()
-
-		// Read first 512 bytes to detect MIME type
-		buffer := make([]byte, 512)
-		_, err = file.Read(buffer)
-		if err != nil && err != io.EOF {
-			return &httpErrors.BadRequestError{Message: "cannot_read_image_file"}
-		}
-
-		// Check MIME type
-		mimeType := http.DetectContentType(buffer)
-		if !isValidImageType(mimeType) {
-			return &httpErrors.BadRequestError{Message: "invalid_image_type_only_jpeg_png_allowed"}
-		}
-
-		// Reset file pointer for later use
-		if seeker, ok := file.(io.Seeker); ok {
-			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
-				return &httpErrors.BadRequestError{Message: "cannot_reset_file_pointer"}
-			}
-		}
-	}
-
-	return nil
-}
-
-// ToImageBuffers converts FileHeaders to byte slices for upload service
-func (r *ProductUpdateRequest) ToImageBuffers() ([][]byte, error) {
-	buffers := make([][]byte, len(r.NewImages))
-
-	for i, imageHeader := range r.NewImages {
-		file, err := imageHeader.Open()
-		if err != nil {
-			return nil, &httpErrors.BadRequestError{Message: "cannot_open_image_file"}
-		}
-		defer file.Close()
-
-		buffer := &bytes.Buffer{}
-		if _, err := io.Copy(buffer, file); err != nil {
-			return nil, &httpErrors.BadRequestError{Message: "cannot_read_image_file"}
-		}
-		buffers[i] = buffer.Bytes()
-	}
-
-	return buffers, nil
-}
@@ -0,0 +1,39 @@
+package contracts
+
+import (
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+// PromotionCreateRequest represents the HTTP request to schedule a product promotion
+// starts_at and ends_at are wall clock times in the shop time zone ("2026-11-01T00:00:00")
+type PromotionCreateRequest struct {
+	Price      models.Money                `json:"price"`
+	StartsAt   models.LocalDateTime        `json:"starts_at"`
+	EndsAt     *models.LocalDateTime       `json:"ends_at,omitempty"`
+	Recurrence *models.PromotionRecurrence `json:"recurrence,omitempty"`
+}
+
+func (r *PromotionCreateRequest) Validate() error {
+	// HTTP validation: required fields
+	if r.Price.IsZero() {
+		return &httpErrors.BadRequestError{Message: "promotion_price_is_required"}
+	}
+	if r.StartsAt.IsZero() {
+		return &httpErrors.BadRequestError{Message: "promotion_starts_at_is_required"}
+	}
+
+	// Note: Business validations (price, window, weekdays, overlaps)
+	// are handled by Promotion.Validate() in the service layer
+	return nil
+}
+
+// ToPromotion converts the HTTP request into the domain promotion
+func (r *PromotionCreateRequest) ToPromotion() *models.Promotion {
+	return &models.Promotion{
+		Price:      r.Price,
+		StartsAt:   r.StartsAt,
+		EndsAt:     r.EndsAt,
+		Recurrence: r.Recurrence,
+	}
+}
@@ -0,0 +1,65 @@
+package contracts
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+// StockAdjustmentRequest represents the HTTP request to record a stock movement
+// quantity is a signed delta: restocks and returns add stock, sales remove it
+type StockAdjustmentRequest struct {
+	Reason    string `json:"reason"`
+	Quantity  int    `json:"quantity"`
+	Reference string `json:"reference,omitempty"`
+}
+
+func (r *StockAdjustmentRequest) Validate() error {
+	// HTTP validation: required fields
+	if strings.TrimSpace(r.Reason) == "" {
+		return &httpErrors.BadRequestError{Message: "reason_is_required"}
+	}
+
+	// Note: Business validations (reason, quantity sign, insufficient stock)
+	// are handled by StockMovement.Validate() and the stock service
+	return nil
+}
+
+// ToStockMovement converts the HTTP request into the domain movement recorded by the staff member
+func (r *StockAdjustmentRequest) ToStockMovement(staff *models.User) *models.StockMovement {
+	return &models.StockMovement{
+		Reason:    models.StockMovementReason(strings.ToLower(strings.TrimSpace(r.Reason))),
+		Quantity:  r.Quantity,
+		Actor:     staff.Actor(),
+		Reference: strings.TrimSpace(r.Reference),
+	}
+}
+
+// ParseStockMovementCursor decodes the cursor of the stock movements listing
+// The cursor is the ID of the last movement of the previous page
+func ParseStockMovementCursor(cursor string) (int, error) {
+	if strings.TrimSpace(cursor) == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.Atoi(cursor)
+	if err != nil || id <= 0 {
+		return 0, &httpErrors.BadRequestError{Message: "invalid_cursor_format"}
+	}
+
+	return id, nil
+}
+
+// StockMovementsResponse represents the HTTP response for the paginated inventory ledger
+type StockMovementsResponse struct {
+	Movements  []*models.StockMovement `json:"movements"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+	HasMore    bool                    `json:"has_more"`
+}
+
+// LowStockReportResponse represents the HTTP response of the low-stock report
+type LowStockReportResponse struct {
+	Products []*models.LowStockProduct `json:"products"`
+}
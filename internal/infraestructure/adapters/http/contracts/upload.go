@@ -0,0 +1,78 @@
+package contracts
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+)
+
+// uploadIDPattern matches the random IDs uploads are created with
+var uploadIDPattern = regexp.MustCompile(`^[a-f0-9]{32}$`)
+
+// UploadCreateRequest declares the image a client is about to upload
+type UploadCreateRequest struct {
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+func (r *UploadCreateRequest) Validate() error {
+	// HTTP validation: required fields
+	if strings.TrimSpace(r.ContentType) == "" {
+		return &httpErrors.BadRequestError{Message: "content_type_is_required"}
+	}
+	if r.Size == 0 {
+		return &httpErrors.BadRequestError{Message: "size_is_required"}
+	}
+
+	// Note: Business validations (allowed types, size limit)
+	// are handled by Upload.Validate() in the service layer
+	return nil
+}
+
+// UploadCreateResponse tells the client where and how to put the file
+// The request must carry the listed headers, as they are part of the signature
+type UploadCreateResponse struct {
+	UploadID        string            `json:"upload_id"`
+	UploadURL       string            `json:"upload_url"`
+	Method          string            `json:"method"`
+	Headers         map[string]string `json:"headers"`
+	UploadExpiresAt time.Time         `json:"upload_url_expires_at"`
+	ExpiresAt       time.Time         `json:"expires_at"`
+}
+
+func NewUploadCreateResponse(upload *models.Upload, signedURL *models.SignedURL) UploadCreateResponse {
+	return UploadCreateResponse{
+		UploadID:        upload.ID,
+		UploadURL:       signedURL.URL,
+		Method:          signedURL.Method,
+		Headers:         signedURL.Headers,
+		UploadExpiresAt: signedURL.ExpiresAt,
+		ExpiresAt:       upload.ExpiresAt,
+	}
+}
+
+// ValidateUploadID rejects path parameters that cannot be an upload ID
+func ValidateUploadID(uploadID string) error {
+	if !uploadIDPattern.MatchString(uploadID) {
+		return &httpErrors.BadRequestError{Message: "invalid_upload_id_format"}
+	}
+	return nil
+}
+
+// HasUploadReferences reports whether any product image references a direct upload
+func HasUploadReferences(images []models.ProductImage) bool {
+	for _, image := range images {
+		if image.UploadID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadPurgeResponse represents the HTTP response of the expired uploads purge
+type UploadPurgeResponse struct {
+	Purged int `json:"purged"`
+}
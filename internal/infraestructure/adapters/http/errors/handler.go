@@ -15,6 +15,7 @@ func HandleError(w http.ResponseWriter, err error) {
 
 	var statusCode int
 	var message string
+	var fields []domainErrors.FieldError
 
 	// Map domain and HTTP errors to HTTP status codes
 	switch e := err.(type) {
@@ -23,6 +24,18 @@ func HandleError(w http.ResponseWriter, err error) {
 		statusCode = http.StatusBadRequest
 		message = e.Message
 
+	case *PreconditionRequiredError:
+		statusCode = http.StatusPreconditionRequired
+		message = e.Message
+
+	case *UnsupportedMediaTypeError:
+		statusCode = http.StatusUnsupportedMediaType
+		message = e.Message
+
+	case *RequestEntityTooLargeError:
+		statusCode = http.StatusRequestEntityTooLarge
+		message = e.Message
+
 	// Domain errors mapped to HTTP status codes
 	case *domainErrors.RecordNotFoundError:
 		statusCode = http.StatusNotFound // 404
@@ -36,6 +49,11 @@ func HandleError(w http.ResponseWriter, err error) {
 		statusCode = http.StatusBadRequest // 400
 		message = e.Message
 
+	case *domainErrors.FieldValidationError:
+		statusCode = http.StatusBadRequest // 400
+		message = e.Message
+		fields = e.Fields
+
 	case *domainErrors.AuthenticationError:
 		statusCode = http.StatusUnauthorized // 401
 		message = e.Message
@@ -44,9 +62,14 @@ func HandleError(w http.ResponseWriter, err error) {
 		statusCode = http.StatusForbidden // 403
 		message = e.Message
 
+	case *domainErrors.PreconditionFailedError:
+		statusCode = http.StatusPreconditionFailed // 412
+		message = e.Message
+
 	case *domainErrors.BusinessRuleError:
 		statusCode = http.StatusUnprocessableEntity // 422
 		message = e.Message
+		fields = e.Fields
 
 	default:
 		// Any other error (technical, unexpected) = 500
@@ -59,7 +82,10 @@ func HandleError(w http.ResponseWriter, err error) {
 		}).Error("Unhandled error")
 	}
 
-	response := map[string]string{"error": message}
+	response := map[string]interface{}{"error": message}
+	if len(fields) > 0 {
+		response["fields"] = fields
+	}
 
 	// Encode response before writing headers
 	responseData, encodeErr := json.Marshal(response)
@@ -9,3 +9,30 @@ type BadRequestError struct {
 func (e *BadRequestError) Error() string {
 	return e.Message
 }
+
+// UnsupportedMediaTypeError represents HTTP 415 errors (request body in an unexpected format)
+type UnsupportedMediaTypeError struct {
+	Message string
+}
+
+func (e *UnsupportedMediaTypeError) Error() string {
+	return e.Message
+}
+
+// RequestEntityTooLargeError represents HTTP 413 errors (request body over the allowed size)
+type RequestEntityTooLargeError struct {
+	Message string
+}
+
+func (e *RequestEntityTooLargeError) Error() string {
+	return e.Message
+}
+
+// PreconditionRequiredError represents HTTP 428 errors (conditional request headers are mandatory)
+type PreconditionRequiredError struct {
+	Message string
+}
+
+func (e *PreconditionRequiredError) Error() string {
+	return e.Message
+}
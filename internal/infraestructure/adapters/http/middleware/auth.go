@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Auth middleware log field constants
+const (
+	AuthMiddlewareField               = "auth_middleware"
+	AuthenticateFunctionField         = "authenticate"
+	RequireShopStaffFunctionField     = "require_shop_staff"
+	RequireProductStaffFunctionField  = "require_product_staff"
+	RequirePlatformAdminFunctionField = "require_platform_admin"
+)
+
+const bearerPrefix = "Bearer "
+
+type userContextKey struct{}
+
+// UserFrom returns the authenticated user of the request, or nil for anonymous callers
+func UserFrom(ctx context.Context) *models.User {
+	user, _ := ctx.Value(userContextKey{}).(*models.User)
+	return user
+}
+
+// WithUser returns a copy of the context carrying the authenticated user
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+type Auth struct {
+	tokenService  ports.TokenService
+	accessService ports.AccessService
+}
+
+func NewAuth(tokenService ports.TokenService, accessService ports.AccessService) *Auth {
+	return &Auth{
+		tokenService:  tokenService,
+		accessService: accessService,
+	}
+}
+
+// Authenticate identifies the caller by the bearer token of the Authorization header
+// Requests without a token go on anonymously; invalid tokens are rejected
+func (a *Auth) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !strings.HasPrefix(header, bearerPrefix) {
+			httpErrors.HandleError(w, &errors.AuthenticationError{Message: errors.TokenInvalid})
+			return
+		}
+
+		user, err := a.tokenService.VerifyToken(r.Context(), strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix)))
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     AuthMiddlewareField,
+				"function": AuthenticateFunctionField,
+				"error":    err.Error(),
+			}).Warn("Rejected bearer token")
+			httpErrors.HandleError(w, &errors.AuthenticationError{Message: errors.TokenInvalid})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+	})
+}
+
+// RequireUser rejects anonymous callers
+func (a *Auth) RequireUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if UserFrom(r.Context()) == nil {
+			httpErrors.HandleError(w, &errors.AuthenticationError{Message: errors.AuthenticationRequired})
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequireShopStaff lets only the staff of the {shop_id} shop through
+func (a *Auth) RequireShopStaff(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		shopID, err := strconv.Atoi(mux.Vars(r)["shop_id"])
+		if err != nil || shopID <= 0 {
+			httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_shop_id_format"})
+			return
+		}
+
+		if err := a.accessService.AuthorizeShopStaff(r.Context(), UserFrom(r.Context()), shopID); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     AuthMiddlewareField,
+				"function": RequireShopStaffFunctionField,
+				"shop_id":  shopID,
+				"error":    err.Error(),
+			}).Warn("Rejected shop staff request")
+			httpErrors.HandleError(w, err)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequireProductStaff lets only the staff of the shop selling the {product_id} product through
+func (a *Auth) RequireProductStaff(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		productID, err := strconv.Atoi(mux.Vars(r)["product_id"])
+		if err != nil || productID <= 0 {
+			httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_product_id_format"})
+			return
+		}
+
+		if err := a.accessService.AuthorizeProductStaff(r.Context(), UserFrom(r.Context()), productID); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":       AuthMiddlewareField,
+				"function":   RequireProductStaffFunctionField,
+				"product_id": productID,
+				"error":      err.Error(),
+			}).Warn("Rejected product staff request")
+			httpErrors.HandleError(w, err)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequirePlatformAdmin lets only signed in platform admins through
+func (a *Auth) RequirePlatformAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return a.RequireUser(func(w http.ResponseWriter, r *http.Request) {
+		if err := a.accessService.AuthorizePlatformAdmin(r.Context(), UserFrom(r.Context())); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     AuthMiddlewareField,
+				"function": RequirePlatformAdminFunctionField,
+				"error":    err.Error(),
+			}).Warn("Rejected platform admin request")
+			httpErrors.HandleError(w, err)
+			return
+		}
+		next(w, r)
+	})
+}
@@ -0,0 +1,332 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/middleware"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Order handler log field constants
+const (
+	OrderHandlerField                = "order_handler"
+	CreateOrderFunctionField         = "create"
+	GetOrderFunctionField            = "get_by_id"
+	GetShopOrdersFunctionField       = "get_shop_orders"
+	GetCustomerOrdersFunctionField   = "get_customer_orders"
+	TransitionOrderFunctionField     = "transition"
+	ParseOrderPaginationSubFuncField = "parse_pagination_params"
+	ParseOrderCustomerSubFuncField   = "parse_customer"
+	ParseIdempotencyKeySubFuncField  = "parse_idempotency_key"
+)
+
+// defaultOrdersLimit is the page size when no limit is requested
+const defaultOrdersLimit = 20
+
+// Checkout idempotency headers
+// Clients send the same Idempotency-Key when retrying a checkout; the response of a
+// retry that returns the order already placed carries Idempotent-Replayed
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+type OrderHandler struct {
+	createOrder       ports.CreateOrderUseCase
+	getOrder          ports.GetOrderUseCase
+	getShopOrders     ports.GetShopOrdersUseCase
+	getCustomerOrders ports.GetCustomerOrdersUseCase
+	transitionOrder   ports.TransitionOrderUseCase
+}
+
+func NewOrderHandler(createOrderUseCase ports.CreateOrderUseCase, getOrderUseCase ports.GetOrderUseCase, getShopOrdersUseCase ports.GetShopOrdersUseCase, getCustomerOrdersUseCase ports.GetCustomerOrdersUseCase, transitionOrderUseCase ports.TransitionOrderUseCase) *OrderHandler {
+	return &OrderHandler{
+		createOrder:       createOrderUseCase,
+		getOrder:          getOrderUseCase,
+		getShopOrders:     getShopOrdersUseCase,
+		getCustomerOrders: getCustomerOrdersUseCase,
+		transitionOrder:   transitionOrderUseCase,
+	}
+}
+
+// Create places an order from the listed items or from the cart of the requester
+// Signed in customers are identified by their bearer token; guests order from their X-Cart-Token cart
+// An Idempotency-Key makes retries return the order already placed instead of a new one
+func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	shopID, err := parsePathID(r, "shop_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	owner, err := h.parseOwner(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	var request contracts.OrderCreateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderHandlerField,
+			"function": CreateOrderFunctionField,
+			"sub_func": "json.Decode",
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Error decoding order request")
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_json_format"})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	idempotencyKey, err := h.parseIdempotencyKey(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	draft := request.ToOrderDraft(owner)
+	draft.IdempotencyKey = idempotencyKey
+
+	order, err := h.createOrder.Execute(ctx, shopID, draft)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderHandlerField,
+			"function": CreateOrderFunctionField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Error creating order")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	if order.Replayed {
+		w.Header().Set(IdempotentReplayedHeader, "true")
+	}
+	h.writeJSON(w, http.StatusCreated, order, CreateOrderFunctionField)
+}
+
+// GetByID returns an order to the customer that placed it or to the staff of its shop
+func (h *OrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orderID, err := parsePathID(r, "order_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	order, err := h.getOrder.Execute(ctx, orderID, middleware.UserFrom(ctx))
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderHandlerField,
+			"function": GetOrderFunctionField,
+			"order_id": orderID,
+			"error":    err.Error(),
+		}).Error("Error getting order")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, order, GetOrderFunctionField)
+}
+
+// Transition moves an order to the requested status and returns it
+// Only the staff of the order's shop may run its lifecycle
+func (h *OrderHandler) Transition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orderID, err := parsePathID(r, "order_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	var request contracts.OrderTransitionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderHandlerField,
+			"function": TransitionOrderFunctionField,
+			"order_id": orderID,
+			"error":    err.Error(),
+		}).Error("Invalid JSON format")
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_json_format"})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	order, err := h.transitionOrder.Execute(ctx, orderID, middleware.UserFrom(ctx), request.ToOrderStatus())
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderHandlerField,
+			"function": TransitionOrderFunctionField,
+			"order_id": orderID,
+			"error":    err.Error(),
+		}).Error("Error transitioning order")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, order, TransitionOrderFunctionField)
+}
+
+// GetShopOrders lists the orders of a shop, newest first
+func (h *OrderHandler) GetShopOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	shopID, err := parsePathID(r, "shop_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	limit, cursor, err := h.parsePaginationParams(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	orders, nextCursor, hasMore, err := h.getShopOrders.Execute(ctx, shopID, limit, cursor)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderHandlerField,
+			"function": GetShopOrdersFunctionField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Error getting shop orders")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, contracts.OrdersResponse{Orders: orders, NextCursor: nextCursor, HasMore: hasMore}, GetShopOrdersFunctionField)
+}
+
+// GetCustomerOrders lists the order history of the signed in customer, newest first
+func (h *OrderHandler) GetCustomerOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	customer := middleware.UserFrom(ctx)
+	if customer == nil {
+		httpErrors.HandleError(w, &errors.AuthenticationError{Message: errors.AuthenticationRequired})
+		return
+	}
+
+	limit, cursor, err := h.parsePaginationParams(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	orders, nextCursor, hasMore, err := h.getCustomerOrders.Execute(ctx, customer.ID, limit, cursor)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":        OrderHandlerField,
+			"function":    GetCustomerOrdersFunctionField,
+			"customer_id": customer.ID,
+			"error":       err.Error(),
+		}).Error("Error getting customer orders")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, contracts.OrdersResponse{Orders: orders, NextCursor: nextCursor, HasMore: hasMore}, GetCustomerOrdersFunctionField)
+}
+
+// parseOwner identifies the requester: signed in customers by their bearer token,
+// guests by the X-Cart-Token of their cart
+func (h *OrderHandler) parseOwner(r *http.Request) (models.CartOwner, error) {
+	owner, err := contracts.ParseCartOwner(middleware.UserFrom(r.Context()), r.Header.Get(CartTokenHeader))
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderHandlerField,
+			"function": ParseOrderCustomerSubFuncField,
+			"error":    err.Error(),
+		}).Error("Invalid order owner headers")
+		return models.CartOwner{}, err
+	}
+	return owner, nil
+}
+
+// parseIdempotencyKey reads the optional Idempotency-Key of a checkout
+func (h *OrderHandler) parseIdempotencyKey(r *http.Request) (string, error) {
+	values := r.Header.Values(IdempotencyKeyHeader)
+	if len(values) == 0 {
+		return "", nil
+	}
+
+	key := strings.TrimSpace(values[0])
+	if len(values) > 1 || key == "" || len(key) > maxIdempotencyKeyLength {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderHandlerField,
+			"function": ParseIdempotencyKeySubFuncField,
+			"error":    "invalid idempotency key",
+		}).Error("Invalid idempotency key header")
+		return "", &httpErrors.BadRequestError{Message: "invalid_idempotency_key"}
+	}
+	return key, nil
+}
+
+func (h *OrderHandler) parsePaginationParams(r *http.Request) (int, int, error) {
+	limitStr := r.URL.Query().Get("limit")
+
+	limit := defaultOrdersLimit
+	if limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit <= 0 {
+			logs.WithFields(map[string]interface{}{
+				"file":     OrderHandlerField,
+				"function": ParseOrderPaginationSubFuncField,
+				"sub_func": "strconv.Atoi",
+				"limit":    limitStr,
+				"error":    err,
+			}).Error("Invalid limit parameter")
+			return 0, 0, &httpErrors.BadRequestError{Message: "invalid_limit_format"}
+		}
+		limit = parsedLimit
+	}
+
+	cursor, err := contracts.ParseOrderCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderHandlerField,
+			"function": ParseOrderPaginationSubFuncField,
+			"cursor":   r.URL.Query().Get("cursor"),
+			"error":    err.Error(),
+		}).Error("Invalid orders cursor")
+		return 0, 0, err
+	}
+
+	return limit, cursor, nil
+}
+
+func (h *OrderHandler) writeJSON(w http.ResponseWriter, status int, body interface{}, function string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderHandlerField,
+			"function": function,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
@@ -2,8 +2,11 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -14,21 +17,30 @@ import (
 	"github.com/mlgaray/ecommerce_api/internal/core/ports"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
 	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/middleware"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
 )
 
 // Product handler log field constants
 const (
-	ProductHandlerField           = "product_handler"
-	GetAllByShopIDFunctionField   = "get_all_by_shop_id"
-	GetByIDFunctionField          = "get_by_id"
-	CreateProductFunctionField    = "create"
-	UpdateProductFunctionField    = "update"
-	ParseShopIDSubFuncField       = "parse_shop_id"
-	ParseProductIDSubFuncField    = "parse_product_id"
-	ParsePaginationSubFuncField   = "parse_pagination_params"
-	BuildRequestSubFuncField      = "build_request"
-	ConvertImagesToBuffersSubFunc = "convert_images_to_buffers"
+	ProductHandlerField         = "product_handler"
+	GetAllByShopIDFunctionField = "get_all_by_shop_id"
+	GetByIDFunctionField        = "get_by_id"
+	CreateProductFunctionField  = "create"
+	UpdateProductFunctionField  = "update"
+	PatchProductFunctionField   = "patch"
+	SearchProductsFunctionField = "search"
+	DeleteProductFunctionField  = "delete"
+	RestoreProductFunctionField = "restore"
+	PurgeProductsFunctionField  = "purge_deleted"
+	QuoteProductFunctionField   = "quote"
+	ReorderImagesFunctionField  = "reorder_images"
+	ParseShopIDSubFuncField     = "parse_shop_id"
+	ParseProductIDSubFuncField  = "parse_product_id"
+	ParseIfMatchSubFuncField    = "parse_if_match"
+	ParsePaginationSubFuncField = "parse_pagination_params"
+	ParseFiltersSubFuncField    = "parse_filter_params"
+	BuildRequestSubFuncField    = "build_request"
 )
 
 type ProductHandler struct {
@@ -36,33 +48,44 @@ type ProductHandler struct {
 	getAllByShopID ports.GetAllByShopIDUseCase
 	getByID        ports.GetByIDUseCase
 	updateProduct  ports.UpdateProductUseCase
+	patchProduct   ports.PatchProductUseCase
+	searchProducts ports.SearchProductsUseCase
+	deleteProduct  ports.DeleteProductUseCase
+	restoreProduct ports.RestoreProductUseCase
+	purgeProducts  ports.PurgeProductsUseCase
+	quoteProduct   ports.QuoteProductUseCase
+	reorderImages  ports.ReorderProductImagesUseCase
+	// requireIfMatch rejects updates without an If-Match header (strict mode)
+	requireIfMatch bool
 }
 
+// defaultPurgeRetentionDays is how long soft-deleted products can still be restored
+const defaultPurgeRetentionDays = 30
+
+// mergePatchContentType is the media type of JSON Merge Patch documents (RFC 7396)
+const mergePatchContentType = "application/merge-patch+json"
+
 func (p *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	startTime := time.Now()
 
-	// Parse multipart form (13MB limit - allows 4 images of 3MB each + product data)
+	// Stream the multipart body part by part instead of buffering the whole form;
+	// images are read one at a time by the use case, so memory stays bounded
 	stepStart := time.Now()
-	err := r.ParseMultipartForm(13 << 20)
+	reader, err := p.multipartReader(w, r)
 	if err != nil {
 		logs.WithFields(map[string]interface{}{
 			"file":     ProductHandlerField,
 			"function": CreateProductFunctionField,
-			"sub_func": "r.ParseMultipartForm",
+			"sub_func": "r.MultipartReader",
 			"error":    err.Error(),
-		}).Error("Error parsing multipart form")
-		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "error_parsing_multipart_form"})
+		}).Error("Error reading multipart form")
+		httpErrors.HandleError(w, err)
 		return
 	}
-	logs.WithFields(map[string]interface{}{
-		"operation":   "parse_multipart_form",
-		"duration_ms": time.Since(stepStart).Milliseconds(),
-	}).Debug("Step 1: Multipart form parsed")
 
 	// Create ProductCreateRequest
-	stepStart = time.Now()
-	request, err := p.buildProductCreateRequest(r)
+	request, err := p.buildProductCreateRequest(reader)
 	if err != nil {
 		logs.WithFields(map[string]interface{}{
 			"file":     ProductHandlerField,
@@ -76,9 +99,9 @@ func (p *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	logs.WithFields(map[string]interface{}{
 		"operation":   "build_request",
 		"duration_ms": time.Since(stepStart).Milliseconds(),
-	}).Debug("Step 2: Request built")
+	}).Debug("Step 1: Form fields read and request built")
 
-	// Validate request (includes product data and images)
+	// Validate request (images are validated as they are streamed)
 	stepStart = time.Now()
 	if err := request.Validate(); err != nil {
 		logs.WithFields(map[string]interface{}{
@@ -94,30 +117,11 @@ func (p *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	logs.WithFields(map[string]interface{}{
 		"operation":   "validate_request",
 		"duration_ms": time.Since(stepStart).Milliseconds(),
-	}).Debug("Step 3: Request validated")
-
-	// Convert images to buffers for upload service
-	stepStart = time.Now()
-	imageBuffers, err := request.ToImageBuffers()
-	if err != nil {
-		logs.WithFields(map[string]interface{}{
-			"file":     ProductHandlerField,
-			"function": CreateProductFunctionField,
-			"sub_func": ConvertImagesToBuffersSubFunc,
-			"error":    err.Error(),
-		}).Error("Error converting images to buffers")
-		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: err.Error()})
-		return
-	}
-	logs.WithFields(map[string]interface{}{
-		"operation":   "convert_images_to_buffers",
-		"image_count": len(imageBuffers),
-		"duration_ms": time.Since(stepStart).Milliseconds(),
-	}).Debug("Step 4: Images converted to buffers")
+	}).Debug("Step 2: Request validated")
 
 	// Create product via use case
 	stepStart = time.Now()
-	createdProduct, err := p.createProduct.Execute(ctx, &request.Product, imageBuffers, request.ShopID)
+	createdProduct, err := p.createProduct.Execute(ctx, &request.Product, request.Images, request.ShopID)
 	if err != nil {
 		logs.WithFields(map[string]interface{}{
 			"file":         ProductHandlerField,
@@ -132,7 +136,7 @@ func (p *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	logs.WithFields(map[string]interface{}{
 		"operation":   "execute_use_case",
 		"duration_ms": time.Since(stepStart).Milliseconds(),
-	}).Debug("Step 5: Use case executed")
+	}).Debug("Step 3: Use case executed")
 
 	logs.WithFields(map[string]interface{}{
 		"operation":         "create_product_total",
@@ -151,9 +155,15 @@ func (p *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (p *ProductHandler) buildProductCreateRequest(r *http.Request) (*contracts.ProductCreateRequest, error) {
+func (p *ProductHandler) buildProductCreateRequest(reader *multipart.Reader) (*contracts.ProductCreateRequest, error) {
+	// Text fields come before the images, which are left in the body to be streamed
+	fields, firstImage, err := contracts.ReadFormFields(reader)
+	if err != nil {
+		return nil, err
+	}
+
 	// Extract product JSON from form data
-	productJSON := r.FormValue("product")
+	productJSON := fields["product"]
 	if strings.TrimSpace(productJSON) == "" {
 		return nil, &httpErrors.BadRequestError{Message: "product_json_required"}
 	}
@@ -165,7 +175,7 @@ func (p *ProductHandler) buildProductCreateRequest(r *http.Request) (*contracts.
 	}
 
 	// Get shop ID from form
-	shopIDStr := r.FormValue("shop_id")
+	shopIDStr := fields["shop_id"]
 	if strings.TrimSpace(shopIDStr) == "" {
 		return nil, &httpErrors.BadRequestError{Message: "shop_id_required"}
 	}
@@ -175,32 +185,38 @@ func (p *ProductHandler) buildProductCreateRequest(r *http.Request) (*contracts.
 		return nil, &httpErrors.BadRequestError{Message: "invalid_shop_id_format"}
 	}
 
-	// Get images from form
-	var images []*multipart.FileHeader
-	if r.MultipartForm != nil && r.MultipartForm.File != nil {
-		for i := 0; ; i++ {
-			key := "images[" + strconv.Itoa(i) + "]"
-			if files, exists := r.MultipartForm.File[key]; exists && len(files) > 0 {
-				images = append(images, files[0])
-			} else {
-				break
-			}
-		}
-	}
-
 	return &contracts.ProductCreateRequest{
 		Product: product,
 		ShopID:  shopID,
-		Images:  images,
+		// Images uploaded beforehand through /uploads make the multipart files optional
+		Images: contracts.NewMultipartImageUploads(reader, firstImage, !contracts.HasUploadReferences(product.Images)),
 	}, nil
 }
 
-func NewProductHandler(createProductUseCase ports.CreateProductUseCase, getAllUseCase ports.GetAllByShopIDUseCase, getByIDUseCase ports.GetByIDUseCase, updateProductUseCase ports.UpdateProductUseCase) *ProductHandler {
+// multipartReader limits the request body and returns a reader over its parts
+func (p *ProductHandler) multipartReader(w http.ResponseWriter, r *http.Request) (*multipart.Reader, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, contracts.MaxUploadRequestBytes)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, &httpErrors.BadRequestError{Message: "error_parsing_multipart_form"}
+	}
+	return reader, nil
+}
+
+func NewProductHandler(createProductUseCase ports.CreateProductUseCase, getAllUseCase ports.GetAllByShopIDUseCase, getByIDUseCase ports.GetByIDUseCase, updateProductUseCase ports.UpdateProductUseCase, patchProductUseCase ports.PatchProductUseCase, searchProductsUseCase ports.SearchProductsUseCase, deleteProductUseCase ports.DeleteProductUseCase, restoreProductUseCase ports.RestoreProductUseCase, purgeProductsUseCase ports.PurgeProductsUseCase, quoteProductUseCase ports.QuoteProductUseCase, reorderProductImagesUseCase ports.ReorderProductImagesUseCase) *ProductHandler {
 	return &ProductHandler{
 		createProduct:  createProductUseCase,
 		getAllByShopID: getAllUseCase,
 		getByID:        getByIDUseCase,
 		updateProduct:  updateProductUseCase,
+		patchProduct:   patchProductUseCase,
+		searchProducts: searchProductsUseCase,
+		deleteProduct:  deleteProductUseCase,
+		restoreProduct: restoreProductUseCase,
+		purgeProducts:  purgeProductsUseCase,
+		quoteProduct:   quoteProductUseCase,
+		reorderImages:  reorderProductImagesUseCase,
+		requireIfMatch: os.Getenv("PRODUCT_REQUIRE_IF_MATCH") == "true",
 	}
 }
 
@@ -214,22 +230,50 @@ func (p *ProductHandler) GetAllByShopID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Parse and validate pagination parameters
-	limit, cursor, err := p.parsePaginationParams(r)
+	// Parse pagination, filter and sort parameters
+	request, err := p.buildProductListRequest(r)
 	if err != nil {
 		httpErrors.HandleError(w, err)
 		return
 	}
 
+	if err := request.Validate(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": GetAllByShopIDFunctionField,
+			"sub_func": "request.Validate",
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Product listing validation failed")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	filter, err := request.ToFilter()
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": GetAllByShopIDFunctionField,
+			"sub_func": "request.ToFilter",
+			"shop_id":  shopID,
+			"cursor":   request.Cursor,
+			"error":    err.Error(),
+		}).Error("Invalid product listing cursor")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
 	// Execute use case
-	products, nextCursor, hasMore, err := p.getAllByShopID.Execute(ctx, shopID, limit, cursor)
+	products, nextCursor, hasMore, err := p.getAllByShopID.Execute(ctx, shopID, filter, middleware.UserFrom(ctx))
 	if err != nil {
 		logs.WithFields(map[string]interface{}{
 			"file":     ProductHandlerField,
 			"function": GetAllByShopIDFunctionField,
 			"shop_id":  shopID,
-			"limit":    limit,
-			"cursor":   cursor,
+			"limit":    filter.Limit,
+			"cursor":   request.Cursor,
+			"sort":     filter.SortBy,
+			"order":    filter.SortDirection,
 			"error":    err.Error(),
 		}).Error("Error retrieving products")
 		httpErrors.HandleError(w, err)
@@ -255,6 +299,80 @@ func (p *ProductHandler) GetAllByShopID(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+func (p *ProductHandler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Parse and validate shop_id
+	shopID, err := p.parseShopID(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	limit, cursor, err := p.parsePaginationParams(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	request := &contracts.ProductSearchRequest{
+		Query:    r.URL.Query().Get("q"),
+		Language: r.URL.Query().Get("lang"),
+		Limit:    limit,
+		Cursor:   cursor,
+	}
+
+	if err := request.Validate(); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	search, err := request.ToSearch()
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": SearchProductsFunctionField,
+			"sub_func": "request.ToSearch",
+			"shop_id":  shopID,
+			"cursor":   cursor,
+			"error":    err.Error(),
+		}).Error("Invalid product search cursor")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	// Execute use case
+	results, nextCursor, hasMore, err := p.searchProducts.Execute(ctx, shopID, search)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": SearchProductsFunctionField,
+			"shop_id":  shopID,
+			"query":    search.Query,
+			"error":    err.Error(),
+		}).Error("Error searching products")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	response := contracts.ProductSearchResponse{
+		Results:    results,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": SearchProductsFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
 func (p *ProductHandler) parseShopID(r *http.Request) (int, error) {
 	vars := mux.Vars(r)
 	shopIDStr := vars["shop_id"]
@@ -282,9 +400,51 @@ func (p *ProductHandler) parseShopID(r *http.Request) (int, error) {
 	return shopID, nil
 }
 
-func (p *ProductHandler) parsePaginationParams(r *http.Request) (int, int, error) {
+func (p *ProductHandler) buildProductListRequest(r *http.Request) (*contracts.ProductListRequest, error) {
+	limit, cursor, err := p.parsePaginationParams(r)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.URL.Query()
+	request := &contracts.ProductListRequest{
+		Limit:  limit,
+		Cursor: cursor,
+		Sort:   query.Get("sort"),
+		Order:  query.Get("order"),
+	}
+
+	if request.CategoryID, err = parseOptionalIntParam(r, "category_id"); err != nil {
+		return nil, err
+	}
+	if request.IsActive, err = parseOptionalBoolParam(r, "is_active"); err != nil {
+		return nil, err
+	}
+	if request.IsPromotional, err = parseOptionalBoolParam(r, "is_promotional"); err != nil {
+		return nil, err
+	}
+	if request.IsHighlighted, err = parseOptionalBoolParam(r, "is_highlighted"); err != nil {
+		return nil, err
+	}
+	if request.InStock, err = parseOptionalBoolParam(r, "in_stock"); err != nil {
+		return nil, err
+	}
+	if request.IsLowStock, err = parseOptionalBoolParam(r, "is_low_stock"); err != nil {
+		return nil, err
+	}
+	if request.MinPrice, err = parseOptionalMoneyParam(r, "min_price"); err != nil {
+		return nil, err
+	}
+	if request.MaxPrice, err = parseOptionalMoneyParam(r, "max_price"); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (p *ProductHandler) parsePaginationParams(r *http.Request) (int, string, error) {
 	limitStr := r.URL.Query().Get("limit")
-	cursorStr := r.URL.Query().Get("cursor")
+	cursor := r.URL.Query().Get("cursor") // opaque token, decoded by the request contract
 
 	limit := 20 // default
 	if limitStr != "" {
@@ -297,28 +457,78 @@ func (p *ProductHandler) parsePaginationParams(r *http.Request) (int, int, error
 				"limit":    limitStr,
 				"error":    err,
 			}).Error("Invalid limit parameter")
-			return 0, 0, &httpErrors.BadRequestError{Message: "invalid_limit_format"}
+			return 0, "", &httpErrors.BadRequestError{Message: "invalid_limit_format"}
 		}
 		limit = parsedLimit
 	}
 
-	cursor := 0 // default (first page)
-	if cursorStr != "" {
-		parsedCursor, err := strconv.Atoi(cursorStr)
-		if err != nil || parsedCursor < 0 {
-			logs.WithFields(map[string]interface{}{
-				"file":     ProductHandlerField,
-				"function": ParsePaginationSubFuncField,
-				"sub_func": "strconv.Atoi",
-				"cursor":   cursorStr,
-				"error":    err,
-			}).Error("Invalid cursor parameter")
-			return 0, 0, &httpErrors.BadRequestError{Message: "invalid_cursor_format"}
-		}
-		cursor = parsedCursor
+	return limit, cursor, nil
+}
+
+// parseOptionalIntParam returns nil when the query parameter is absent
+func parseOptionalIntParam(r *http.Request, name string) (*int, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(name))
+	if raw == "" {
+		return nil, nil
 	}
 
-	return limit, cursor, nil
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": ParseFiltersSubFuncField,
+			"sub_func": "strconv.Atoi",
+			name:       raw,
+			"error":    err.Error(),
+		}).Error("Invalid integer query parameter")
+		return nil, &httpErrors.BadRequestError{Message: "invalid_" + name + "_format"}
+	}
+
+	return &value, nil
+}
+
+// parseOptionalBoolParam returns nil when the query parameter is absent
+func parseOptionalBoolParam(r *http.Request, name string) (*bool, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(name))
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": ParseFiltersSubFuncField,
+			"sub_func": "strconv.ParseBool",
+			name:       raw,
+			"error":    err.Error(),
+		}).Error("Invalid boolean query parameter")
+		return nil, &httpErrors.BadRequestError{Message: "invalid_" + name + "_format"}
+	}
+
+	return &value, nil
+}
+
+// parseOptionalMoneyParam returns nil when the query parameter is absent
+func parseOptionalMoneyParam(r *http.Request, name string) (*models.Money, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(name))
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := models.ParseMoney(raw)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": ParseFiltersSubFuncField,
+			"sub_func": "models.ParseMoney",
+			name:       raw,
+			"error":    err.Error(),
+		}).Error("Invalid decimal query parameter")
+		return nil, &httpErrors.BadRequestError{Message: "invalid_" + name + "_format"}
+	}
+
+	return &value, nil
 }
 
 func (p *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -345,6 +555,7 @@ func (p *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Return product directly (no DTO wrapper needed for single product)
+	w.Header().Set("ETag", productETag(product.Version))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(product); err != nil {
@@ -357,6 +568,43 @@ func (p *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// productETag formats the product version as a strong entity tag
+func productETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// parseIfMatch returns the product version expected by the client
+// Zero means no version check: the header was omitted (non strict mode) or is "*"
+func (p *ProductHandler) parseIfMatch(r *http.Request) (int, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		if p.requireIfMatch {
+			return 0, &httpErrors.PreconditionRequiredError{Message: "if_match_header_required"}
+		}
+		return 0, nil
+	}
+
+	if ifMatch == "*" {
+		return 0, nil
+	}
+
+	// Only strong tags are accepted: weak tags (W/"1") never match for If-Match
+	version := 0
+	if unquoted, err := strconv.Unquote(ifMatch); err == nil {
+		version, _ = strconv.Atoi(unquoted)
+	}
+	if version <= 0 {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": ParseIfMatchSubFuncField,
+			"if_match": ifMatch,
+		}).Error("Invalid If-Match header")
+		return 0, &httpErrors.BadRequestError{Message: "invalid_if_match_header"}
+	}
+
+	return version, nil
+}
+
 func (p *ProductHandler) parseProductID(r *http.Request) (int, error) {
 	vars := mux.Vars(r)
 	productIDStr := vars["product_id"]
@@ -394,21 +642,21 @@ func (p *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse multipart form
-	err = r.ParseMultipartForm(13 << 20) // 13MB limit
+	// Stream the multipart body instead of buffering the whole form
+	reader, err := p.multipartReader(w, r)
 	if err != nil {
 		logs.WithFields(map[string]interface{}{
 			"file":     ProductHandlerField,
 			"function": UpdateProductFunctionField,
-			"sub_func": "r.ParseMultipartForm",
+			"sub_func": "r.MultipartReader",
 			"error":    err.Error(),
-		}).Error("Error parsing multipart form")
-		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "error_parsing_multipart_form"})
+		}).Error("Error reading multipart form")
+		httpErrors.HandleError(w, err)
 		return
 	}
 
 	// Build product update request (different from create)
-	request, err := p.buildProductUpdateRequest(r)
+	request, err := p.buildProductUpdateRequest(reader)
 	if err != nil {
 		logs.WithFields(map[string]interface{}{
 			"file":     ProductHandlerField,
@@ -423,6 +671,13 @@ func (p *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	// Set product ID from path param (override any ID in JSON)
 	request.Product.ID = productID
 
+	// The expected version only comes from If-Match (override any version in JSON)
+	request.Product.Version, err = p.parseIfMatch(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
 	// Validate request
 	if err := request.Validate(); err != nil {
 		logs.WithFields(map[string]interface{}{
@@ -437,21 +692,8 @@ func (p *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Convert new images to buffers for upload service
-	imageBuffers, err := request.ToImageBuffers()
-	if err != nil {
-		logs.WithFields(map[string]interface{}{
-			"file":     ProductHandlerField,
-			"function": UpdateProductFunctionField,
-			"sub_func": ConvertImagesToBuffersSubFunc,
-			"error":    err.Error(),
-		}).Error("Error converting images to buffers")
-		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: err.Error()})
-		return
-	}
-
 	// Update product via use case
-	err = p.updateProduct.Execute(ctx, productID, &request.Product, imageBuffers)
+	err = p.updateProduct.Execute(ctx, productID, &request.Product, request.NewImages)
 	if err != nil {
 		logs.WithFields(map[string]interface{}{
 			"file":         ProductHandlerField,
@@ -481,9 +723,15 @@ func (p *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (p *ProductHandler) buildProductUpdateRequest(r *http.Request) (*contracts.ProductUpdateRequest, error) {
+func (p *ProductHandler) buildProductUpdateRequest(reader *multipart.Reader) (*contracts.ProductUpdateRequest, error) {
+	// Text fields come before the new images, which are left in the body to be streamed
+	fields, firstImage, err := contracts.ReadFormFields(reader)
+	if err != nil {
+		return nil, err
+	}
+
 	// Extract product JSON from form data
-	productJSON := r.FormValue("product")
+	productJSON := fields["product"]
 	if strings.TrimSpace(productJSON) == "" {
 		return nil, &httpErrors.BadRequestError{Message: "product_json_required"}
 	}
@@ -495,7 +743,7 @@ func (p *ProductHandler) buildProductUpdateRequest(r *http.Request) (*contracts.
 	}
 
 	// Get shop ID from form
-	shopIDStr := r.FormValue("shop_id")
+	shopIDStr := fields["shop_id"]
 	if strings.TrimSpace(shopIDStr) == "" {
 		return nil, &httpErrors.BadRequestError{Message: "shop_id_required"}
 	}
@@ -505,22 +753,306 @@ func (p *ProductHandler) buildProductUpdateRequest(r *http.Request) (*contracts.
 		return nil, &httpErrors.BadRequestError{Message: "invalid_shop_id_format"}
 	}
 
-	// Get new images from multipart form (optional for update)
-	var newImages []*multipart.FileHeader
-	if r.MultipartForm != nil && r.MultipartForm.File != nil {
-		for i := 0; ; i++ {
-			key := "images[" + strconv.Itoa(i) + "]"
-			if files, exists := r.MultipartForm.File[key]; exists && len(files) > 0 {
-				newImages = append(newImages, files[0])
-			} else {
-				break
-			}
-		}
-	}
-
+	// New images are optional, unless every existing image was removed
 	return &contracts.ProductUpdateRequest{
 		Product:   product,
 		ShopID:    shopID,
-		NewImages: newImages,
+		NewImages: contracts.NewMultipartImageUploads(reader, firstImage, len(product.Images) == 0),
 	}, nil
 }
+
+func (p *ProductHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Parse and validate product_id
+	productID, err := p.parseProductID(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	expectedVersion, err := p.parseIfMatch(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != mergePatchContentType {
+		logs.WithFields(map[string]interface{}{
+			"file":         ProductHandlerField,
+			"function":     PatchProductFunctionField,
+			"product_id":   productID,
+			"content_type": r.Header.Get("Content-Type"),
+		}).Error("Unsupported content type for product patch")
+		httpErrors.HandleError(w, &httpErrors.UnsupportedMediaTypeError{Message: "content_type_must_be_merge_patch_json"})
+		return
+	}
+
+	// A merge patch must be a JSON object (1MB limit)
+	var document interface{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&document); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductHandlerField,
+			"function":   PatchProductFunctionField,
+			"sub_func":   "json.Decode",
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error decoding merge patch")
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_merge_patch_document"})
+		return
+	}
+
+	patch, ok := document.(map[string]interface{})
+	if !ok {
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_merge_patch_document"})
+		return
+	}
+
+	product, err := p.patchProduct.Execute(ctx, productID, patch, expectedVersion)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductHandlerField,
+			"function":   PatchProductFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error patching product")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", productETag(product.Version))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(product); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": PatchProductFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+func (p *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Parse and validate product_id
+	productID, err := p.parseProductID(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	if err := p.deleteProduct.Execute(ctx, productID); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductHandlerField,
+			"function":   DeleteProductFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error deleting product")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	p.writeMessage(w, DeleteProductFunctionField, "product_deleted_successfully")
+}
+
+func (p *ProductHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Parse and validate product_id
+	productID, err := p.parseProductID(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	if err := p.restoreProduct.Execute(ctx, productID); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductHandlerField,
+			"function":   RestoreProductFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error restoring product")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	p.writeMessage(w, RestoreProductFunctionField, "product_restored_successfully")
+}
+
+func (p *ProductHandler) PurgeDeleted(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	retentionDays := defaultPurgeRetentionDays
+	if retentionStr := r.URL.Query().Get("retention_days"); retentionStr != "" {
+		parsed, err := strconv.Atoi(retentionStr)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":           ProductHandlerField,
+				"function":       PurgeProductsFunctionField,
+				"sub_func":       "strconv.Atoi",
+				"retention_days": retentionStr,
+				"error":          err.Error(),
+			}).Error("Invalid retention_days parameter")
+			httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_retention_days_format"})
+			return
+		}
+		retentionDays = parsed
+	}
+
+	purged, err := p.purgeProducts.Execute(ctx, time.Duration(retentionDays)*24*time.Hour)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":           ProductHandlerField,
+			"function":       PurgeProductsFunctionField,
+			"retention_days": retentionDays,
+			"error":          err.Error(),
+		}).Error("Error purging deleted products")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	response := contracts.ProductPurgeResponse{
+		Purged:        purged,
+		RetentionDays: retentionDays,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": PurgeProductsFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+// writeMessage writes a 200 response carrying only a message code
+func (p *ProductHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Parse and validate product_id
+	productID, err := p.parseProductID(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	var request contracts.ProductQuoteRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductHandlerField,
+			"function":   QuoteProductFunctionField,
+			"sub_func":   "json.Decode",
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error decoding quote request")
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_json_format"})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	quote, err := p.quoteProduct.Execute(ctx, productID, request.Selections, request.GetQuantity())
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductHandlerField,
+			"function":   QuoteProductFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error quoting product")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(quote); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": QuoteProductFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+// ReorderImages stores a new order and primary image for the images of a product
+func (p *ProductHandler) ReorderImages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Parse and validate product_id
+	productID, err := p.parseProductID(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	expectedVersion, err := p.parseIfMatch(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	var request contracts.ProductImageOrderRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductHandlerField,
+			"function":   ReorderImagesFunctionField,
+			"sub_func":   "json.Decode",
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error decoding image order request")
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_json_format"})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	product, err := p.reorderImages.Execute(ctx, productID, request.ToImageOrder(), expectedVersion)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductHandlerField,
+			"function":   ReorderImagesFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error reordering product images")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", productETag(product.Version))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(product); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": ReorderImagesFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+func (p *ProductHandler) writeMessage(w http.ResponseWriter, function, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(contracts.MessageResponse{Message: message}); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductHandlerField,
+			"function": function,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
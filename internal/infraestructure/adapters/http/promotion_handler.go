@@ -0,0 +1,154 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	coreErrors "github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Promotion handler log field constants
+const (
+	PromotionHandlerField        = "promotion_handler"
+	CreatePromotionFunctionField = "create"
+	DeletePromotionFunctionField = "delete"
+	ParsePathIDSubFuncField      = "parse_path_id"
+)
+
+type PromotionHandler struct {
+	createPromotion ports.CreatePromotionUseCase
+	deletePromotion ports.DeletePromotionUseCase
+}
+
+func NewPromotionHandler(createPromotionUseCase ports.CreatePromotionUseCase, deletePromotionUseCase ports.DeletePromotionUseCase) *PromotionHandler {
+	return &PromotionHandler{
+		createPromotion: createPromotionUseCase,
+		deletePromotion: deletePromotionUseCase,
+	}
+}
+
+func (h *PromotionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	productID, err := parsePathID(r, "product_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	var request contracts.PromotionCreateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       PromotionHandlerField,
+			"function":   CreatePromotionFunctionField,
+			"sub_func":   "json.Decode",
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error decoding promotion request")
+
+		// Malformed dates, times and amounts keep their specific message
+		var validationErr *coreErrors.ValidationError
+		if errors.As(err, &validationErr) {
+			httpErrors.HandleError(w, validationErr)
+			return
+		}
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_json_format"})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	promotion, err := h.createPromotion.Execute(ctx, productID, request.ToPromotion())
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       PromotionHandlerField,
+			"function":   CreatePromotionFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error creating promotion")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(promotion); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     PromotionHandlerField,
+			"function": CreatePromotionFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+func (h *PromotionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	productID, err := parsePathID(r, "product_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	promotionID, err := parsePathID(r, "promotion_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	if err := h.deletePromotion.Execute(ctx, productID, promotionID); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":         PromotionHandlerField,
+			"function":     DeletePromotionFunctionField,
+			"product_id":   productID,
+			"promotion_id": promotionID,
+			"error":        err.Error(),
+		}).Error("Error deleting promotion")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(contracts.MessageResponse{Message: "promotion_deleted_successfully"}); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     PromotionHandlerField,
+			"function": DeletePromotionFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+// parsePathID parses a positive integer path parameter such as product_id
+func parsePathID(r *http.Request, name string) (int, error) {
+	value := mux.Vars(r)[name]
+	if value == "" {
+		return 0, &httpErrors.BadRequestError{Message: name + "_parameter_required"}
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		logs.WithFields(map[string]interface{}{
+			"file":     PromotionHandlerField,
+			"function": ParsePathIDSubFuncField,
+			"param":    name,
+			"value":    value,
+		}).Error("Invalid path parameter")
+		return 0, &httpErrors.BadRequestError{Message: "invalid_" + name + "_format"}
+	}
+
+	return id, nil
+}
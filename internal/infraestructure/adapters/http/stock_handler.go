@@ -0,0 +1,205 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/middleware"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Stock handler log field constants
+const (
+	StockHandlerField                = "stock_handler"
+	AdjustStockFunctionField         = "adjust_stock"
+	GetStockMovementsFunctionField   = "get_movements"
+	ParseStockPaginationSubFuncField = "parse_pagination"
+	GetLowStockReportFunctionField   = "get_low_stock_report"
+)
+
+// Page sizes of the stock movements listing; larger limits are capped
+const (
+	defaultStockMovementsLimit = 20
+	maxStockMovementsLimit     = 100
+)
+
+type StockHandler struct {
+	adjustStock       ports.AdjustStockUseCase
+	getStockMovements ports.GetStockMovementsUseCase
+	getLowStockReport ports.GetLowStockReportUseCase
+}
+
+func NewStockHandler(adjustStockUseCase ports.AdjustStockUseCase, getStockMovementsUseCase ports.GetStockMovementsUseCase, getLowStockReportUseCase ports.GetLowStockReportUseCase) *StockHandler {
+	return &StockHandler{
+		adjustStock:       adjustStockUseCase,
+		getStockMovements: getStockMovementsUseCase,
+		getLowStockReport: getLowStockReportUseCase,
+	}
+}
+
+func (h *StockHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	productID, err := parsePathID(r, "product_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	var request contracts.StockAdjustmentRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockHandlerField,
+			"function":   AdjustStockFunctionField,
+			"sub_func":   "json.Decode",
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error decoding stock adjustment request")
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_json_format"})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	// The route only lets the shop staff through, so the ledger records who moved the stock
+	movement, err := h.adjustStock.Execute(ctx, productID, request.ToStockMovement(middleware.UserFrom(ctx)))
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockHandlerField,
+			"function":   AdjustStockFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error adjusting stock")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(movement); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     StockHandlerField,
+			"function": AdjustStockFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+func (h *StockHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	productID, err := parsePathID(r, "product_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	limit, cursor, err := h.parsePaginationParams(r)
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	movements, nextCursor, hasMore, err := h.getStockMovements.Execute(ctx, productID, limit, cursor)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockHandlerField,
+			"function":   GetStockMovementsFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Error getting stock movements")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	response := contracts.StockMovementsResponse{
+		Movements:  movements,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     StockHandlerField,
+			"function": GetStockMovementsFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+func (h *StockHandler) GetLowStockReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	shopID, err := parsePathID(r, "shop_id")
+	if err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	products, err := h.getLowStockReport.Execute(ctx, shopID)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     StockHandlerField,
+			"function": GetLowStockReportFunctionField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Error getting low stock report")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(contracts.LowStockReportResponse{Products: products}); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     StockHandlerField,
+			"function": GetLowStockReportFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+func (h *StockHandler) parsePaginationParams(r *http.Request) (int, int, error) {
+	limitStr := r.URL.Query().Get("limit")
+
+	limit := defaultStockMovementsLimit
+	if limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit <= 0 {
+			logs.WithFields(map[string]interface{}{
+				"file":     StockHandlerField,
+				"function": ParseStockPaginationSubFuncField,
+				"sub_func": "strconv.Atoi",
+				"limit":    limitStr,
+				"error":    err,
+			}).Error("Invalid limit parameter")
+			return 0, 0, &httpErrors.BadRequestError{Message: "invalid_limit_format"}
+		}
+		limit = min(parsedLimit, maxStockMovementsLimit)
+	}
+
+	cursor, err := contracts.ParseStockMovementCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     StockHandlerField,
+			"function": ParseStockPaginationSubFuncField,
+			"cursor":   r.URL.Query().Get("cursor"),
+			"error":    err.Error(),
+		}).Error("Invalid stock movements cursor")
+		return 0, 0, err
+	}
+
+	return limit, cursor, nil
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+	httpErrors "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/errors"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Upload handler log field constants
+const (
+	UploadHandlerField          = "upload_handler"
+	CreateUploadFunctionField   = "create"
+	FinalizeUploadFunctionField = "finalize"
+	PurgeUploadsFunctionField   = "purge_expired"
+)
+
+type UploadHandler struct {
+	createUpload   ports.CreateUploadUseCase
+	finalizeUpload ports.FinalizeUploadUseCase
+	purgeUploads   ports.PurgeUploadsUseCase
+}
+
+func NewUploadHandler(createUploadUseCase ports.CreateUploadUseCase, finalizeUploadUseCase ports.FinalizeUploadUseCase, purgeUploadsUseCase ports.PurgeUploadsUseCase) *UploadHandler {
+	return &UploadHandler{
+		createUpload:   createUploadUseCase,
+		finalizeUpload: finalizeUploadUseCase,
+		purgeUploads:   purgeUploadsUseCase,
+	}
+}
+
+func (h *UploadHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var request contracts.UploadCreateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&request); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     UploadHandlerField,
+			"function": CreateUploadFunctionField,
+			"sub_func": "json.Decode",
+			"error":    err.Error(),
+		}).Error("Error decoding upload request")
+		httpErrors.HandleError(w, &httpErrors.BadRequestError{Message: "invalid_json_format"})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	upload, signedURL, err := h.createUpload.Execute(ctx, request.ContentType, request.Size)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":         UploadHandlerField,
+			"function":     CreateUploadFunctionField,
+			"content_type": request.ContentType,
+			"size":         request.Size,
+			"error":        err.Error(),
+		}).Error("Error creating upload")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(contracts.NewUploadCreateResponse(upload, signedURL)); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     UploadHandlerField,
+			"function": CreateUploadFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+func (h *UploadHandler) Finalize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	uploadID := mux.Vars(r)["upload_id"]
+	if err := contracts.ValidateUploadID(uploadID); err != nil {
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	upload, err := h.finalizeUpload.Execute(ctx, uploadID)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":      UploadHandlerField,
+			"function":  FinalizeUploadFunctionField,
+			"upload_id": uploadID,
+			"error":     err.Error(),
+		}).Error("Error finalizing upload")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(upload); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     UploadHandlerField,
+			"function": FinalizeUploadFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
+
+func (h *UploadHandler) PurgeExpired(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	purged, err := h.purgeUploads.Execute(ctx)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     UploadHandlerField,
+			"function": PurgeUploadsFunctionField,
+			"error":    err.Error(),
+		}).Error("Error purging expired uploads")
+		httpErrors.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(contracts.UploadPurgeResponse{Purged: purged}); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     UploadHandlerField,
+			"function": PurgeUploadsFunctionField,
+			"sub_func": "json.Encode",
+			"error":    err.Error(),
+		}).Error("Error encoding response")
+	}
+}
@@ -0,0 +1,129 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+// EXIF orientation values (TIFF tag 0x0112)
+const (
+	orientationNormal         = 1
+	orientationFlipHorizontal = 2
+	orientationRotate180      = 3
+	orientationFlipVertical   = 4
+	orientationTranspose      = 5
+	orientationRotate90       = 6
+	orientationTransverse     = 7
+	orientationRotate270      = 8
+)
+
+// jpegOrientation reads the EXIF orientation of a JPEG, 1 when it has none
+// Phones store photos sideways and rely on this tag, so it must be applied
+// before the metadata is dropped by re-encoding
+func jpegOrientation(content []byte) int {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != 0xD8 {
+		return orientationNormal
+	}
+
+	// Walk the JPEG segments up to the start of the image data
+	for offset := 2; offset+4 <= len(content); {
+		if content[offset] != 0xFF {
+			return orientationNormal
+		}
+		marker := content[offset+1]
+		length := int(binary.BigEndian.Uint16(content[offset+2:]))
+		if marker == 0xDA || length < 2 || offset+2+length > len(content) {
+			return orientationNormal
+		}
+
+		segment := content[offset+4 : offset+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		offset += 2 + length
+	}
+
+	return orientationNormal
+}
+
+// tiffOrientation finds the orientation tag in the first IFD of an EXIF block
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return orientationNormal
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return orientationNormal
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return orientationNormal
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return orientationNormal
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < orientationNormal || orientation > orientationRotate270 {
+				return orientationNormal
+			}
+			return orientation
+		}
+	}
+
+	return orientationNormal
+}
+
+// applyOrientation returns the image as it should be displayed
+func applyOrientation(src image.Image, orientation int) image.Image {
+	if orientation == orientationNormal {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// Orientations 5 to 8 swap width and height
+	dstWidth, dstHeight := width, height
+	if orientation >= orientationTranspose {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case orientationFlipHorizontal:
+				dx, dy = width-1-x, y
+			case orientationRotate180:
+				dx, dy = width-1-x, height-1-y
+			case orientationFlipVertical:
+				dx, dy = x, height-1-y
+			case orientationTranspose:
+				dx, dy = y, x
+			case orientationRotate90:
+				dx, dy = height-1-y, x
+			case orientationTransverse:
+				dx, dy = height-1-y, width-1-x
+			case orientationRotate270:
+				dx, dy = y, width-1-x
+			}
+			dst.Set(dx, dy, src.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+
+	return dst
+}
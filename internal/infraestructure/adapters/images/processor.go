@@ -0,0 +1,213 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"image"
+	_ "image/gif" // register decoders
+	"image/jpeg"
+	_ "image/png" // register decoder
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register decoder
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Image processor log field constants
+const (
+	ImageProcessorField  = "image_processor"
+	ProcessFunctionField = "process"
+	EncodeSubFuncField   = "encode"
+)
+
+const (
+	// defaultJPEGQuality keeps photos sharp at a fraction of the phone file size
+	defaultJPEGQuality = 85
+	// defaultMaxPixels bounds the decoded size of a single image (about 160 MB as RGBA)
+	defaultMaxPixels  = 40_000_000
+	maxDefaultWorkers = 4
+)
+
+// RenditionSpec describes one rendition: its longest side in pixels, 0 keeps the original size
+type RenditionSpec struct {
+	Name    string
+	MaxSize int
+}
+
+// defaultRenditions are produced when IMAGE_RENDITIONS is not configured
+var defaultRenditions = []RenditionSpec{
+	{Name: "thumb", MaxSize: 200},
+	{Name: "medium", MaxSize: 800},
+}
+
+// ImageProcessor re-encodes uploads into the configured renditions
+// Decoding drops every metadata block (EXIF, GPS, ICC), so no rendition leaks them
+// Images are processed by a bounded pool: at most workers images are decoded at once,
+// the rest wait for a free slot, so concurrent uploads cannot exhaust memory
+type ImageProcessor struct {
+	renditions []RenditionSpec
+	slots      chan struct{}
+	maxPixels  int
+	quality    int
+}
+
+// NewImageProcessor reads the renditions from IMAGE_RENDITIONS (e.g. "thumb:200,medium:800")
+// and the pool size from IMAGE_PROCESSING_WORKERS
+func NewImageProcessor() *ImageProcessor {
+	renditions := parseRenditions(os.Getenv("IMAGE_RENDITIONS"))
+	if len(renditions) == 0 {
+		renditions = defaultRenditions
+	}
+
+	workers, err := strconv.Atoi(os.Getenv("IMAGE_PROCESSING_WORKERS"))
+	if err != nil || workers <= 0 {
+		workers = min(runtime.NumCPU(), maxDefaultWorkers)
+	}
+
+	return NewImageProcessorWith(renditions, workers)
+}
+
+// NewImageProcessorWith creates a processor for the renditions; the original is always produced
+func NewImageProcessorWith(renditions []RenditionSpec, workers int) *ImageProcessor {
+	specs := make([]RenditionSpec, 0, len(renditions)+1)
+	for _, spec := range renditions {
+		if spec.Name != models.OriginalRendition {
+			specs = append(specs, spec)
+		}
+	}
+	specs = append(specs, RenditionSpec{Name: models.OriginalRendition})
+
+	return &ImageProcessor{
+		renditions: specs,
+		slots:      make(chan struct{}, max(workers, 1)),
+		maxPixels:  defaultMaxPixels,
+		quality:    defaultJPEGQuality,
+	}
+}
+
+// parseRenditions reads a "name:size" list, ignoring malformed entries
+func parseRenditions(value string) []RenditionSpec {
+	var renditions []RenditionSpec
+	for _, entry := range strings.Split(value, ",") {
+		name, size, found := strings.Cut(strings.TrimSpace(entry), ":")
+		maxSize, err := strconv.Atoi(size)
+		if !found || name == "" || err != nil || maxSize <= 0 {
+			continue
+		}
+		renditions = append(renditions, RenditionSpec{Name: name, MaxSize: maxSize})
+	}
+	sort.SliceStable(renditions, func(i, j int) bool { return renditions[i].MaxSize < renditions[j].MaxSize })
+	return renditions
+}
+
+// Process decodes the image straight from the stream: the header read to check its size is
+// kept aside and replayed to the decoder, so the upload itself is never buffered whole
+func (p *ImageProcessor) Process(ctx context.Context, content io.Reader) ([]*models.ImageRendition, error) {
+	// Reject oversized images before allocating their pixels
+	var header bytes.Buffer
+	config, _, err := image.DecodeConfig(io.TeeReader(content, &header))
+	if err != nil {
+		return nil, &errors.ValidationError{Message: errors.InvalidImage}
+	}
+	if config.Width*config.Height > p.maxPixels {
+		return nil, &errors.ValidationError{Message: errors.ImageTooLarge}
+	}
+	// EXIF comes before the frame, so the header already holds it
+	orientation := jpegOrientation(header.Bytes())
+
+	select {
+	case p.slots <- struct{}{}:
+		defer func() { <-p.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	decoded, _, err := image.Decode(io.MultiReader(&header, content))
+	if err != nil {
+		return nil, &errors.ValidationError{Message: errors.InvalidImage}
+	}
+	source := toNRGBA(applyOrientation(decoded, orientation))
+
+	renditions := make([]*models.ImageRendition, 0, len(p.renditions))
+	for _, spec := range p.renditions {
+		rendition, err := p.render(source, spec)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":      ImageProcessorField,
+				"function":  ProcessFunctionField,
+				"sub_func":  EncodeSubFuncField,
+				"rendition": spec.Name,
+				"error":     err.Error(),
+			}).Error("Failed to encode image rendition")
+			return nil, err
+		}
+		renditions = append(renditions, rendition)
+	}
+
+	return renditions, nil
+}
+
+// render scales the source down to the rendition size and encodes it
+// Opaque images become JPEG; images with transparency become lossless WebP, smaller
+// than PNG. Photos stay JPEG because the only pure Go WebP encoder is lossless,
+// which is several times larger than a JPEG of the same photo
+func (p *ImageProcessor) render(source *image.NRGBA, spec RenditionSpec) (*models.ImageRendition, error) {
+	width, height := fit(source.Bounds().Dx(), source.Bounds().Dy(), spec.MaxSize)
+
+	scaled := source
+	if width != source.Bounds().Dx() || height != source.Bounds().Dy() {
+		scaled = image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), source, source.Bounds(), draw.Src, nil)
+	}
+
+	var buffer bytes.Buffer
+	contentType := "image/jpeg"
+	if scaled.Opaque() {
+		if err := jpeg.Encode(&buffer, scaled, &jpeg.Options{Quality: p.quality}); err != nil {
+			return nil, err
+		}
+	} else {
+		contentType = "image/webp"
+		if err := nativewebp.Encode(&buffer, scaled, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.ImageRendition{
+		Name:        spec.Name,
+		Content:     buffer.Bytes(),
+		ContentType: contentType,
+		Width:       width,
+		Height:      height,
+	}, nil
+}
+
+// fit scales the dimensions so the longest side is at most maxSize, never enlarging
+func fit(width, height, maxSize int) (int, int) {
+	longest := max(width, height)
+	if maxSize <= 0 || longest <= maxSize {
+		return width, height
+	}
+	return max(width*maxSize/longest, 1), max(height*maxSize/longest, 1)
+}
+
+// toNRGBA copies the image into an NRGBA so every rendition starts from the same pixels
+func toNRGBA(src image.Image) *image.NRGBA {
+	if nrgba, ok := src.(*image.NRGBA); ok {
+		return nrgba
+	}
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,163 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+func jpegContent(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 80, B: 40, A: 255})
+		}
+	}
+	var buffer bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buffer, img, nil))
+	return buffer.Bytes()
+}
+
+// withOrientation inserts an EXIF block with the orientation tag right after the JPEG SOI marker
+func withOrientation(content []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)      // one IFD entry
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112) // orientation tag
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)      // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)      // count
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0) // value padding + next IFD offset
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	result := append([]byte{}, content[:2]...)
+	result = append(result, app1...)
+	return append(result, content[2:]...)
+}
+
+func renditionsByName(renditions []*models.ImageRendition) map[string]*models.ImageRendition {
+	byName := make(map[string]*models.ImageRendition, len(renditions))
+	for _, rendition := range renditions {
+		byName[rendition.Name] = rendition
+	}
+	return byName
+}
+
+func TestImageProcessor_Process(t *testing.T) {
+	specs := []RenditionSpec{{Name: "thumb", MaxSize: 20}, {Name: "medium", MaxSize: 50}}
+
+	t.Run("when the image is larger than the renditions then scales its longest side", func(t *testing.T) {
+		// Arrange
+		processor := NewImageProcessorWith(specs, 1)
+
+		// Act
+		renditions, err := processor.Process(context.Background(), bytes.NewReader(jpegContent(t, 100, 40)))
+
+		// Assert
+		require.NoError(t, err)
+		byName := renditionsByName(renditions)
+		require.Len(t, byName, 3)
+		assert.Equal(t, []int{20, 8}, []int{byName["thumb"].Width, byName["thumb"].Height})
+		assert.Equal(t, []int{50, 20}, []int{byName["medium"].Width, byName["medium"].Height})
+		assert.Equal(t, []int{100, 40}, []int{byName[models.OriginalRendition].Width, byName[models.OriginalRendition].Height})
+
+		decoded, err := jpeg.Decode(bytes.NewReader(byName["thumb"].Content))
+		require.NoError(t, err)
+		assert.Equal(t, 20, decoded.Bounds().Dx())
+	})
+
+	t.Run("when the image arrives in small reads then decodes it from the stream", func(t *testing.T) {
+		// Arrange
+		content := withOrientation(jpegContent(t, 40, 10), orientationRotate90)
+
+		// Act
+		renditions, err := NewImageProcessorWith(specs, 1).Process(context.Background(), iotest.OneByteReader(bytes.NewReader(content)))
+
+		// Assert
+		require.NoError(t, err)
+		original := renditionsByName(renditions)[models.OriginalRendition]
+		assert.Equal(t, []int{10, 40}, []int{original.Width, original.Height})
+	})
+
+	t.Run("when the image is smaller than a rendition then never enlarges it", func(t *testing.T) {
+		renditions, err := NewImageProcessorWith(specs, 1).Process(context.Background(), bytes.NewReader(jpegContent(t, 30, 10)))
+
+		require.NoError(t, err)
+		medium := renditionsByName(renditions)["medium"]
+		assert.Equal(t, []int{30, 10}, []int{medium.Width, medium.Height})
+	})
+
+	t.Run("when the photo has an EXIF orientation then renditions are rotated upright", func(t *testing.T) {
+		// Arrange
+		content := withOrientation(jpegContent(t, 40, 10), orientationRotate90)
+
+		// Act
+		renditions, err := NewImageProcessorWith(specs, 1).Process(context.Background(), bytes.NewReader(content))
+
+		// Assert
+		require.NoError(t, err)
+		original := renditionsByName(renditions)[models.OriginalRendition]
+		assert.Equal(t, []int{10, 40}, []int{original.Width, original.Height})
+		assert.False(t, bytes.Contains(original.Content, []byte("Exif")))
+	})
+
+	t.Run("when the image is opaque then encodes JPEG and when it is transparent encodes WebP", func(t *testing.T) {
+		// Arrange
+		var transparent bytes.Buffer
+		require.NoError(t, png.Encode(&transparent, image.NewNRGBA(image.Rect(0, 0, 10, 10))))
+		processor := NewImageProcessorWith(specs, 1)
+
+		// Act
+		opaque, opaqueErr := processor.Process(context.Background(), bytes.NewReader(jpegContent(t, 10, 10)))
+		alpha, alphaErr := processor.Process(context.Background(), bytes.NewReader(transparent.Bytes()))
+
+		// Assert
+		require.NoError(t, opaqueErr)
+		require.NoError(t, alphaErr)
+		assert.Equal(t, "image/jpeg", opaque[0].ContentType)
+		assert.Equal(t, "image/webp", alpha[0].ContentType)
+		decoded, format, err := image.Decode(bytes.NewReader(alpha[0].Content))
+		require.NoError(t, err)
+		assert.Equal(t, "webp", format)
+		assert.False(t, decoded.(interface{ Opaque() bool }).Opaque())
+	})
+
+	t.Run("when the content is not an image then returns a validation error", func(t *testing.T) {
+		_, err := NewImageProcessorWith(specs, 1).Process(context.Background(), bytes.NewReader([]byte("not an image")))
+		assert.Equal(t, &errors.ValidationError{Message: errors.InvalidImage}, err)
+	})
+
+	t.Run("when the image has too many pixels then rejects it before decoding", func(t *testing.T) {
+		// Arrange
+		processor := NewImageProcessorWith(specs, 1)
+		processor.maxPixels = 100
+
+		// Act
+		_, err := processor.Process(context.Background(), bytes.NewReader(jpegContent(t, 20, 20)))
+
+		// Assert
+		assert.Equal(t, &errors.ValidationError{Message: errors.ImageTooLarge}, err)
+	})
+}
+
+func TestParseRenditions(t *testing.T) {
+	t.Run("when the list has malformed entries then skips them and sorts by size", func(t *testing.T) {
+		renditions := parseRenditions("medium:800, thumb:200,broken,zero:0")
+		assert.Equal(t, []RenditionSpec{{Name: "thumb", MaxSize: 200}, {Name: "medium", MaxSize: 800}}, renditions)
+	})
+}
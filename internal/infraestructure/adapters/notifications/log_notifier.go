@@ -0,0 +1,34 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Notifier log field constants
+const (
+	LogNotifierField            = "log_notifier"
+	WebhookNotifierField        = "webhook_notifier"
+	NotifyLowStockFunctionField = "notify_low_stock"
+)
+
+// LogNotifier writes alerts to the application log
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) NotifyLowStock(_ context.Context, product *models.LowStockProduct) error {
+	logs.WithFields(map[string]interface{}{
+		"file":          LogNotifierField,
+		"function":      NotifyLowStockFunctionField,
+		"shop_id":       product.ShopID,
+		"product_id":    product.ProductID,
+		"stock":         product.Stock,
+		"minimum_stock": product.MinimumStock,
+	}).Warn("Product stock is low")
+	return nil
+}
@@ -0,0 +1,18 @@
+package notifications
+
+import (
+	"os"
+	"strings"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+)
+
+// NewNotifier delivers alerts to the webhook configured in LOW_STOCK_WEBHOOK_URL,
+// falling back to the application log when no webhook is configured
+func NewNotifier() ports.Notifier {
+	url := strings.TrimSpace(os.Getenv("LOW_STOCK_WEBHOOK_URL"))
+	if url == "" {
+		return NewLogNotifier()
+	}
+	return NewWebhookNotifier(url)
+}
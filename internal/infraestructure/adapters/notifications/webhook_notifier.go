@@ -0,0 +1,66 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// LowStockEvent is the event name sent to webhooks for low-stock alerts
+const LowStockEvent = "product.low_stock"
+
+// webhookPayload is the JSON body posted to the webhook
+type webhookPayload struct {
+	Event   string                  `json:"event"`
+	Product *models.LowStockProduct `json:"product"`
+}
+
+// WebhookNotifier posts alerts as JSON to an HTTP endpoint
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (n *WebhookNotifier) NotifyLowStock(ctx context.Context, product *models.LowStockProduct) error {
+	body, err := json.Marshal(webhookPayload{Event: LowStockEvent, Product: product})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logs.WithFields(map[string]interface{}{
+			"file":       WebhookNotifierField,
+			"function":   NotifyLowStockFunctionField,
+			"product_id": product.ProductID,
+			"status":     resp.StatusCode,
+		}).Error("Webhook rejected low stock alert")
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
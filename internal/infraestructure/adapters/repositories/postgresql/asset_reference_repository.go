@@ -0,0 +1,81 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Asset reference repository log field constants
+const (
+	AssetReferenceRepositoryField  = "asset_reference_repository"
+	GetReferencedURLsFunctionField = "get_referenced_urls"
+	failedReadReferencedAssets     = "Failed to read referenced assets"
+)
+
+// assetReferencesQuery lists every URL that points at a stored asset: product images
+// and their renditions (deleted products keep theirs until purged), shop and category
+// images, and finalized uploads waiting for a product until they are purged
+const assetReferencesQuery = `
+		SELECT url FROM product_images
+		UNION
+		SELECT rendition.value FROM product_images, jsonb_each_text(product_images.renditions) AS rendition
+		UNION
+		SELECT image FROM shops WHERE image IS NOT NULL
+		UNION
+		SELECT image FROM categories WHERE image IS NOT NULL
+		UNION
+		SELECT url FROM uploads WHERE url IS NOT NULL
+		UNION
+		SELECT rendition.value FROM uploads, jsonb_each_text(uploads.renditions) AS rendition`
+
+type AssetReferenceRepository struct {
+	db *sql.DB
+}
+
+func NewAssetReferenceRepository(dataBaseConnection DataBaseConnection) *AssetReferenceRepository {
+	return &AssetReferenceRepository{
+		db: dataBaseConnection.Connect(),
+	}
+}
+
+func (r *AssetReferenceRepository) GetReferencedURLs(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, assetReferencesQuery)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     AssetReferenceRepositoryField,
+			"function": GetReferencedURLsFunctionField,
+			"error":    err.Error(),
+		}).Error(failedReadReferencedAssets)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     AssetReferenceRepositoryField,
+				"function": GetReferencedURLsFunctionField,
+				"sub_func": ScanField,
+				"error":    err.Error(),
+			}).Error(DatabaseScanFailedLog)
+			return nil, fmt.Errorf("database operation failed")
+		}
+		urls = append(urls, url)
+	}
+	if err := rows.Err(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     AssetReferenceRepositoryField,
+			"function": GetReferencedURLsFunctionField,
+			"sub_func": NextField,
+			"error":    err.Error(),
+		}).Error(failedReadReferencedAssets)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,398 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Cart repository log field constants
+const (
+	CartRepositoryField            = "cart_repository"
+	CartGetByOwnerFunctionField    = "get_by_owner"
+	CartCreateFunctionField        = "create"
+	CartAddLineFunctionField       = "add_line"
+	CartUpdateLineFunctionField    = "update_line"
+	CartRemoveLineFunctionField    = "remove_line"
+	CartClearFunctionField         = "clear"
+	CartTouchFunctionField         = "touch"
+	CartDeleteExpiredFunctionField = "delete_expired"
+	ReadCartLinesSubFuncField      = "read_cart_lines"
+	DeleteExpiredCartSubFuncField  = "delete_expired_cart"
+	InsertCartSubFuncField         = "insert_cart"
+	MarshalSelectionsSubFuncField  = "marshal_selections"
+)
+
+// Cart foreign keys that point at a missing shop or customer
+const (
+	cartShopForeignKey     = "carts_shop_id_fkey"
+	cartCustomerForeignKey = "carts_customer_id_fkey"
+)
+
+// Cart repository log message constants
+const (
+	failedReadCart           = "Failed to read cart"
+	failedCreateCart         = "Failed to create cart"
+	failedAddCartLine        = "Failed to add cart line"
+	failedUpdateCartLine     = "Failed to update cart line"
+	failedRemoveCartLine     = "Failed to remove cart line"
+	failedClearCart          = "Failed to clear cart"
+	failedTouchCart          = "Failed to touch cart"
+	failedDeleteExpiredCarts = "Failed to delete expired carts"
+)
+
+type CartRepository struct {
+	db *sql.DB
+}
+
+func NewCartRepository(dataBaseConnection DataBaseConnection) *CartRepository {
+	return &CartRepository{
+		db: dataBaseConnection.Connect(),
+	}
+}
+
+// cartOwnerCondition matches the carts of the owner, using the given argument position
+func cartOwnerCondition(owner models.CartOwner, position int) (string, interface{}) {
+	if owner.IsAnonymous() {
+		return fmt.Sprintf("token = $%d", position), owner.Token
+	}
+	return fmt.Sprintf("customer_id = $%d", position), owner.CustomerID
+}
+
+func (r *CartRepository) GetByOwner(ctx context.Context, shopID int, owner models.CartOwner, now time.Time) (*models.Cart, error) {
+	condition, ownerArg := cartOwnerCondition(owner, 3)
+
+	cart := &models.Cart{Lines: []*models.CartLine{}}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, shop_id, COALESCE(customer_id, 0), COALESCE(token, ''), expires_at, created_at, updated_at
+		FROM carts
+		WHERE shop_id = $1 AND expires_at > $2 AND `+condition,
+		shopID,
+		now,
+		ownerArg,
+	).Scan(&cart.ID, &cart.ShopID, &cart.CustomerID, &cart.Token, &cart.ExpiresAt, &cart.CreatedAt, &cart.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, &errors.RecordNotFoundError{Message: errors.CartNotFound}
+	}
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartGetByOwnerFunctionField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error(failedReadCart)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, product_id, selections, quantity
+		FROM cart_lines
+		WHERE cart_id = $1
+		ORDER BY id`,
+		cart.ID,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartGetByOwnerFunctionField,
+			"sub_func": ReadCartLinesSubFuncField,
+			"cart_id":  cart.ID,
+			"error":    err.Error(),
+		}).Error(failedReadCart)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		line := &models.CartLine{}
+		if err := rows.Scan(&line.ID, &line.ProductID, jsonColumn{dest: &line.Selections}, &line.Quantity); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     CartRepositoryField,
+				"function": CartGetByOwnerFunctionField,
+				"sub_func": ScanField,
+				"cart_id":  cart.ID,
+				"error":    err.Error(),
+			}).Error(DatabaseScanFailedLog)
+			return nil, fmt.Errorf("database operation failed")
+		}
+		cart.Lines = append(cart.Lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartGetByOwnerFunctionField,
+			"sub_func": NextField,
+			"cart_id":  cart.ID,
+			"error":    err.Error(),
+		}).Error(failedReadCart)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return cart, nil
+}
+
+// Create opens the cart of the owner, replacing an expired cart the owner still has in the shop
+func (r *CartRepository) Create(ctx context.Context, cart *models.Cart) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartCreateFunctionField,
+			"sub_func": BeginTransactionField,
+			"shop_id":  cart.ShopID,
+			"error":    err.Error(),
+		}).Error(FailedBeginTransactionLog)
+		return fmt.Errorf("database operation failed")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	condition, ownerArg := cartOwnerCondition(models.CartOwner{CustomerID: cart.CustomerID, Token: cart.Token}, 2)
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM carts
+		WHERE shop_id = $1 AND `+condition+` AND expires_at <= $3`,
+		cart.ShopID,
+		ownerArg,
+		cart.CreatedAt,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartCreateFunctionField,
+			"sub_func": DeleteExpiredCartSubFuncField,
+			"shop_id":  cart.ShopID,
+			"error":    err.Error(),
+		}).Error(failedCreateCart)
+		return fmt.Errorf("database operation failed")
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO carts (shop_id, customer_id, token, expires_at, created_at, updated_at)
+		VALUES ($1, NULLIF($2, 0), NULLIF($3, ''), $4, $5, $6)
+		RETURNING id`,
+		cart.ShopID,
+		cart.CustomerID,
+		cart.Token,
+		cart.ExpiresAt,
+		cart.CreatedAt,
+		cart.UpdatedAt,
+	).Scan(&cart.ID)
+	if err != nil {
+		// The shop or the customer may not exist
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			switch pqErr.Constraint {
+			case cartShopForeignKey:
+				return &errors.RecordNotFoundError{Message: errors.ShopNotFound}
+			case cartCustomerForeignKey:
+				return &errors.RecordNotFoundError{Message: errors.UserNotFound}
+			}
+		}
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartCreateFunctionField,
+			"sub_func": InsertCartSubFuncField,
+			"shop_id":  cart.ShopID,
+			"error":    err.Error(),
+		}).Error(failedCreateCart)
+		return fmt.Errorf("database operation failed")
+	}
+
+	if err = tx.Commit(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartCreateFunctionField,
+			"sub_func": CommitTransactionField,
+			"shop_id":  cart.ShopID,
+			"error":    err.Error(),
+		}).Error(FailedCommitTransactionLog)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// AddLine inserts the line when the product is an active listing of the cart shop
+func (r *CartRepository) AddLine(ctx context.Context, cart *models.Cart, line *models.CartLine) error {
+	selections, err := marshalSelections(line.Selections)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartAddLineFunctionField,
+			"sub_func": MarshalSelectionsSubFuncField,
+			"cart_id":  cart.ID,
+			"error":    err.Error(),
+		}).Error(failedAddCartLine)
+		return fmt.Errorf("database operation failed")
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO cart_lines (cart_id, product_id, selections, quantity)
+		SELECT $1, p.id, $3, $4
+		FROM products p
+		WHERE p.id = $2 AND p.shop_id = $5 AND p.deleted_at IS NULL
+		RETURNING id`,
+		cart.ID,
+		line.ProductID,
+		selections,
+		line.Quantity,
+		cart.ShopID,
+	).Scan(&line.ID)
+	if err == sql.ErrNoRows {
+		return &errors.RecordNotFoundError{Message: errors.ProductNotFound}
+	}
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       CartRepositoryField,
+			"function":   CartAddLineFunctionField,
+			"cart_id":    cart.ID,
+			"product_id": line.ProductID,
+			"error":      err.Error(),
+		}).Error(failedAddCartLine)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+func (r *CartRepository) UpdateLine(ctx context.Context, cartID int, line *models.CartLine) error {
+	selections, err := marshalSelections(line.Selections)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartUpdateLineFunctionField,
+			"sub_func": MarshalSelectionsSubFuncField,
+			"cart_id":  cartID,
+			"error":    err.Error(),
+		}).Error(failedUpdateCartLine)
+		return fmt.Errorf("database operation failed")
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE cart_lines SET quantity = $3, selections = $4, updated_at = now()
+		WHERE id = $2 AND cart_id = $1`,
+		cartID,
+		line.ID,
+		line.Quantity,
+		selections,
+	)
+	return r.expectLine(result, err, CartUpdateLineFunctionField, cartID, line.ID, failedUpdateCartLine)
+}
+
+func (r *CartRepository) RemoveLine(ctx context.Context, cartID, lineID int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM cart_lines WHERE id = $2 AND cart_id = $1`, cartID, lineID)
+	return r.expectLine(result, err, CartRemoveLineFunctionField, cartID, lineID, failedRemoveCartLine)
+}
+
+// expectLine checks that a line statement affected the line, which a concurrent request may have removed
+func (r *CartRepository) expectLine(result sql.Result, err error, function string, cartID, lineID int, message string) error {
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": function,
+			"cart_id":  cartID,
+			"line_id":  lineID,
+			"error":    err.Error(),
+		}).Error(message)
+		return fmt.Errorf("database operation failed")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": function,
+			"sub_func": RowsAffectedSubFuncField,
+			"cart_id":  cartID,
+			"line_id":  lineID,
+			"error":    err.Error(),
+		}).Error(message)
+		return fmt.Errorf("database operation failed")
+	}
+	if affected == 0 {
+		return &errors.RecordNotFoundError{Message: errors.CartLineNotFound}
+	}
+
+	return nil
+}
+
+func (r *CartRepository) Clear(ctx context.Context, cartID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM cart_lines WHERE cart_id = $1`, cartID)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartClearFunctionField,
+			"cart_id":  cartID,
+			"error":    err.Error(),
+		}).Error(failedClearCart)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// Touch stores the last activity of the cart and its new expiration
+func (r *CartRepository) Touch(ctx context.Context, cart *models.Cart) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE carts SET updated_at = $2, expires_at = $3
+		WHERE id = $1`,
+		cart.ID,
+		cart.UpdatedAt,
+		cart.ExpiresAt,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartTouchFunctionField,
+			"cart_id":  cart.ID,
+			"error":    err.Error(),
+		}).Error(failedTouchCart)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes the expired carts with their lines and returns how many were deleted
+func (r *CartRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM carts WHERE expires_at <= $1`, now)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartDeleteExpiredFunctionField,
+			"error":    err.Error(),
+		}).Error(failedDeleteExpiredCarts)
+		return 0, fmt.Errorf("database operation failed")
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     CartRepositoryField,
+			"function": CartDeleteExpiredFunctionField,
+			"sub_func": RowsAffectedSubFuncField,
+			"error":    err.Error(),
+		}).Error(failedDeleteExpiredCarts)
+		return 0, fmt.Errorf("database operation failed")
+	}
+
+	return int(deleted), nil
+}
+
+// marshalSelections encodes the chosen options, storing no choice as an empty array
+func marshalSelections(selections []models.VariantSelection) ([]byte, error) {
+	if selections == nil {
+		selections = []models.VariantSelection{}
+	}
+	return json.Marshal(selections)
+}
@@ -0,0 +1,739 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Order repository log field constants
+const (
+	OrderRepositoryField                  = "order_repository"
+	OrderCreateFunctionField              = "create"
+	OrderGetByIDFunctionField             = "get_by_id"
+	OrderGetByIdempotencyKeyFunctionField = "get_by_idempotency_key"
+	OrderGetAllByShopIDFunctionField      = "get_all_by_shop_id"
+	OrderGetAllByCustomerIDFunctionField  = "get_all_by_customer_id"
+	OrderTransitionFunctionField          = "transition"
+	InsertOrderSubFuncField               = "insert_order"
+	InsertOrderItemSubFuncField           = "insert_order_item"
+	MarshalOrderItemSubFuncField          = "marshal_order_item"
+	ReadOrderItemsSubFuncField            = "read_order_items"
+	LockStockSubFuncField                 = "lock_stock"
+	UpdateOptionStockSubFuncField         = "update_option_stock"
+	LockOrderSubFuncField                 = "lock_order"
+	UpdateOrderStatusSubFuncField         = "update_order_status"
+	InsertStatusChangeSubFuncField        = "insert_status_change"
+)
+
+// Order foreign keys that point at a missing shop or customer
+const (
+	orderShopForeignKey     = "orders_shop_id_fkey"
+	orderCustomerForeignKey = "orders_customer_id_fkey"
+)
+
+// orderIdempotencyKeyIndex keeps one order per idempotency key and shop
+const orderIdempotencyKeyIndex = "idx_orders_shop_id_idempotency_key"
+
+// Order repository log message constants
+const (
+	failedCreateOrder = "Failed to create order"
+	failedReadOrder   = "Failed to read order"
+	failedReadOrders  = "Failed to read orders"
+	failedTransition  = "Failed to transition order"
+	failedOrderStock  = "Failed to update the stock of an order"
+)
+
+// Queries that lock the ordered products, in ID order so concurrent orders queue instead of deadlocking
+// Checkouts only take active listings of the order shop; cancellations return the units of deleted ones too
+const (
+	lockListedProductsQuery = `
+		SELECT id, stock
+		FROM products
+		WHERE id = ANY($1) AND shop_id = $2 AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE`
+	lockOrderedProductsQuery = `
+		SELECT id, stock
+		FROM products
+		WHERE id = ANY($1) AND shop_id = $2
+		ORDER BY id
+		FOR UPDATE`
+)
+
+// orderColumns are the order columns every read scans with scanOrder
+const orderColumns = `id, shop_id, COALESCE(customer_id, 0), status, subtotal, total, item_count, COALESCE(notes, ''),
+	COALESCE(idempotency_key, ''), COALESCE(request_fingerprint, ''), created_at`
+
+type OrderRepository struct {
+	db *sql.DB
+}
+
+func NewOrderRepository(dataBaseConnection DataBaseConnection) *OrderRepository {
+	return &OrderRepository{
+		db: dataBaseConnection.Connect(),
+	}
+}
+
+// Create places the order in a single transaction: it stores the order and the snapshot
+// of its items, takes the ordered units from the locked stock and records the sales
+func (r *OrderRepository) Create(ctx context.Context, order *models.Order) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": OrderCreateFunctionField,
+			"sub_func": BeginTransactionField,
+			"shop_id":  order.ShopID,
+			"error":    err.Error(),
+		}).Error(FailedBeginTransactionLog)
+		return fmt.Errorf("database operation failed")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// The order goes first: the idempotency key index makes a concurrent retry
+	// wait here for this checkout instead of competing for the stock
+	if err = r.insertOrder(ctx, tx, order); err != nil {
+		return err
+	}
+
+	levels, err := r.lockStock(ctx, tx, order, OrderCreateFunctionField, true)
+	if err != nil {
+		return err
+	}
+
+	// Domain rules (e.g. insufficient stock) are checked against the locked stock
+	movements, err := order.Reserve(levels)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range order.Items {
+		if err = r.insertItem(ctx, tx, order, item); err != nil {
+			return err
+		}
+	}
+	if err = r.writeStock(ctx, tx, order, levels, movements, OrderCreateFunctionField); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": OrderCreateFunctionField,
+			"sub_func": CommitTransactionField,
+			"order_id": order.ID,
+			"error":    err.Error(),
+		}).Error(FailedCommitTransactionLog)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// Transition moves the order to the next status in a single transaction: it locks the order,
+// checks the transition against its current status and records the change in its history
+// Cancelled orders return their units to the locked stock
+func (r *OrderRepository) Transition(ctx context.Context, order *models.Order, next models.OrderStatus, actor string) (*models.OrderStatusChange, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": OrderTransitionFunctionField,
+			"sub_func": BeginTransactionField,
+			"order_id": order.ID,
+			"error":    err.Error(),
+		}).Error(FailedBeginTransactionLog)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// Concurrent transitions of the order queue here, each one seeing the status left by the previous
+	if err = r.lockStatus(ctx, tx, order); err != nil {
+		return nil, err
+	}
+
+	change, err := order.TransitionTo(next, actor, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if change.ReturnsStock() {
+		var levels *models.StockLevels
+		if levels, err = r.lockStock(ctx, tx, order, OrderTransitionFunctionField, false); err != nil {
+			return nil, err
+		}
+		movements := order.Restock(levels, actor)
+		if err = r.writeStock(ctx, tx, order, levels, movements, OrderTransitionFunctionField); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = r.recordStatusChange(ctx, tx, change); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": OrderTransitionFunctionField,
+			"sub_func": CommitTransactionField,
+			"order_id": order.ID,
+			"error":    err.Error(),
+		}).Error(FailedCommitTransactionLog)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return change, nil
+}
+
+// lockStatus locks the order row and refreshes the status the transition starts from
+func (r *OrderRepository) lockStatus(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	err := tx.QueryRowContext(ctx, `
+		SELECT status
+		FROM orders
+		WHERE id = $1
+		FOR UPDATE`,
+		order.ID,
+	).Scan(&order.Status)
+	if err == sql.ErrNoRows {
+		return &errors.RecordNotFoundError{Message: errors.OrderNotFound}
+	}
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": OrderTransitionFunctionField,
+			"sub_func": LockOrderSubFuncField,
+			"order_id": order.ID,
+			"error":    err.Error(),
+		}).Error(failedTransition)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// recordStatusChange stores the new status of the order and appends the change to its history
+func (r *OrderRepository) recordStatusChange(ctx context.Context, tx *sql.Tx, change *models.OrderStatusChange) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE orders
+		SET status = $2, updated_at = $3
+		WHERE id = $1`,
+		change.OrderID,
+		change.To,
+		change.CreatedAt,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": OrderTransitionFunctionField,
+			"sub_func": UpdateOrderStatusSubFuncField,
+			"order_id": change.OrderID,
+			"error":    err.Error(),
+		}).Error(failedTransition)
+		return fmt.Errorf("database operation failed")
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO order_status_history (order_id, from_status, to_status, actor, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		RETURNING id`,
+		change.OrderID,
+		change.From,
+		change.To,
+		change.Actor,
+		change.CreatedAt,
+	).Scan(&change.ID)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": OrderTransitionFunctionField,
+			"sub_func": InsertStatusChangeSubFuncField,
+			"order_id": change.OrderID,
+			"error":    err.Error(),
+		}).Error(failedTransition)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+func (r *OrderRepository) insertOrder(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO orders (shop_id, customer_id, status, subtotal, total, item_count, notes, idempotency_key, request_fingerprint, created_at, updated_at)
+		VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), $10, $10)
+		RETURNING id`,
+		order.ShopID,
+		order.CustomerID,
+		order.Status,
+		order.Subtotal,
+		order.Total,
+		order.ItemCount,
+		order.Notes,
+		order.IdempotencyKey,
+		order.RequestFingerprint,
+		order.CreatedAt,
+	).Scan(&order.ID)
+	if err == nil {
+		return nil
+	}
+
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch {
+		// Another checkout with the same key was placed first
+		case pqErr.Code == "23505" && pqErr.Constraint == orderIdempotencyKeyIndex:
+			return &errors.DuplicateRecordError{Message: errors.IdempotencyKeyInUse}
+		// The shop or the customer may not exist
+		case pqErr.Code == "23503" && pqErr.Constraint == orderShopForeignKey:
+			return &errors.RecordNotFoundError{Message: errors.ShopNotFound}
+		case pqErr.Code == "23503" && pqErr.Constraint == orderCustomerForeignKey:
+			return &errors.RecordNotFoundError{Message: errors.UserNotFound}
+		}
+	}
+	logs.WithFields(map[string]interface{}{
+		"file":     OrderRepositoryField,
+		"function": OrderCreateFunctionField,
+		"sub_func": InsertOrderSubFuncField,
+		"shop_id":  order.ShopID,
+		"error":    err.Error(),
+	}).Error(failedCreateOrder)
+	return fmt.Errorf("database operation failed")
+}
+
+// lockStock locks the ordered products and the options that track their stock
+// With listedOnly every product must be an active listing of the order shop
+func (r *OrderRepository) lockStock(ctx context.Context, tx *sql.Tx, order *models.Order, function string, listedOnly bool) (*models.StockLevels, error) {
+	levels := &models.StockLevels{Products: map[int]int{}, Options: map[int]int{}}
+
+	productIDs := order.ProductIDs()
+	productsQuery := lockOrderedProductsQuery
+	if listedOnly {
+		productsQuery = lockListedProductsQuery
+	}
+	if err := r.lockLevels(ctx, tx, function, productsQuery, levels.Products, pq.Array(productIDs), order.ShopID); err != nil {
+		return nil, err
+	}
+	if listedOnly && len(levels.Products) != len(productIDs) {
+		return nil, &errors.RecordNotFoundError{Message: errors.ProductNotFound}
+	}
+
+	optionIDs := order.OptionIDs()
+	if len(optionIDs) == 0 {
+		return levels, nil
+	}
+	if err := r.lockLevels(ctx, tx, function, `
+		SELECT id, stock
+		FROM variant_options
+		WHERE id = ANY($1) AND stock IS NOT NULL
+		ORDER BY id
+		FOR UPDATE`,
+		levels.Options,
+		pq.Array(optionIDs),
+	); err != nil {
+		return nil, err
+	}
+
+	return levels, nil
+}
+
+// lockLevels reads the stock of the locked rows into levels
+func (r *OrderRepository) lockLevels(ctx context.Context, tx *sql.Tx, function, query string, levels map[int]int, args ...interface{}) error {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": function,
+			"sub_func": LockStockSubFuncField,
+			"error":    err.Error(),
+		}).Error(failedOrderStock)
+		return fmt.Errorf("database operation failed")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, stock int
+		if err := rows.Scan(&id, &stock); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     OrderRepositoryField,
+				"function": function,
+				"sub_func": ScanField,
+				"error":    err.Error(),
+			}).Error(DatabaseScanFailedLog)
+			return fmt.Errorf("database operation failed")
+		}
+		levels[id] = stock
+	}
+	if err := rows.Err(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": function,
+			"sub_func": NextField,
+			"error":    err.Error(),
+		}).Error(failedOrderStock)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// insertItem stores the snapshot of an item
+func (r *OrderRepository) insertItem(ctx context.Context, tx *sql.Tx, order *models.Order, item *models.OrderItem) error {
+	selections, err := marshalSelections(item.Selections)
+	if err != nil {
+		return r.failMarshalItem(item, err)
+	}
+	options, err := json.Marshal(item.Options)
+	if err != nil {
+		return r.failMarshalItem(item, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO order_items (order_id, product_id, product_name, selections, options, quantity, base_price, unit_price, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		order.ID,
+		item.ProductID,
+		item.ProductName,
+		selections,
+		options,
+		item.Quantity,
+		item.BasePrice,
+		item.UnitPrice,
+		item.Total,
+	).Scan(&item.ID)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       OrderRepositoryField,
+			"function":   OrderCreateFunctionField,
+			"sub_func":   InsertOrderItemSubFuncField,
+			"order_id":   order.ID,
+			"product_id": item.ProductID,
+			"error":      err.Error(),
+		}).Error(failedCreateOrder)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// writeStock stores the stock levels left by the order movements and appends them to the ledger
+// Options are written in ID order, the order they were locked in
+func (r *OrderRepository) writeStock(ctx context.Context, tx *sql.Tx, order *models.Order, levels *models.StockLevels, movements []*models.StockMovement, function string) error {
+	for _, movement := range movements {
+		if err := r.applyMovement(ctx, tx, movement, function); err != nil {
+			return err
+		}
+	}
+	for _, optionID := range order.OptionIDs() {
+		stock, tracked := levels.Options[optionID]
+		if !tracked {
+			continue
+		}
+		if err := r.updateOptionStock(ctx, tx, optionID, stock, function); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyMovement stores the stock left by a movement of the order and appends it to the ledger
+func (r *OrderRepository) applyMovement(ctx context.Context, tx *sql.Tx, movement *models.StockMovement, function string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE products
+		SET stock = $2, version = version + 1
+		WHERE id = $1`,
+		movement.ProductID,
+		movement.StockAfter,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       OrderRepositoryField,
+			"function":   function,
+			"sub_func":   UpdateStockSubFuncField,
+			"product_id": movement.ProductID,
+			"error":      err.Error(),
+		}).Error(failedOrderStock)
+		return fmt.Errorf("database operation failed")
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO stock_movements (product_id, reason, quantity, stock_after, actor, reference)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+		RETURNING id, created_at`,
+		movement.ProductID,
+		movement.Reason,
+		movement.Quantity,
+		movement.StockAfter,
+		movement.Actor,
+		movement.Reference,
+	).Scan(&movement.ID, &movement.CreatedAt)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       OrderRepositoryField,
+			"function":   function,
+			"sub_func":   InsertStockMovementSubFuncField,
+			"product_id": movement.ProductID,
+			"error":      err.Error(),
+		}).Error(failedOrderStock)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+func (r *OrderRepository) updateOptionStock(ctx context.Context, tx *sql.Tx, optionID, stock int, function string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE variant_options SET stock = $2 WHERE id = $1`, optionID, stock)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":      OrderRepositoryField,
+			"function":  function,
+			"sub_func":  UpdateOptionStockSubFuncField,
+			"option_id": optionID,
+			"error":     err.Error(),
+		}).Error(failedOrderStock)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+func (r *OrderRepository) failMarshalItem(item *models.OrderItem, err error) error {
+	logs.WithFields(map[string]interface{}{
+		"file":       OrderRepositoryField,
+		"function":   OrderCreateFunctionField,
+		"sub_func":   MarshalOrderItemSubFuncField,
+		"product_id": item.ProductID,
+		"error":      err.Error(),
+	}).Error(failedCreateOrder)
+	return fmt.Errorf("database operation failed")
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, orderID int) (*models.Order, error) {
+	order, err := scanOrder(r.db.QueryRowContext(ctx, `
+		SELECT `+orderColumns+`
+		FROM orders
+		WHERE id = $1`,
+		orderID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, &errors.RecordNotFoundError{Message: errors.OrderNotFound}
+	}
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": OrderGetByIDFunctionField,
+			"order_id": orderID,
+			"error":    err.Error(),
+		}).Error(failedReadOrder)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	if err := r.loadItems(ctx, []*models.Order{order}, OrderGetByIDFunctionField); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetByIdempotencyKey returns the order placed in the shop with the idempotency key
+func (r *OrderRepository) GetByIdempotencyKey(ctx context.Context, shopID int, key string) (*models.Order, error) {
+	order, err := scanOrder(r.db.QueryRowContext(ctx, `
+		SELECT `+orderColumns+`
+		FROM orders
+		WHERE shop_id = $1 AND idempotency_key = $2`,
+		shopID,
+		key,
+	))
+	if err == sql.ErrNoRows {
+		return nil, &errors.RecordNotFoundError{Message: errors.OrderNotFound}
+	}
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": OrderGetByIdempotencyKeyFunctionField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error(failedReadOrder)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	if err := r.loadItems(ctx, []*models.Order{order}, OrderGetByIdempotencyKeyFunctionField); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetAllByShopID returns the orders of a shop, newest first
+// A positive cursor returns the orders older than that order ID
+func (r *OrderRepository) GetAllByShopID(ctx context.Context, shopID, limit, cursor int) ([]*models.Order, error) {
+	return r.getAll(ctx, OrderGetAllByShopIDFunctionField, `shop_id = $1`, shopID, limit, cursor)
+}
+
+// GetAllByCustomerID returns the order history of a customer across shops, newest first
+func (r *OrderRepository) GetAllByCustomerID(ctx context.Context, customerID, limit, cursor int) ([]*models.Order, error) {
+	return r.getAll(ctx, OrderGetAllByCustomerIDFunctionField, `customer_id = $1`, customerID, limit, cursor)
+}
+
+func (r *OrderRepository) getAll(ctx context.Context, function, condition string, ownerID, limit, cursor int) ([]*models.Order, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+orderColumns+`
+		FROM orders
+		WHERE `+condition+` AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3`,
+		ownerID,
+		cursor,
+		limit,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": function,
+			"owner_id": ownerID,
+			"error":    err.Error(),
+		}).Error(failedReadOrders)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer rows.Close()
+
+	orders := []*models.Order{}
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     OrderRepositoryField,
+				"function": function,
+				"sub_func": ScanField,
+				"owner_id": ownerID,
+				"error":    err.Error(),
+			}).Error(DatabaseScanFailedLog)
+			return nil, fmt.Errorf("database operation failed")
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": function,
+			"sub_func": NextField,
+			"owner_id": ownerID,
+			"error":    err.Error(),
+		}).Error(failedReadOrders)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	if err := r.loadItems(ctx, orders, function); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// loadItems reads the items of every order with a single query
+func (r *OrderRepository) loadItems(ctx context.Context, orders []*models.Order, function string) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.Order, len(orders))
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+		ids = append(ids, int64(order.ID))
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, order_id, COALESCE(product_id, 0), product_name, selections, options, quantity, base_price, unit_price, total
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, id`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": function,
+			"sub_func": ReadOrderItemsSubFuncField,
+			"error":    err.Error(),
+		}).Error(failedReadOrder)
+		return fmt.Errorf("database operation failed")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID int
+		item := &models.OrderItem{}
+		err := rows.Scan(
+			&item.ID,
+			&orderID,
+			&item.ProductID,
+			&item.ProductName,
+			jsonColumn{dest: &item.Selections},
+			jsonColumn{dest: &item.Options},
+			&item.Quantity,
+			&item.BasePrice,
+			&item.UnitPrice,
+			&item.Total,
+		)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     OrderRepositoryField,
+				"function": function,
+				"sub_func": ScanField,
+				"error":    err.Error(),
+			}).Error(DatabaseScanFailedLog)
+			return fmt.Errorf("database operation failed")
+		}
+		if order, ok := byID[orderID]; ok {
+			order.Items = append(order.Items, item)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     OrderRepositoryField,
+			"function": function,
+			"sub_func": NextField,
+			"error":    err.Error(),
+		}).Error(failedReadOrder)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// scanOrder reads the orderColumns of a row into an order without items
+func scanOrder(row rowScanner) (*models.Order, error) {
+	order := &models.Order{Items: []*models.OrderItem{}}
+	err := row.Scan(
+		&order.ID,
+		&order.ShopID,
+		&order.CustomerID,
+		&order.Status,
+		&order.Subtotal,
+		&order.Total,
+		&order.ItemCount,
+		&order.Notes,
+		&order.IdempotencyKey,
+		&order.RequestFingerprint,
+		&order.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
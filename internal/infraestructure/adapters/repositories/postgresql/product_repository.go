@@ -5,6 +5,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"html"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
@@ -25,10 +28,22 @@ const (
 	ProductGetAllByShopIDFunctionField = "get_all_by_shop_id"
 	ProductGetByIDFunctionField        = "get_by_id"
 	ProductUpdateFunctionField         = "update"
+	ProductPatchFunctionField          = "patch"
+	ProductSearchFunctionField         = "search"
+	ProductSoftDeleteFunctionField     = "soft_delete"
+	ProductRestoreFunctionField        = "restore"
+	ProductPurgeDeletedFunctionField   = "purge_deleted"
+	ProductReorderImagesFunctionField  = "reorder_images"
+	CountImagesSubFuncField            = "count_images"
+	ClearPrimaryImageSubFuncField      = "clear_primary_image"
+	UpdateImagePositionsSubFuncField   = "update_image_positions"
+	BumpVersionSubFuncField            = "bump_version"
+	RowsAffectedSubFuncField           = "rows_affected"
 	ProductUnmarshallSubFuncField      = "unmarshall"
 	MarshalVariantsSubFuncField        = "marshal_variants"
 	MarshalImagesSubFuncField          = "marshal_images"
 	CallStoredProcedureSubFuncField    = "call_stored_procedure"
+	ApplyCursorSubFuncField            = "apply_cursor"
 )
 
 // Product repository log message constants
@@ -43,6 +58,11 @@ const (
 	LogFailedMarshalImages       = "Failed to marshal images for stored procedure"
 	failedReadProductsByShop     = "Failed to read products by shop"
 	failedReadProductByID        = "Failed to read product by ID"
+	failedSearchProducts         = "Failed to search products"
+	failedSoftDeleteProduct      = "Failed to soft delete product"
+	failedRestoreProduct         = "Failed to restore product"
+	failedPurgeDeletedProducts   = "Failed to purge deleted products"
+	failedReorderProductImages   = "Failed to reorder product images"
 	productNotFoundMessage       = "Product not found"
 )
 
@@ -52,8 +72,306 @@ func NewProductRepository(dataBaseConnection DataBaseConnection) *ProductReposit
 	}
 }
 
-func (r *ProductRepository) GetAllByShopID(ctx context.Context, shopID, limit, cursor int) ([]*models.Product, error) {
+// PostgreSQL error codes raised by the update_product stored procedure
+const (
+	pgSerializationFailureCode = "40001" // stored version differs from the expected one
+	pgNoDataFoundCode          = "P0002" // product does not exist or was deleted
+	pgRestrictViolationCode    = "23001" // new price is not above a promotion that has not ended
+)
+
+// productSelectColumns is shared by every product read query
+// Images, variants and promotions are aggregated as JSONB to load a product in a single round trip
+// The shop time zone is loaded to evaluate promotion schedules
+const productSelectColumns = `
+		p.id, p.name, p.description, p.price, p.stock, COALESCE(p.minimum_stock, 0),
+		p.is_active, p.is_highlighted, p.is_promotional, COALESCE(p.promotional_price, 0),
+		p.created_at, p.version,
+		c.id, c.name, COALESCE(c.description, ''),
+		COALESCE(
+			(SELECT jsonb_agg(
+				jsonb_build_object(
+					'id', pi2.id,
+					'url', pi2.url,
+					'renditions', pi2.renditions,
+					'position', pi2.position,
+					'is_primary', pi2.is_primary
+				) ORDER BY pi2.position, pi2.id
+			)
+			FROM product_images pi2
+			WHERE pi2.product_id = p.id),
+			'[]'::jsonb
+		) AS images,
+		COALESCE(
+			(SELECT jsonb_agg(
+				jsonb_build_object(
+					'id', pv2.id,
+					'name', pv2.name,
+					'order', pv2."order",
+					'selection_type', pv2.selection_type,
+					'max_selections', pv2.max_selections,
+					'is_required', pv2.is_required,
+					'rules', pv2.rules,
+					'options', (
+						SELECT COALESCE(jsonb_agg(
+							jsonb_build_object(
+								'id', vo.id,
+								'name', vo.name,
+								'price', vo.price,
+								'order', vo."order",
+								'stock', vo.stock,
+								'is_available', vo.is_available
+							) ORDER BY vo."order"
+						), '[]'::jsonb)
+						FROM variant_options vo
+						WHERE vo.variant_id = pv2.id
+					)
+				) ORDER BY pv2."order"
+			)
+			FROM product_variants pv2
+			WHERE pv2.product_id = p.id),
+			'[]'::jsonb
+		) AS variants,
+		COALESCE(
+			(SELECT jsonb_agg(
+				jsonb_build_object(
+					'id', pp.id,
+					'price', pp.price,
+					'starts_at', pp.starts_at,
+					'ends_at', pp.ends_at,
+					'recurrence', CASE WHEN pp.weekdays IS NULL THEN NULL ELSE jsonb_build_object(
+						'weekdays', to_jsonb(pp.weekdays),
+						'start_time', to_char(pp.start_time, 'HH24:MI'),
+						'end_time', to_char(pp.end_time, 'HH24:MI')
+					) END
+				) ORDER BY pp.starts_at, pp.id
+			)
+			FROM product_promotions pp
+			WHERE pp.product_id = p.id),
+			'[]'::jsonb
+		) AS promotions,
+		COALESCE((SELECT s.time_zone FROM shops s WHERE s.id = p.shop_id), '') AS time_zone`
+
+const productSelectQuery = `
+	SELECT` + productSelectColumns + `
+	FROM products p
+	INNER JOIN categories c ON p.category_id = c.id`
+
+// productSearchRankQuery ranks every match and opens the "page" CTE, which the
+// caller completes with the keyset condition, ordering and limit
+// $1 shop ID, $2 language override (empty uses the shop setting), $3 tsquery text
+const productSearchRankQuery = `
+	WITH search_config AS (
+		SELECT (CASE COALESCE(NULLIF($2, ''), (SELECT s.search_language FROM shops s WHERE s.id = $1), 'es')
+			WHEN 'en' THEN 'en_unaccent'
+			ELSE 'es_unaccent'
+		END)::regconfig AS config
+	),
+	search AS (
+		SELECT sc.config, to_tsquery(sc.config, $3) AS query
+		FROM search_config sc
+	),
+	ranked AS (
+		SELECT p.id, ts_rank(p.search_vector, search.query)::float8 AS rank
+		FROM products p
+		CROSS JOIN search
+		WHERE p.shop_id = $1
+		  AND p.deleted_at IS NULL
+		  AND COALESCE(p.is_active, false) = true
+		  AND p.search_vector @@ search.query
+	),
+	page AS (
+		SELECT ranked.id, ranked.rank
+		FROM ranked`
+
+// Highlight markers are control characters stripped from the product text, so
+// the highlight can be HTML-escaped before they become <mark> tags
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var highlightMarkers = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// markHighlight escapes the product text of a highlight and marks the matched words
+func markHighlight(highlight string) string {
+	return highlightMarkers.Replace(html.EscapeString(highlight))
+}
+
+// productSearchPageQuery loads the products of the page; highlights are only
+// computed for these rows
+const productSearchPageQuery = `
+	)
+	SELECT` + productSelectColumns + `,
+		page.rank,
+		ts_headline(search.config, translate(COALESCE(p.name, ''), chr(2) || chr(3), ''), search.query,
+			'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', HighlightAll=true'),
+		ts_headline(search.config, translate(COALESCE(p.description, ''), chr(2) || chr(3), ''), search.query,
+			'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxFragments=2, MaxWords=20, MinWords=5')
+	FROM page
+	INNER JOIN products p ON p.id = page.id
+	INNER JOIN categories c ON p.category_id = c.id
+	CROSS JOIN search
+	ORDER BY page.rank DESC, page.id DESC`
+
+// productScanDest returns the scan destinations matching productSelectQuery columns
+func productScanDest(product *models.Product, imagesJSON, variantsJSON *[]byte) []interface{} {
+	return []interface{}{
+		&product.ID,
+		&product.Name,
+		&product.Description,
+		&product.Price,
+		&product.Stock,
+		&product.MinimumStock,
+		&product.IsActive,
+		&product.IsHighlighted,
+		&product.IsPromotional,
+		&product.PromotionalPrice,
+		&product.CreatedAt,
+		&product.Version,
+		&product.Category.ID,
+		&product.Category.Name,
+		&product.Category.Description,
+		imagesJSON,
+		variantsJSON,
+		jsonColumn{dest: &product.Promotions},
+		&product.TimeZone,
+	}
+}
+
+// jsonColumn scans a JSONB column straight into dest
+type jsonColumn struct {
+	dest interface{}
+}
+
+func (c jsonColumn) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, c.dest)
+	case string:
+		return json.Unmarshal([]byte(value), c.dest)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T as JSON", src)
+	}
+}
+
+// productSortExpressions maps each sort field to its SQL expression
+// NULL-able columns are coalesced so keyset comparisons never see NULLs
+var productSortExpressions = map[models.ProductSortField]string{
+	models.ProductSortByPrice:     "COALESCE(p.price, 0)",
+	models.ProductSortByName:      "COALESCE(p.name, '')",
+	models.ProductSortByCreatedAt: "p.created_at",
+}
+
+// productListQuery accumulates the WHERE conditions and positional arguments of a listing
+type productListQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+// addArg appends a positional argument and returns its placeholder ($n)
+func (q *productListQuery) addArg(value interface{}) string {
+	q.args = append(q.args, value)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+func (q *productListQuery) where(condition string) {
+	q.conditions = append(q.conditions, condition)
+}
+
+func (q *productListQuery) applyFilters(filter *models.ProductFilter) {
+	if filter.CategoryID != nil {
+		q.where("p.category_id = " + q.addArg(*filter.CategoryID))
+	}
+	if filter.IsActive != nil {
+		q.where("COALESCE(p.is_active, false) = " + q.addArg(*filter.IsActive))
+	}
+	if filter.IsPromotional != nil {
+		q.where("p.is_promotional = " + q.addArg(*filter.IsPromotional))
+	}
+	if filter.IsHighlighted != nil {
+		q.where("p.is_highlighted = " + q.addArg(*filter.IsHighlighted))
+	}
+	if filter.InStock != nil {
+		if *filter.InStock {
+			q.where("p.stock > 0")
+		} else {
+			q.where("p.stock <= 0")
+		}
+	}
+	// Mirrors Product.IsLowStock: stock at or below the minimum
+	if filter.IsLowStock != nil {
+		if *filter.IsLowStock {
+			q.where("p.stock <= COALESCE(p.minimum_stock, 0)")
+		} else {
+			q.where("p.stock > COALESCE(p.minimum_stock, 0)")
+		}
+	}
+	if filter.MinPrice != nil {
+		q.where("p.price >= " + q.addArg(*filter.MinPrice))
+	}
+	if filter.MaxPrice != nil {
+		q.where("p.price <= " + q.addArg(*filter.MaxPrice))
+	}
+}
+
+// applyCursor adds the keyset condition that skips every row up to the cursor
+// The sort key and the ID are compared as a row value so ties are broken by ID
+func (q *productListQuery) applyCursor(filter *models.ProductFilter) error {
+	cursor := filter.Cursor
+	if cursor == nil {
+		return nil
+	}
+
+	operator := "<"
+	if filter.SortDirection == models.SortAscending {
+		operator = ">"
+	}
+
+	expression, sorted := productSortExpressions[filter.SortBy]
+	if !sorted {
+		q.where("p.id " + operator + " " + q.addArg(cursor.ID))
+		return nil
+	}
+
+	var value interface{}
+	switch filter.SortBy {
+	case models.ProductSortByPrice:
+		price, err := cursor.PriceValue()
+		if err != nil {
+			return err
+		}
+		value = price
+	case models.ProductSortByCreatedAt:
+		createdAt, err := cursor.TimeValue()
+		if err != nil {
+			return err
+		}
+		value = createdAt
+	default:
+		value = cursor.Value
+	}
+
+	q.where(fmt.Sprintf("(%s, p.id) %s (%s, %s)", expression, operator, q.addArg(value), q.addArg(cursor.ID)))
+	return nil
+}
+
+func productOrderBy(filter *models.ProductFilter) string {
+	direction := "DESC"
+	if filter.SortDirection == models.SortAscending {
+		direction = "ASC"
+	}
+
+	if expression, sorted := productSortExpressions[filter.SortBy]; sorted {
+		return fmt.Sprintf("ORDER BY %s %s, p.id %s", expression, direction, direction)
+	}
+	return "ORDER BY p.id " + direction
+}
+
+func (r *ProductRepository) GetAllByShopID(ctx context.Context, shopID int, filter *models.ProductFilter) ([]*models.Product, error) {
 	// Default limit if not specified
+	limit := filter.Limit
 	if limit <= 0 {
 		limit = 20
 	}
@@ -62,112 +380,27 @@ func (r *ProductRepository) GetAllByShopID(ctx context.Context, shopID, limit, c
 		limit = 100
 	}
 
-	var query string
-	var args []interface{}
-
-	if cursor > 0 {
-		// Cursor-based pagination
-		query = `
-			SELECT
-				p.id, p.name, p.description, p.price, p.stock, COALESCE(p.minimum_stock, 0),
-				p.is_active, p.is_highlighted, p.is_promotional, COALESCE(p.promotional_price, 0),
-				c.id, c.name, COALESCE(c.description, ''),
-				COALESCE(
-					(SELECT jsonb_agg(
-						jsonb_build_object(
-							'id', pi2.id,
-							'url', pi2.url
-						) ORDER BY pi2.id
-					)
-					FROM product_images pi2
-					WHERE pi2.product_id = p.id),
-					'[]'::jsonb
-				) AS images,
-				COALESCE(
-					(SELECT jsonb_agg(
-						jsonb_build_object(
-							'id', pv2.id,
-							'name', pv2.name,
-							'order', pv2."order",
-							'selection_type', pv2.selection_type,
-							'max_selections', pv2.max_selections,
-							'options', (
-								SELECT COALESCE(jsonb_agg(
-									jsonb_build_object(
-										'id', vo.id,
-										'name', vo.name,
-										'price', vo.price,
-										'order', vo."order"
-									) ORDER BY vo."order"
-								), '[]'::jsonb)
-								FROM variant_options vo
-								WHERE vo.variant_id = pv2.id
-							)
-						) ORDER BY pv2."order"
-					)
-					FROM product_variants pv2
-					WHERE pv2.product_id = p.id),
-					'[]'::jsonb
-				) AS variants
-			FROM products p
-			INNER JOIN categories c ON p.category_id = c.id
-			WHERE p.shop_id = $1 AND p.id < $2
-			ORDER BY p.id DESC
-			LIMIT $3`
-		args = []interface{}{shopID, cursor, limit}
-	} else {
-		// First page
-		query = `
-			SELECT
-				p.id, p.name, p.description, p.price, p.stock, COALESCE(p.minimum_stock, 0),
-				p.is_active, p.is_highlighted, p.is_promotional, COALESCE(p.promotional_price, 0),
-				c.id, c.name, COALESCE(c.description, ''),
-				COALESCE(
-					(SELECT jsonb_agg(
-						jsonb_build_object(
-							'id', pi2.id,
-							'url', pi2.url
-						) ORDER BY pi2.id
-					)
-					FROM product_images pi2
-					WHERE pi2.product_id = p.id),
-					'[]'::jsonb
-				) AS images,
-				COALESCE(
-					(SELECT jsonb_agg(
-						jsonb_build_object(
-							'id', pv2.id,
-							'name', pv2.name,
-							'order', pv2."order",
-							'selection_type', pv2.selection_type,
-							'max_selections', pv2.max_selections,
-							'options', (
-								SELECT COALESCE(jsonb_agg(
-									jsonb_build_object(
-										'id', vo.id,
-										'name', vo.name,
-										'price', vo.price,
-										'order', vo."order"
-									) ORDER BY vo."order"
-								), '[]'::jsonb)
-								FROM variant_options vo
-								WHERE vo.variant_id = pv2.id
-							)
-						) ORDER BY pv2."order"
-					)
-					FROM product_variants pv2
-					WHERE pv2.product_id = p.id),
-					'[]'::jsonb
-				) AS variants
-			FROM products p
-			INNER JOIN categories c ON p.category_id = c.id
-			WHERE p.shop_id = $1
-			ORDER BY p.id DESC
-			LIMIT $2`
-		args = []interface{}{shopID, limit}
+	listQuery := &productListQuery{}
+	listQuery.where("p.shop_id = " + listQuery.addArg(shopID))
+	listQuery.where("p.deleted_at IS NULL")
+	listQuery.applyFilters(filter)
+	if err := listQuery.applyCursor(filter); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductRepositoryField,
+			"function": ProductGetAllByShopIDFunctionField,
+			"sub_func": ApplyCursorSubFuncField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Invalid product cursor")
+		return nil, &errors.ValidationError{Message: errors.InvalidCursorFormat}
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	query := productSelectQuery + `
+		WHERE ` + strings.Join(listQuery.conditions, " AND ") + `
+		` + productOrderBy(filter) + `
+		LIMIT ` + listQuery.addArg(limit)
+
+	rows, err := r.db.QueryContext(ctx, query, listQuery.args...)
 	if err != nil {
 		logs.WithFields(map[string]interface{}{
 			"file":     ProductRepositoryField,
@@ -189,23 +422,7 @@ func (r *ProductRepository) GetAllByShopID(ctx context.Context, shopID, limit, c
 
 		var imagesJSON, variantsJSON []byte
 
-		err := rows.Scan(
-			&product.ID,
-			&product.Name,
-			&product.Description,
-			&product.Price,
-			&product.Stock,
-			&product.MinimumStock,
-			&product.IsActive,
-			&product.IsHighlighted,
-			&product.IsPromotional,
-			&product.PromotionalPrice,
-			&product.Category.ID,
-			&product.Category.Name,
-			&product.Category.Description,
-			&imagesJSON,
-			&variantsJSON,
-		)
+		err := rows.Scan(productScanDest(product, &imagesJSON, &variantsJSON)...)
 		if err != nil {
 			logs.WithFields(map[string]interface{}{
 				"file":     ProductRepositoryField,
@@ -251,52 +468,118 @@ func (r *ProductRepository) GetAllByShopID(ctx context.Context, shopID, limit, c
 	return products, nil
 }
 
+// buildPrefixTSQuery turns search terms into a tsquery where every term must
+// match as a prefix ("burg chee" -> "burg:* & chee:*")
+// Terms only contain letters and digits, so no tsquery operator can be injected
+func buildPrefixTSQuery(terms []string) string {
+	prefixed := make([]string, len(terms))
+	for i, term := range terms {
+		prefixed[i] = term + ":*"
+	}
+	return strings.Join(prefixed, " & ")
+}
+
+func (r *ProductRepository) Search(ctx context.Context, shopID int, search *models.ProductSearch) ([]*models.ProductSearchResult, error) {
+	// Default limit if not specified
+	limit := search.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	// Max limit to prevent abuse
+	if limit > 100 {
+		limit = 100
+	}
+
+	query := productSearchRankQuery
+	args := []interface{}{shopID, string(search.Language), buildPrefixTSQuery(search.Terms())}
+
+	// Keyset pagination on (rank DESC, id DESC)
+	if search.Cursor != nil {
+		query += `
+		WHERE (ranked.rank, ranked.id) < ($4, $5)
+		ORDER BY ranked.rank DESC, ranked.id DESC
+		LIMIT $6`
+		args = append(args, search.Cursor.Rank, search.Cursor.ID, limit)
+	} else {
+		query += `
+		ORDER BY ranked.rank DESC, ranked.id DESC
+		LIMIT $4`
+		args = append(args, limit)
+	}
+	query += productSearchPageQuery
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductRepositoryField,
+			"function": ProductSearchFunctionField,
+			"shop_id":  shopID,
+			"query":    search.Query,
+			"error":    err.Error(),
+		}).Error(failedSearchProducts)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer rows.Close()
+
+	results := make([]*models.ProductSearchResult, 0)
+
+	for rows.Next() {
+		result := &models.ProductSearchResult{
+			Product: &models.Product{Category: &models.Category{}},
+		}
+
+		var imagesJSON, variantsJSON []byte
+
+		dest := append(productScanDest(result.Product, &imagesJSON, &variantsJSON),
+			&result.Rank, &result.NameHighlight, &result.DescriptionHighlight)
+		if err := rows.Scan(dest...); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     ProductRepositoryField,
+				"function": ProductSearchFunctionField,
+				"sub_func": ScanField,
+				"shop_id":  shopID,
+				"error":    err.Error(),
+			}).Error("Failed to scan product search row")
+			return nil, fmt.Errorf("database operation failed")
+		}
+		result.NameHighlight = markHighlight(result.NameHighlight)
+		result.DescriptionHighlight = markHighlight(result.DescriptionHighlight)
+
+		if err := json.Unmarshal(imagesJSON, &result.Product.Images); err != nil {
+			return nil, fmt.Errorf("database operation failed")
+		}
+
+		if err := json.Unmarshal(variantsJSON, &result.Product.Variants); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":       ProductRepositoryField,
+				"function":   ProductSearchFunctionField,
+				"sub_func":   UnmarshallField,
+				"product_id": result.Product.ID,
+				"error":      err.Error(),
+			}).Error("Failed to unmarshal product variants")
+			return nil, fmt.Errorf("database operation failed")
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductRepositoryField,
+			"function": ProductSearchFunctionField,
+			"sub_func": NextField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error("Error iterating product search rows")
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return results, nil
+}
+
 func (r *ProductRepository) GetByID(ctx context.Context, productID int) (*models.Product, error) {
-	query := `
-		SELECT
-			p.id, p.name, p.description, p.price, p.stock, COALESCE(p.minimum_stock, 0),
-			p.is_active, p.is_highlighted, p.is_promotional, COALESCE(p.promotional_price, 0),
-			c.id, c.name, COALESCE(c.description, ''),
-			COALESCE(
-				(SELECT jsonb_agg(
-					jsonb_build_object(
-						'id', pi2.id,
-						'url', pi2.url
-					) ORDER BY pi2.id
-				)
-				FROM product_images pi2
-				WHERE pi2.product_id = p.id),
-				'[]'::jsonb
-			) AS images,
-			COALESCE(
-				(SELECT jsonb_agg(
-					jsonb_build_object(
-						'id', pv2.id,
-						'name', pv2.name,
-						'order', pv2."order",
-						'selection_type', pv2.selection_type,
-						'max_selections', pv2.max_selections,
-						'options', (
-							SELECT COALESCE(jsonb_agg(
-								jsonb_build_object(
-									'id', vo.id,
-									'name', vo.name,
-									'price', vo.price,
-									'order', vo."order"
-								) ORDER BY vo."order"
-							), '[]'::jsonb)
-							FROM variant_options vo
-							WHERE vo.variant_id = pv2.id
-						)
-					) ORDER BY pv2."order"
-				)
-				FROM product_variants pv2
-				WHERE pv2.product_id = p.id),
-				'[]'::jsonb
-			) AS variants
-		FROM products p
-		INNER JOIN categories c ON p.category_id = c.id
-		WHERE p.id = $1`
+	query := productSelectQuery + `
+		WHERE p.id = $1 AND p.deleted_at IS NULL`
 
 	product := &models.Product{
 		Category: &models.Category{},
@@ -304,23 +587,7 @@ func (r *ProductRepository) GetByID(ctx context.Context, productID int) (*models
 
 	var imagesJSON, variantsJSON []byte
 
-	err := r.db.QueryRowContext(ctx, query, productID).Scan(
-		&product.ID,
-		&product.Name,
-		&product.Description,
-		&product.Price,
-		&product.Stock,
-		&product.MinimumStock,
-		&product.IsActive,
-		&product.IsHighlighted,
-		&product.IsPromotional,
-		&product.PromotionalPrice,
-		&product.Category.ID,
-		&product.Category.Name,
-		&product.Category.Description,
-		&imagesJSON,
-		&variantsJSON,
-	)
+	err := r.db.QueryRowContext(ctx, query, productID).Scan(productScanDest(product, &imagesJSON, &variantsJSON)...)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -372,10 +639,16 @@ func (r *ProductRepository) GetByID(ctx context.Context, productID int) (*models
 func (r *ProductRepository) Create(ctx context.Context, product *models.Product, shopID int) (*models.Product, error) {
 	startTime := time.Now()
 
-	// 1. Prepare image URLs array
-	imageURLs := make([]string, len(product.Images))
-	for i, img := range product.Images {
-		imageURLs[i] = img.URL
+	// 1. Serialize images (with their renditions) to JSON
+	imagesJSON, err := json.Marshal(product.Images)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductRepositoryField,
+			"function": ProductCreateFunctionField,
+			"sub_func": MarshalImagesSubFuncField,
+			"error":    err.Error(),
+		}).Error(LogFailedMarshalImages)
+		return nil, fmt.Errorf("failed to prepare images: %w", err)
 	}
 
 	// 2. Serialize variants to JSON
@@ -408,7 +681,7 @@ func (r *ProductRepository) Create(ctx context.Context, product *models.Product,
 		product.PromotionalPrice,
 		product.Category.ID,
 		shopID,
-		pq.Array(imageURLs),
+		imagesJSON,
 		variantsJSON,
 	).Scan(&productID)
 
@@ -503,10 +776,65 @@ func (r *ProductRepository) Update(ctx context.Context, productID int, product *
 		"duration_ms":   time.Since(startTime).Milliseconds(),
 	}).Debug("Data prepared for stored procedure")
 
+	return r.execUpdateProduct(ctx, ProductUpdateFunctionField, productID, product, imagesJSON, variantsJSON, startTime)
+}
+
+// Patch updates the product fields and only replaces images or variants when requested
+func (r *ProductRepository) Patch(ctx context.Context, productID int, product *models.Product, replaceImages, replaceVariants bool) error {
+	startTime := time.Now()
+
+	var imagesJSON, variantsJSON interface{}
+
+	if replaceImages {
+		data, err := json.Marshal(product.Images)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":       ProductRepositoryField,
+				"function":   ProductPatchFunctionField,
+				"sub_func":   MarshalImagesSubFuncField,
+				"product_id": productID,
+				"error":      err.Error(),
+			}).Error(LogFailedMarshalImages)
+			return fmt.Errorf("database operation failed")
+		}
+		imagesJSON = data
+	}
+
+	if replaceVariants {
+		variants := product.Variants
+		if variants == nil {
+			variants = []*models.Variant{}
+		}
+		data, err := json.Marshal(variants)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":       ProductRepositoryField,
+				"function":   ProductPatchFunctionField,
+				"sub_func":   MarshalVariantsSubFuncField,
+				"product_id": productID,
+				"error":      err.Error(),
+			}).Error(LogFailedMarshalVariants)
+			return fmt.Errorf("database operation failed")
+		}
+		variantsJSON = data
+	}
+
+	return r.execUpdateProduct(ctx, ProductPatchFunctionField, productID, product, imagesJSON, variantsJSON, startTime)
+}
+
+// execUpdateProduct calls the update_product stored procedure
+// A nil images or variants argument is sent as NULL so the procedure keeps the stored rows
+// A positive product.Version is checked against the stored version (optimistic lock)
+func (r *ProductRepository) execUpdateProduct(ctx context.Context, function string, productID int, product *models.Product, imagesJSON, variantsJSON interface{}, startTime time.Time) error {
+	var expectedVersion interface{}
+	if product.Version > 0 {
+		expectedVersion = product.Version
+	}
+
 	// Call stored procedure (single query does everything)
 	spStart := time.Now()
-	_, err = r.db.ExecContext(ctx, `
-		SELECT update_product($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+	_, err := r.db.ExecContext(ctx, `
+		SELECT update_product($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
 		productID,
 		product.Name,
 		product.Description,
@@ -520,12 +848,13 @@ func (r *ProductRepository) Update(ctx context.Context, productID int, product *
 		product.Category.ID,
 		imagesJSON,
 		variantsJSON,
+		expectedVersion,
 	)
 
 	if err != nil {
 		logs.WithFields(map[string]interface{}{
 			"file":       ProductRepositoryField,
-			"function":   ProductUpdateFunctionField,
+			"function":   function,
 			"sub_func":   CallStoredProcedureSubFuncField,
 			"product_id": productID,
 			"error":      err.Error(),
@@ -536,13 +865,22 @@ func (r *ProductRepository) Update(ctx context.Context, productID int, product *
 			// RAISE EXCEPTION from stored procedure comes as pq.Error
 			logs.WithFields(map[string]interface{}{
 				"file":       ProductRepositoryField,
-				"function":   ProductUpdateFunctionField,
+				"function":   function,
 				"pg_code":    pqErr.Code,    // PostgreSQL error code
 				"pg_message": pqErr.Message, // Error message from RAISE EXCEPTION
 				"pg_detail":  pqErr.Detail,  // Additional detail if any
 				"pg_hint":    pqErr.Hint,    // Hint if provided
 			}).Debug("PostgreSQL error details from stored procedure")
 
+			switch pqErr.Code {
+			case pgSerializationFailureCode:
+				return &errors.PreconditionFailedError{Message: errors.ProductVersionMismatch}
+			case pgNoDataFoundCode:
+				return &errors.RecordNotFoundError{Message: errors.ProductNotFound}
+			case pgRestrictViolationCode:
+				return &errors.ValidationError{Message: errors.PromotionalPriceMustBeLowerThanRegularPrice}
+			}
+
 			// Return error with SP context (preserves original message)
 			return fmt.Errorf("stored procedure error: %s", pqErr.Message)
 		}
@@ -553,7 +891,7 @@ func (r *ProductRepository) Update(ctx context.Context, productID int, product *
 
 	logs.WithFields(map[string]interface{}{
 		"file":        ProductRepositoryField,
-		"function":    ProductUpdateFunctionField,
+		"function":    function,
 		"sub_func":    CallStoredProcedureSubFuncField,
 		"product_id":  productID,
 		"duration_ms": time.Since(spStart).Milliseconds(),
@@ -561,10 +899,291 @@ func (r *ProductRepository) Update(ctx context.Context, productID int, product *
 
 	logs.WithFields(map[string]interface{}{
 		"file":              ProductRepositoryField,
-		"function":          ProductUpdateFunctionField,
+		"function":          function,
 		"product_id":        productID,
 		"total_duration_ms": time.Since(startTime).Milliseconds(),
 	}).Info("Product update completed (stored procedure)")
 
 	return nil
 }
+
+func (r *ProductRepository) SoftDelete(ctx context.Context, productID int) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE products
+		SET deleted_at = now()
+		WHERE id = $1 AND deleted_at IS NULL`,
+		productID,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductSoftDeleteFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedSoftDeleteProduct)
+		return fmt.Errorf("database operation failed")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductSoftDeleteFunctionField,
+			"sub_func":   RowsAffectedSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedSoftDeleteProduct)
+		return fmt.Errorf("database operation failed")
+	}
+
+	// Missing and already deleted products are indistinguishable to callers
+	if affected == 0 {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductSoftDeleteFunctionField,
+			"product_id": productID,
+		}).Warn(productNotFoundMessage)
+		return &errors.RecordNotFoundError{Message: errors.ProductNotFound}
+	}
+
+	return nil
+}
+
+func (r *ProductRepository) Restore(ctx context.Context, productID int) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE products
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL`,
+		productID,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductRestoreFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedRestoreProduct)
+		return fmt.Errorf("database operation failed")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductRestoreFunctionField,
+			"sub_func":   RowsAffectedSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedRestoreProduct)
+		return fmt.Errorf("database operation failed")
+	}
+
+	if affected == 0 {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductRestoreFunctionField,
+			"product_id": productID,
+		}).Warn("Deleted product not found")
+		return &errors.RecordNotFoundError{Message: errors.DeletedProductNotFound}
+	}
+
+	return nil
+}
+
+// ReorderImages stores the position and primary flag of every image of the product
+// The images must be exactly the stored ones; the product row is locked so the order
+// cannot interleave with an update, and its version is bumped like any other edit
+func (r *ProductRepository) ReorderImages(ctx context.Context, productID int, images []models.ProductImage, expectedVersion int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductReorderImagesFunctionField,
+			"sub_func":   BeginTransactionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(FailedBeginTransactionLog)
+		return fmt.Errorf("database operation failed")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var version int
+	err = tx.QueryRowContext(ctx, `
+		SELECT version
+		FROM products
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE`,
+		productID,
+	).Scan(&version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logs.WithFields(map[string]interface{}{
+				"file":       ProductRepositoryField,
+				"function":   ProductReorderImagesFunctionField,
+				"product_id": productID,
+			}).Warn(productNotFoundMessage)
+			return &errors.RecordNotFoundError{Message: errors.ProductNotFound}
+		}
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductReorderImagesFunctionField,
+			"sub_func":   LockProductSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedReorderProductImages)
+		return fmt.Errorf("database operation failed")
+	}
+
+	if expectedVersion > 0 && version != expectedVersion {
+		err = &errors.PreconditionFailedError{Message: errors.ProductVersionMismatch}
+		return err
+	}
+
+	var stored int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM product_images WHERE product_id = $1`, productID).Scan(&stored)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductReorderImagesFunctionField,
+			"sub_func":   CountImagesSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedReorderProductImages)
+		return fmt.Errorf("database operation failed")
+	}
+	if stored != len(images) {
+		err = &errors.ValidationError{Message: errors.ImageOrderMustListAllImages}
+		return err
+	}
+
+	// Clear the primary image first: the unique index allows one per product at any time
+	_, err = tx.ExecContext(ctx, `
+		UPDATE product_images
+		SET is_primary = false
+		WHERE product_id = $1 AND is_primary`,
+		productID,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductReorderImagesFunctionField,
+			"sub_func":   ClearPrimaryImageSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedReorderProductImages)
+		return fmt.Errorf("database operation failed")
+	}
+
+	ids := make([]int64, len(images))
+	positions := make([]int64, len(images))
+	primary := make([]bool, len(images))
+	for i, image := range images {
+		ids[i], positions[i], primary[i] = int64(image.ID), int64(image.Position), image.IsPrimary
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE product_images pi
+		SET position = ordered.position, is_primary = ordered.is_primary
+		FROM unnest($2::bigint[], $3::integer[], $4::boolean[]) AS ordered(id, position, is_primary)
+		WHERE pi.id = ordered.id AND pi.product_id = $1`,
+		productID,
+		pq.Array(ids),
+		pq.Array(positions),
+		pq.Array(primary),
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductReorderImagesFunctionField,
+			"sub_func":   UpdateImagePositionsSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedReorderProductImages)
+		return fmt.Errorf("database operation failed")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductReorderImagesFunctionField,
+			"sub_func":   RowsAffectedSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedReorderProductImages)
+		return fmt.Errorf("database operation failed")
+	}
+	// As many images as stored were listed, so a missing one means another product's image
+	if int(affected) != len(images) {
+		err = &errors.ValidationError{Message: errors.ImageOrderMustListAllImages}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE products SET version = version + 1 WHERE id = $1`, productID)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductReorderImagesFunctionField,
+			"sub_func":   BumpVersionSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedReorderProductImages)
+		return fmt.Errorf("database operation failed")
+	}
+
+	if err = tx.Commit(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ProductRepositoryField,
+			"function":   ProductReorderImagesFunctionField,
+			"sub_func":   CommitTransactionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(FailedCommitTransactionLog)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// PurgeDeleted hard-deletes products soft-deleted before the given time
+// Images, variants and options are removed by ON DELETE CASCADE
+func (r *ProductRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM products
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1`,
+		deletedBefore,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":           ProductRepositoryField,
+			"function":       ProductPurgeDeletedFunctionField,
+			"deleted_before": deletedBefore,
+			"error":          err.Error(),
+		}).Error(failedPurgeDeletedProducts)
+		return 0, fmt.Errorf("database operation failed")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ProductRepositoryField,
+			"function": ProductPurgeDeletedFunctionField,
+			"sub_func": RowsAffectedSubFuncField,
+			"error":    err.Error(),
+		}).Error(failedPurgeDeletedProducts)
+		return 0, fmt.Errorf("database operation failed")
+	}
+
+	logs.WithFields(map[string]interface{}{
+		"file":           ProductRepositoryField,
+		"function":       ProductPurgeDeletedFunctionField,
+		"deleted_before": deletedBefore,
+		"purged":         affected,
+	}).Info("Soft-deleted products purged")
+
+	return int(affected), nil
+}
@@ -2,14 +2,17 @@ package postgresql
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
+	coreErrors "github.com/mlgaray/ecommerce_api/internal/core/errors"
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
 	"github.com/mlgaray/ecommerce_api/mocks"
@@ -45,6 +48,21 @@ func TestNewProductRepository(t *testing.T) {
 	})
 }
 
+// createdAt is the creation timestamp used by mocked product rows
+var createdAt = time.Date(2025, time.January, 10, 12, 30, 0, 0, time.UTC)
+
+// newTestProductFilter builds a filter with default ordering, cursorID 0 meaning first page
+func newTestProductFilter(limit, cursorID int) *models.ProductFilter {
+	filter := &models.ProductFilter{
+		SortDirection: models.SortDescending,
+		Limit:         limit,
+	}
+	if cursorID > 0 {
+		filter.Cursor = &models.ProductCursor{SortDirection: models.SortDescending, ID: cursorID}
+	}
+	return filter
+}
+
 func TestProductRepository_GetAllByShopID(t *testing.T) {
 	t.Run("when getting products without cursor then returns first page", func(t *testing.T) {
 		// Arrange
@@ -62,21 +80,23 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 
 		rows := sqlmock.NewRows([]string{
 			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
+			"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
 			"category_id", "category_name", "category_description",
-			"images", "variants",
+			"images", "variants", "promotions", "time_zone",
 		}).
 			AddRow(
 				1, "Product 1", "Description 1", 99.99, 10, 5,
-				true, false, false, 0.0,
+				true, false, false, 0.0, createdAt, 1,
 				1, "Category 1", "Category Description",
 				[]byte(imagesJSON), []byte(variantsJSON),
+				[]byte("[]"), "",
 			).
 			AddRow(
 				2, "Product 2", "Description 2", 149.99, 20, 10,
-				true, true, true, 129.99,
+				true, true, true, 129.99, createdAt, 1,
 				2, "Category 2", "Category Description 2",
 				[]byte("[]"), []byte("[]"),
+				[]byte("[]"), "",
 			)
 
 		mock.ExpectQuery(`SELECT(.+)FROM products p(.+)WHERE p.shop_id = \$1(.+)ORDER BY p.id DESC(.+)LIMIT \$2`).
@@ -86,7 +106,7 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 		repo := &ProductRepository{db: db}
 
 		// Act
-		products, err := repo.GetAllByShopID(ctx, shopID, limit, cursor)
+		products, err := repo.GetAllByShopID(ctx, shopID, newTestProductFilter(limit, cursor))
 
 		// Assert
 		assert.NoError(t, err)
@@ -114,25 +134,26 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 
 		rows := sqlmock.NewRows([]string{
 			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
+			"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
 			"category_id", "category_name", "category_description",
-			"images", "variants",
+			"images", "variants", "promotions", "time_zone",
 		}).
 			AddRow(
 				99, "Product 99", "Description 99", 79.99, 15, 5,
-				true, false, false, 0.0,
+				true, false, false, 0.0, createdAt, 1,
 				1, "Category 1", "",
 				[]byte("[]"), []byte("[]"),
+				[]byte("[]"), "",
 			)
 
-		mock.ExpectQuery(`SELECT(.+)FROM products p(.+)WHERE p.shop_id = \$1 AND p.id < \$2(.+)ORDER BY p.id DESC(.+)LIMIT \$3`).
+		mock.ExpectQuery(`SELECT(.+)FROM products p(.+)WHERE p.shop_id = \$1 AND p.deleted_at IS NULL AND p.id < \$2(.+)ORDER BY p.id DESC(.+)LIMIT \$3`).
 			WithArgs(shopID, cursor, limit).
 			WillReturnRows(rows)
 
 		repo := &ProductRepository{db: db}
 
 		// Act
-		products, err := repo.GetAllByShopID(ctx, shopID, limit, cursor)
+		products, err := repo.GetAllByShopID(ctx, shopID, newTestProductFilter(limit, cursor))
 
 		// Assert
 		assert.NoError(t, err)
@@ -155,9 +176,9 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 
 		rows := sqlmock.NewRows([]string{
 			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
+			"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
 			"category_id", "category_name", "category_description",
-			"images", "variants",
+			"images", "variants", "promotions", "time_zone",
 		})
 
 		// Expect default limit of 20
@@ -168,7 +189,7 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 		repo := &ProductRepository{db: db}
 
 		// Act
-		products, err := repo.GetAllByShopID(ctx, shopID, limit, cursor)
+		products, err := repo.GetAllByShopID(ctx, shopID, newTestProductFilter(limit, cursor))
 
 		// Assert
 		assert.NoError(t, err)
@@ -190,9 +211,9 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 
 		rows := sqlmock.NewRows([]string{
 			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
+			"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
 			"category_id", "category_name", "category_description",
-			"images", "variants",
+			"images", "variants", "promotions", "time_zone",
 		})
 
 		// Expect max limit of 100
@@ -203,7 +224,7 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 		repo := &ProductRepository{db: db}
 
 		// Act
-		products, err := repo.GetAllByShopID(ctx, shopID, limit, cursor)
+		products, err := repo.GetAllByShopID(ctx, shopID, newTestProductFilter(limit, cursor))
 
 		// Assert
 		assert.NoError(t, err)
@@ -224,9 +245,9 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 
 		rows := sqlmock.NewRows([]string{
 			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
+			"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
 			"category_id", "category_name", "category_description",
-			"images", "variants",
+			"images", "variants", "promotions", "time_zone",
 		})
 
 		mock.ExpectQuery(`SELECT(.+)FROM products p(.+)WHERE p.shop_id = \$1(.+)ORDER BY p.id DESC(.+)LIMIT \$2`).
@@ -236,7 +257,7 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 		repo := &ProductRepository{db: db}
 
 		// Act
-		products, err := repo.GetAllByShopID(ctx, shopID, limit, cursor)
+		products, err := repo.GetAllByShopID(ctx, shopID, newTestProductFilter(limit, cursor))
 
 		// Assert
 		assert.NoError(t, err)
@@ -264,7 +285,7 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 		repo := &ProductRepository{db: db}
 
 		// Act
-		products, err := repo.GetAllByShopID(ctx, shopID, limit, cursor)
+		products, err := repo.GetAllByShopID(ctx, shopID, newTestProductFilter(limit, cursor))
 
 		// Assert
 		assert.Error(t, err)
@@ -294,7 +315,7 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 		repo := &ProductRepository{db: db}
 
 		// Act
-		products, err := repo.GetAllByShopID(ctx, shopID, limit, cursor)
+		products, err := repo.GetAllByShopID(ctx, shopID, newTestProductFilter(limit, cursor))
 
 		// Assert
 		assert.Error(t, err)
@@ -317,15 +338,16 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 
 		rows := sqlmock.NewRows([]string{
 			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
+			"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
 			"category_id", "category_name", "category_description",
-			"images", "variants",
+			"images", "variants", "promotions", "time_zone",
 		}).
 			AddRow(
 				1, "Product 1", "Description 1", 99.99, 10, 5,
-				true, false, false, 0.0,
+				true, false, false, 0.0, createdAt, 1,
 				1, "Category 1", "",
 				[]byte(invalidImagesJSON), []byte("[]"),
+				[]byte("[]"), "",
 			)
 
 		mock.ExpectQuery(`SELECT(.+)FROM products p`).
@@ -335,7 +357,7 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 		repo := &ProductRepository{db: db}
 
 		// Act
-		products, err := repo.GetAllByShopID(ctx, shopID, limit, cursor)
+		products, err := repo.GetAllByShopID(ctx, shopID, newTestProductFilter(limit, cursor))
 
 		// Assert
 		assert.Error(t, err)
@@ -358,15 +380,16 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 
 		rows := sqlmock.NewRows([]string{
 			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
+			"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
 			"category_id", "category_name", "category_description",
-			"images", "variants",
+			"images", "variants", "promotions", "time_zone",
 		}).
 			AddRow(
 				1, "Product 1", "Description 1", 99.99, 10, 5,
-				true, false, false, 0.0,
+				true, false, false, 0.0, createdAt, 1,
 				1, "Category 1", "",
 				[]byte("[]"), []byte(invalidVariantsJSON),
+				[]byte("[]"), "",
 			)
 
 		mock.ExpectQuery(`SELECT(.+)FROM products p`).
@@ -376,7 +399,7 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 		repo := &ProductRepository{db: db}
 
 		// Act
-		products, err := repo.GetAllByShopID(ctx, shopID, limit, cursor)
+		products, err := repo.GetAllByShopID(ctx, shopID, newTestProductFilter(limit, cursor))
 
 		// Assert
 		assert.Error(t, err)
@@ -397,15 +420,16 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 
 		rows := sqlmock.NewRows([]string{
 			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
+			"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
 			"category_id", "category_name", "category_description",
-			"images", "variants",
+			"images", "variants", "promotions", "time_zone",
 		}).
 			AddRow(
 				1, "Product 1", "Description 1", 99.99, 10, 5,
-				true, false, false, 0.0,
+				true, false, false, 0.0, createdAt, 1,
 				1, "Category 1", "",
 				[]byte("[]"), []byte("[]"),
+				[]byte("[]"), "",
 			).
 			RowError(0, errors.New("rows iteration error"))
 
@@ -416,13 +440,147 @@ func TestProductRepository_GetAllByShopID(t *testing.T) {
 		repo := &ProductRepository{db: db}
 
 		// Act
-		products, err := repo.GetAllByShopID(ctx, shopID, limit, cursor)
+		products, err := repo.GetAllByShopID(ctx, shopID, newTestProductFilter(limit, cursor))
 
 		// Assert
 		assert.Error(t, err)
 		assert.Nil(t, products)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("when filters are provided then adds them as query conditions", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+		shopID := 1
+		categoryID := 3
+		isActive := true
+		inStock := true
+		isLowStock := true
+		minPrice := models.MoneyFromFloat(10)
+		maxPrice := models.MoneyFromFloat(50)
+		filter := &models.ProductFilter{
+			CategoryID:    &categoryID,
+			IsActive:      &isActive,
+			InStock:       &inStock,
+			IsLowStock:    &isLowStock,
+			MinPrice:      &minPrice,
+			MaxPrice:      &maxPrice,
+			SortDirection: models.SortDescending,
+			Limit:         20,
+		}
+
+		rows := sqlmock.NewRows([]string{
+			"id", "name", "description", "price", "stock", "minimum_stock",
+			"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+			"category_id", "category_name", "category_description",
+			"images", "variants", "promotions", "time_zone",
+		})
+
+		mock.ExpectQuery(`WHERE p.shop_id = \$1 AND p.deleted_at IS NULL AND p.category_id = \$2 AND COALESCE\(p.is_active, false\) = \$3 AND p.stock > 0 AND p.stock <= COALESCE\(p.minimum_stock, 0\) AND p.price >= \$4 AND p.price <= \$5(.+)ORDER BY p.id DESC(.+)LIMIT \$6`).
+			WithArgs(shopID, categoryID, isActive, "10.00", "50.00", 20).
+			WillReturnRows(rows)
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		products, err := repo.GetAllByShopID(ctx, shopID, filter)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, products, 0)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when sorting by price with cursor then compares sort key and ID together", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+		shopID := 1
+		filter := &models.ProductFilter{
+			SortBy:        models.ProductSortByPrice,
+			SortDirection: models.SortAscending,
+			Limit:         10,
+			Cursor: &models.ProductCursor{
+				SortBy:        models.ProductSortByPrice,
+				SortDirection: models.SortAscending,
+				Value:         "99.99",
+				ID:            7,
+			},
+		}
+
+		rows := sqlmock.NewRows([]string{
+			"id", "name", "description", "price", "stock", "minimum_stock",
+			"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+			"category_id", "category_name", "category_description",
+			"images", "variants", "promotions", "time_zone",
+		}).
+			AddRow(
+				3, "Product 3", "Description 3", 99.99, 10, 0,
+				true, false, false, 0.0, createdAt, 1,
+				1, "Category 1", "",
+				[]byte("[]"), []byte("[]"),
+				[]byte("[]"), "",
+			)
+
+		mock.ExpectQuery(`WHERE p.shop_id = \$1 AND p.deleted_at IS NULL AND \(COALESCE\(p.price, 0\), p.id\) > \(\$2, \$3\)(.+)ORDER BY COALESCE\(p.price, 0\) ASC, p.id ASC(.+)LIMIT \$4`).
+			WithArgs(shopID, "99.99", 7, 10).
+			WillReturnRows(rows)
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		products, err := repo.GetAllByShopID(ctx, shopID, filter)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, products, 1)
+		assert.Equal(t, createdAt, products[0].CreatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when sorting by creation date descending then orders by created_at and ID", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+		shopID := 1
+		filter := &models.ProductFilter{
+			SortBy:        models.ProductSortByCreatedAt,
+			SortDirection: models.SortDescending,
+			Limit:         5,
+			Cursor: &models.ProductCursor{
+				SortBy:        models.ProductSortByCreatedAt,
+				SortDirection: models.SortDescending,
+				Value:         createdAt.Format(time.RFC3339Nano),
+				ID:            12,
+			},
+		}
+
+		rows := sqlmock.NewRows([]string{"id"})
+
+		mock.ExpectQuery(`\(p.created_at, p.id\) < \(\$2, \$3\)(.+)ORDER BY p.created_at DESC, p.id DESC`).
+			WithArgs(shopID, createdAt, 12, 5).
+			WillReturnRows(rows)
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		products, err := repo.GetAllByShopID(ctx, shopID, filter)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, products, 0)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestProductRepository_Create(t *testing.T) {
@@ -437,13 +595,13 @@ func TestProductRepository_Create(t *testing.T) {
 		product := &models.Product{
 			Name:             "Test Product",
 			Description:      "Test Description",
-			Price:            99.99,
+			Price:            models.MoneyFromFloat(99.99),
 			Stock:            10,
 			MinimumStock:     5,
 			IsActive:         true,
 			IsHighlighted:    false,
 			IsPromotional:    false,
-			PromotionalPrice: 0,
+			PromotionalPrice: models.MoneyFromFloat(0),
 			Category: &models.Category{
 				ID: 1,
 			},
@@ -457,7 +615,7 @@ func TestProductRepository_Create(t *testing.T) {
 					SelectionType: "single",
 					MaxSelections: 1,
 					Options: []*models.Option{
-						{Name: "Small", Price: 0.0, Order: 1},
+						{Name: "Small", Price: models.MoneyFromFloat(0.0), Order: 1},
 					},
 				},
 			},
@@ -513,7 +671,7 @@ func TestProductRepository_Create(t *testing.T) {
 		// Create circular reference (this will cause json.Marshal to fail)
 		option := &models.Option{
 			Name:  "Small",
-			Price: 0.0,
+			Price: models.MoneyFromFloat(0.0),
 			Order: 1,
 		}
 		variant.Options = []*models.Option{option}
@@ -521,13 +679,13 @@ func TestProductRepository_Create(t *testing.T) {
 		product := &models.Product{
 			Name:             "Test Product",
 			Description:      "Test Description",
-			Price:            99.99,
+			Price:            models.MoneyFromFloat(99.99),
 			Stock:            10,
 			MinimumStock:     5,
 			IsActive:         true,
 			IsHighlighted:    false,
 			IsPromotional:    false,
-			PromotionalPrice: 0,
+			PromotionalPrice: models.MoneyFromFloat(0),
 			Category:         &models.Category{ID: 1},
 			Images:           []models.ProductImage{},
 			Variants:         []*models.Variant{variant},
@@ -560,13 +718,13 @@ func TestProductRepository_Create(t *testing.T) {
 		product := &models.Product{
 			Name:             "Test Product",
 			Description:      "Test Description",
-			Price:            99.99,
+			Price:            models.MoneyFromFloat(99.99),
 			Stock:            10,
 			MinimumStock:     5,
 			IsActive:         true,
 			IsHighlighted:    false,
 			IsPromotional:    false,
-			PromotionalPrice: 0,
+			PromotionalPrice: models.MoneyFromFloat(0),
 			Category:         &models.Category{ID: 1},
 			Images:           []models.ProductImage{},
 			Variants:         []*models.Variant{},
@@ -619,13 +777,13 @@ func TestProductRepository_Create(t *testing.T) {
 		product := &models.Product{
 			Name:             "Test Product",
 			Description:      "Test Description",
-			Price:            99.99,
+			Price:            models.MoneyFromFloat(99.99),
 			Stock:            10,
 			MinimumStock:     5,
 			IsActive:         true,
 			IsHighlighted:    false,
 			IsPromotional:    false,
-			PromotionalPrice: 0,
+			PromotionalPrice: models.MoneyFromFloat(0),
 			Category:         &models.Category{ID: 1},
 			Images:           []models.ProductImage{},
 			Variants:         []*models.Variant{},
@@ -676,13 +834,13 @@ func TestProductRepository_Update(t *testing.T) {
 		product := &models.Product{
 			Name:             "Updated Product",
 			Description:      "Updated Description",
-			Price:            149.99,
+			Price:            models.MoneyFromFloat(149.99),
 			Stock:            20,
 			MinimumStock:     10,
 			IsActive:         true,
 			IsHighlighted:    true,
 			IsPromotional:    true,
-			PromotionalPrice: 129.99,
+			PromotionalPrice: models.MoneyFromFloat(129.99),
 			Category: &models.Category{
 				ID: 2,
 			},
@@ -698,8 +856,8 @@ func TestProductRepository_Update(t *testing.T) {
 					SelectionType: "single",
 					MaxSelections: 1,
 					Options: []*models.Option{
-						{ID: 1, Name: "Small", Price: 0.0, Order: 1},
-						{Name: "Large", Price: 5.0, Order: 2},
+						{ID: 1, Name: "Small", Price: models.MoneyFromFloat(0.0), Order: 1},
+						{Name: "Large", Price: models.MoneyFromFloat(5.0), Order: 2},
 					},
 				},
 			},
@@ -721,6 +879,7 @@ func TestProductRepository_Update(t *testing.T) {
 				product.Category.ID,
 				sqlmock.AnyArg(), // images JSON
 				sqlmock.AnyArg(), // variants JSON
+				nil,              // expected version (no If-Match)
 			).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
@@ -748,13 +907,13 @@ func TestProductRepository_Update(t *testing.T) {
 		product := &models.Product{
 			Name:             "Test Product",
 			Description:      "Test Description",
-			Price:            99.99,
+			Price:            models.MoneyFromFloat(99.99),
 			Stock:            10,
 			MinimumStock:     5,
 			IsActive:         true,
 			IsHighlighted:    false,
 			IsPromotional:    false,
-			PromotionalPrice: 0,
+			PromotionalPrice: models.MoneyFromFloat(0),
 			Category:         &models.Category{ID: 1},
 			Images:           []models.ProductImage{{URL: "http://example.com/image.jpg"}},
 			Variants:         []*models.Variant{},
@@ -789,13 +948,13 @@ func TestProductRepository_Update(t *testing.T) {
 		product := &models.Product{
 			Name:             "Test Product",
 			Description:      "Test Description",
-			Price:            99.99,
+			Price:            models.MoneyFromFloat(99.99),
 			Stock:            10,
 			MinimumStock:     5,
 			IsActive:         true,
 			IsHighlighted:    false,
 			IsPromotional:    false,
-			PromotionalPrice: 0,
+			PromotionalPrice: models.MoneyFromFloat(0),
 			Category:         &models.Category{ID: 1},
 			Images:           []models.ProductImage{},
 			Variants:         []*models.Variant{variant},
@@ -822,13 +981,13 @@ func TestProductRepository_Update(t *testing.T) {
 		product := &models.Product{
 			Name:             "Updated Product",
 			Description:      "Updated Description",
-			Price:            149.99,
+			Price:            models.MoneyFromFloat(149.99),
 			Stock:            20,
 			MinimumStock:     10,
 			IsActive:         true,
 			IsHighlighted:    false,
 			IsPromotional:    false,
-			PromotionalPrice: 0,
+			PromotionalPrice: models.MoneyFromFloat(0),
 			Category:         &models.Category{ID: 999},
 			Images:           []models.ProductImage{},
 			Variants:         []*models.Variant{},
@@ -854,6 +1013,7 @@ func TestProductRepository_Update(t *testing.T) {
 				product.Category.ID,
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
+				nil,
 			).
 			WillReturnError(pgErr)
 
@@ -880,13 +1040,13 @@ func TestProductRepository_Update(t *testing.T) {
 		product := &models.Product{
 			Name:             "Updated Product",
 			Description:      "Updated Description",
-			Price:            149.99,
+			Price:            models.MoneyFromFloat(149.99),
 			Stock:            20,
 			MinimumStock:     10,
 			IsActive:         true,
 			IsHighlighted:    false,
 			IsPromotional:    false,
-			PromotionalPrice: 0,
+			PromotionalPrice: models.MoneyFromFloat(0),
 			Category:         &models.Category{ID: 1},
 			Images:           []models.ProductImage{},
 			Variants:         []*models.Variant{},
@@ -909,6 +1069,7 @@ func TestProductRepository_Update(t *testing.T) {
 				product.Category.ID,
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
+				nil,
 			).
 			WillReturnError(expectedError)
 
@@ -923,3 +1084,544 @@ func TestProductRepository_Update(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestProductRepository_Search(t *testing.T) {
+	searchColumns := []string{
+		"id", "name", "description", "price", "stock", "minimum_stock",
+		"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+		"category_id", "category_name", "category_description",
+		"images", "variants", "promotions", "time_zone",
+		"rank", "name_highlight", "description_highlight",
+	}
+
+	t.Run("when searching first page then sends prefix tsquery and returns ranked results", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+		shopID := 1
+		search := &models.ProductSearch{Query: "Hamburguesa  con-queso!", Limit: 10}
+
+		rows := sqlmock.NewRows(searchColumns).
+			AddRow(
+				4, "Hamburguesa", "Con queso cheddar", 12.5, 10, 0,
+				true, false, false, 0.0, createdAt, 1,
+				1, "Burgers", "",
+				[]byte("[]"), []byte("[]"),
+				[]byte("[]"), "",
+				0.6079, "\x02Hamburguesa\x03 <b>XL</b>", "Con \x02queso\x03 cheddar",
+			)
+
+		mock.ExpectQuery(`to_tsquery\(sc.config, \$3\)(.+)ORDER BY ranked.rank DESC, ranked.id DESC(.+)LIMIT \$4(.+)ts_headline`).
+			WithArgs(shopID, "", "hamburguesa:* & con:* & queso:*", 10).
+			WillReturnRows(rows)
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		results, err := repo.Search(ctx, shopID, search)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, 4, results[0].Product.ID)
+		assert.Equal(t, 0.6079, results[0].Rank)
+		assert.Equal(t, "<mark>Hamburguesa</mark> &lt;b&gt;XL&lt;/b&gt;", results[0].NameHighlight)
+		assert.Equal(t, "Con <mark>queso</mark> cheddar", results[0].DescriptionHighlight)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when searching with cursor and language then pages on rank and ID", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+		shopID := 1
+		search := &models.ProductSearch{
+			Query:    "fries",
+			Language: models.SearchLanguageEnglish,
+			Limit:    5,
+			Cursor:   &models.ProductSearchCursor{Rank: 0.25, ID: 8},
+		}
+
+		mock.ExpectQuery(`WHERE \(ranked.rank, ranked.id\) < \(\$4, \$5\)(.+)LIMIT \$6`).
+			WithArgs(shopID, "en", "fries:*", 0.25, 8, 5).
+			WillReturnRows(sqlmock.NewRows(searchColumns))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		results, err := repo.Search(ctx, shopID, search)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, results, 0)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when query fails then returns error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+		search := &models.ProductSearch{Query: "pizza", Limit: 20}
+
+		mock.ExpectQuery(`WITH search_config`).
+			WillReturnError(errors.New("database query failed"))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		results, err := repo.Search(ctx, 1, search)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, results)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_SoftDelete(t *testing.T) {
+	t.Run("when product is live then marks it as deleted", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE products(.+)SET deleted_at = now\(\)(.+)WHERE id = \$1 AND deleted_at IS NULL`).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.SoftDelete(context.Background(), 7)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when product is missing or already deleted then returns not found error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE products`).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.SoftDelete(context.Background(), 7)
+
+		// Assert
+		assert.Error(t, err)
+		assert.IsType(t, &coreErrors.RecordNotFoundError{}, err)
+		assert.Equal(t, coreErrors.ProductNotFound, err.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when exec fails then returns error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE products`).
+			WithArgs(7).
+			WillReturnError(errors.New("connection reset"))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.SoftDelete(context.Background(), 7)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, "database operation failed", err.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_Restore(t *testing.T) {
+	t.Run("when product is deleted then clears deleted_at", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE products(.+)SET deleted_at = NULL(.+)WHERE id = \$1 AND deleted_at IS NOT NULL`).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.Restore(context.Background(), 7)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when product is not deleted then returns not found error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE products`).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.Restore(context.Background(), 7)
+
+		// Assert
+		assert.Error(t, err)
+		assert.IsType(t, &coreErrors.RecordNotFoundError{}, err)
+		assert.Equal(t, coreErrors.DeletedProductNotFound, err.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_PurgeDeleted(t *testing.T) {
+	t.Run("when products were deleted before the cutoff then returns purged count", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectExec(`DELETE FROM products(.+)WHERE deleted_at IS NOT NULL AND deleted_at < \$1`).
+			WithArgs(cutoff).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		purged, err := repo.PurgeDeleted(context.Background(), cutoff)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 3, purged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when exec fails then returns error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectExec(`DELETE FROM products`).
+			WithArgs(cutoff).
+			WillReturnError(errors.New("connection reset"))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		purged, err := repo.PurgeDeleted(context.Background(), cutoff)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, 0, purged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_Patch(t *testing.T) {
+	product := &models.Product{
+		Name:         "Patched Product",
+		Description:  "Patched Description",
+		Price:        models.MoneyFromFloat(99.5),
+		Stock:        10,
+		MinimumStock: 2,
+		IsActive:     false,
+		Category:     &models.Category{ID: 3},
+		Images: []models.ProductImage{
+			{ID: 4, URL: "http://example.com/image4.jpg"},
+		},
+	}
+
+	t.Run("when images and variants are not replaced then sends them as null", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`SELECT update_product`).
+			WithArgs(1, product.Name, product.Description, product.Price, product.Stock, product.MinimumStock,
+				product.IsActive, product.IsHighlighted, product.IsPromotional, product.PromotionalPrice,
+				product.Category.ID, nil, nil, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.Patch(context.Background(), 1, product, false, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when images and variants are replaced then sends them as json arrays", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`SELECT update_product`).
+			WithArgs(1, product.Name, product.Description, product.Price, product.Stock, product.MinimumStock,
+				product.IsActive, product.IsHighlighted, product.IsPromotional, product.PromotionalPrice,
+				product.Category.ID, []byte(`[{"id":4,"url":"http://example.com/image4.jpg","position":0,"is_primary":false}]`), []byte(`[]`), nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.Patch(context.Background(), 1, product, true, true)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when stored procedure fails then returns error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`SELECT update_product`).
+			WillReturnError(&pq.Error{Message: "Error updating product (ID: 1): category not found"})
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.Patch(context.Background(), 1, product, false, false)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "stored procedure error")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when product has a version then sends it as the expected version", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		versioned := *product
+		versioned.Version = 7
+
+		mock.ExpectExec(`SELECT update_product`).
+			WithArgs(1, product.Name, product.Description, product.Price, product.Stock, product.MinimumStock,
+				product.IsActive, product.IsHighlighted, product.IsPromotional, product.PromotionalPrice,
+				product.Category.ID, nil, nil, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.Patch(context.Background(), 1, &versioned, false, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when stored version changed then returns precondition failed error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`SELECT update_product`).
+			WillReturnError(&pq.Error{Code: "40001", Message: "Error updating product (ID: 1): product_version_mismatch"})
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.Patch(context.Background(), 1, product, false, false)
+
+		// Assert
+		assert.Error(t, err)
+		assert.IsType(t, &coreErrors.PreconditionFailedError{}, err)
+		assert.Equal(t, coreErrors.ProductVersionMismatch, err.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when product does not exist then returns not found error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(`SELECT update_product`).
+			WillReturnError(&pq.Error{Code: "P0002", Message: "Error updating product (ID: 1): product_not_found"})
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.Patch(context.Background(), 1, product, false, false)
+
+		// Assert
+		assert.Error(t, err)
+		assert.IsType(t, &coreErrors.RecordNotFoundError{}, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_ReorderImages(t *testing.T) {
+	images := []models.ProductImage{
+		{ID: 7, Position: 0, IsPrimary: true},
+		{ID: 5, Position: 1},
+	}
+
+	t.Run("when every image is listed then stores the order and bumps the version", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT version(.+)FROM products(.+)FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM product_images`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectExec(`UPDATE product_images(.+)SET is_primary = false`).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE product_images pi(.+)FROM unnest`).
+			WithArgs(1, pq.Array([]int64{7, 5}), pq.Array([]int64{0, 1}), pq.Array([]bool{true, false})).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`UPDATE products SET version = version \+ 1`).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.ReorderImages(context.Background(), 1, images, 3)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the product has other images then returns validation error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT version`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM product_images`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectRollback()
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.ReorderImages(context.Background(), 1, images, 0)
+
+		// Assert
+		assert.Equal(t, &coreErrors.ValidationError{Message: coreErrors.ImageOrderMustListAllImages}, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when an image belongs to another product then returns validation error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT version`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM product_images`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectExec(`SET is_primary = false`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`FROM unnest`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.ReorderImages(context.Background(), 1, images, 0)
+
+		// Assert
+		assert.Equal(t, &coreErrors.ValidationError{Message: coreErrors.ImageOrderMustListAllImages}, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the stored version differs then returns precondition failed", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT version`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+		mock.ExpectRollback()
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.ReorderImages(context.Background(), 1, images, 3)
+
+		// Assert
+		assert.Equal(t, &coreErrors.PreconditionFailedError{Message: coreErrors.ProductVersionMismatch}, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the product does not exist then returns not found error", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT version`).
+			WithArgs(1).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		repo := &ProductRepository{db: db}
+
+		// Act
+		err = repo.ReorderImages(context.Background(), 1, images, 0)
+
+		// Assert
+		assert.Equal(t, &coreErrors.RecordNotFoundError{Message: coreErrors.ProductNotFound}, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
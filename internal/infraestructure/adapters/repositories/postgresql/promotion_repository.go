@@ -0,0 +1,246 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Promotion repository log field constants
+const (
+	PromotionRepositoryField        = "promotion_repository"
+	PromotionCreateFunctionField    = "create"
+	PromotionDeleteFunctionField    = "delete"
+	ScheduledPromotionsSubFuncField = "scheduled_promotions"
+	BumpProductVersionSubFuncField  = "bump_product_version"
+)
+
+// Promotion repository log message constants
+const (
+	failedInsertPromotion = "Failed to insert product promotion"
+	failedDeletePromotion = "Failed to delete product promotion"
+)
+
+// scheduledPromotionsQuery reads the promotions of a product in the shape the product detail loads them
+const scheduledPromotionsQuery = `
+	SELECT COALESCE(
+		jsonb_agg(
+			jsonb_build_object(
+				'id', pp.id,
+				'price', pp.price,
+				'starts_at', pp.starts_at,
+				'ends_at', pp.ends_at,
+				'recurrence', CASE WHEN pp.weekdays IS NULL THEN NULL ELSE jsonb_build_object(
+					'weekdays', to_jsonb(pp.weekdays),
+					'start_time', to_char(pp.start_time, 'HH24:MI'),
+					'end_time', to_char(pp.end_time, 'HH24:MI')
+				) END
+			) ORDER BY pp.starts_at, pp.id
+		),
+		'[]'::jsonb
+	)
+	FROM product_promotions pp
+	WHERE pp.product_id = $1`
+
+type PromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(dataBaseConnection DataBaseConnection) *PromotionRepository {
+	return &PromotionRepository{
+		db: dataBaseConnection.Connect(),
+	}
+}
+
+// Create schedules the promotion while the product row is locked, so concurrent promotions
+// of the same product are checked against each other, and bumps the product version
+func (r *PromotionRepository) Create(ctx context.Context, productID int, promotion *models.Promotion) (*models.Promotion, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       PromotionRepositoryField,
+			"function":   PromotionCreateFunctionField,
+			"sub_func":   BeginTransactionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(FailedBeginTransactionLog)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// The row lock serializes promotions and price edits of the same product
+	var regularPrice models.Money
+	err = tx.QueryRowContext(ctx, `
+		SELECT price
+		FROM products
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE`,
+		productID,
+	).Scan(&regularPrice)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logs.WithFields(map[string]interface{}{
+				"file":       PromotionRepositoryField,
+				"function":   PromotionCreateFunctionField,
+				"product_id": productID,
+			}).Warn(productNotFoundMessage)
+			return nil, &errors.RecordNotFoundError{Message: errors.ProductNotFound}
+		}
+		logs.WithFields(map[string]interface{}{
+			"file":       PromotionRepositoryField,
+			"function":   PromotionCreateFunctionField,
+			"sub_func":   LockProductSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedInsertPromotion)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	var scheduled []*models.Promotion
+	err = tx.QueryRowContext(ctx, scheduledPromotionsQuery, productID).Scan(jsonColumn{dest: &scheduled})
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       PromotionRepositoryField,
+			"function":   PromotionCreateFunctionField,
+			"sub_func":   ScheduledPromotionsSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedInsertPromotion)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	// Domain rules are checked again against the locked price and schedule
+	if err = promotion.ValidateSchedule(regularPrice, scheduled); err != nil {
+		return nil, err
+	}
+
+	// Non recurring promotions store NULL weekdays and times
+	var weekdays, startTime, endTime interface{}
+	if promotion.Recurrence != nil {
+		days := make([]int64, len(promotion.Recurrence.Weekdays))
+		for i, weekday := range promotion.Recurrence.Weekdays {
+			days[i] = int64(weekday)
+		}
+		weekdays = pq.Array(days)
+		startTime = promotion.Recurrence.StartTime
+		endTime = promotion.Recurrence.EndTime
+	}
+
+	var endsAt interface{}
+	if promotion.EndsAt != nil {
+		endsAt = *promotion.EndsAt
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO product_promotions (product_id, price, starts_at, ends_at, weekdays, start_time, end_time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		productID,
+		promotion.Price,
+		promotion.StartsAt,
+		endsAt,
+		weekdays,
+		startTime,
+		endTime,
+	).Scan(&promotion.ID)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       PromotionRepositoryField,
+			"function":   PromotionCreateFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedInsertPromotion)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	// A new promotion changes the prices the product reports, so its ETag must change too
+	_, err = tx.ExecContext(ctx, `
+		UPDATE products
+		SET version = version + 1
+		WHERE id = $1`,
+		productID,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       PromotionRepositoryField,
+			"function":   PromotionCreateFunctionField,
+			"sub_func":   BumpProductVersionSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedInsertPromotion)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	if err = tx.Commit(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       PromotionRepositoryField,
+			"function":   PromotionCreateFunctionField,
+			"sub_func":   CommitTransactionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(FailedCommitTransactionLog)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return promotion, nil
+}
+
+// Delete removes the promotion and bumps the version of its product in a single statement
+func (r *PromotionRepository) Delete(ctx context.Context, productID, promotionID int) error {
+	result, err := r.db.ExecContext(ctx, `
+		WITH deleted AS (
+			DELETE FROM product_promotions
+			WHERE id = $1 AND product_id = $2
+			RETURNING product_id
+		)
+		UPDATE products
+		SET version = version + 1
+		WHERE id IN (SELECT product_id FROM deleted)`,
+		promotionID,
+		productID,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":         PromotionRepositoryField,
+			"function":     PromotionDeleteFunctionField,
+			"product_id":   productID,
+			"promotion_id": promotionID,
+			"error":        err.Error(),
+		}).Error(failedDeletePromotion)
+		return fmt.Errorf("database operation failed")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":         PromotionRepositoryField,
+			"function":     PromotionDeleteFunctionField,
+			"sub_func":     RowsAffectedSubFuncField,
+			"promotion_id": promotionID,
+			"error":        err.Error(),
+		}).Error(failedDeletePromotion)
+		return fmt.Errorf("database operation failed")
+	}
+
+	if affected == 0 {
+		logs.WithFields(map[string]interface{}{
+			"file":         PromotionRepositoryField,
+			"function":     PromotionDeleteFunctionField,
+			"product_id":   productID,
+			"promotion_id": promotionID,
+		}).Warn("Promotion not found")
+		return &errors.RecordNotFoundError{Message: errors.PromotionNotFound}
+	}
+
+	return nil
+}
@@ -3,9 +3,19 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
 	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Shop repository log field constants
+const (
+	ShopRepositoryField                    = "shop_repository"
+	ShopIsOwnedByFunctionField             = "is_owned_by"
+	ShopGetOwnerIDByProductIDFunctionField = "get_owner_id_by_product_id"
 )
 
 type ShopSQLRepository struct {
@@ -56,6 +66,28 @@ func (s *ShopSQLRepository) createWithDB(ctx context.Context, shop *models.Shop)
 	return shop, nil
 }
 
+// IsOwnedBy reports whether the user owns the shop
+func (s *ShopSQLRepository) IsOwnedBy(ctx context.Context, shopID, userID int) (bool, error) {
+	var owned bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM shops WHERE id = $1 AND user_id = $2)`,
+		shopID,
+		userID,
+	).Scan(&owned)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     ShopRepositoryField,
+			"function": ShopIsOwnedByFunctionField,
+			"shop_id":  shopID,
+			"user_id":  userID,
+			"error":    err.Error(),
+		}).Error("Failed to read shop owner")
+		return false, fmt.Errorf("database operation failed")
+	}
+
+	return owned, nil
+}
+
 //	func (s *ShopRepository) GetByID(ctx context.Context, shopID int) (*entities.Shop, error) {
 //		query := `
 //	       		SELECT
@@ -280,3 +312,29 @@ func NewShopRepository(dataBaseConnection DataBaseConnection) ports.ShopReposito
 		db: dataBaseConnection.Connect(),
 	}
 }
+
+// GetOwnerIDByProductID returns the owner of the shop selling the product, including deleted products
+func (s *ShopSQLRepository) GetOwnerIDByProductID(ctx context.Context, productID int) (int, error) {
+	var ownerID int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT s.user_id
+		FROM products p
+		JOIN shops s ON s.id = p.shop_id
+		WHERE p.id = $1`,
+		productID,
+	).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		return 0, &errors.RecordNotFoundError{Message: errors.ProductNotFound}
+	}
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       ShopRepositoryField,
+			"function":   ShopGetOwnerIDByProductIDFunctionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Failed to read product owner")
+		return 0, fmt.Errorf("database operation failed")
+	}
+
+	return ownerID, nil
+}
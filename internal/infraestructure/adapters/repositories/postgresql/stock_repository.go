@@ -0,0 +1,381 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Stock repository log field constants
+const (
+	StockRepositoryField            = "stock_repository"
+	StockApplyMovementField         = "apply_movement"
+	StockGetMovementsField          = "get_movements"
+	LockProductSubFuncField         = "lock_product"
+	UpdateStockSubFuncField         = "update_stock"
+	InsertStockMovementSubFuncField = "insert_stock_movement"
+	StockGetLowStockByShopIDField   = "get_low_stock_by_shop_id"
+	StockClaimLowStockAlertField    = "claim_low_stock_alert"
+	StockReleaseLowStockAlertField  = "release_low_stock_alert"
+	RearmLowStockAlertSubFuncField  = "rearm_low_stock_alert"
+	ProductExistsSubFuncField       = "product_exists"
+)
+
+// Stock repository log message constants
+const (
+	failedApplyStockMovement = "Failed to apply stock movement"
+	failedReadStockMovements = "Failed to read stock movements"
+	failedReadLowStock       = "Failed to read low stock products"
+	failedClaimLowStockAlert = "Failed to claim low stock alert"
+	failedReleaseLowStock    = "Failed to release low stock alert"
+)
+
+type StockRepository struct {
+	db *sql.DB
+}
+
+func NewStockRepository(dataBaseConnection DataBaseConnection) *StockRepository {
+	return &StockRepository{
+		db: dataBaseConnection.Connect(),
+	}
+}
+
+// ApplyStockMovement locks the product row, applies the movement to its stock and
+// appends the movement to the ledger in a single transaction
+func (r *StockRepository) ApplyStockMovement(ctx context.Context, productID int, movement *models.StockMovement) (*models.StockMovement, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockApplyMovementField,
+			"sub_func":   BeginTransactionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(FailedBeginTransactionLog)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// The row lock serializes concurrent movements of the same product
+	product := &models.Product{ID: productID}
+	err = tx.QueryRowContext(ctx, `
+		SELECT stock
+		FROM products
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE`,
+		productID,
+	).Scan(&product.Stock)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logs.WithFields(map[string]interface{}{
+				"file":       StockRepositoryField,
+				"function":   StockApplyMovementField,
+				"product_id": productID,
+			}).Warn(productNotFoundMessage)
+			return nil, &errors.RecordNotFoundError{Message: errors.ProductNotFound}
+		}
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockApplyMovementField,
+			"sub_func":   LockProductSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedApplyStockMovement)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	// Domain rules (e.g. insufficient stock) are checked against the locked stock
+	if err = movement.ApplyTo(product); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE products
+		SET stock = $2, version = version + 1
+		WHERE id = $1`,
+		productID,
+		product.Stock,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockApplyMovementField,
+			"sub_func":   UpdateStockSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedApplyStockMovement)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO stock_movements (product_id, reason, quantity, stock_after, actor, reference)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+		RETURNING id, created_at`,
+		productID,
+		movement.Reason,
+		movement.Quantity,
+		movement.StockAfter,
+		movement.Actor,
+		movement.Reference,
+	).Scan(&movement.ID, &movement.CreatedAt)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockApplyMovementField,
+			"sub_func":   InsertStockMovementSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedApplyStockMovement)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	if err = tx.Commit(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockApplyMovementField,
+			"sub_func":   CommitTransactionField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(FailedCommitTransactionLog)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return movement, nil
+}
+
+// GetMovements returns the ledger of a product, newest first
+// A positive cursor returns the movements older than that movement ID
+// A missing product is reported as such instead of an empty ledger
+func (r *StockRepository) GetMovements(ctx context.Context, productID, limit, cursor int) ([]*models.StockMovement, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)`,
+		productID,
+	).Scan(&exists)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockGetMovementsField,
+			"sub_func":   ProductExistsSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedReadStockMovements)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	if !exists {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockGetMovementsField,
+			"product_id": productID,
+		}).Warn(productNotFoundMessage)
+		return nil, &errors.RecordNotFoundError{Message: errors.ProductNotFound}
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, product_id, reason, quantity, stock_after, COALESCE(actor, ''), COALESCE(reference, ''), created_at
+		FROM stock_movements
+		WHERE product_id = $1 AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3`,
+		productID,
+		cursor,
+		limit,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockGetMovementsField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedReadStockMovements)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer rows.Close()
+
+	movements := make([]*models.StockMovement, 0)
+
+	for rows.Next() {
+		movement := &models.StockMovement{}
+		err := rows.Scan(
+			&movement.ID,
+			&movement.ProductID,
+			&movement.Reason,
+			&movement.Quantity,
+			&movement.StockAfter,
+			&movement.Actor,
+			&movement.Reference,
+			&movement.CreatedAt,
+		)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":       StockRepositoryField,
+				"function":   StockGetMovementsField,
+				"sub_func":   ScanField,
+				"product_id": productID,
+				"error":      err.Error(),
+			}).Error(DatabaseScanFailedLog)
+			return nil, fmt.Errorf("database operation failed")
+		}
+
+		movements = append(movements, movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockGetMovementsField,
+			"sub_func":   NextField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedReadStockMovements)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return movements, nil
+}
+
+// GetLowStockByShopID returns the products of a shop at or below their minimum stock,
+// the ones furthest below their minimum first
+func (r *StockRepository) GetLowStockByShopID(ctx context.Context, shopID int) ([]*models.LowStockProduct, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, shop_id, name, stock, COALESCE(minimum_stock, 0), low_stock_alerted_at
+		FROM products
+		WHERE shop_id = $1 AND deleted_at IS NULL AND stock <= COALESCE(minimum_stock, 0)
+		ORDER BY stock - COALESCE(minimum_stock, 0), id`,
+		shopID,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     StockRepositoryField,
+			"function": StockGetLowStockByShopIDField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error(failedReadLowStock)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer rows.Close()
+
+	products := make([]*models.LowStockProduct, 0)
+
+	for rows.Next() {
+		product := &models.LowStockProduct{}
+		if err := scanLowStockProduct(rows, product); err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     StockRepositoryField,
+				"function": StockGetLowStockByShopIDField,
+				"sub_func": ScanField,
+				"shop_id":  shopID,
+				"error":    err.Error(),
+			}).Error(DatabaseScanFailedLog)
+			return nil, fmt.Errorf("database operation failed")
+		}
+
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     StockRepositoryField,
+			"function": StockGetLowStockByShopIDField,
+			"sub_func": NextField,
+			"shop_id":  shopID,
+			"error":    err.Error(),
+		}).Error(failedReadLowStock)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return products, nil
+}
+
+// ClaimLowStockAlert marks the product as alerted when its stock is low and no alert
+// was sent yet; it returns nil when there is nothing to alert
+// Products whose stock recovered are re-armed first so a later drop alerts again
+// The conditional updates make the claim safe when several changes race
+func (r *StockRepository) ClaimLowStockAlert(ctx context.Context, productID int) (*models.LowStockProduct, error) {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE products
+		SET low_stock_alerted_at = NULL
+		WHERE id = $1 AND low_stock_alerted_at IS NOT NULL AND stock > COALESCE(minimum_stock, 0)`,
+		productID,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockClaimLowStockAlertField,
+			"sub_func":   RearmLowStockAlertSubFuncField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedClaimLowStockAlert)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	product := &models.LowStockProduct{}
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE products
+		SET low_stock_alerted_at = now()
+		WHERE id = $1 AND deleted_at IS NULL AND low_stock_alerted_at IS NULL
+			AND stock <= COALESCE(minimum_stock, 0)
+		RETURNING id, shop_id, name, stock, COALESCE(minimum_stock, 0), low_stock_alerted_at`,
+		productID,
+	)
+	if err := scanLowStockProduct(row, product); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockClaimLowStockAlertField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedClaimLowStockAlert)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return product, nil
+}
+
+// ReleaseLowStockAlert clears a claimed alert that could not be delivered,
+// so the next stock change retries it
+func (r *StockRepository) ReleaseLowStockAlert(ctx context.Context, productID int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE products
+		SET low_stock_alerted_at = NULL
+		WHERE id = $1`,
+		productID,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       StockRepositoryField,
+			"function":   StockReleaseLowStockAlertField,
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error(failedReleaseLowStock)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLowStockProduct(row rowScanner, product *models.LowStockProduct) error {
+	return row.Scan(
+		&product.ProductID,
+		&product.ShopID,
+		&product.Name,
+		&product.Stock,
+		&product.MinimumStock,
+		&product.AlertedAt,
+	)
+}
@@ -0,0 +1,286 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Upload repository log field constants
+const (
+	UploadRepositoryField            = "upload_repository"
+	UploadCreateFunctionField        = "create"
+	UploadGetByIDFunctionField       = "get_by_id"
+	UploadFinalizeFunctionField      = "finalize"
+	UploadClaimFunctionField         = "claim"
+	UploadReleaseFunctionField       = "release"
+	UploadDeleteExpiredFunctionField = "delete_expired"
+)
+
+// Upload repository log message constants
+const (
+	failedInsertUpload         = "Failed to insert upload"
+	failedReadUpload           = "Failed to read upload"
+	failedFinalizeUpload       = "Failed to finalize upload"
+	failedClaimUploads         = "Failed to claim uploads"
+	failedReleaseUploads       = "Failed to release uploads"
+	failedDeleteExpiredUploads = "Failed to delete expired uploads"
+)
+
+// uploadColumns is shared by every upload read query
+const uploadColumns = `id, status, content_type, size, COALESCE(url, ''), renditions, expires_at, created_at`
+
+type UploadRepository struct {
+	db *sql.DB
+}
+
+func NewUploadRepository(dataBaseConnection DataBaseConnection) *UploadRepository {
+	return &UploadRepository{
+		db: dataBaseConnection.Connect(),
+	}
+}
+
+func (r *UploadRepository) Create(ctx context.Context, upload *models.Upload) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO uploads (id, status, content_type, size, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		upload.ID,
+		upload.Status,
+		upload.ContentType,
+		upload.Size,
+		upload.ExpiresAt,
+		upload.CreatedAt,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":      UploadRepositoryField,
+			"function":  UploadCreateFunctionField,
+			"upload_id": upload.ID,
+			"error":     err.Error(),
+		}).Error(failedInsertUpload)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+func (r *UploadRepository) GetByID(ctx context.Context, uploadID string) (*models.Upload, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+uploadColumns+` FROM uploads WHERE id = $1`, uploadID)
+
+	upload, err := scanUpload(row)
+	if err == sql.ErrNoRows {
+		return nil, &errors.RecordNotFoundError{Message: errors.UploadNotFound}
+	}
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":      UploadRepositoryField,
+			"function":  UploadGetByIDFunctionField,
+			"upload_id": uploadID,
+			"error":     err.Error(),
+		}).Error(failedReadUpload)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return upload, nil
+}
+
+// Finalize stores the verified image of an upload still awaiting its content
+// A concurrent finalize of the same upload makes the second one fail
+func (r *UploadRepository) Finalize(ctx context.Context, upload *models.Upload) error {
+	renditions, err := json.Marshal(upload.Renditions)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":      UploadRepositoryField,
+			"function":  UploadFinalizeFunctionField,
+			"sub_func":  MarshalImagesSubFuncField,
+			"upload_id": upload.ID,
+			"error":     err.Error(),
+		}).Error(failedFinalizeUpload)
+		return fmt.Errorf("database operation failed")
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE uploads SET status = $2, url = $3, renditions = $4
+		WHERE id = $1 AND status = $5`,
+		upload.ID,
+		upload.Status,
+		upload.URL,
+		renditions,
+		models.UploadAwaitingContent,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":      UploadRepositoryField,
+			"function":  UploadFinalizeFunctionField,
+			"upload_id": upload.ID,
+			"error":     err.Error(),
+		}).Error(failedFinalizeUpload)
+		return fmt.Errorf("database operation failed")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":      UploadRepositoryField,
+			"function":  UploadFinalizeFunctionField,
+			"sub_func":  RowsAffectedSubFuncField,
+			"upload_id": upload.ID,
+			"error":     err.Error(),
+		}).Error(failedFinalizeUpload)
+		return fmt.Errorf("database operation failed")
+	}
+	if affected == 0 {
+		return &errors.BusinessRuleError{Message: errors.UploadAlreadyFinalized}
+	}
+
+	return nil
+}
+
+// Claim marks the pending, unexpired uploads as attached and returns them
+// Uploads that cannot be attached are left untouched and missing from the result
+func (r *UploadRepository) Claim(ctx context.Context, uploadIDs []string, now time.Time) ([]*models.Upload, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE uploads SET status = $2, attached_at = $3
+		WHERE id = ANY($1) AND status = $4 AND expires_at > $3
+		RETURNING `+uploadColumns,
+		pq.Array(uploadIDs),
+		models.UploadAttached,
+		now,
+		models.UploadPending,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       UploadRepositoryField,
+			"function":   UploadClaimFunctionField,
+			"upload_ids": uploadIDs,
+			"error":      err.Error(),
+		}).Error(failedClaimUploads)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer rows.Close()
+
+	var uploads []*models.Upload
+	for rows.Next() {
+		upload, err := scanUpload(rows)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     UploadRepositoryField,
+				"function": UploadClaimFunctionField,
+				"sub_func": ScanField,
+				"error":    err.Error(),
+			}).Error(DatabaseScanFailedLog)
+			return nil, fmt.Errorf("database operation failed")
+		}
+		uploads = append(uploads, upload)
+	}
+	if err := rows.Err(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     UploadRepositoryField,
+			"function": UploadClaimFunctionField,
+			"sub_func": NextField,
+			"error":    err.Error(),
+		}).Error(failedClaimUploads)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return uploads, nil
+}
+
+// Release returns claimed uploads to pending when the product could not be saved
+func (r *UploadRepository) Release(ctx context.Context, uploadIDs []string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE uploads SET status = $2, attached_at = NULL
+		WHERE id = ANY($1) AND status = $3`,
+		pq.Array(uploadIDs),
+		models.UploadPending,
+		models.UploadAttached,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":       UploadRepositoryField,
+			"function":   UploadReleaseFunctionField,
+			"upload_ids": uploadIDs,
+			"error":      err.Error(),
+		}).Error(failedReleaseUploads)
+		return fmt.Errorf("database operation failed")
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes the uploads never attached to a product and returns them
+func (r *UploadRepository) DeleteExpired(ctx context.Context, now time.Time) ([]*models.Upload, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		DELETE FROM uploads
+		WHERE status <> $1 AND expires_at <= $2
+		RETURNING `+uploadColumns,
+		models.UploadAttached,
+		now,
+	)
+	if err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     UploadRepositoryField,
+			"function": UploadDeleteExpiredFunctionField,
+			"error":    err.Error(),
+		}).Error(failedDeleteExpiredUploads)
+		return nil, fmt.Errorf("database operation failed")
+	}
+	defer rows.Close()
+
+	var uploads []*models.Upload
+	for rows.Next() {
+		upload, err := scanUpload(rows)
+		if err != nil {
+			logs.WithFields(map[string]interface{}{
+				"file":     UploadRepositoryField,
+				"function": UploadDeleteExpiredFunctionField,
+				"sub_func": ScanField,
+				"error":    err.Error(),
+			}).Error(DatabaseScanFailedLog)
+			return nil, fmt.Errorf("database operation failed")
+		}
+		uploads = append(uploads, upload)
+	}
+	if err := rows.Err(); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     UploadRepositoryField,
+			"function": UploadDeleteExpiredFunctionField,
+			"sub_func": NextField,
+			"error":    err.Error(),
+		}).Error(failedDeleteExpiredUploads)
+		return nil, fmt.Errorf("database operation failed")
+	}
+
+	return uploads, nil
+}
+
+// uploadScanner is satisfied by *sql.Row and *sql.Rows
+type uploadScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUpload(row uploadScanner) (*models.Upload, error) {
+	var upload models.Upload
+	err := row.Scan(
+		&upload.ID,
+		&upload.Status,
+		&upload.ContentType,
+		&upload.Size,
+		&upload.URL,
+		jsonColumn{dest: &upload.Renditions},
+		&upload.ExpiresAt,
+		&upload.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
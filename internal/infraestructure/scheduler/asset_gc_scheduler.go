@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+)
+
+// Asset GC scheduler log field constants
+const (
+	AssetGCSchedulerField   = "asset_gc_scheduler"
+	RunAssetGCFunctionField = "run"
+)
+
+// defaultAssetGCGracePeriod keeps the images of requests in progress when no grace period is configured
+const defaultAssetGCGracePeriod = 24 * time.Hour
+
+// AssetGCScheduler collects the orphaned assets every interval while the server runs
+type AssetGCScheduler struct {
+	collectOrphanedAssets ports.CollectOrphanedAssetsUseCase
+	interval              time.Duration
+	grace                 time.Duration
+	dryRun                bool
+
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// NewAssetGCScheduler reads ASSET_GC_INTERVAL, ASSET_GC_GRACE_PERIOD and ASSET_GC_DRY_RUN
+// The schedule is disabled unless ASSET_GC_INTERVAL is a positive duration
+func NewAssetGCScheduler(collectOrphanedAssetsUseCase ports.CollectOrphanedAssetsUseCase) *AssetGCScheduler {
+	interval, _ := time.ParseDuration(strings.TrimSpace(os.Getenv("ASSET_GC_INTERVAL")))
+
+	grace, err := time.ParseDuration(strings.TrimSpace(os.Getenv("ASSET_GC_GRACE_PERIOD")))
+	if err != nil || grace <= 0 {
+		grace = defaultAssetGCGracePeriod
+	}
+
+	return &AssetGCScheduler{
+		collectOrphanedAssets: collectOrphanedAssetsUseCase,
+		interval:              interval,
+		grace:                 grace,
+		dryRun:                os.Getenv("ASSET_GC_DRY_RUN") == "true",
+	}
+}
+
+// Start runs the collection every interval until Stop is called
+func (s *AssetGCScheduler) Start() {
+	if s.interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done.Add(1)
+	go func() {
+		defer s.done.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.run(ctx)
+			}
+		}
+	}()
+}
+
+// Stop cancels a collection in progress and waits for it to return
+func (s *AssetGCScheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.done.Wait()
+}
+
+func (s *AssetGCScheduler) run(ctx context.Context) {
+	// The report is logged by the service, only failures are logged here
+	if _, err := s.collectOrphanedAssets.Execute(ctx, s.grace, s.dryRun); err != nil {
+		logs.WithFields(map[string]interface{}{
+			"file":     AssetGCSchedulerField,
+			"function": RunAssetGCFunctionField,
+			"error":    err.Error(),
+		}).Error("Error collecting orphaned assets")
+	}
+}
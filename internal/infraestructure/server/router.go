@@ -14,21 +14,36 @@ type Router interface {
 	RouteApp() *mux.Router
 }
 type router struct {
-	router         *mux.Router
-	authHandler    ports.AuthHandler
-	healthHandler  ports.HealthHandler
-	productHandler ports.ProductHandler
+	router           *mux.Router
+	authHandler      ports.AuthHandler
+	healthHandler    ports.HealthHandler
+	productHandler   ports.ProductHandler
+	promotionHandler ports.PromotionHandler
+	stockHandler     ports.StockHandler
+	assetHandler     ports.AssetHandler
+	uploadHandler    ports.UploadHandler
+	cartHandler      ports.CartHandler
+	orderHandler     ports.OrderHandler
+	auth             *middleware.Auth
 }
 
-func NewRouter(authHandler ports.AuthHandler, healthHandler ports.HealthHandler, productHandler ports.ProductHandler) *router {
+func NewRouter(authHandler ports.AuthHandler, healthHandler ports.HealthHandler, productHandler ports.ProductHandler, promotionHandler ports.PromotionHandler, stockHandler ports.StockHandler, assetHandler ports.AssetHandler, uploadHandler ports.UploadHandler, cartHandler ports.CartHandler, orderHandler ports.OrderHandler, auth *middleware.Auth) *router {
 	r := mux.NewRouter()
 	r.Use(middleware.Logging)
 	r.Use(middleware.PrometheusMiddleware)
+	r.Use(auth.Authenticate)
 	return &router{
-		router:         r,
-		authHandler:    authHandler,
-		healthHandler:  healthHandler,
-		productHandler: productHandler,
+		router:           r,
+		authHandler:      authHandler,
+		healthHandler:    healthHandler,
+		productHandler:   productHandler,
+		promotionHandler: promotionHandler,
+		stockHandler:     stockHandler,
+		assetHandler:     assetHandler,
+		uploadHandler:    uploadHandler,
+		cartHandler:      cartHandler,
+		orderHandler:     orderHandler,
+		auth:             auth,
 	}
 }
 
@@ -38,6 +53,10 @@ func (r *router) RouteApp() *mux.Router {
 	r.productRoutes()
 	r.metricsRoutes()
 	r.shopRoutes()
+	r.adminRoutes()
+	r.assetRoutes()
+	r.uploadRoutes()
+	r.orderRoutes()
 	return r.router
 }
 
@@ -56,11 +75,54 @@ func (r *router) productRoutes() {
 	sub.HandleFunc("", r.productHandler.Create).Methods(http.MethodPost)
 	sub.HandleFunc("/{product_id}", r.productHandler.GetByID).Methods(http.MethodGet)
 	sub.HandleFunc("/{product_id}", r.productHandler.Update).Methods(http.MethodPut)
+	sub.HandleFunc("/{product_id}", r.productHandler.Patch).Methods(http.MethodPatch)
+	sub.HandleFunc("/{product_id}", r.auth.RequireProductStaff(r.productHandler.Delete)).Methods(http.MethodDelete)
+	sub.HandleFunc("/{product_id}/restore", r.auth.RequireProductStaff(r.productHandler.Restore)).Methods(http.MethodPost)
+	sub.HandleFunc("/{product_id}/quote", r.productHandler.Quote).Methods(http.MethodPost)
+	sub.HandleFunc("/{product_id}/images/order", r.productHandler.ReorderImages).Methods(http.MethodPut)
+	sub.HandleFunc("/{product_id}/promotions", r.promotionHandler.Create).Methods(http.MethodPost)
+	sub.HandleFunc("/{product_id}/promotions/{promotion_id}", r.promotionHandler.Delete).Methods(http.MethodDelete)
+	sub.HandleFunc("/{product_id}/stock-movements", r.auth.RequireProductStaff(r.stockHandler.AdjustStock)).Methods(http.MethodPost)
+	sub.HandleFunc("/{product_id}/stock-movements", r.stockHandler.GetMovements).Methods(http.MethodGet)
+}
+
+func (r *router) adminRoutes() {
+	sub := r.router.PathPrefix("/admin").Subrouter()
+	sub.HandleFunc("/products/purge", r.auth.RequirePlatformAdmin(r.productHandler.PurgeDeleted)).Methods(http.MethodPost)
+	sub.HandleFunc("/uploads/purge", r.auth.RequirePlatformAdmin(r.uploadHandler.PurgeExpired)).Methods(http.MethodPost)
+	sub.HandleFunc("/assets/gc", r.auth.RequirePlatformAdmin(r.assetHandler.CollectOrphans)).Methods(http.MethodPost)
+	sub.HandleFunc("/carts/purge", r.auth.RequirePlatformAdmin(r.cartHandler.PurgeExpired)).Methods(http.MethodPost)
 }
 
 func (r *router) shopRoutes() {
 	sub := r.router.PathPrefix("/shops").Subrouter()
 	sub.HandleFunc("/{shop_id}/products", r.productHandler.GetAllByShopID).Methods(http.MethodGet)
+	sub.HandleFunc("/{shop_id}/products/search", r.productHandler.Search).Methods(http.MethodGet)
+	sub.HandleFunc("/{shop_id}/inventory/low-stock", r.stockHandler.GetLowStockReport).Methods(http.MethodGet)
+	sub.HandleFunc("/{shop_id}/cart", r.cartHandler.Get).Methods(http.MethodGet)
+	sub.HandleFunc("/{shop_id}/cart", r.cartHandler.Clear).Methods(http.MethodDelete)
+	sub.HandleFunc("/{shop_id}/cart/lines", r.cartHandler.AddLine).Methods(http.MethodPost)
+	sub.HandleFunc("/{shop_id}/cart/lines/{line_id}", r.cartHandler.UpdateLine).Methods(http.MethodPatch)
+	sub.HandleFunc("/{shop_id}/cart/lines/{line_id}", r.cartHandler.RemoveLine).Methods(http.MethodDelete)
+	sub.HandleFunc("/{shop_id}/orders", r.orderHandler.Create).Methods(http.MethodPost)
+	sub.HandleFunc("/{shop_id}/orders", r.auth.RequireShopStaff(r.orderHandler.GetShopOrders)).Methods(http.MethodGet)
+}
+
+func (r *router) assetRoutes() {
+	r.router.HandleFunc("/assets/{key}", r.assetHandler.Serve).Methods(http.MethodGet)
+}
+
+func (r *router) uploadRoutes() {
+	sub := r.router.PathPrefix("/uploads").Subrouter()
+	sub.HandleFunc("", r.uploadHandler.Create).Methods(http.MethodPost)
+	sub.HandleFunc("/{upload_id}/finalize", r.uploadHandler.Finalize).Methods(http.MethodPost)
+}
+
+func (r *router) orderRoutes() {
+	sub := r.router.PathPrefix("/orders").Subrouter()
+	sub.HandleFunc("", r.auth.RequireUser(r.orderHandler.GetCustomerOrders)).Methods(http.MethodGet)
+	sub.HandleFunc("/{order_id}", r.auth.RequireUser(r.orderHandler.GetByID)).Methods(http.MethodGet)
+	sub.HandleFunc("/{order_id}/transitions", r.orderHandler.Transition).Methods(http.MethodPost)
 }
 
 func (r *router) metricsRoutes() {
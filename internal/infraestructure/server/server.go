@@ -16,7 +16,17 @@ type Server struct {
 }
 
 func (s *Server) Initialize() {
-	handler := cors.AllowAll().Handler(s.Router.RouteApp())
+	// Same as cors.AllowAll, exposing ETag so admin clients can send it back in If-Match
+	handler := cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{
+			http.MethodHead, http.MethodGet, http.MethodPost,
+			http.MethodPut, http.MethodPatch, http.MethodDelete,
+		},
+		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{"ETag"},
+		AllowCredentials: false,
+	}).Handler(s.Router.RouteApp())
 	writeTimeout := 10 * time.Second // Producción
 	if os.Getenv("ENVIRONMENT") == "test" {
 		writeTimeout = 300 * time.Second // 5 minutos para debug
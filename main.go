@@ -11,14 +11,24 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/mlgaray/ecommerce_api/internal/application/services"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/asset"
 	"github.com/mlgaray/ecommerce_api/internal/application/usecases/auth"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/cart"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/order"
 	"github.com/mlgaray/ecommerce_api/internal/application/usecases/product"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/stock"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/upload"
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
 	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/assets"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/auth/jwt"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/middleware"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/images"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/notifications"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/repositories/postgresql"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/scheduler"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/server"
 )
 
@@ -40,6 +50,8 @@ var Module = fx.Options(
 		// SHOP
 		// fx.Annotate(services.NewShopService, fx.As(new(ports.ShopService))),
 		fx.Annotate(postgresql.NewShopRepository, fx.As(new(ports.ShopRepository))),
+		fx.Annotate(services.NewAccessService, fx.As(new(ports.AccessService))),
+		middleware.NewAuth,
 
 		// ROLE
 		fx.Annotate(postgresql.NewRoleRepository, fx.As(new(ports.RoleRepository))),
@@ -53,6 +65,9 @@ var Module = fx.Options(
 
 		// PAGINATION (shared service for cursor-based pagination)
 		fx.Annotate(services.NewPaginationService[*models.Product], fx.As(new(ports.PaginationService[*models.Product]))),
+		fx.Annotate(services.NewPaginationService[*models.ProductSearchResult], fx.As(new(ports.PaginationService[*models.ProductSearchResult]))),
+		fx.Annotate(services.NewPaginationService[*models.StockMovement], fx.As(new(ports.PaginationService[*models.StockMovement]))),
+		fx.Annotate(services.NewPaginationService[*models.Order], fx.As(new(ports.PaginationService[*models.Order]))),
 
 		// PRODUCT
 		fx.Annotate(http.NewProductHandler, fx.As(new(ports.ProductHandler))),
@@ -60,9 +75,71 @@ var Module = fx.Options(
 		fx.Annotate(product.NewGetAllByShopIDUseCase, fx.As(new(ports.GetAllByShopIDUseCase))),
 		fx.Annotate(product.NewGetByIDUseCase, fx.As(new(ports.GetByIDUseCase))),
 		fx.Annotate(product.NewUpdateProductUseCase, fx.As(new(ports.UpdateProductUseCase))),
+		fx.Annotate(product.NewPatchProductUseCase, fx.As(new(ports.PatchProductUseCase))),
+		fx.Annotate(product.NewSearchProductsUseCase, fx.As(new(ports.SearchProductsUseCase))),
+		fx.Annotate(product.NewDeleteProductUseCase, fx.As(new(ports.DeleteProductUseCase))),
+		fx.Annotate(product.NewRestoreProductUseCase, fx.As(new(ports.RestoreProductUseCase))),
+		fx.Annotate(product.NewPurgeProductsUseCase, fx.As(new(ports.PurgeProductsUseCase))),
+		fx.Annotate(product.NewQuoteProductUseCase, fx.As(new(ports.QuoteProductUseCase))),
+		fx.Annotate(product.NewReorderProductImagesUseCase, fx.As(new(ports.ReorderProductImagesUseCase))),
 		fx.Annotate(services.NewProductService, fx.As(new(ports.ProductService))),
 		fx.Annotate(postgresql.NewProductRepository, fx.As(new(ports.ProductRepository))),
 
+		// PROMOTION
+		fx.Annotate(http.NewPromotionHandler, fx.As(new(ports.PromotionHandler))),
+		fx.Annotate(product.NewCreatePromotionUseCase, fx.As(new(ports.CreatePromotionUseCase))),
+		fx.Annotate(product.NewDeletePromotionUseCase, fx.As(new(ports.DeletePromotionUseCase))),
+		fx.Annotate(services.NewPromotionService, fx.As(new(ports.PromotionService))),
+		fx.Annotate(postgresql.NewPromotionRepository, fx.As(new(ports.PromotionRepository))),
+
+		// STOCK
+		fx.Annotate(http.NewStockHandler, fx.As(new(ports.StockHandler))),
+		fx.Annotate(stock.NewAdjustStockUseCase, fx.As(new(ports.AdjustStockUseCase))),
+		fx.Annotate(stock.NewGetStockMovementsUseCase, fx.As(new(ports.GetStockMovementsUseCase))),
+		fx.Annotate(services.NewStockService, fx.As(new(ports.StockService))),
+		fx.Annotate(postgresql.NewStockRepository, fx.As(new(ports.StockRepository))),
+		fx.Annotate(stock.NewGetLowStockReportUseCase, fx.As(new(ports.GetLowStockReportUseCase))),
+		fx.Annotate(services.NewInventoryService, fx.As(new(ports.InventoryService))),
+		notifications.NewNotifier,
+
+		// ASSETS
+		fx.Annotate(http.NewAssetHandler, fx.As(new(ports.AssetHandler))),
+		assets.NewAssetService,
+		fx.Annotate(images.NewImageProcessor, fx.As(new(ports.ImageProcessor))),
+		fx.Annotate(asset.NewCollectOrphanedAssetsUseCase, fx.As(new(ports.CollectOrphanedAssetsUseCase))),
+		fx.Annotate(services.NewAssetGCService, fx.As(new(ports.AssetGCService))),
+		fx.Annotate(postgresql.NewAssetReferenceRepository, fx.As(new(ports.AssetReferenceRepository))),
+		scheduler.NewAssetGCScheduler,
+
+		// UPLOADS
+		fx.Annotate(http.NewUploadHandler, fx.As(new(ports.UploadHandler))),
+		fx.Annotate(upload.NewCreateUploadUseCase, fx.As(new(ports.CreateUploadUseCase))),
+		fx.Annotate(upload.NewFinalizeUploadUseCase, fx.As(new(ports.FinalizeUploadUseCase))),
+		fx.Annotate(upload.NewPurgeUploadsUseCase, fx.As(new(ports.PurgeUploadsUseCase))),
+		fx.Annotate(services.NewUploadService, fx.As(new(ports.UploadService))),
+		fx.Annotate(postgresql.NewUploadRepository, fx.As(new(ports.UploadRepository))),
+
+		// CART
+		fx.Annotate(http.NewCartHandler, fx.As(new(ports.CartHandler))),
+		fx.Annotate(cart.NewGetCartUseCase, fx.As(new(ports.GetCartUseCase))),
+		fx.Annotate(cart.NewAddCartLineUseCase, fx.As(new(ports.AddCartLineUseCase))),
+		fx.Annotate(cart.NewUpdateCartLineUseCase, fx.As(new(ports.UpdateCartLineUseCase))),
+		fx.Annotate(cart.NewRemoveCartLineUseCase, fx.As(new(ports.RemoveCartLineUseCase))),
+		fx.Annotate(cart.NewClearCartUseCase, fx.As(new(ports.ClearCartUseCase))),
+		fx.Annotate(cart.NewPurgeCartsUseCase, fx.As(new(ports.PurgeCartsUseCase))),
+		fx.Annotate(services.NewCartService, fx.As(new(ports.CartService))),
+		fx.Annotate(postgresql.NewCartRepository, fx.As(new(ports.CartRepository))),
+
+		// ORDER
+		fx.Annotate(http.NewOrderHandler, fx.As(new(ports.OrderHandler))),
+		fx.Annotate(order.NewCreateOrderUseCase, fx.As(new(ports.CreateOrderUseCase))),
+		fx.Annotate(order.NewGetOrderUseCase, fx.As(new(ports.GetOrderUseCase))),
+		fx.Annotate(order.NewGetShopOrdersUseCase, fx.As(new(ports.GetShopOrdersUseCase))),
+		fx.Annotate(order.NewGetCustomerOrdersUseCase, fx.As(new(ports.GetCustomerOrdersUseCase))),
+		fx.Annotate(order.NewTransitionOrderUseCase, fx.As(new(ports.TransitionOrderUseCase))),
+		fx.Annotate(services.NewOrderService, fx.As(new(ports.OrderService))),
+		fx.Annotate(postgresql.NewOrderRepository, fx.As(new(ports.OrderRepository))),
+
 		// SERVER
 		server.NewServer,
 		fx.Annotate(server.NewRouter, fx.As(new(server.Router))),
@@ -71,11 +148,17 @@ var Module = fx.Options(
 	),
 	fx.Invoke(
 		RegisterHooks,
+		RegisterSchedulerHooks,
 		InitializeLogger,
 	),
 )
 
 func main() {
+	// Subcommands run a maintenance job once instead of the server
+	if len(os.Args) > 1 && os.Args[1] == gcAssetsCommand {
+		os.Exit(runGCAssets(os.Args[2:]))
+	}
+
 	log.Println("Starting application...")
 	app := fx.New(Module, fx.StartTimeout(30*time.Second))
 	app.Run()
@@ -113,6 +196,19 @@ func RegisterHooks(lc fx.Lifecycle, server *server.Server) {
 	})
 }
 
+func RegisterSchedulerHooks(lc fx.Lifecycle, assetGCScheduler *scheduler.AssetGCScheduler) {
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			assetGCScheduler.Start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			assetGCScheduler.Stop()
+			return nil
+		},
+	})
+}
+
 // func NewServerHooks(router *mux.Router) fx.Hook {
 //	return fx.Hook{
 //		OnStart: func(context.Context) error {
@@ -105,3 +105,75 @@ func (_c *ShopRepository_Create_Call) RunAndReturn(run func(ctx context.Context,
 	_c.Call.Return(run)
 	return _c
 }
+
+// IsOwnedBy provides a mock function for the type ShopRepository
+func (_mock *ShopRepository) IsOwnedBy(ctx context.Context, shopID int, userID int) (bool, error) {
+	ret := _mock.Called(ctx, shopID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsOwnedBy")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) (bool, error)); ok {
+		return returnFunc(ctx, shopID, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) bool); ok {
+		r0 = returnFunc(ctx, shopID, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, shopID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// ShopRepository_IsOwnedBy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsOwnedBy'
+type ShopRepository_IsOwnedBy_Call struct {
+	*mock.Call
+}
+
+// IsOwnedBy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - shopID int
+//   - userID int
+func (_e *ShopRepository_Expecter) IsOwnedBy(ctx interface{}, shopID interface{}, userID interface{}) *ShopRepository_IsOwnedBy_Call {
+	return &ShopRepository_IsOwnedBy_Call{Call: _e.mock.On("IsOwnedBy", ctx, shopID, userID)}
+}
+
+func (_c *ShopRepository_IsOwnedBy_Call) Run(run func(ctx context.Context, shopID int, userID int)) *ShopRepository_IsOwnedBy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *ShopRepository_IsOwnedBy_Call) Return(b bool, err error) *ShopRepository_IsOwnedBy_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *ShopRepository_IsOwnedBy_Call) RunAndReturn(run func(ctx context.Context, shopID int, userID int) (bool, error)) *ShopRepository_IsOwnedBy_Call {
+	_c.Call.Return(run)
+	return _c
+}
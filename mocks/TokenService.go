@@ -103,3 +103,71 @@ func (_c *TokenService_Generate_Call) RunAndReturn(run func(ctx context.Context,
 	_c.Call.Return(run)
 	return _c
 }
+
+// VerifyToken provides a mock function for the type TokenService
+func (_mock *TokenService) VerifyToken(ctx context.Context, token string) (*models.User, error) {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyToken")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return returnFunc(ctx, token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// TokenService_VerifyToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyToken'
+type TokenService_VerifyToken_Call struct {
+	*mock.Call
+}
+
+// VerifyToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *TokenService_Expecter) VerifyToken(ctx interface{}, token interface{}) *TokenService_VerifyToken_Call {
+	return &TokenService_VerifyToken_Call{Call: _e.mock.On("VerifyToken", ctx, token)}
+}
+
+func (_c *TokenService_VerifyToken_Call) Run(run func(ctx context.Context, token string)) *TokenService_VerifyToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *TokenService_VerifyToken_Call) Return(user *models.User, err error) *TokenService_VerifyToken_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *TokenService_VerifyToken_Call) RunAndReturn(run func(ctx context.Context, token string) (*models.User, error)) *TokenService_VerifyToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
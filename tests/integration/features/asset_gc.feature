@@ -0,0 +1,49 @@
+Feature: Orphaned Asset Collection
+  As a platform operator
+  I want images no product, shop or category references anymore deleted
+  So that the storage does not keep growing with images nobody can see
+
+  Scenario: Report orphaned images in a dry run
+    Given the asset storage is an S3 compatible bucket
+    And the storage holds referenced, orphaned and recently stored images
+    When I collect the orphaned assets in a dry run
+    Then the response status should be 200
+    And only the orphaned image should be reported
+    And no image should be deleted
+
+  Scenario: Delete orphaned images from the bucket
+    Given the asset storage is an S3 compatible bucket
+    And the storage holds referenced, orphaned and recently stored images
+    When I collect the orphaned assets
+    Then the response status should be 200
+    And only the orphaned image should be reported
+    And the orphaned image should be deleted
+    And the referenced and recently stored images should be kept
+
+  Scenario: Delete orphaned images from the local storage
+    Given the storage holds referenced, orphaned and recently stored images
+    When I collect the orphaned assets
+    Then the response status should be 200
+    And only the orphaned image should be reported
+    And the orphaned image should be deleted
+    And the referenced and recently stored images should be kept
+
+  Scenario: Reject a collection without a grace period
+    When I collect the orphaned assets with a grace period of 0 hours
+    Then the response status should be 400
+    And the user should receive an error message "grace_period_must_be_positive"
+
+  Scenario: Anonymous callers cannot collect orphaned assets
+    Given the storage holds referenced, orphaned and recently stored images
+    And the request is sent anonymously
+    When I collect the orphaned assets
+    Then the response status should be 401
+    And the user should receive an error message "authentication_required"
+    And every image should be kept
+
+  Scenario: Shop owners cannot collect orphaned assets
+    Given the storage holds referenced, orphaned and recently stored images
+    And the request is sent by user 1
+    When I collect the orphaned assets
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
@@ -0,0 +1,20 @@
+Feature: Asset Storage
+  As a platform operator
+  I want product images stored in an S3 compatible bucket
+  So that they do not live on the API container disk
+
+  Scenario: Store product images in an S3 compatible bucket
+    Given the asset storage is an S3 compatible bucket
+    And I have valid product data with images
+    When I send a create product request
+    Then the response status should be 201
+    And the product images should be stored in the bucket
+    And the product images should be served from the asset storage
+
+  Scenario: Upload large images in parts
+    Given the asset storage is an S3 compatible bucket with parts of 64 bytes
+    And I have valid product data with images
+    When I send a create product request
+    Then the response status should be 201
+    And the bucket should have received a multipart upload
+    And the product images should be stored in the bucket
@@ -0,0 +1,128 @@
+Feature: Shopping Cart
+  As a customer
+  I want to keep the products I pick in a cart
+  So that I can review what I will pay before checking out
+
+  Scenario: The first line opens an anonymous cart
+    Given the shop sells burger 1 with stock 10
+    When I add 2 of burger 1 configured as '[{"variant_id": 1, "option_ids": [2]}, {"variant_id": 2, "option_ids": [4]}]' to the cart
+    Then the response status should be 200
+    And the response should carry the cart token
+    And the cart should have 1 line
+    And the cart subtotal should be 28.00
+    And the cart changes should be stored
+
+  Scenario: Adding the same configuration increases its line
+    Given my cart holds 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}, {"variant_id": 2, "option_ids": [4, 5]}]'
+    When I add 2 of burger 1 configured as '[{"variant_id": 2, "option_ids": [5, 4]}, {"variant_id": 1, "option_ids": [1]}]' to the cart
+    Then the response status should be 200
+    And the cart should have 1 line
+    And cart line 1 should have quantity 3
+    And the cart subtotal should be 36.00
+    And the cart changes should be stored
+
+  Scenario: A different configuration gets its own line
+    Given my cart holds 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    When I add 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [2]}]' to the cart
+    Then the response status should be 200
+    And the cart should have 2 lines
+    And the cart subtotal should be 23.00
+
+  Scenario: Reject a line that exceeds the stock
+    Given the shop sells burger 1 with stock 1
+    When I add 2 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]' to the cart
+    Then the response status should be 422
+    And the user should receive an error message "insufficient_stock"
+
+  Scenario: Reject a line with an invalid configuration
+    Given the shop sells burger 1 with stock 10
+    When I add 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1, 2]}]' to the cart
+    Then the response status should be 400
+    And the user should receive an error message "single_selection_allows_one_option"
+
+  Scenario: Reject a product of another shop
+    Given burger 7 belongs to another shop
+    When I add 1 of burger 7 configured as '[{"variant_id": 1, "option_ids": [1]}]' to the cart
+    Then the response status should be 404
+    And the user should receive an error message "product_not_found"
+
+  Scenario: Prices are recomputed when the cart is read
+    Given my cart holds 2 of burger 1 configured as '[{"variant_id": 1, "option_ids": [2]}]'
+    And burger 1 is now on promotion at 8.00
+    When I view the cart
+    Then the response status should be 200
+    And the cart subtotal should be 22.00
+
+  Scenario: Lines of removed products stay in the cart without a price
+    Given my cart holds 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    And my cart holds 1 of burger 2 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    And burger 2 was removed from the shop
+    When I view the cart
+    Then the response status should be 200
+    And cart line 2 should be unavailable because "product_not_available"
+    And the cart subtotal should be 10.00
+
+  Scenario: Change the quantity of a line
+    Given my cart holds 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    When I change cart line 1 to quantity 4
+    Then the response status should be 200
+    And cart line 1 should have quantity 4
+    And the cart subtotal should be 40.00
+    And the cart changes should be stored
+
+  Scenario: Reject a quantity above the stock
+    Given my cart holds 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    And the shop sells burger 1 with stock 3
+    When I change cart line 1 to quantity 4
+    Then the response status should be 422
+    And the user should receive an error message "insufficient_stock"
+
+  Scenario: Change a line that is not in the cart
+    Given my cart holds 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    When I change cart line 9 to quantity 2
+    Then the response status should be 404
+    And the user should receive an error message "cart_line_not_found"
+
+  Scenario: Remove a line
+    Given my cart holds 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    And my cart holds 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [2]}]'
+    When I remove cart line 1
+    Then the response status should be 200
+    And the cart should have 1 line
+    And the cart subtotal should be 13.00
+    And the cart changes should be stored
+
+  Scenario: Clear the cart
+    Given my cart holds 2 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    When I clear the cart
+    Then the response status should be 200
+    And the cart should have 0 lines
+    And the cart subtotal should be 0.00
+    And the cart changes should be stored
+
+  Scenario: There is no cart before the first line
+    When I view a cart I did not open
+    Then the response status should be 404
+    And the user should receive an error message "cart_not_found"
+
+  Scenario: Reject a cart token the API did not issue
+    When I view the cart with the token "../other"
+    Then the response status should be 400
+    And the user should receive an error message "invalid_cart_token_format"
+
+  Scenario: Purge the carts without activity
+    When I purge the expired carts when 3 carts had expired
+    Then the response status should be 200
+    And 3 carts should be purged
+
+  Scenario: Anonymous callers cannot purge carts
+    Given the request is sent anonymously
+    When I purge the expired carts when 3 carts had expired
+    Then the response status should be 401
+    And the user should receive an error message "authentication_required"
+
+  Scenario: Shop owners cannot purge the carts of every shop
+    Given the request is sent by user 1
+    When I purge the expired carts when 3 carts had expired
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
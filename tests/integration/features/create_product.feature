@@ -8,6 +8,12 @@ Feature: Product Creation
     When I send a create product request
     Then the response status should be 201
     And the product should be created successfully
+    And the product images should be served from the asset storage
+
+  Scenario: Serve a missing asset
+    When I request the asset "0000000000000000000000000000000000000000000000000000000000000000.png"
+    Then the response status should be 404
+    And the user should receive an error message "asset_not_found"
 
   # HTTP Validations (Infrastructure layer - Contracts)
   Scenario: Create product without images
@@ -45,12 +51,21 @@ Feature: Product Creation
     When I send a create product request
     Then the response status should be 400
     And the user should receive an error message "image_size_too_large_max_3mb"
+    And the field "images[0]" should report "image_size_too_large_max_3mb"
 
   Scenario: Create product with invalid image type
     Given I have product data with invalid image type
     When I send a create product request
     Then the response status should be 400
-    And the user should receive an error message "invalid_image_type_only_jpeg_png_allowed"
+    And the user should receive an error message "unsupported_image_format"
+    And the field "images[0]" should report "unsupported_image_format"
+
+  Scenario: Create product with an image declaring huge dimensions
+    Given I have product data with an image declaring 50000x50000 pixels
+    When I send a create product request
+    Then the response status should be 400
+    And the user should receive an error message "image_dimensions_too_large"
+    And the field "images[0]" should report "image_dimensions_too_large"
 
   # Business Validations (Domain layer - Product.Validate())
   Scenario: Create product with negative price
@@ -94,3 +109,12 @@ Feature: Product Creation
     When I send a create product request
     Then the response status should be 400
     And the user should receive an error message "promotional_price_must_be_lower_than_regular_price"
+
+  Scenario: Create product with inconsistent variants
+    Given I have product data with inconsistent variants
+    When I send a create product request
+    Then the response status should be 400
+    And the user should receive an error message "invalid_variants"
+    And the field "variants[0].max_selections" should report "single_selection_allows_max_selections_of_one"
+    And the field "variants[1].options[1].order" should report "option_orders_must_be_unique"
+    And the field "variants[1].name" should report "variant_names_must_be_unique"
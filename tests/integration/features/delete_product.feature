@@ -0,0 +1,79 @@
+Feature: Product Deletion
+  As a shop owner
+  I want to delete products without losing them immediately
+  So that I can restore a product removed by mistake
+
+  Scenario: Successfully delete a product
+    Given a live product with id 1
+    When I send a delete product request for product 1
+    Then the response status should be 200
+    And the user should receive a success message "product_deleted_successfully"
+
+  Scenario: Delete a product that does not exist or is already deleted
+    Given no live product with id 99
+    When I send a delete product request for product 99
+    Then the response status should be 404
+    And the user should receive an error message "product_not_found"
+
+  Scenario: Anonymous callers cannot delete products
+    Given a live product with id 1
+    And the request is sent anonymously
+    When I send a delete product request for product 1
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
+
+  Scenario: Only the shop staff can delete its products
+    Given a live product with id 1
+    And the request is sent by user 8
+    When I send a delete product request for product 1
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
+
+  Scenario: Delete a product with invalid id
+    When I send a delete product request for product "abc"
+    Then the response status should be 400
+    And the user should receive an error message "invalid_product_id_format"
+
+  Scenario: Successfully restore a deleted product
+    Given a deleted product with id 1
+    When I send a restore product request for product 1
+    Then the response status should be 200
+    And the user should receive a success message "product_restored_successfully"
+
+  Scenario: Restore a product that is not deleted
+    Given no deleted product with id 1
+    When I send a restore product request for product 1
+    Then the response status should be 404
+    And the user should receive an error message "deleted_product_not_found"
+
+  Scenario: Only the shop staff can restore its products
+    Given a deleted product with id 1
+    And the request is sent by user 8
+    When I send a restore product request for product 1
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
+
+  Scenario: Purge products deleted before the retention window
+    Given 3 products were deleted before the retention window
+    When I send a purge deleted products request with retention of 30 days
+    Then the response status should be 200
+    And the response should report 3 purged products
+
+  Scenario: Purge with a non positive retention
+    When I send a purge deleted products request with retention of 0 days
+    Then the response status should be 400
+    And the user should receive an error message "retention_days_must_be_positive"
+
+  Scenario: Anonymous callers cannot purge products
+    Given 3 products were deleted before the retention window
+    And the request is sent anonymously
+    When I send a purge deleted products request with retention of 30 days
+    Then the response status should be 401
+    And the user should receive an error message "authentication_required"
+
+  Scenario: Shop owners cannot purge the products of every shop
+    Given 3 products were deleted before the retention window
+    And the request is sent by user 1
+    When I send a purge deleted products request with retention of 30 days
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
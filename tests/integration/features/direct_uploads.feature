@@ -0,0 +1,75 @@
+Feature: Direct Uploads
+  As a mobile client on a weak connection
+  I want to upload product images straight to the storage, one at a time
+  So that a failed upload does not make me send the whole product form again
+
+  Scenario: Request a signed upload URL
+    Given the asset storage is an S3 compatible bucket
+    When I request an upload URL for a "image/png" image of 2048 bytes
+    Then the response status should be 201
+    And the upload URL should be signed for the bucket
+
+  Scenario: Reject uploads of an unsupported type
+    Given the asset storage is an S3 compatible bucket
+    When I request an upload URL for a "image/gif" image of 2048 bytes
+    Then the response status should be 400
+    And the user should receive an error message "unsupported_image_format"
+
+  Scenario: Reject uploads over the size limit
+    Given the asset storage is an S3 compatible bucket
+    When I request an upload URL for a "image/png" image of 3145729 bytes
+    Then the response status should be 400
+    And the user should receive an error message "upload_size_must_be_between_1_byte_and_3mb"
+
+  Scenario: Direct uploads need an object storage
+    When I request an upload URL for a "image/png" image of 2048 bytes
+    Then the response status should be 422
+    And the user should receive an error message "direct_uploads_require_object_storage"
+
+  Scenario: Finalize an image put through the signed URL
+    Given the asset storage is an S3 compatible bucket
+    And I requested an upload URL for a test image
+    When I put the image at the upload URL
+    And I finalize the upload
+    Then the response status should be 200
+    And the upload should be pending with its renditions
+    And the staging object should be deleted
+
+  Scenario: Finalize an upload whose image was never put
+    Given the asset storage is an S3 compatible bucket
+    And I requested an upload URL for a test image
+    When I finalize the upload
+    Then the response status should be 422
+    And the user should receive an error message "upload_content_not_found"
+
+  Scenario: Create a product with an uploaded image
+    Given I have valid product data referencing a finalized upload
+    When I send a create product request
+    Then the response status should be 201
+    And the product should be created successfully
+
+  Scenario: Reject a product referencing an expired upload
+    Given I have valid product data referencing an expired upload
+    When I send a create product request
+    Then the response status should be 422
+    And the user should receive an error message "upload_expired"
+
+  Scenario: Purge uploads never attached to a product
+    Given there are 2 expired uploads
+    When I purge the expired uploads
+    Then the response status should be 200
+    And 2 uploads should be purged
+
+  Scenario: Anonymous callers cannot purge uploads
+    Given there are 2 expired uploads
+    And the request is sent anonymously
+    When I purge the expired uploads
+    Then the response status should be 401
+    And the user should receive an error message "authentication_required"
+
+  Scenario: Shop owners cannot purge the uploads of every shop
+    Given there are 2 expired uploads
+    And the request is sent by user 1
+    When I purge the expired uploads
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
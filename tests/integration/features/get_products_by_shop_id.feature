@@ -38,3 +38,39 @@ Feature: Get Products by Shop ID
     When I send a get products request for shop 1 with cursor -1
     Then the response status should be 400
     And the user should receive an error message "invalid_cursor_format"
+
+  Scenario: Get products filtered by category and sorted by price
+    Given a shop with ID 1 has products
+    When I send a get products request for shop 1 with query "category_id=1&in_stock=true&sort=price&order=desc"
+    Then the response status should be 200
+    And the response should contain a list of products
+
+  Scenario: Get products with unsupported sort field
+    When I send a get products request for shop 1 with query "sort=popularity"
+    Then the response status should be 400
+    And the user should receive an error message "invalid_sort_must_be_price_name_or_created_at"
+
+  Scenario: Get products with inverted price range
+    When I send a get products request for shop 1 with query "min_price=50&max_price=10"
+    Then the response status should be 400
+    And the user should receive an error message "min_price_cannot_be_greater_than_max_price"
+
+  Scenario: Get products with invalid boolean filter
+    When I send a get products request for shop 1 with query "in_stock=maybe"
+    Then the response status should be 400
+    And the user should receive an error message "invalid_in_stock_format"
+
+  Scenario: Get products with invalid low stock filter
+    When I send a get products request for shop 1 with query "is_low_stock=maybe"
+    Then the response status should be 400
+    And the user should receive an error message "invalid_is_low_stock_format"
+
+  Scenario: The public listing ignores the request for inactive products
+    When an anonymous caller lists the inactive products of shop 1
+    Then the response status should be 200
+    And the listing should only show active products
+
+  Scenario: The shop owner lists inactive products
+    When the shop owner lists the inactive products of shop 1
+    Then the response status should be 200
+    And the listing should show inactive products
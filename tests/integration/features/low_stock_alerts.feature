@@ -0,0 +1,34 @@
+Feature: Low Stock Alerts
+  As a shop owner
+  I want to be alerted when a product runs low
+  So that I can restock before it sells out
+
+  Scenario: Alert when a sale leaves the stock at the minimum
+    Given a product with id 1, stock 10 and minimum stock 5
+    When I record the stock movement '{"reason": "sale", "quantity": -5}' for product 1
+    Then the response status should be 201
+    And a low stock alert should be sent for product 1
+
+  Scenario: Do not alert while the stock stays above the minimum
+    Given a product with id 1, stock 10 and minimum stock 5
+    When I record the stock movement '{"reason": "sale", "quantity": -4}' for product 1
+    Then the response status should be 201
+    And no low stock alert should be sent
+
+  Scenario: Do not repeat an alert until the stock recovers
+    Given a product with id 1, stock 4 and minimum stock 5
+    And a low stock alert was already sent for product 1
+    When I record the stock movement '{"reason": "sale", "quantity": -1}' for product 1
+    Then the response status should be 201
+    And no low stock alert should be sent
+
+  Scenario: Report the products of a shop below their minimum stock
+    Given shop 1 has 2 products below their minimum stock
+    When I request the low stock report of shop 1
+    Then the response status should be 200
+    And the low stock report should contain 2 products
+
+  Scenario: Reject a low stock report for an invalid shop
+    When I request the low stock report of shop 0
+    Then the response status should be 400
+    And the user should receive an error message "invalid_shop_id_format"
@@ -0,0 +1,177 @@
+Feature: Orders
+  As a customer
+  I want to place orders with the prices I was shown
+  So that later product changes do not alter what I bought
+
+  Scenario: Place an order from a direct payload
+    Given burger 1 can be ordered with stock 10
+    When customer 7 orders 2 of burger 1 configured as '[{"variant_id": 1, "option_ids": [2]}, {"variant_id": 2, "option_ids": [4]}]'
+    Then the response status should be 201
+    And the order should be pending with 1 item
+    And the order total should be 28.00
+    And order item 1 should snapshot "Classic Burger" at 14.00 with options "Size: Double, Extras: Cheese"
+    And the order should be stored
+
+  Scenario: Placing an order takes the stock of the product and its tracked options
+    Given burger 1 can be ordered with stock 10
+    And option 2 has stock 5
+    When customer 7 orders 2 of burger 1 configured as '[{"variant_id": 1, "option_ids": [2]}, {"variant_id": 2, "option_ids": [4]}]'
+    Then the response status should be 201
+    And the checkout should leave burger 1 with stock 8
+    And the checkout should leave option 2 with stock 3
+    And the order should be stored
+
+  Scenario: Reject an order when the stock ran out before checkout
+    Given burger 1 can be ordered with stock 10
+    And only 1 of burger 1 is left when the order is placed
+    When customer 7 orders 2 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    Then the response status should be 422
+    And the user should receive an error message "insufficient_stock"
+    And order line 1 should fail with "insufficient_stock"
+    And the order should be stored
+
+  Scenario: Reject an order when a tracked option ran out before checkout
+    Given burger 1 can be ordered with stock 10
+    And option 2 has stock 1
+    When customer 7 orders 2 of burger 1 configured as '[{"variant_id": 1, "option_ids": [2]}]'
+    Then the response status should be 422
+    And order line 1 should fail with "option_out_of_stock"
+    And the order should be stored
+
+  Scenario: Reject an order above the stock
+    Given burger 1 can be ordered with stock 1
+    When customer 7 orders 2 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    Then the response status should be 422
+    And the user should receive an error message "insufficient_stock"
+
+  Scenario: Reject a product of another shop
+    Given burger 7 is sold by another shop
+    When customer 7 orders 1 of burger 7 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    Then the response status should be 404
+    And the user should receive an error message "product_not_found"
+    And the order should be stored
+
+  Scenario: A checkout with a new idempotency key places the order
+    Given burger 1 can be ordered with stock 10
+    When customer 7 orders 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]' with the idempotency key "checkout-1"
+    Then the response status should be 201
+    And the checkout should leave burger 1 with stock 9
+    And the order should be stored
+
+  Scenario: A retried checkout returns the order already placed
+    Given customer 7 placed an order with the idempotency key "checkout-1" for 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    When customer 7 orders 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]' with the idempotency key "checkout-1"
+    Then the response status should be 201
+    And the response should replay order 1
+    And the order should be stored
+
+  Scenario: A concurrent retry returns the order placed first
+    Given burger 1 can be ordered with stock 10
+    And a concurrent retry places the order first
+    When customer 7 orders 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]' with the idempotency key "checkout-1"
+    Then the response status should be 201
+    And the response should replay order 1
+    And the order should be stored
+
+  Scenario: Reject an idempotency key reused for a different checkout
+    Given customer 7 placed an order with the idempotency key "checkout-1" for 1 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]'
+    When customer 7 orders 3 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}]' with the idempotency key "checkout-1"
+    Then the response status should be 422
+    And the user should receive an error message "idempotency_key_reused_with_a_different_request"
+
+  Scenario: Reject an order with items and the cart
+    When I order my cart together with burger 1
+    Then the response status should be 400
+    And the user should receive an error message "items_and_from_cart_are_mutually_exclusive"
+
+  Scenario: Place an order from the cart
+    Given my cart to order holds 2 of burger 1 configured as '[{"variant_id": 1, "option_ids": [1]}, {"variant_id": 2, "option_ids": [5]}]'
+    When I order my cart
+    Then the response status should be 201
+    And the order should be pending with 1 item
+    And the order total should be 22.00
+    And the order should be stored
+
+  Scenario: Reject an empty cart
+    Given my cart to order is empty
+    When I order my cart
+    Then the response status should be 422
+    And the user should receive an error message "cart_is_empty"
+
+  Scenario: An order keeps its snapshot after the product is purged
+    Given order 5 of customer 7 bought 1 "Classic Burger" whose product was purged
+    When customer 7 views order 5
+    Then the response status should be 200
+    And order item 1 should snapshot "Classic Burger" at 10.00 with options ""
+
+  Scenario: Customers cannot see the orders of others
+    Given order 5 of customer 7 bought 1 "Classic Burger" whose product was purged
+    When customer 8 views order 5
+    Then the response status should be 404
+    And the user should receive an error message "order_not_found"
+
+  Scenario: List the orders of a shop
+    Given the shop has 3 orders
+    When I list the orders of the shop with limit 2
+    Then the response status should be 200
+    And 2 orders should be listed with more to follow
+
+  Scenario: The order history requires a customer
+    When I list my orders without signing in
+    Then the response status should be 401
+    And the user should receive an error message "authentication_required"
+
+  Scenario: The shop confirms a pending order
+    Given order 5 of customer 7 is "pending" with 2 of burger 1
+    When the shop moves order 5 to "confirmed"
+    Then the response status should be 200
+    And the order should be "confirmed"
+    And the status change should be recorded
+
+  Scenario: Reject a transition outside the lifecycle
+    Given order 5 of customer 7 is "pending" with 2 of burger 1
+    When the shop moves order 5 to "delivered"
+    Then the response status should be 422
+    And the user should receive an error message "order_status_transition_not_allowed"
+
+  Scenario: Cancelled orders are final
+    Given order 5 of customer 7 is "cancelled" with 2 of burger 1
+    When the shop moves order 5 to "confirmed"
+    Then the response status should be 422
+    And the user should receive an error message "order_status_transition_not_allowed"
+
+  Scenario: Reject an unknown status
+    Given order 5 of customer 7 is "pending" with 2 of burger 1
+    When the shop moves order 5 to "shipped"
+    Then the response status should be 400
+    And the user should receive an error message "invalid_order_status"
+
+  Scenario: Cancelling an order returns its items to the stock
+    Given burger 1 can be ordered with stock 5
+    And option 2 has stock 0
+    And order 5 of customer 7 is "preparing" with 2 of burger 1
+    And the ordered burger came with option 2
+    When the shop moves order 5 to "cancelled"
+    Then the response status should be 200
+    And the order should be "cancelled"
+    And the cancellation should return burger 1 to stock 7
+    And the cancellation should return option 2 to stock 2
+    And the status change should be recorded
+
+  Scenario: Customers cannot cancel their own orders
+    Given order 5 of customer 7 is "pending" with 1 of burger 1
+    When customer 7 moves order 5 to "cancelled"
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
+
+  Scenario: Customers cannot move the orders of others
+    Given order 5 of customer 7 is "pending" with 1 of burger 1
+    When customer 8 moves order 5 to "refunded"
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
+
+  Scenario: Anonymous callers cannot move orders
+    Given order 5 of customer 7 is "pending" with 1 of burger 1
+    When an anonymous caller moves order 5 to "cancelled"
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
@@ -0,0 +1,47 @@
+Feature: Partial Product Update
+  As a shop owner
+  I want to change single product fields with a JSON Merge Patch
+  So that I do not have to re-send images and variants
+
+  Scenario: Successfully deactivate a product
+    Given a stored product with id 1
+    When I send a merge patch '{"is_active": false}' for product 1
+    Then the response status should be 200
+    And the patched product should not be active
+    And the stored images and variants should be left untouched
+
+  Scenario: Patch with a content type other than merge patch
+    Given a stored product with id 1
+    When I send a patch '{"is_active": false}' for product 1 with content type "application/json"
+    Then the response status should be 415
+    And the user should receive an error message "content_type_must_be_merge_patch_json"
+
+  Scenario: Patch that is not a JSON object
+    Given a stored product with id 1
+    When I send a merge patch '[1, 2]' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "invalid_merge_patch_document"
+
+  Scenario: Patch leaving the product invalid
+    Given a stored product with id 1
+    When I send a merge patch '{"price": null}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "product_price_must_be_positive"
+
+  Scenario: Patch changing a read-only field
+    Given a stored product with id 1
+    When I send a merge patch '{"id": 2}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "id_created_at_and_version_are_read_only"
+
+  Scenario: Patch referencing images that do not belong to the product
+    Given a stored product with id 1
+    When I send a merge patch '{"images": [{"id": 99, "url": "https://elsewhere.com/a.jpg"}]}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "patched_images_must_reference_existing_images"
+
+  Scenario: Patch a product that does not exist
+    Given no stored product with id 404
+    When I send a merge patch '{"is_active": false}' for product 404
+    Then the response status should be 404
+    And the user should receive an error message "product_not_found"
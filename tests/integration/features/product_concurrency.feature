@@ -0,0 +1,39 @@
+Feature: Product Optimistic Concurrency
+  As a shop owner sharing the admin with my staff
+  I want edits based on an outdated product to be rejected
+  So that nobody silently overwrites someone else's changes
+
+  Scenario: Product is returned with its version as ETag
+    Given a product with id 1 at version 3
+    When I get product 1
+    Then the response status should be 200
+    And the response should have ETag "\"3\""
+
+  Scenario: Patch with the current version
+    Given a product with id 1 at version 3
+    When I send a merge patch '{"is_highlighted": true}' for product 1 with If-Match "\"3\""
+    Then the response status should be 200
+    And the response should have ETag "\"4\""
+
+  Scenario: Patch with an outdated version
+    Given a product with id 1 at version 4
+    When I send a merge patch '{"is_highlighted": true}' for product 1 with If-Match "\"3\""
+    Then the response status should be 412
+    And the user should receive an error message "product_was_modified_by_another_request"
+
+  Scenario: Product changes between reading and writing
+    Given a product with id 1 at version 3 that is modified concurrently
+    When I send a merge patch '{"is_highlighted": true}' for product 1 with If-Match "\"3\""
+    Then the response status should be 412
+    And the user should receive an error message "product_was_modified_by_another_request"
+
+  Scenario: Patch with a weak entity tag
+    When I send a merge patch '{"is_highlighted": true}' for product 1 with If-Match "W/\"3\""
+    Then the response status should be 400
+    And the user should receive an error message "invalid_if_match_header"
+
+  Scenario: Strict mode requires If-Match
+    Given strict If-Match mode is enabled
+    When I send a merge patch '{"is_highlighted": true}' for product 1 without If-Match
+    Then the response status should be 428
+    And the user should receive an error message "if_match_header_required"
@@ -0,0 +1,46 @@
+Feature: Product Image Order
+  As a shop owner
+  I want to choose the order of my product photos and the one shown first
+  So that product cards show my best photo
+
+  Scenario: Order the images and choose the primary one
+    Given product 1 has the images "10,11,12"
+    When I order the images of product 1 as "12,10,11" with primary image 10
+    Then the response status should be 200
+    And the images should be ordered as "12,10,11" with primary image 10
+    And the product version should be 2
+
+  Scenario: The first image is primary when none is chosen
+    Given product 1 has the images "10,11,12"
+    When I order the images of product 1 as "11,12,10"
+    Then the response status should be 200
+    And the images should be ordered as "11,12,10" with primary image 11
+
+  Scenario: Reject an order missing an image
+    Given product 1 has the images "10,11,12"
+    When I order the images of product 1 as "12,10"
+    Then the response status should be 400
+    And the user should receive an error message "image_order_must_list_every_product_image"
+
+  Scenario: Reject an order repeating an image
+    Given product 1 has the images "10,11,12"
+    When I order the images of product 1 as "10,10,11"
+    Then the response status should be 400
+    And the user should receive an error message "image_order_cannot_repeat_images"
+
+  Scenario: Reject a primary image outside the order
+    Given product 1 has the images "10,11"
+    When I order the images of product 1 as "10,11" with primary image 12
+    Then the response status should be 400
+    And the user should receive an error message "primary_image_must_be_in_the_image_order"
+
+  Scenario: Order the images of a product that does not exist
+    When I order the images of product 99 as "10,11"
+    Then the response status should be 404
+    And the user should receive an error message "product_not_found"
+
+  Scenario: List products with their primary image
+    Given product 1 has the images "10,11,12"
+    When I list the products of shop 1
+    Then the response status should be 200
+    And the listed product should expose image 11 as its primary image
@@ -0,0 +1,73 @@
+Feature: Scheduled Product Promotions
+  As a shop owner
+  I want to schedule promotions with start and end windows
+  So that prices change on time without flipping them by hand
+
+  Scenario: Schedule a promotion for a product
+    Given a product with id 1 priced at 10.00 without promotions
+    When I schedule the promotion '{"price": 7.5, "starts_at": "2026-11-01T00:00:00", "ends_at": "2026-11-30T00:00:00"}' for product 1
+    Then the response status should be 201
+    And the scheduled promotion should have id 5
+    And the product version should be bumped
+
+  Scenario: Schedule a happy hour that does not overlap a weekend promotion
+    Given a product with id 1 priced at 10.00 with the promotion '{"id": 3, "price": 8, "starts_at": "2026-01-01T00:00:00", "recurrence": {"weekdays": [0, 6], "start_time": "12:00", "end_time": "15:00"}}'
+    When I schedule the promotion '{"price": 7.5, "starts_at": "2026-01-01T00:00:00", "recurrence": {"weekdays": [1, 2, 3, 4, 5], "start_time": "18:00", "end_time": "20:00"}}' for product 1
+    Then the response status should be 201
+
+  Scenario: Reject a promotion overlapping a scheduled one
+    Given a product with id 1 priced at 10.00 with the promotion '{"id": 3, "price": 8, "starts_at": "2026-11-01T00:00:00", "ends_at": "2026-11-30T00:00:00"}'
+    When I schedule the promotion '{"price": 7.5, "starts_at": "2026-11-15T00:00:00"}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "promotions_cannot_overlap"
+
+  Scenario: Reject a promotion overlapping one scheduled concurrently
+    Given a product with id 1 priced at 10.00 without promotions
+    And another request schedules the promotion '{"id": 3, "price": 8, "starts_at": "2026-11-01T00:00:00", "ends_at": "2026-11-30T00:00:00"}' first
+    When I schedule the promotion '{"price": 7.5, "starts_at": "2026-11-15T00:00:00"}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "promotions_cannot_overlap"
+
+  Scenario: Reject a promotion that is not cheaper than the regular price
+    Given a product with id 1 priced at 10.00 without promotions
+    When I schedule the promotion '{"price": 12, "starts_at": "2026-11-01T00:00:00"}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "promotional_price_must_be_lower_than_regular_price"
+
+  Scenario: Reject a recurring promotion whose window ends before it starts
+    Given a product with id 1 priced at 10.00 without promotions
+    When I schedule the promotion '{"price": 7.5, "starts_at": "2026-11-01T00:00:00", "recurrence": {"weekdays": [1], "start_time": "20:00", "end_time": "18:00"}}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "promotion_end_time_must_be_after_start_time"
+
+  Scenario: Reject a promotion with a malformed start
+    When I schedule the promotion '{"price": 7.5, "starts_at": "next monday"}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "invalid_date_time_format_must_be_yyyy_mm_ddthh_mm_ss"
+
+  Scenario: Product detail reports the active promotional price
+    Given a product with id 1 priced at 10.00 with the promotion '{"id": 3, "price": 7.5, "starts_at": "2020-01-01T00:00:00", "ends_at": "2099-01-01T00:00:00"}'
+    When I request product 1
+    Then the response status should be 200
+    And the product active price should be 7.5
+    And the product should report when the promotion ends
+
+  Scenario: Product detail reports the regular price after a promotion ended
+    Given a product with id 1 priced at 10.00 with the promotion '{"id": 3, "price": 7.5, "starts_at": "2020-01-01T00:00:00", "ends_at": "2020-02-01T00:00:00"}'
+    When I request product 1
+    Then the response status should be 200
+    And the product active price should be 10
+    And the product should not report a promotion end
+
+  Scenario: Delete a scheduled promotion
+    Given a scheduled promotion with id 3 for product 1
+    When I delete promotion 3 of product 1
+    Then the response status should be 200
+    And the user should receive a success message "promotion_deleted_successfully"
+    And the product version should be bumped
+
+  Scenario: Delete a promotion that does not exist
+    Given no scheduled promotion with id 99 for product 1
+    When I delete promotion 99 of product 1
+    Then the response status should be 404
+    And the user should receive an error message "promotion_not_found"
@@ -0,0 +1,55 @@
+Feature: Product Price Quote
+  As a customer
+  I want to price a product with the options I picked
+  So that I know what I will pay before ordering
+
+  Scenario: Quote a configured product
+    Given a configurable burger with id 1
+    When I request a quote '{"quantity": 2, "selections": [{"variant_id": 1, "option_ids": [2]}, {"variant_id": 2, "option_ids": [4, 5]}]}' for product 1
+    Then the response status should be 200
+    And the quote should have 4 lines
+    And the quote total should be 30.00
+
+  Scenario: Quote a product on promotion
+    Given a configurable burger with id 1 on promotion at 8.50
+    When I request a quote '{"selections": [{"variant_id": 1, "option_ids": [1]}]}' for product 1
+    Then the response status should be 200
+    And the quote should use the promotional price
+    And the quote total should be 8.50
+
+  Scenario: Reject a quote without a required variant
+    Given a configurable burger with id 1
+    When I request a quote '{"selections": [{"variant_id": 2, "option_ids": [4]}]}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "variant_selection_is_required"
+
+  Scenario: Reject two options for a single selection variant
+    Given a configurable burger with id 1
+    When I request a quote '{"selections": [{"variant_id": 1, "option_ids": [1, 2]}]}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "single_selection_allows_one_option"
+
+  Scenario: Reject more options than allowed
+    Given a configurable burger with id 1
+    When I request a quote '{"selections": [{"variant_id": 1, "option_ids": [1]}, {"variant_id": 2, "option_ids": [3, 4, 5]}]}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "max_selections_exceeded"
+
+  Scenario: Reject an out of stock option
+    Given a configurable burger with id 1
+    When I request a quote '{"selections": [{"variant_id": 1, "option_ids": [1]}, {"variant_id": 2, "option_ids": [3]}]}' for product 1
+    Then the response status should be 422
+    And the user should receive an error message "option_out_of_stock"
+
+  Scenario: Quote a custom variant with free selections
+    Given a configurable burger with id 1
+    When I request a quote '{"selections": [{"variant_id": 1, "option_ids": [1]}, {"variant_id": 3, "option_ids": [8, 6, 7]}]}' for product 1
+    Then the response status should be 200
+    And the quote should have 5 lines
+    And the quote total should be 12.00
+
+  Scenario: Reject mutually exclusive options of a custom variant
+    Given a configurable burger with id 1
+    When I request a quote '{"selections": [{"variant_id": 1, "option_ids": [1]}, {"variant_id": 3, "option_ids": [8, 9]}]}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "options_are_mutually_exclusive"
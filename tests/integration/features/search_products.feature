@@ -0,0 +1,32 @@
+Feature: Search Products
+  As a customer
+  I want to search a shop's menu by text
+  So that I can quickly find the products I am looking for
+
+  Scenario: Successfully search products by name
+    Given a shop with ID 1 has products matching "burger"
+    When I search products of shop 1 for "burger"
+    Then the response status should be 200
+    And the search results should be ranked by relevance
+    And the search results should contain highlighted names
+
+  Scenario: Search with no matches
+    Given a shop with ID 1 has no products matching "sushi"
+    When I search products of shop 1 for "sushi"
+    Then the response status should be 200
+    And the search results should be empty
+
+  Scenario: Search without query
+    When I search products of shop 1 for ""
+    Then the response status should be 400
+    And the user should receive an error message "search_query_is_required"
+
+  Scenario: Search with only punctuation
+    When I search products of shop 1 for "!!!"
+    Then the response status should be 400
+    And the user should receive an error message "search_query_is_required"
+
+  Scenario: Search with unsupported language
+    When I search products of shop 1 for "burger" in language "fr"
+    Then the response status should be 400
+    And the user should receive an error message "invalid_lang_must_be_es_or_en"
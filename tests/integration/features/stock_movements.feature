@@ -0,0 +1,88 @@
+Feature: Inventory Ledger
+  As a shop owner
+  I want every stock change recorded with its reason
+  So that I can tell why the stock of a product changed
+
+  Scenario: Record a restock
+    Given a product with id 1 and stock 10
+    When I record the stock movement '{"reason": "restock", "quantity": 5, "reference": "delivery-42"}' for product 1
+    Then the response status should be 201
+    And the stock movement should leave 15 units in stock
+    And the stock movement should be recorded by "user:1"
+
+  Scenario: The ledger ignores an actor sent by the client
+    Given a product with id 1 and stock 10
+    When I record the stock movement '{"reason": "restock", "quantity": 5, "actor": "warehouse"}' for product 1
+    Then the response status should be 201
+    And the stock movement should be recorded by "user:1"
+
+  Scenario: Anonymous callers cannot record stock movements
+    Given a product with id 1 and stock 10
+    And the request is sent anonymously
+    When I record the stock movement '{"reason": "restock", "quantity": 5}' for product 1
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
+
+  Scenario: Only the shop staff can record stock movements
+    Given a product with id 1 and stock 10
+    And the request is sent by user 8
+    When I record the stock movement '{"reason": "restock", "quantity": 5}' for product 1
+    Then the response status should be 403
+    And the user should receive an error message "forbidden"
+
+  Scenario: Record a sale
+    Given a product with id 1 and stock 10
+    When I record the stock movement '{"reason": "sale", "quantity": -3}' for product 1
+    Then the response status should be 201
+    And the stock movement should leave 7 units in stock
+
+  Scenario: Reject a sale larger than the stock
+    Given a product with id 1 and stock 2
+    When I record the stock movement '{"reason": "sale", "quantity": -3}' for product 1
+    Then the response status should be 422
+    And the user should receive an error message "insufficient_stock"
+
+  Scenario: Reject a restock with a negative quantity
+    When I record the stock movement '{"reason": "restock", "quantity": -5}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "quantity_sign_does_not_match_reason"
+
+  Scenario: Reject an unknown reason
+    When I record the stock movement '{"reason": "theft", "quantity": -1}' for product 1
+    Then the response status should be 400
+    And the user should receive an error message "invalid_reason_must_be_restock_sale_return_or_adjustment"
+
+  Scenario: Reject a movement for a missing product
+    When I record the stock movement '{"reason": "restock", "quantity": 5}' for product 99
+    Then the response status should be 404
+    And the user should receive an error message "product_not_found"
+
+  Scenario: Reject a movement for a product deleted after the staff check
+    Given no product with id 9 to adjust
+    When I record the stock movement '{"reason": "restock", "quantity": 5}' for product 9
+    Then the response status should be 404
+    And the user should receive an error message "product_not_found"
+
+  Scenario: List the stock movements of a product
+    Given product 1 has 3 stock movements
+    When I request the stock movements of product 1 with limit 3
+    Then the response status should be 200
+    And I should receive 3 stock movements
+    And the stock movements should have a next cursor
+
+  Scenario: Cap the page size of the stock movements
+    Given product 1 has 3 stock movements
+    When I request the stock movements of product 1 with limit 500
+    Then the response status should be 200
+    And at most 100 stock movements should be requested
+
+  Scenario: Reject the stock movements of a missing product
+    Given no product with id 99 to list
+    When I request the stock movements of product 99 with limit 3
+    Then the response status should be 404
+    And the user should receive an error message "product_not_found"
+
+  Scenario: Reject a malformed stock movements cursor
+    When I request the stock movements of product 1 with cursor "abc"
+    Then the response status should be 400
+    And the user should receive an error message "invalid_cursor_format"
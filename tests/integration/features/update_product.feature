@@ -57,12 +57,14 @@ Feature: Product Update
     When I send an update product request
     Then the response status should be 400
     And the user should receive an error message "image_size_too_large_max_3mb"
+    And the field "images[0]" should report "image_size_too_large_max_3mb"
 
   Scenario: Update product with invalid new image type
     Given I have a product with id 1 and invalid new image type
     When I send an update product request
     Then the response status should be 400
-    And the user should receive an error message "invalid_image_type_only_jpeg_png_allowed"
+    And the user should receive an error message "unsupported_image_format"
+    And the field "images[0]" should report "unsupported_image_format"
 
   Scenario: Update product with invalid product_id format
     Given I have an invalid product_id "abc"
@@ -112,3 +114,9 @@ Feature: Product Update
     When I send an update product request
     Then the response status should be 400
     And the user should receive an error message "promotional_price_must_be_lower_than_regular_price"
+
+  Scenario: Update product with a price not above a scheduled promotion
+    Given I have a product with id 1 priced not above a scheduled promotion
+    When I send an update product request
+    Then the response status should be 400
+    And the user should receive an error message "promotional_price_must_be_lower_than_regular_price"
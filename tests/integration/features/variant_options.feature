@@ -0,0 +1,20 @@
+Feature: Variant Option Availability
+  As a customer
+  I want to see which extras and sizes ran out
+  So that I only pick options the shop can deliver
+
+  Scenario: Flag options without stock left
+    Given product 1 has the option "Bacon" with stock 0
+    And product 1 has the option "Cheese" with stock 4
+    And product 1 has the option "Ketchup" without stock tracking
+    When I view product 1 in the storefront
+    Then the response status should be 200
+    And the option "Bacon" should be flagged as sold out
+    And the option "Cheese" should not be flagged as sold out
+    And the option "Ketchup" should not be flagged as sold out
+
+  Scenario: Flag options switched off by the shop
+    Given product 1 has the option "XL" switched off
+    When I view product 1 in the storefront
+    Then the response status should be 200
+    And the option "XL" should be flagged as sold out
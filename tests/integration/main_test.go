@@ -48,6 +48,20 @@ func InitializeScenario(sc *godog.ScenarioContext) {
 	productSteps := steps.NewProductSteps()
 	getProductsByShopIDSteps := steps.NewGetProductsByShopIDSteps()
 	updateProductSteps := steps.NewUpdateProductSteps()
+	searchProductsSteps := steps.NewSearchProductsSteps()
+	deleteProductSteps := steps.NewDeleteProductSteps()
+	patchProductSteps := steps.NewPatchProductSteps()
+	productConcurrencySteps := steps.NewProductConcurrencySteps()
+	productPromotionsSteps := steps.NewProductPromotionsSteps()
+	stockMovementsSteps := steps.NewStockMovementsSteps()
+	variantOptionsSteps := steps.NewVariantOptionsSteps()
+	productQuoteSteps := steps.NewProductQuoteSteps()
+	assetStorageSteps := steps.NewAssetStorageSteps()
+	uploadSteps := steps.NewUploadSteps()
+	assetGCSteps := steps.NewAssetGCSteps()
+	productImageOrderSteps := steps.NewProductImageOrderSteps()
+	cartSteps := steps.NewCartSteps()
+	orderSteps := steps.NewOrderSteps()
 	commonSteps := steps.NewCommonSteps()
 
 	// Register steps
@@ -56,6 +70,20 @@ func InitializeScenario(sc *godog.ScenarioContext) {
 	productSteps.RegisterSteps(sc)
 	getProductsByShopIDSteps.RegisterSteps(sc)
 	updateProductSteps.RegisterSteps(sc)
+	searchProductsSteps.RegisterSteps(sc)
+	deleteProductSteps.RegisterSteps(sc)
+	patchProductSteps.RegisterSteps(sc)
+	productConcurrencySteps.RegisterSteps(sc)
+	productPromotionsSteps.RegisterSteps(sc)
+	stockMovementsSteps.RegisterSteps(sc)
+	variantOptionsSteps.RegisterSteps(sc)
+	productQuoteSteps.RegisterSteps(sc)
+	assetStorageSteps.RegisterSteps(sc)
+	uploadSteps.RegisterSteps(sc)
+	assetGCSteps.RegisterSteps(sc)
+	productImageOrderSteps.RegisterSteps(sc)
+	cartSteps.RegisterSteps(sc)
+	orderSteps.RegisterSteps(sc)
 	commonSteps.RegisterSteps(sc)
 
 	// Setup hooks
@@ -0,0 +1,222 @@
+package steps
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+// Assets stored before the scenarios collect them
+var (
+	referencedAssetKey = strings.Repeat("a", 64) + ".jpg"
+	renditionAssetKey  = strings.Repeat("b", 64) + ".jpg"
+	orphanedAssetKey   = strings.Repeat("c", 64) + ".jpg"
+	recentAssetKey     = strings.Repeat("d", 64) + ".jpg"
+)
+
+type AssetGCSteps struct {
+	report *models.AssetGCReport
+}
+
+func NewAssetGCSteps() *AssetGCSteps {
+	return &AssetGCSteps{}
+}
+
+// storeAsset stores an asset as if it had been stored at modifiedAt
+func (a *AssetGCSteps) storeAsset(ctx *TestContext, key string, modifiedAt time.Time) error {
+	if ctx.s3 != nil {
+		ctx.s3.putObject(fakeS3Prefix+"/"+key, []byte(key), "image/jpeg", modifiedAt)
+		return nil
+	}
+
+	// The local storage shards assets by the first two characters of their key
+	file := filepath.Join(ctx.assetsDir, key[:2], key)
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, []byte(key), 0o644); err != nil {
+		return err
+	}
+	return os.Chtimes(file, modifiedAt, modifiedAt)
+}
+
+// assetStored reports whether the asset is still in the storage
+func (a *AssetGCSteps) assetStored(ctx *TestContext, key string) bool {
+	if ctx.s3 != nil {
+		return ctx.s3.object(fakeS3Prefix+"/"+key) != nil
+	}
+	_, err := os.Stat(filepath.Join(ctx.assetsDir, key[:2], key))
+	return err == nil
+}
+
+// ===== Given Steps =====
+
+func (a *AssetGCSteps) theStorageHoldsReferencedOrphanedAndRecentImages() error {
+	ctx := GetTestContext()
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	for _, key := range []string{referencedAssetKey, renditionAssetKey, orphanedAssetKey} {
+		if err := a.storeAsset(ctx, key, old); err != nil {
+			return err
+		}
+	}
+	if err := a.storeAsset(ctx, recentAssetKey, time.Now()); err != nil {
+		return err
+	}
+
+	// An old direct upload waiting to be finalized is not an asset yet
+	if ctx.s3 != nil {
+		ctx.s3.putObject(fakeS3Prefix+"/uploads/"+testUploadID, []byte("staging"), "image/png", old)
+	}
+	return nil
+}
+
+// ===== When Steps =====
+
+func (a *AssetGCSteps) iCollectTheOrphanedAssets() error {
+	return a.collect("grace_hours=24")
+}
+
+func (a *AssetGCSteps) iCollectTheOrphanedAssetsInADryRun() error {
+	return a.collect("grace_hours=24&dry_run=true")
+}
+
+func (a *AssetGCSteps) iCollectTheOrphanedAssetsWithAGracePeriodOfHours(hours int) error {
+	return a.collect(fmt.Sprintf("grace_hours=%d", hours))
+}
+
+func (a *AssetGCSteps) collect(query string) error {
+	ctx := GetTestContext()
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	// The rendition is referenced through a CDN, as images stored with ASSETS_BASE_URL are
+	if ctx.caller(platformAdminID) == platformAdminID {
+		ctx.mockSQLMock.ExpectQuery("SELECT url FROM product_images").
+			WillReturnRows(sqlmock.NewRows([]string{"url"}).
+				AddRow("/assets/" + referencedAssetKey).
+				AddRow("https://cdn.example.com/" + renditionAssetKey))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, ctx.server.URL+"/admin/assets/gc?"+query, nil)
+	if err != nil {
+		return err
+	}
+	if err := ctx.authorize(req, platformAdminID); err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage, _ = errorResponse["error"].(string)
+		}
+		return nil
+	}
+
+	a.report = &models.AssetGCReport{}
+	return json.NewDecoder(resp.Body).Decode(a.report)
+}
+
+// ===== Then Steps =====
+
+func (a *AssetGCSteps) onlyTheOrphanedImageShouldBeReported() error {
+	if a.report == nil {
+		return fmt.Errorf("expected a garbage collection report")
+	}
+	if a.report.Scanned != 4 || a.report.Referenced != 2 {
+		return fmt.Errorf("expected 4 scanned and 2 referenced assets, got %d and %d", a.report.Scanned, a.report.Referenced)
+	}
+
+	orphaned := make([]string, 0, len(a.report.Orphaned))
+	for _, asset := range a.report.Orphaned {
+		orphaned = append(orphaned, asset.Key)
+	}
+	if !slices.Equal(orphaned, []string{orphanedAssetKey}) {
+		return fmt.Errorf("expected only %s to be orphaned, got %v", orphanedAssetKey, orphaned)
+	}
+	return nil
+}
+
+func (a *AssetGCSteps) theOrphanedImageShouldBeDeleted() error {
+	ctx := GetTestContext()
+	if a.report.Deleted != 1 || a.report.FreedBytes != int64(len(orphanedAssetKey)) {
+		return fmt.Errorf("expected 1 deleted asset of %d bytes, got %d of %d", len(orphanedAssetKey), a.report.Deleted, a.report.FreedBytes)
+	}
+	if a.assetStored(ctx, orphanedAssetKey) {
+		return fmt.Errorf("expected %s to be deleted", orphanedAssetKey)
+	}
+	return nil
+}
+
+func (a *AssetGCSteps) noImageShouldBeDeleted() error {
+	if a.report.Deleted != 0 || !a.report.DryRun {
+		return fmt.Errorf("expected a dry run deleting nothing, got %+v", a.report)
+	}
+	return a.theOtherImagesShouldBeKept(orphanedAssetKey)
+}
+
+func (a *AssetGCSteps) everyImageShouldBeKept() error {
+	return a.theOtherImagesShouldBeKept(orphanedAssetKey)
+}
+
+func (a *AssetGCSteps) theReferencedAndRecentImagesShouldBeKept() error {
+	return a.theOtherImagesShouldBeKept()
+}
+
+func (a *AssetGCSteps) theOtherImagesShouldBeKept(keys ...string) error {
+	ctx := GetTestContext()
+	for _, key := range append(keys, referencedAssetKey, renditionAssetKey, recentAssetKey) {
+		if !a.assetStored(ctx, key) {
+			return fmt.Errorf("expected %s to be kept", key)
+		}
+	}
+	if ctx.s3 != nil && ctx.s3.object(fakeS3Prefix+"/uploads/"+testUploadID) == nil {
+		return fmt.Errorf("expected the staging object to be kept")
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (a *AssetGCSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^the storage holds referenced, orphaned and recently stored images$`, a.theStorageHoldsReferencedOrphanedAndRecentImages)
+
+	// When steps
+	sc.Step(`^I collect the orphaned assets$`, a.iCollectTheOrphanedAssets)
+	sc.Step(`^I collect the orphaned assets in a dry run$`, a.iCollectTheOrphanedAssetsInADryRun)
+	sc.Step(`^I collect the orphaned assets with a grace period of (-?\d+) hours$`, a.iCollectTheOrphanedAssetsWithAGracePeriodOfHours)
+
+	// Then steps
+	sc.Step(`^only the orphaned image should be reported$`, a.onlyTheOrphanedImageShouldBeReported)
+	sc.Step(`^the orphaned image should be deleted$`, a.theOrphanedImageShouldBeDeleted)
+	sc.Step(`^no image should be deleted$`, a.noImageShouldBeDeleted)
+	sc.Step(`^the referenced and recently stored images should be kept$`, a.theReferencedAndRecentImagesShouldBeKept)
+	sc.Step(`^every image should be kept$`, a.everyImageShouldBeKept)
+}
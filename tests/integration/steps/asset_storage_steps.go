@@ -0,0 +1,78 @@
+package steps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+type AssetStorageSteps struct{}
+
+func NewAssetStorageSteps() *AssetStorageSteps {
+	return &AssetStorageSteps{}
+}
+
+// ===== Given Steps =====
+
+func (a *AssetStorageSteps) theAssetStorageIsAnS3CompatibleBucket() error {
+	ctx := GetTestContext()
+	ctx.s3 = newFakeS3Server()
+	return nil
+}
+
+func (a *AssetStorageSteps) theAssetStorageIsAnS3CompatibleBucketWithPartsOfBytes(partSize int) error {
+	ctx := GetTestContext()
+	ctx.s3 = newFakeS3Server()
+	ctx.s3PartSize = partSize
+	return nil
+}
+
+// ===== Then Steps =====
+
+func (a *AssetStorageSteps) theProductImagesShouldBeStoredInTheBucket() error {
+	ctx := GetTestContext()
+
+	// Each image is stored once per rendition, plus its original
+	expected := len(ctx.productImages) * (len(testImageRenditions) + 1)
+	keys := ctx.s3.objectKeys()
+	if len(keys) != expected {
+		return fmt.Errorf("expected %d objects in the bucket, got %v", expected, keys)
+	}
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, fakeS3Prefix+"/") {
+			return fmt.Errorf("expected object %s under the %s prefix", key, fakeS3Prefix)
+		}
+
+		object := ctx.s3.object(key)
+		if object.contentType != "image/jpeg" {
+			return fmt.Errorf("expected object %s with content type image/jpeg, got %q", key, object.contentType)
+		}
+		if !strings.Contains(object.cacheControl, "immutable") {
+			return fmt.Errorf("expected object %s to be cacheable forever, got %q", key, object.cacheControl)
+		}
+	}
+
+	return nil
+}
+
+func (a *AssetStorageSteps) theBucketShouldHaveReceivedAMultipartUpload() error {
+	ctx := GetTestContext()
+	if ctx.s3.completedMultipartUploads() == 0 {
+		return fmt.Errorf("expected a completed multipart upload")
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (a *AssetStorageSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^the asset storage is an S3 compatible bucket$`, a.theAssetStorageIsAnS3CompatibleBucket)
+	sc.Step(`^the asset storage is an S3 compatible bucket with parts of (\d+) bytes$`, a.theAssetStorageIsAnS3CompatibleBucketWithPartsOfBytes)
+
+	// Then steps
+	sc.Step(`^the product images should be stored in the bucket$`, a.theProductImagesShouldBeStoredInTheBucket)
+	sc.Step(`^the bucket should have received a multipart upload$`, a.theBucketShouldHaveReceivedAMultipartUpload)
+}
@@ -0,0 +1,471 @@
+package steps
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	authhttp "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+)
+
+// existingCartToken is the token of the anonymous cart opened by a previous request
+const existingCartToken = "0123456789abcdef0123456789abcdef"
+
+var cartTokenFormat = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// cartProduct is a configurable burger sold by the shop
+type cartProduct struct {
+	stock            int
+	promotionalPrice float64
+	removed          bool
+	otherShop        bool
+}
+
+type CartSteps struct {
+	products map[int]*cartProduct
+	cart     *models.Cart // stored cart, nil until one is opened
+	response *models.Cart
+	purged   int
+}
+
+func NewCartSteps() *CartSteps {
+	return &CartSteps{products: map[int]*cartProduct{}}
+}
+
+func (s *CartSteps) product(productID int) *cartProduct {
+	product, ok := s.products[productID]
+	if !ok {
+		product = &cartProduct{stock: 10}
+		s.products[productID] = product
+	}
+	return product
+}
+
+// expectProduct mocks the read of a product, missing when it was removed
+func (s *CartSteps) expectProduct(productID int) {
+	ctx := GetTestContext()
+	product := s.product(productID)
+
+	rows := sqlmock.NewRows(productImageColumns)
+	if !product.removed {
+		rows.AddRow(productID, "Classic Burger", "Beef burger", 10.0, product.stock, 0, true, false,
+			product.promotionalPrice > 0, product.promotionalPrice, time.Now(), 1,
+			1, "Burgers", "", "[]", quotedProductVariants, "[]", models.DefaultTimeZone)
+	}
+	ctx.mockSQLMock.ExpectQuery(`FROM products p(.+)WHERE p.id = \$1`).WillReturnRows(rows)
+}
+
+// expectCartRead mocks the read of the stored cart with its lines
+func (s *CartSteps) expectCartRead() {
+	mock := GetTestContext().mockSQLMock
+	if s.cart == nil {
+		mock.ExpectQuery("FROM carts").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		return
+	}
+
+	mock.ExpectQuery("FROM carts").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "shop_id", "customer_id", "token", "expires_at", "created_at", "updated_at"}).
+			AddRow(s.cart.ID, s.cart.ShopID, 0, s.cart.Token, time.Now().Add(time.Hour), time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+
+	rows := sqlmock.NewRows([]string{"id", "product_id", "selections", "quantity"})
+	for _, line := range s.cart.Lines {
+		selections, _ := json.Marshal(line.Selections)
+		rows.AddRow(line.ID, line.ProductID, selections, line.Quantity)
+	}
+	mock.ExpectQuery("FROM cart_lines").WillReturnRows(rows)
+}
+
+// expectTouchAndPricing mocks the activity update and the read of every product in the cart
+func (s *CartSteps) expectTouchAndPricing() {
+	GetTestContext().mockSQLMock.ExpectExec("UPDATE carts SET updated_at").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.expectPricing()
+}
+
+func (s *CartSteps) expectPricing() {
+	priced := map[int]bool{}
+	for _, line := range s.cart.Lines {
+		if !priced[line.ProductID] {
+			s.expectProduct(line.ProductID)
+			priced[line.ProductID] = true
+		}
+	}
+}
+
+// canBuy mirrors the stock check of the quote, the only rule the scenarios break
+func (s *CartSteps) canBuy(productID, quantity int) bool {
+	product := s.product(productID)
+	return !product.removed && quantity <= product.stock
+}
+
+func parseSelections(selections string) ([]models.VariantSelection, error) {
+	var parsed []models.VariantSelection
+	if err := json.Unmarshal([]byte(selections), &parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// ===== Given Steps =====
+
+func (s *CartSteps) theShopSellsBurgerWithStock(productID, stock int) error {
+	s.product(productID).stock = stock
+	return nil
+}
+
+func (s *CartSteps) burgerIsNowOnPromotionAt(productID int, price float64) error {
+	s.product(productID).promotionalPrice = price
+	return nil
+}
+
+func (s *CartSteps) burgerWasRemovedFromTheShop(productID int) error {
+	s.product(productID).removed = true
+	return nil
+}
+
+func (s *CartSteps) burgerBelongsToAnotherShop(productID int) error {
+	s.product(productID).otherShop = true
+	return nil
+}
+
+func (s *CartSteps) myCartHoldsOfBurgerConfiguredAs(quantity, productID int, selections string) error {
+	parsed, err := parseSelections(selections)
+	if err != nil {
+		return err
+	}
+
+	if s.cart == nil {
+		s.cart = &models.Cart{ID: 1, ShopID: 1, Token: existingCartToken}
+	}
+	s.cart.Lines = append(s.cart.Lines, &models.CartLine{
+		ID:         len(s.cart.Lines) + 1,
+		ProductID:  productID,
+		Selections: parsed,
+		Quantity:   quantity,
+	})
+	return nil
+}
+
+// ===== When Steps =====
+
+func (s *CartSteps) iAddOfBurgerConfiguredAsToTheCart(quantity, productID int, selections string) error {
+	parsed, err := parseSelections(selections)
+	if err != nil {
+		return err
+	}
+	if err := s.setup(); err != nil {
+		return err
+	}
+	mock := GetTestContext().mockSQLMock
+
+	var existing *models.CartLine
+	if s.cart != nil {
+		s.expectCartRead()
+		existing = s.cart.FindMatchingLine(productID, parsed)
+	}
+
+	total := quantity
+	if existing != nil {
+		total += existing.Quantity
+	}
+	s.expectProduct(productID)
+	if !s.canBuy(productID, total) {
+		return s.send(http.MethodPost, "/lines", contracts.CartLineAddRequest{ProductID: productID, Quantity: &quantity, Selections: parsed})
+	}
+
+	if s.cart == nil {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM carts").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("INSERT INTO carts").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+		s.cart = &models.Cart{ID: 1, ShopID: 1}
+	}
+
+	switch {
+	case existing != nil:
+		mock.ExpectExec("UPDATE cart_lines").WillReturnResult(sqlmock.NewResult(0, 1))
+		existing.Quantity = total
+	case s.product(productID).otherShop:
+		mock.ExpectQuery("INSERT INTO cart_lines").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		return s.send(http.MethodPost, "/lines", contracts.CartLineAddRequest{ProductID: productID, Quantity: &quantity, Selections: parsed})
+	default:
+		lineID := len(s.cart.Lines) + 1
+		mock.ExpectQuery("INSERT INTO cart_lines").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(lineID))
+		s.cart.Lines = append(s.cart.Lines, &models.CartLine{ID: lineID, ProductID: productID, Selections: parsed, Quantity: quantity})
+	}
+	s.expectTouchAndPricing()
+
+	return s.send(http.MethodPost, "/lines", contracts.CartLineAddRequest{ProductID: productID, Quantity: &quantity, Selections: parsed})
+}
+
+func (s *CartSteps) iViewTheCart() error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	s.expectCartRead()
+	if s.cart != nil {
+		s.expectPricing()
+	}
+	return s.send(http.MethodGet, "", nil)
+}
+
+func (s *CartSteps) iViewACartIDidNotOpen() error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+	return s.send(http.MethodGet, "", nil)
+}
+
+func (s *CartSteps) iViewTheCartWithTheToken(token string) error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+	s.cart = &models.Cart{ID: 1, ShopID: 1, Token: token}
+	return s.send(http.MethodGet, "", nil)
+}
+
+func (s *CartSteps) iChangeCartLineToQuantity(lineID, quantity int) error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	s.expectCartRead()
+	line, err := s.cart.FindLine(lineID)
+	if err != nil {
+		return s.send(http.MethodPatch, fmt.Sprintf("/lines/%d", lineID), contracts.CartLineUpdateRequest{Quantity: &quantity})
+	}
+	s.expectProduct(line.ProductID)
+	if s.canBuy(line.ProductID, quantity) {
+		GetTestContext().mockSQLMock.ExpectExec("UPDATE cart_lines").WillReturnResult(sqlmock.NewResult(0, 1))
+		line.Quantity = quantity
+		s.expectTouchAndPricing()
+	}
+
+	return s.send(http.MethodPatch, fmt.Sprintf("/lines/%d", lineID), contracts.CartLineUpdateRequest{Quantity: &quantity})
+}
+
+func (s *CartSteps) iRemoveCartLine(lineID int) error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	s.expectCartRead()
+	if _, err := s.cart.FindLine(lineID); err == nil {
+		GetTestContext().mockSQLMock.ExpectExec("DELETE FROM cart_lines").WillReturnResult(sqlmock.NewResult(0, 1))
+		var kept []*models.CartLine
+		for _, line := range s.cart.Lines {
+			if line.ID != lineID {
+				kept = append(kept, line)
+			}
+		}
+		s.cart.Lines = kept
+		s.expectTouchAndPricing()
+	}
+
+	return s.send(http.MethodDelete, fmt.Sprintf("/lines/%d", lineID), nil)
+}
+
+func (s *CartSteps) iClearTheCart() error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	s.expectCartRead()
+	GetTestContext().mockSQLMock.ExpectExec("DELETE FROM cart_lines").WillReturnResult(sqlmock.NewResult(0, int64(len(s.cart.Lines))))
+	s.cart.Lines = nil
+	s.expectTouchAndPricing()
+
+	return s.send(http.MethodDelete, "", nil)
+}
+
+func (s *CartSteps) iPurgeTheExpiredCartsWhenCartsHadExpired(expired int) error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+	ctx := GetTestContext()
+	if ctx.caller(platformAdminID) == platformAdminID {
+		ctx.mockSQLMock.ExpectExec("DELETE FROM carts WHERE expires_at").WillReturnResult(sqlmock.NewResult(0, int64(expired)))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, ctx.server.URL+"/admin/carts/purge", nil)
+	if err != nil {
+		return err
+	}
+	if err := ctx.authorize(req, platformAdminID); err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse["error"]
+		}
+		return nil
+	}
+
+	var response contracts.CartPurgeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return err
+	}
+	s.purged = response.Purged
+	return nil
+}
+
+func (s *CartSteps) setup() error {
+	ctx := GetTestContext()
+	if ctx.app == nil {
+		return ctx.SetupProductTestApp()
+	}
+	return nil
+}
+
+// send calls the cart endpoint of shop 1 as the owner of the stored cart
+func (s *CartSteps) send(method, path string, body interface{}) error {
+	ctx := GetTestContext()
+
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, ctx.server.URL+"/shops/1/cart"+path, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.cart != nil && s.cart.Token != "" {
+		req.Header.Set(authhttp.CartTokenHeader, s.cart.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse["error"]
+		}
+		return nil
+	}
+
+	s.response = &models.Cart{}
+	if err := json.NewDecoder(resp.Body).Decode(s.response); err != nil {
+		return err
+	}
+	if s.cart != nil && s.cart.Token == "" {
+		s.cart.Token = resp.Header.Get(authhttp.CartTokenHeader)
+	}
+	return nil
+}
+
+// ===== Then Steps =====
+
+func (s *CartSteps) theCartShouldHaveLines(expected int) error {
+	if s.response == nil || len(s.response.Lines) != expected {
+		return fmt.Errorf("expected %d cart lines, got %+v", expected, s.response)
+	}
+	return nil
+}
+
+func (s *CartSteps) theCartSubtotalShouldBe(expected string) error {
+	if s.response == nil || s.response.Subtotal.String() != expected {
+		return fmt.Errorf("expected cart subtotal %s, got %+v", expected, s.response)
+	}
+	return nil
+}
+
+func (s *CartSteps) cartLineShouldHaveQuantity(lineID, quantity int) error {
+	line, err := s.response.FindLine(lineID)
+	if err != nil {
+		return err
+	}
+	if line.Quantity != quantity || !line.Available {
+		return fmt.Errorf("expected line %d with quantity %d, got %+v", lineID, quantity, line)
+	}
+	return nil
+}
+
+func (s *CartSteps) cartLineShouldBeUnavailableBecause(lineID int, issue string) error {
+	line, err := s.response.FindLine(lineID)
+	if err != nil {
+		return err
+	}
+	if line.Available || line.Issue != issue {
+		return fmt.Errorf("expected line %d to be unavailable because %s, got %+v", lineID, issue, line)
+	}
+	return nil
+}
+
+func (s *CartSteps) theResponseShouldCarryTheCartToken() error {
+	ctx := GetTestContext()
+	token := ctx.response.Header.Get(authhttp.CartTokenHeader)
+	if !cartTokenFormat.MatchString(token) || s.response.Token != token {
+		return fmt.Errorf("expected a cart token in the header and the body, got %q and %q", token, s.response.Token)
+	}
+	return nil
+}
+
+func (s *CartSteps) theCartChangesShouldBeStored() error {
+	return GetTestContext().mockSQLMock.ExpectationsWereMet()
+}
+
+func (s *CartSteps) cartsShouldBePurged(expected int) error {
+	if s.purged != expected {
+		return fmt.Errorf("expected %d purged carts, got %d", expected, s.purged)
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (s *CartSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^the shop sells burger (\d+) with stock (\d+)$`, s.theShopSellsBurgerWithStock)
+	sc.Step(`^burger (\d+) is now on promotion at (\d+(?:\.\d+)?)$`, s.burgerIsNowOnPromotionAt)
+	sc.Step(`^burger (\d+) was removed from the shop$`, s.burgerWasRemovedFromTheShop)
+	sc.Step(`^burger (\d+) belongs to another shop$`, s.burgerBelongsToAnotherShop)
+	sc.Step(`^my cart holds (\d+) of burger (\d+) configured as '([^']*)'$`, s.myCartHoldsOfBurgerConfiguredAs)
+
+	// When steps
+	sc.Step(`^I add (\d+) of burger (\d+) configured as '([^']*)' to the cart$`, s.iAddOfBurgerConfiguredAsToTheCart)
+	sc.Step(`^I view the cart$`, s.iViewTheCart)
+	sc.Step(`^I view a cart I did not open$`, s.iViewACartIDidNotOpen)
+	sc.Step(`^I view the cart with the token "([^"]*)"$`, s.iViewTheCartWithTheToken)
+	sc.Step(`^I change cart line (\d+) to quantity (\d+)$`, s.iChangeCartLineToQuantity)
+	sc.Step(`^I remove cart line (\d+)$`, s.iRemoveCartLine)
+	sc.Step(`^I clear the cart$`, s.iClearTheCart)
+	sc.Step(`^I purge the expired carts when (\d+) carts had expired$`, s.iPurgeTheExpiredCartsWhenCartsHadExpired)
+
+	// Then steps
+	sc.Step(`^the cart should have (\d+) lines?$`, s.theCartShouldHaveLines)
+	sc.Step(`^the cart subtotal should be (\d+\.\d+)$`, s.theCartSubtotalShouldBe)
+	sc.Step(`^cart line (\d+) should have quantity (\d+)$`, s.cartLineShouldHaveQuantity)
+	sc.Step(`^cart line (\d+) should be unavailable because "([^"]*)"$`, s.cartLineShouldBeUnavailableBecause)
+	sc.Step(`^the response should carry the cart token$`, s.theResponseShouldCarryTheCartToken)
+	sc.Step(`^the cart changes should be stored$`, s.theCartChangesShouldBeStored)
+	sc.Step(`^(\d+) carts should be purged$`, s.cartsShouldBePurged)
+}
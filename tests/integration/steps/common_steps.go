@@ -49,9 +49,25 @@ func (c *CommonSteps) iShouldReceiveASuccessMessage(expectedMessage string) erro
 	return nil
 }
 
+func (c *CommonSteps) theRequestIsSentAnonymously() error {
+	ctx := GetTestContext()
+	ctx.callerSet = true
+	ctx.callerID = 0
+	return nil
+}
+
+func (c *CommonSteps) theRequestIsSentByUser(userID int) error {
+	ctx := GetTestContext()
+	ctx.callerSet = true
+	ctx.callerID = userID
+	return nil
+}
+
 // RegisterSteps registers all common step definitions
 func (c *CommonSteps) RegisterSteps(sc *godog.ScenarioContext) {
 	sc.Step(`^the response status should be (\d+)$`, c.theResponseStatusShouldBe)
 	sc.Step(`^the user should receive an error message "([^"]*)"$`, c.iShouldReceiveAnErrorMessage)
 	sc.Step(`^the user should receive a success message "([^"]*)"$`, c.iShouldReceiveASuccessMessage)
+	sc.Step(`^the request is sent anonymously$`, c.theRequestIsSentAnonymously)
+	sc.Step(`^the request is sent by user (\d+)$`, c.theRequestIsSentByUser)
 }
@@ -0,0 +1,189 @@
+package steps
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+)
+
+type DeleteProductSteps struct {
+	affectedRows int64
+}
+
+func NewDeleteProductSteps() *DeleteProductSteps {
+	return &DeleteProductSteps{}
+}
+
+// ===== Given Steps =====
+
+func (d *DeleteProductSteps) aLiveProductWithID(_ int) error {
+	d.affectedRows = 1
+	return nil
+}
+
+func (d *DeleteProductSteps) noLiveProductWithID(_ int) error {
+	d.affectedRows = 0
+	return nil
+}
+
+func (d *DeleteProductSteps) aDeletedProductWithID(_ int) error {
+	d.affectedRows = 1
+	return nil
+}
+
+func (d *DeleteProductSteps) noDeletedProductWithID(_ int) error {
+	d.affectedRows = 0
+	return nil
+}
+
+func (d *DeleteProductSteps) productsWereDeletedBeforeTheRetentionWindow(count int) error {
+	d.affectedRows = int64(count)
+	return nil
+}
+
+// ===== When Steps =====
+
+func (d *DeleteProductSteps) iSendADeleteProductRequestFor(productID string) error {
+	ctx := GetTestContext()
+	if err := d.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	if d.expectProductStaff(ctx, productID) {
+		ctx.mockSQLMock.ExpectExec(`UPDATE products SET deleted_at = now\(\)`).
+			WillReturnResult(sqlmock.NewResult(0, d.affectedRows))
+	}
+
+	return d.sendRequest(ctx, http.MethodDelete, "/products/"+productID, shopOwnerID)
+}
+
+func (d *DeleteProductSteps) iSendADeleteProductRequestForID(productID int) error {
+	return d.iSendADeleteProductRequestFor(fmt.Sprintf("%d", productID))
+}
+
+func (d *DeleteProductSteps) iSendARestoreProductRequestFor(productID int) error {
+	ctx := GetTestContext()
+	if err := d.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	if d.expectProductStaff(ctx, strconv.Itoa(productID)) {
+		ctx.mockSQLMock.ExpectExec("UPDATE products SET deleted_at = NULL").
+			WillReturnResult(sqlmock.NewResult(0, d.affectedRows))
+	}
+
+	return d.sendRequest(ctx, http.MethodPost, fmt.Sprintf("/products/%d/restore", productID), shopOwnerID)
+}
+
+func (d *DeleteProductSteps) iSendAPurgeDeletedProductsRequestWithRetention(days int) error {
+	ctx := GetTestContext()
+	if err := d.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	if ctx.caller(platformAdminID) == platformAdminID && days > 0 {
+		ctx.mockSQLMock.ExpectExec("DELETE FROM products").
+			WillReturnResult(sqlmock.NewResult(0, d.affectedRows))
+	}
+
+	return d.sendRequest(ctx, http.MethodPost, fmt.Sprintf("/admin/products/purge?retention_days=%d", days), platformAdminID)
+}
+
+// expectProductStaff mocks the staff check of the product and reports whether the caller passes it
+func (d *DeleteProductSteps) expectProductStaff(ctx *TestContext, productID string) bool {
+	id, err := strconv.Atoi(productID)
+	if err != nil || ctx.caller(shopOwnerID) == 0 {
+		return false
+	}
+	ctx.expectProductStaff(id)
+	return id <= 90 && ctx.caller(shopOwnerID) == shopOwnerID
+}
+
+func (d *DeleteProductSteps) setupTestApp(ctx *TestContext) error {
+	if ctx.app == nil {
+		return ctx.SetupProductTestApp()
+	}
+	return nil
+}
+
+func (d *DeleteProductSteps) sendRequest(ctx *TestContext, method, path string, defaultUserID int) error {
+	req, err := http.NewRequest(method, ctx.server.URL+path, nil)
+	if err != nil {
+		return err
+	}
+	if err := ctx.authorize(req, defaultUserID); err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse["error"]
+		}
+		return nil
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil
+	}
+
+	if message, ok := body["message"].(string); ok {
+		ctx.successMessage = message
+		return nil
+	}
+
+	if purged, ok := body["purged"].(float64); ok {
+		ctx.responseBody = contracts.ProductPurgeResponse{Purged: int(purged)}
+	}
+
+	return nil
+}
+
+// ===== Then Steps =====
+
+func (d *DeleteProductSteps) theResponseShouldReportPurgedProducts(expected int) error {
+	ctx := GetTestContext()
+	purgeResponse, ok := ctx.responseBody.(contracts.ProductPurgeResponse)
+	if !ok {
+		return fmt.Errorf("expected ProductPurgeResponse, got: %T", ctx.responseBody)
+	}
+	if purgeResponse.Purged != expected {
+		return fmt.Errorf("expected %d purged products, got %d", expected, purgeResponse.Purged)
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (d *DeleteProductSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^a live product with id (\d+)$`, d.aLiveProductWithID)
+	sc.Step(`^no live product with id (\d+)$`, d.noLiveProductWithID)
+	sc.Step(`^a deleted product with id (\d+)$`, d.aDeletedProductWithID)
+	sc.Step(`^no deleted product with id (\d+)$`, d.noDeletedProductWithID)
+	sc.Step(`^(\d+) products were deleted before the retention window$`, d.productsWereDeletedBeforeTheRetentionWindow)
+
+	// When steps
+	sc.Step(`^I send a delete product request for product (\d+)$`, d.iSendADeleteProductRequestForID)
+	sc.Step(`^I send a delete product request for product "([^"]*)"$`, d.iSendADeleteProductRequestFor)
+	sc.Step(`^I send a restore product request for product (\d+)$`, d.iSendARestoreProductRequestFor)
+	sc.Step(`^I send a purge deleted products request with retention of (-?\d+) days$`, d.iSendAPurgeDeletedProductsRequestWithRetention)
+
+	// Then steps
+	sc.Step(`^the response should report (\d+) purged products$`, d.theResponseShouldReportPurgedProducts)
+}
@@ -0,0 +1,306 @@
+package steps
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fakeS3AccessKeyID     = "test-access-key"
+	fakeS3SecretAccessKey = "test-secret-key"
+	fakeS3Bucket          = "product-images"
+	fakeS3Prefix          = "images"
+	// fakeS3ListPageSize is small so listings are paginated
+	fakeS3ListPageSize = 2
+)
+
+// fakeS3Object is an object stored by the fake S3 server
+type fakeS3Object struct {
+	body         []byte
+	contentType  string
+	cacheControl string
+	modifiedAt   time.Time
+}
+
+// fakeS3Upload is a multipart upload in progress
+type fakeS3Upload struct {
+	key          string
+	contentType  string
+	cacheControl string
+	parts        map[int][]byte
+}
+
+// fakeS3Server is an in-memory S3-compatible storage speaking the path-style API
+// It checks that requests are signed and that payload hashes match their bodies,
+// unless the payload is unsigned; presigned requests are checked for their credential, expiry and signed headers
+type fakeS3Server struct {
+	server *httptest.Server
+
+	mu               sync.Mutex
+	objects          map[string]*fakeS3Object
+	uploads          map[string]*fakeS3Upload
+	completedUploads int
+	nextUploadID     int
+}
+
+func newFakeS3Server() *fakeS3Server {
+	fake := &fakeS3Server{
+		objects: make(map[string]*fakeS3Object),
+		uploads: make(map[string]*fakeS3Upload),
+	}
+	fake.server = httptest.NewServer(http.HandlerFunc(fake.handle))
+	return fake
+}
+
+func (f *fakeS3Server) URL() string {
+	return f.server.URL
+}
+
+func (f *fakeS3Server) Close() {
+	f.server.Close()
+}
+
+func (f *fakeS3Server) objectKeys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *fakeS3Server) object(key string) *fakeS3Object {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.objects[key]
+}
+
+// putObject stores an object as if it had been put at modifiedAt
+func (f *fakeS3Server) putObject(key string, body []byte, contentType string, modifiedAt time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[key] = &fakeS3Object{body: body, contentType: contentType, modifiedAt: modifiedAt}
+}
+
+func (f *fakeS3Server) completedMultipartUploads() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.completedUploads
+}
+
+func (f *fakeS3Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Query().Has("X-Amz-Signature") {
+		if code, status := f.checkPresigned(r, body); code != "" {
+			f.writeError(w, status, code)
+			return
+		}
+	} else {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="+fakeS3AccessKeyID+"/") {
+			f.writeError(w, http.StatusForbidden, "AccessDenied")
+			return
+		}
+		if code, status := f.checkPayload(r, body); code != "" {
+			f.writeError(w, status, code)
+			return
+		}
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != fakeS3Bucket {
+		f.writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		f.nextUploadID++
+		uploadID := fmt.Sprintf("upload-%d", f.nextUploadID)
+		f.uploads[uploadID] = &fakeS3Upload{
+			key:          key,
+			contentType:  r.Header.Get("Content-Type"),
+			cacheControl: r.Header.Get("Cache-Control"),
+			parts:        make(map[int][]byte),
+		}
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>", bucket, key, uploadID)
+
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		upload, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		upload.parts[partNumber] = body
+		etag := md5.Sum(body)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(etag[:])+`"`)
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		upload, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		var completion struct {
+			Parts []struct {
+				PartNumber int `xml:"PartNumber"`
+			} `xml:"Part"`
+		}
+		if err := xml.Unmarshal(body, &completion); err != nil || len(completion.Parts) == 0 {
+			f.writeError(w, http.StatusBadRequest, "MalformedXML")
+			return
+		}
+		var content []byte
+		for _, part := range completion.Parts {
+			content = append(content, upload.parts[part.PartNumber]...)
+		}
+		f.objects[upload.key] = &fakeS3Object{body: content, contentType: upload.contentType, cacheControl: upload.cacheControl, modifiedAt: time.Now()}
+		delete(f.uploads, query.Get("uploadId"))
+		f.completedUploads++
+		fmt.Fprintf(w, "<CompleteMultipartUploadResult><Key>%s</Key></CompleteMultipartUploadResult>", upload.key)
+
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(f.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		source, ok := f.objects[strings.TrimPrefix(r.Header.Get("X-Amz-Copy-Source"), "/"+fakeS3Bucket+"/")]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		copied := &fakeS3Object{body: source.body, contentType: source.contentType, cacheControl: source.cacheControl, modifiedAt: time.Now()}
+		if r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+			copied.contentType = r.Header.Get("Content-Type")
+			copied.cacheControl = r.Header.Get("Cache-Control")
+		}
+		f.objects[key] = copied
+		fmt.Fprint(w, "<CopyObjectResult></CopyObjectResult>")
+
+	case r.Method == http.MethodPut:
+		f.objects[key] = &fakeS3Object{body: body, contentType: r.Header.Get("Content-Type"), cacheControl: r.Header.Get("Cache-Control"), modifiedAt: time.Now()}
+
+	case r.Method == http.MethodGet && key == "" && query.Get("list-type") == "2":
+		f.list(w, query.Get("prefix"), query.Get("continuation-token"))
+
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		object, ok := f.objects[key]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Header().Set("Cache-Control", object.cacheControl)
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.body)))
+		if r.Method == http.MethodGet {
+			w.Write(object.body)
+		}
+
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		f.writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// list writes the page of the ListObjectsV2 answer after the continuation token
+// The token is the last key of the previous page, as keys are listed in order
+func (f *fakeS3Server) list(w http.ResponseWriter, prefix, continuationToken string) {
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) && key > continuationToken {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	truncated := len(keys) > fakeS3ListPageSize
+	if truncated {
+		keys = keys[:fakeS3ListPageSize]
+	}
+
+	fmt.Fprintf(w, "<ListBucketResult><IsTruncated>%t</IsTruncated>", truncated)
+	if truncated {
+		fmt.Fprintf(w, "<NextContinuationToken>%s</NextContinuationToken>", keys[len(keys)-1])
+	}
+	for _, key := range keys {
+		object := f.objects[key]
+		fmt.Fprintf(w, "<Contents><Key>%s</Key><LastModified>%s</LastModified><Size>%d</Size></Contents>",
+			key, object.modifiedAt.UTC().Format(time.RFC3339), len(object.body))
+	}
+	fmt.Fprint(w, "</ListBucketResult>")
+}
+
+// checkPayload checks the payload hash of a signed request against its body
+// Unsigned payloads are accepted when the body has the announced Content-Length
+func (f *fakeS3Server) checkPayload(r *http.Request, body []byte) (string, int) {
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "UNSIGNED-PAYLOAD" {
+		if r.ContentLength != int64(len(body)) {
+			return "IncompleteBody", http.StatusBadRequest
+		}
+		return "", 0
+	}
+
+	sum := sha256.Sum256(body)
+	if payloadHash != hex.EncodeToString(sum[:]) {
+		return "XAmzContentSHA256Mismatch", http.StatusBadRequest
+	}
+	return "", 0
+}
+
+// checkPresigned checks the credential and expiry of a query string signature and
+// that the signed Content-Length matches the body; it returns the S3 error code if any
+func (f *fakeS3Server) checkPresigned(r *http.Request, body []byte) (string, int) {
+	query := r.URL.Query()
+	if !strings.HasPrefix(query.Get("X-Amz-Credential"), fakeS3AccessKeyID+"/") {
+		return "AccessDenied", http.StatusForbidden
+	}
+
+	signedAt, err := time.Parse("20060102T150405Z", query.Get("X-Amz-Date"))
+	expires, expiresErr := strconv.Atoi(query.Get("X-Amz-Expires"))
+	if err != nil || expiresErr != nil || time.Now().After(signedAt.Add(time.Duration(expires)*time.Second)) {
+		return "AccessDenied", http.StatusForbidden
+	}
+
+	signedHeaders := strings.Split(query.Get("X-Amz-SignedHeaders"), ";")
+	if !slices.Contains(signedHeaders, "content-length") || !slices.Contains(signedHeaders, "content-type") {
+		return "AccessDenied", http.StatusForbidden
+	}
+	if r.ContentLength != int64(len(body)) {
+		return "IncompleteBody", http.StatusBadRequest
+	}
+	return "", 0
+}
+
+func (f *fakeS3Server) writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code></Error>", code)
+}
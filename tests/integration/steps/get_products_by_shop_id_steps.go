@@ -1,13 +1,16 @@
 package steps
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/cucumber/godog"
 
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
 )
 
@@ -17,7 +20,30 @@ const (
 	scenarioShopWithProductsCursor = "shop-with-products-cursor"
 )
 
-type GetProductsByShopIDSteps struct{}
+// productListColumns match the Scan of the product listing in product_repository.go
+var productListColumns = []string{
+	"id", "name", "description", "price", "stock", "minimum_stock",
+	"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+	"category_id", "category_name", "category_description",
+	"images", "variants", "promotions", "time_zone",
+}
+
+type GetProductsByShopIDSteps struct {
+	listedActive *bool
+}
+
+// capturedActive records the is_active filter the listing query was sent with
+type capturedActive struct {
+	into **bool
+}
+
+func (c capturedActive) Match(value driver.Value) bool {
+	active, ok := value.(bool)
+	if ok {
+		*c.into = &active
+	}
+	return ok
+}
 
 func NewGetProductsByShopIDSteps() *GetProductsByShopIDSteps {
 	return &GetProductsByShopIDSteps{}
@@ -61,6 +87,75 @@ func (g *GetProductsByShopIDSteps) iSendAGetProductsRequestForShopWithCursor(sho
 	return g.sendGetProductsRequest(shopID, 0, cursor)
 }
 
+func (g *GetProductsByShopIDSteps) iSendAGetProductsRequestForShopWithQuery(shopID int, rawQuery string) error {
+	ctx := GetTestContext()
+
+	// Setup test app if not already done
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	// Only scenarios with products reach the database
+	g.setupGetProductsSQLExpectations()
+
+	resp, err := http.Get(ctx.server.URL + fmt.Sprintf("/shops/%d/products?%s", shopID, rawQuery))
+	if err != nil {
+		return err
+	}
+
+	ctx.response = resp
+	g.parseResponse(ctx, resp)
+
+	return nil
+}
+
+func (g *GetProductsByShopIDSteps) anAnonymousCallerListsTheInactiveProductsOfShop(shopID int) error {
+	return g.listInactiveProducts(shopID, 0)
+}
+
+func (g *GetProductsByShopIDSteps) theShopOwnerListsTheInactiveProductsOfShop(shopID int) error {
+	return g.listInactiveProducts(shopID, shopOwnerID)
+}
+
+// listInactiveProducts asks for is_active=false, signed in as the given user (0 for anonymous)
+func (g *GetProductsByShopIDSteps) listInactiveProducts(shopID, userID int) error {
+	ctx := GetTestContext()
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	if userID > 0 {
+		ctx.expectShopStaff(shopID, userID)
+	}
+	ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM products").
+		WithArgs(shopID, capturedActive{into: &g.listedActive}, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(productListColumns))
+
+	req, err := http.NewRequest(http.MethodGet, ctx.server.URL+fmt.Sprintf("/shops/%d/products?is_active=false", shopID), nil)
+	if err != nil {
+		return err
+	}
+	if userID > 0 {
+		if err := signIn(req, userID); err != nil {
+			return err
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	ctx.response = resp
+	g.parseResponse(ctx, resp)
+
+	return nil
+}
+
 func (g *GetProductsByShopIDSteps) sendGetProductsRequest(shopID, limit, cursor int) error {
 	ctx := GetTestContext()
 
@@ -102,12 +197,22 @@ func (g *GetProductsByShopIDSteps) buildRequestURL(baseURL string, shopID, limit
 		if hasParams {
 			separator = "&"
 		}
-		url += fmt.Sprintf("%scursor=%d", separator, cursor)
+		url += fmt.Sprintf("%scursor=%s", separator, g.buildCursorToken(cursor))
 	}
 
 	return url
 }
 
+// buildCursorToken encodes positive IDs as opaque cursors (default sort)
+// and passes invalid values through as-is to exercise validation
+func (g *GetProductsByShopIDSteps) buildCursorToken(cursor int) string {
+	if cursor <= 0 {
+		return fmt.Sprintf("%d", cursor)
+	}
+	token := &models.ProductCursor{SortDirection: models.SortDescending, ID: cursor}
+	return token.Encode()
+}
+
 func (g *GetProductsByShopIDSteps) parseResponse(ctx *TestContext, resp *http.Response) {
 	if resp.Body == nil {
 		return
@@ -136,15 +241,10 @@ func (g *GetProductsByShopIDSteps) setupGetProductsSQLExpectations() {
 	case scenarioShopWithProducts:
 		// Mock products query returning sample data (first page)
 		// Columns match the Scan in product_repository.go:356-372
-		rows := sqlmock.NewRows([]string{
-			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
-			"category_id", "category_name", "category_description",
-			"images", "variants",
-		}).
-			AddRow(15, "Product 15", "Description 15", 99.99, 10, 5, true, false, false, 0.0, 1, "Category 1", "", "[]", "[]").
-			AddRow(14, "Product 14", "Description 14", 199.99, 20, 10, true, false, false, 0.0, 1, "Category 1", "", "[]", "[]").
-			AddRow(13, "Product 13", "Description 13", 299.99, 30, 15, true, false, false, 0.0, 1, "Category 1", "", "[]", "[]")
+		rows := sqlmock.NewRows(productListColumns).
+			AddRow(15, "Product 15", "Description 15", 99.99, 10, 5, true, false, false, 0.0, time.Now(), 1, 1, "Category 1", "", "[]", "[]", "[]", "").
+			AddRow(14, "Product 14", "Description 14", 199.99, 20, 10, true, false, false, 0.0, time.Now(), 1, 1, "Category 1", "", "[]", "[]", "[]", "").
+			AddRow(13, "Product 13", "Description 13", 299.99, 30, 15, true, false, false, 0.0, time.Now(), 1, 1, "Category 1", "", "[]", "[]", "[]", "")
 
 		ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM products").
 			WillReturnRows(rows)
@@ -152,27 +252,17 @@ func (g *GetProductsByShopIDSteps) setupGetProductsSQLExpectations() {
 	case scenarioShopWithProductsCursor:
 		// Mock products query with cursor (returns products with ID < cursor=10)
 		// Ordered DESC, so 9, 8, 7...
-		rows := sqlmock.NewRows([]string{
-			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
-			"category_id", "category_name", "category_description",
-			"images", "variants",
-		}).
-			AddRow(9, "Product 9", "Description 9", 99.99, 10, 5, true, false, false, 0.0, 1, "Category 1", "", "[]", "[]").
-			AddRow(8, "Product 8", "Description 8", 199.99, 20, 10, true, false, false, 0.0, 1, "Category 1", "", "[]", "[]").
-			AddRow(7, "Product 7", "Description 7", 299.99, 30, 15, true, false, false, 0.0, 1, "Category 1", "", "[]", "[]")
+		rows := sqlmock.NewRows(productListColumns).
+			AddRow(9, "Product 9", "Description 9", 99.99, 10, 5, true, false, false, 0.0, time.Now(), 1, 1, "Category 1", "", "[]", "[]", "[]", "").
+			AddRow(8, "Product 8", "Description 8", 199.99, 20, 10, true, false, false, 0.0, time.Now(), 1, 1, "Category 1", "", "[]", "[]", "[]", "").
+			AddRow(7, "Product 7", "Description 7", 299.99, 30, 15, true, false, false, 0.0, time.Now(), 1, 1, "Category 1", "", "[]", "[]", "[]", "")
 
 		ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM products").
 			WillReturnRows(rows)
 
 	case scenarioShopWithoutProducts:
 		// Mock empty result
-		emptyRows := sqlmock.NewRows([]string{
-			"id", "name", "description", "price", "stock", "minimum_stock",
-			"is_active", "is_highlighted", "is_promotional", "promotional_price",
-			"category_id", "category_name", "category_description",
-			"images", "variants",
-		})
+		emptyRows := sqlmock.NewRows(productListColumns)
 
 		ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM products").
 			WillReturnRows(emptyRows)
@@ -181,6 +271,20 @@ func (g *GetProductsByShopIDSteps) setupGetProductsSQLExpectations() {
 
 // ===== Then Steps =====
 
+func (g *GetProductsByShopIDSteps) theListingShouldOnlyShowActiveProducts() error {
+	if g.listedActive == nil || !*g.listedActive {
+		return fmt.Errorf("expected the listing to be limited to active products, got is_active %v", g.listedActive)
+	}
+	return nil
+}
+
+func (g *GetProductsByShopIDSteps) theListingShouldShowInactiveProducts() error {
+	if g.listedActive == nil || *g.listedActive {
+		return fmt.Errorf("expected the listing of inactive products, got is_active %v", g.listedActive)
+	}
+	return nil
+}
+
 func (g *GetProductsByShopIDSteps) theResponseShouldContainAListOfProducts() error {
 	ctx := GetTestContext()
 	paginatedResponse, ok := ctx.responseBody.(contracts.PaginatedProductsResponse)
@@ -268,9 +372,14 @@ func (g *GetProductsByShopIDSteps) RegisterSteps(sc *godog.ScenarioContext) {
 	sc.Step(`^I send a get products request for shop (\d+)$`, g.iSendAGetProductsRequestForShop)
 	sc.Step(`^I send a get products request for shop (\d+) with limit (-?\d+)$`, g.iSendAGetProductsRequestForShopWithLimit)
 	sc.Step(`^I send a get products request for shop (\d+) with cursor (-?\d+)$`, g.iSendAGetProductsRequestForShopWithCursor)
+	sc.Step(`^I send a get products request for shop (\d+) with query "([^"]*)"$`, g.iSendAGetProductsRequestForShopWithQuery)
+	sc.Step(`^an anonymous caller lists the inactive products of shop (\d+)$`, g.anAnonymousCallerListsTheInactiveProductsOfShop)
+	sc.Step(`^the shop owner lists the inactive products of shop (\d+)$`, g.theShopOwnerListsTheInactiveProductsOfShop)
 
 	// Then steps
 	sc.Step(`^the response should contain a list of products$`, g.theResponseShouldContainAListOfProducts)
+	sc.Step(`^the listing should only show active products$`, g.theListingShouldOnlyShowActiveProducts)
+	sc.Step(`^the listing should show inactive products$`, g.theListingShouldShowInactiveProducts)
 	sc.Step(`^the response should contain pagination metadata$`, g.theResponseShouldContainPaginationMetadata)
 	sc.Step(`^the response should contain at most (\d+) products$`, g.theResponseShouldContainAtMostNProducts)
 	sc.Step(`^the response should contain products after cursor (\d+)$`, g.theResponseShouldContainProductsAfterCursor)
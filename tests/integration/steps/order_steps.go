@@ -0,0 +1,736 @@
+package steps
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+	"github.com/lib/pq"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/errors"
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	authhttp "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+)
+
+var orderColumns = []string{"id", "shop_id", "customer_id", "status", "subtotal", "total", "item_count", "notes", "idempotency_key", "request_fingerprint", "created_at"}
+
+var orderItemColumns = []string{"id", "order_id", "product_id", "product_name", "selections", "options", "quantity", "base_price", "unit_price", "total"}
+
+// capturedStock records the stock a checkout writes for a product or option
+type capturedStock struct {
+	into map[int]int
+	id   int
+}
+
+func (c capturedStock) Match(value driver.Value) bool {
+	stock, ok := value.(int64)
+	if ok {
+		c.into[c.id] = int(stock)
+	}
+	return ok
+}
+
+type OrderSteps struct {
+	stock       map[int]int
+	lockedStock map[int]int // stock left when the checkout locks the product, if it changed after the quote
+	optionStock map[int]int // options that track their stock
+	otherShop   map[int]bool
+	cart        *models.Cart
+	order       *models.Order
+	placed      *models.Order // order already placed with the idempotency key
+	keyRace     bool          // a concurrent retry stores the order first
+	response    *models.Order
+	orders      *contracts.OrdersResponse
+	fields      []errors.FieldError
+	written     map[int]int
+	writtenOpts map[int]int
+}
+
+func NewOrderSteps() *OrderSteps {
+	return &OrderSteps{
+		stock:       map[int]int{},
+		lockedStock: map[int]int{},
+		optionStock: map[int]int{},
+		otherShop:   map[int]bool{},
+		written:     map[int]int{},
+		writtenOpts: map[int]int{},
+	}
+}
+
+func (s *OrderSteps) stockOf(productID int) int {
+	if stock, ok := s.stock[productID]; ok {
+		return stock
+	}
+	return 10
+}
+
+// expectProduct mocks the read of a configurable burger
+func (s *OrderSteps) expectProduct(productID int) {
+	GetTestContext().mockSQLMock.ExpectQuery(`FROM products p(.+)WHERE p.id = \$1`).
+		WillReturnRows(sqlmock.NewRows(productImageColumns).
+			AddRow(productID, "Classic Burger", "Beef burger", 10.0, s.stockOf(productID), 0, true, false,
+				false, 0.0, time.Now(), 1,
+				1, "Burgers", "", "[]", quotedProductVariants, "[]", models.DefaultTimeZone))
+}
+
+// expectCheckout mocks the transaction that stores the order, locks and takes the stock,
+// reporting whether the order is placed
+// It mirrors the reservation of the domain, the locked stock being the only rule the scenarios break
+func (s *OrderSteps) expectCheckout(lines []models.OrderLine) bool {
+	mock := GetTestContext().mockSQLMock
+	mock.ExpectBegin()
+	if s.keyRace {
+		mock.ExpectQuery("INSERT INTO orders").WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_orders_shop_id_idempotency_key"})
+		mock.ExpectRollback()
+		return false
+	}
+	mock.ExpectQuery("INSERT INTO orders").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	products, options := map[int]int{}, map[int]int{}
+	productRows := sqlmock.NewRows([]string{"id", "stock"})
+	optionRows := sqlmock.NewRows([]string{"id", "stock"})
+	productIDs, optionIDs := []int{}, []int{}
+	for _, line := range lines {
+		productIDs = append(productIDs, line.ProductID)
+		for _, selection := range line.Selections {
+			optionIDs = append(optionIDs, selection.OptionIDs...)
+		}
+	}
+	slices.Sort(productIDs)
+	slices.Sort(optionIDs)
+	productIDs, optionIDs = slices.Compact(productIDs), slices.Compact(optionIDs)
+	for _, productID := range productIDs {
+		if s.otherShop[productID] {
+			continue
+		}
+		products[productID] = s.stockOf(productID)
+		if stock, changed := s.lockedStock[productID]; changed {
+			products[productID] = stock
+		}
+		productRows.AddRow(productID, products[productID])
+	}
+	for _, optionID := range optionIDs {
+		if stock, tracked := s.optionStock[optionID]; tracked {
+			options[optionID] = stock
+			optionRows.AddRow(optionID, stock)
+		}
+	}
+
+	mock.ExpectQuery(`FROM products\s+WHERE id = ANY`).WillReturnRows(productRows)
+	if len(products) != len(productIDs) {
+		mock.ExpectRollback()
+		return false
+	}
+	if len(optionIDs) > 0 {
+		mock.ExpectQuery("FROM variant_options").WillReturnRows(optionRows)
+	}
+
+	for _, line := range lines {
+		if products[line.ProductID] < line.Quantity {
+			mock.ExpectRollback()
+			return false
+		}
+		products[line.ProductID] -= line.Quantity
+		for _, selection := range line.Selections {
+			for _, optionID := range selection.OptionIDs {
+				if _, tracked := options[optionID]; !tracked {
+					continue
+				}
+				if options[optionID] < line.Quantity {
+					mock.ExpectRollback()
+					return false
+				}
+				options[optionID] -= line.Quantity
+			}
+		}
+	}
+
+	for i := range lines {
+		mock.ExpectQuery("INSERT INTO order_items").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+	}
+	for _, productID := range productIDs {
+		mock.ExpectExec("UPDATE products").
+			WithArgs(productID, capturedStock{into: s.written, id: productID}).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("INSERT INTO stock_movements").
+			WithArgs(productID, "sale", sqlmock.AnyArg(), sqlmock.AnyArg(), "", "order:1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+	}
+	for _, optionID := range optionIDs {
+		if _, tracked := options[optionID]; tracked {
+			mock.ExpectExec("UPDATE variant_options").
+				WithArgs(optionID, capturedStock{into: s.writtenOpts, id: optionID}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+	}
+	mock.ExpectCommit()
+	return true
+}
+
+// expectTransition mocks the transaction that moves the stored order to the next status
+// It mirrors the transition table of the domain and returns the units of cancelled orders
+func (s *OrderSteps) expectTransition(next models.OrderStatus, actor string) {
+	mock := GetTestContext().mockSQLMock
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status\s+FROM orders`).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(string(s.order.Status)))
+	if !s.order.Status.CanTransitionTo(next) {
+		mock.ExpectRollback()
+		return
+	}
+
+	reference := fmt.Sprintf("order:%d", s.order.ID)
+	if next == models.OrderCancelled {
+		item := s.order.Items[0]
+		mock.ExpectQuery(`FROM products\s+WHERE id = ANY`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(item.ProductID, s.stockOf(item.ProductID)))
+		optionRows := sqlmock.NewRows([]string{"id", "stock"})
+		for _, option := range item.Options {
+			optionRows.AddRow(option.OptionID, s.optionStock[option.OptionID])
+		}
+		if len(item.Options) > 0 {
+			mock.ExpectQuery("FROM variant_options").WillReturnRows(optionRows)
+		}
+
+		mock.ExpectExec("UPDATE products").
+			WithArgs(item.ProductID, capturedStock{into: s.written, id: item.ProductID}).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("INSERT INTO stock_movements").
+			WithArgs(item.ProductID, "return", item.Quantity, sqlmock.AnyArg(), actor, reference).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+		for _, option := range item.Options {
+			mock.ExpectExec("UPDATE variant_options").
+				WithArgs(option.OptionID, capturedStock{into: s.writtenOpts, id: option.OptionID}).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+	}
+
+	mock.ExpectExec("UPDATE orders").
+		WithArgs(s.order.ID, string(next), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO order_status_history").
+		WithArgs(s.order.ID, string(s.order.Status), string(next), actor, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+}
+
+// expectLowStockChecks mocks the low-stock alert check of every ordered product
+func (s *OrderSteps) expectLowStockChecks(lines []models.OrderLine) {
+	productIDs := []int{}
+	for _, line := range lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+	slices.Sort(productIDs)
+	for _, productID := range slices.Compact(productIDs) {
+		GetTestContext().expectLowStockCheck(productID, nil)
+	}
+}
+
+// expectPlacedOrder mocks the lookup of the order placed with the idempotency key
+func (s *OrderSteps) expectPlacedOrder() {
+	if s.placed == nil {
+		GetTestContext().mockSQLMock.ExpectQuery("FROM orders").WillReturnRows(sqlmock.NewRows(orderColumns))
+		return
+	}
+	s.expectOrderRows(s.placed)
+}
+
+// expectOrderRows mocks the read of orders and their items
+func (s *OrderSteps) expectOrderRows(orders ...*models.Order) {
+	mock := GetTestContext().mockSQLMock
+
+	rows := sqlmock.NewRows(orderColumns)
+	items := sqlmock.NewRows(orderItemColumns)
+	for _, order := range orders {
+		rows.AddRow(order.ID, order.ShopID, order.CustomerID, string(order.Status), order.Total.String(), order.Total.String(), order.ItemCount, "",
+			order.IdempotencyKey, order.RequestFingerprint, order.CreatedAt)
+		for _, item := range order.Items {
+			options, _ := json.Marshal(item.Options)
+			items.AddRow(item.ID, order.ID, item.ProductID, item.ProductName, "[]", options, item.Quantity,
+				item.BasePrice.String(), item.UnitPrice.String(), item.Total.String())
+		}
+	}
+	mock.ExpectQuery("FROM orders").WillReturnRows(rows)
+	if len(orders) > 0 {
+		mock.ExpectQuery("FROM order_items").WillReturnRows(items)
+	}
+}
+
+func storedOrder(orderID, customerID int) *models.Order {
+	price := models.MoneyFromFloat(10)
+	return &models.Order{
+		ID:         orderID,
+		ShopID:     1,
+		CustomerID: customerID,
+		Status:     models.OrderPending,
+		Total:      price,
+		ItemCount:  1,
+		CreatedAt:  time.Now(),
+		Items: []*models.OrderItem{{
+			ID: 1, ProductName: "Classic Burger", Quantity: 1, BasePrice: price, UnitPrice: price, Total: price,
+		}},
+	}
+}
+
+// ===== Given Steps =====
+
+func (s *OrderSteps) burgerCanBeOrderedWithStock(productID, stock int) error {
+	s.stock[productID] = stock
+	return nil
+}
+
+func (s *OrderSteps) onlyOfBurgerIsLeftWhenTheOrderIsPlaced(stock, productID int) error {
+	s.lockedStock[productID] = stock
+	return nil
+}
+
+func (s *OrderSteps) optionHasStock(optionID, stock int) error {
+	s.optionStock[optionID] = stock
+	return nil
+}
+
+func (s *OrderSteps) customerPlacedAnOrderWithTheIdempotencyKeyFor(customerID int, key string, quantity, productID int, selections string) error {
+	parsed, err := parseSelections(selections)
+	if err != nil {
+		return err
+	}
+	draft := models.OrderDraft{CustomerID: customerID, Lines: []models.OrderLine{{ProductID: productID, Selections: parsed, Quantity: quantity}}}
+
+	s.placed = storedOrder(1, customerID)
+	s.placed.IdempotencyKey = key
+	s.placed.RequestFingerprint = draft.Fingerprint()
+	return nil
+}
+
+func (s *OrderSteps) aConcurrentRetryPlacesTheOrderFirst() error {
+	s.keyRace = true
+	return nil
+}
+
+func (s *OrderSteps) burgerIsSoldByAnotherShop(productID int) error {
+	s.otherShop[productID] = true
+	return nil
+}
+
+func (s *OrderSteps) myCartToOrderHoldsOfBurgerConfiguredAs(quantity, productID int, selections string) error {
+	parsed, err := parseSelections(selections)
+	if err != nil {
+		return err
+	}
+	s.cart = &models.Cart{ID: 1, ShopID: 1, Token: existingCartToken, Lines: []*models.CartLine{
+		{ID: 1, ProductID: productID, Selections: parsed, Quantity: quantity},
+	}}
+	return nil
+}
+
+func (s *OrderSteps) myCartToOrderIsEmpty() error {
+	s.cart = &models.Cart{ID: 1, ShopID: 1, Token: existingCartToken}
+	return nil
+}
+
+func (s *OrderSteps) orderOfCustomerBoughtWhoseProductWasPurged(orderID, customerID, quantity int, name string) error {
+	s.order = storedOrder(orderID, customerID)
+	s.order.Items[0].Quantity = quantity
+	s.order.Items[0].ProductName = name
+	return nil
+}
+
+func (s *OrderSteps) orderOfCustomerIsWithOfBurger(orderID, customerID int, status string, quantity, productID int) error {
+	s.order = storedOrder(orderID, customerID)
+	s.order.Status = models.OrderStatus(status)
+	s.order.Items[0].ProductID = productID
+	s.order.Items[0].Quantity = quantity
+	return nil
+}
+
+func (s *OrderSteps) theOrderedBurgerCameWithOption(optionID int) error {
+	s.order.Items[0].Options = append(s.order.Items[0].Options, models.OrderItemOption{VariantID: 1, OptionID: optionID, Name: "Size: Double"})
+	return nil
+}
+
+// ===== When Steps =====
+
+func (s *OrderSteps) customerOrdersOfBurgerConfiguredAs(customerID, quantity, productID int, selections string) error {
+	return s.placeOrder(customerID, quantity, productID, selections, "")
+}
+
+func (s *OrderSteps) customerOrdersOfBurgerConfiguredAsWithTheIdempotencyKey(customerID, quantity, productID int, selections, key string) error {
+	return s.placeOrder(customerID, quantity, productID, selections, key)
+}
+
+// placeOrder sends a direct order of a single line, expecting the checkout when the quote passes
+func (s *OrderSteps) placeOrder(customerID, quantity, productID int, selections, key string) error {
+	parsed, err := parseSelections(selections)
+	if err != nil {
+		return err
+	}
+	if err := s.setup(); err != nil {
+		return err
+	}
+	lines := []models.OrderLine{{ProductID: productID, Selections: parsed, Quantity: quantity}}
+
+	if key != "" {
+		s.expectPlacedOrder()
+	}
+	if s.placed == nil {
+		s.expectProduct(productID)
+		if quantity <= s.stockOf(productID) {
+			placed := s.expectCheckout(lines)
+			if s.keyRace {
+				s.placed = storedOrder(1, customerID)
+				s.placed.IdempotencyKey = key
+				s.placed.RequestFingerprint = (&models.OrderDraft{CustomerID: customerID, Lines: lines}).Fingerprint()
+				s.expectPlacedOrder()
+			} else if placed {
+				s.expectLowStockChecks(lines)
+			}
+		}
+	}
+
+	request := contracts.OrderCreateRequest{Items: []contracts.OrderItemRequest{{ProductID: productID, Quantity: &quantity, Selections: parsed}}}
+	return s.send(http.MethodPost, "/shops/1/orders", request, customerID, "", key)
+}
+
+func (s *OrderSteps) iOrderMyCartTogetherWithBurger(productID int) error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	request := contracts.OrderCreateRequest{FromCart: true, Items: []contracts.OrderItemRequest{{ProductID: productID}}}
+	return s.send(http.MethodPost, "/shops/1/orders", request, 0, existingCartToken, "")
+}
+
+func (s *OrderSteps) iOrderMyCart() error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+	mock := GetTestContext().mockSQLMock
+
+	mock.ExpectQuery("FROM carts").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "shop_id", "customer_id", "token", "expires_at", "created_at", "updated_at"}).
+			AddRow(s.cart.ID, s.cart.ShopID, 0, s.cart.Token, time.Now().Add(time.Hour), time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+	rows := sqlmock.NewRows([]string{"id", "product_id", "selections", "quantity"})
+	productIDs := []int{}
+	for _, line := range s.cart.Lines {
+		selections, _ := json.Marshal(line.Selections)
+		rows.AddRow(line.ID, line.ProductID, selections, line.Quantity)
+		productIDs = append(productIDs, line.ProductID)
+	}
+	mock.ExpectQuery("FROM cart_lines").WillReturnRows(rows)
+
+	if len(productIDs) > 0 {
+		for _, productID := range productIDs {
+			s.expectProduct(productID)
+		}
+		lines := []models.OrderLine{}
+		for _, line := range s.cart.Lines {
+			lines = append(lines, line.ToOrderLine())
+		}
+		if s.expectCheckout(lines) {
+			mock.ExpectExec("DELETE FROM cart_lines").WillReturnResult(sqlmock.NewResult(0, int64(len(productIDs))))
+			s.expectLowStockChecks(lines)
+		}
+	}
+
+	return s.send(http.MethodPost, "/shops/1/orders", contracts.OrderCreateRequest{FromCart: true}, 0, s.cart.Token, "")
+}
+
+func (s *OrderSteps) customerViewsOrder(customerID, orderID int) error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	s.expectOrderRows(s.order)
+	if !s.order.BelongsTo(customerID) {
+		GetTestContext().expectShopStaff(1, customerID)
+	}
+	return s.send(http.MethodGet, fmt.Sprintf("/orders/%d", orderID), nil, customerID, "", "")
+}
+
+func (s *OrderSteps) theShopMovesOrderTo(orderID int, status string) error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	s.expectOrderRows(s.order)
+	GetTestContext().expectShopStaff(1, shopOwnerID)
+	if models.OrderStatus(status).IsValid() {
+		s.expectTransition(models.OrderStatus(status), (&models.User{ID: shopOwnerID}).Actor())
+	} else {
+		// Unknown statuses are rejected once the order is locked, before any write
+		mock := GetTestContext().mockSQLMock
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status\s+FROM orders`).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(string(s.order.Status)))
+		mock.ExpectRollback()
+	}
+
+	request := contracts.OrderTransitionRequest{Status: status}
+	return s.send(http.MethodPost, fmt.Sprintf("/orders/%d/transitions", orderID), request, shopOwnerID, "", "")
+}
+
+func (s *OrderSteps) customerMovesOrderTo(customerID, orderID int, status string) error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	// Nothing is written: the transaction of the transition is never opened
+	s.expectOrderRows(s.order)
+	GetTestContext().expectShopStaff(1, customerID)
+
+	request := contracts.OrderTransitionRequest{Status: status}
+	return s.send(http.MethodPost, fmt.Sprintf("/orders/%d/transitions", orderID), request, customerID, "", "")
+}
+
+func (s *OrderSteps) anAnonymousCallerMovesOrderTo(orderID int, status string) error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	s.expectOrderRows(s.order)
+
+	request := contracts.OrderTransitionRequest{Status: status}
+	return s.send(http.MethodPost, fmt.Sprintf("/orders/%d/transitions", orderID), request, 0, "", "")
+}
+
+func (s *OrderSteps) theShopHasOrders(count int) error {
+	for i := count; i > 0; i-- {
+		if s.orders == nil {
+			s.orders = &contracts.OrdersResponse{}
+		}
+		s.orders.Orders = append(s.orders.Orders, storedOrder(i, 7))
+	}
+	return nil
+}
+
+func (s *OrderSteps) iListTheOrdersOfTheShopWithLimit(limit int) error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+
+	stored := s.orders.Orders
+	if len(stored) > limit {
+		stored = stored[:limit]
+	}
+	GetTestContext().expectShopStaff(1, shopOwnerID)
+	s.expectOrderRows(stored...)
+	return s.send(http.MethodGet, fmt.Sprintf("/shops/1/orders?limit=%d", limit), nil, shopOwnerID, "", "")
+}
+
+func (s *OrderSteps) iListMyOrdersWithoutSigningIn() error {
+	if err := s.setup(); err != nil {
+		return err
+	}
+	return s.send(http.MethodGet, "/orders", nil, 0, "", "")
+}
+
+func (s *OrderSteps) setup() error {
+	ctx := GetTestContext()
+	if ctx.app == nil {
+		return ctx.SetupProductTestApp()
+	}
+	return nil
+}
+
+// send calls an order endpoint as the given customer or cart owner
+func (s *OrderSteps) send(method, path string, body interface{}, userID int, token, idempotencyKey string) error {
+	ctx := GetTestContext()
+
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, ctx.server.URL+path, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if userID > 0 {
+		if err := signIn(req, userID); err != nil {
+			return err
+		}
+	}
+	if token != "" {
+		req.Header.Set(authhttp.CartTokenHeader, token)
+	}
+	if idempotencyKey != "" {
+		req.Header.Set(authhttp.IdempotencyKeyHeader, idempotencyKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse struct {
+			Error  string              `json:"error"`
+			Fields []errors.FieldError `json:"fields"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse.Error
+			s.fields = errorResponse.Fields
+		}
+		return nil
+	}
+
+	if strings.HasSuffix(req.URL.Path, "/orders") && method == http.MethodGet {
+		s.orders = &contracts.OrdersResponse{}
+		return json.NewDecoder(resp.Body).Decode(s.orders)
+	}
+	s.response = &models.Order{}
+	return json.NewDecoder(resp.Body).Decode(s.response)
+}
+
+// ===== Then Steps =====
+
+func (s *OrderSteps) theOrderShouldBePendingWithItems(count int) error {
+	if s.response == nil || s.response.Status != models.OrderPending || len(s.response.Items) != count {
+		return fmt.Errorf("expected a pending order with %d items, got %+v", count, s.response)
+	}
+	return nil
+}
+
+func (s *OrderSteps) theOrderTotalShouldBe(expected string) error {
+	if s.response == nil || s.response.Total.String() != expected {
+		return fmt.Errorf("expected order total %s, got %+v", expected, s.response)
+	}
+	return nil
+}
+
+func (s *OrderSteps) orderItemShouldSnapshotAtWithOptions(position int, name, unitPrice, options string) error {
+	if s.response == nil || len(s.response.Items) < position {
+		return fmt.Errorf("expected order item %d, got %+v", position, s.response)
+	}
+	item := s.response.Items[position-1]
+
+	names := []string{}
+	for _, option := range item.Options {
+		names = append(names, option.Name)
+	}
+	if item.ProductName != name || item.UnitPrice.String() != unitPrice || strings.Join(names, ", ") != options {
+		return fmt.Errorf("expected %s at %s with options %q, got %+v", name, unitPrice, options, item)
+	}
+	return nil
+}
+
+func (s *OrderSteps) theOrderShouldBeStored() error {
+	return GetTestContext().mockSQLMock.ExpectationsWereMet()
+}
+
+func (s *OrderSteps) ordersShouldBeListedWithMoreToFollow(count int) error {
+	if s.orders == nil || len(s.orders.Orders) != count || !s.orders.HasMore || s.orders.NextCursor == "" {
+		return fmt.Errorf("expected %d orders with a next cursor, got %+v", count, s.orders)
+	}
+	return nil
+}
+
+func (s *OrderSteps) theCheckoutShouldLeaveBurgerWithStock(productID, stock int) error {
+	if written, ok := s.written[productID]; !ok || written != stock {
+		return fmt.Errorf("expected the checkout to leave burger %d with stock %d, got %v", productID, stock, s.written)
+	}
+	return nil
+}
+
+func (s *OrderSteps) theCheckoutShouldLeaveOptionWithStock(optionID, stock int) error {
+	if written, ok := s.writtenOpts[optionID]; !ok || written != stock {
+		return fmt.Errorf("expected the checkout to leave option %d with stock %d, got %v", optionID, stock, s.writtenOpts)
+	}
+	return nil
+}
+
+func (s *OrderSteps) theOrderShouldBe(status string) error {
+	if s.response == nil || string(s.response.Status) != status {
+		return fmt.Errorf("expected a %s order, got %+v", status, s.response)
+	}
+	return nil
+}
+
+func (s *OrderSteps) theCancellationShouldReturnBurgerToStock(productID, stock int) error {
+	if written, ok := s.written[productID]; !ok || written != stock {
+		return fmt.Errorf("expected the cancellation to return burger %d to stock %d, got %v", productID, stock, s.written)
+	}
+	return nil
+}
+
+func (s *OrderSteps) theCancellationShouldReturnOptionToStock(optionID, stock int) error {
+	if written, ok := s.writtenOpts[optionID]; !ok || written != stock {
+		return fmt.Errorf("expected the cancellation to return option %d to stock %d, got %v", optionID, stock, s.writtenOpts)
+	}
+	return nil
+}
+
+func (s *OrderSteps) orderLineShouldFailWith(position int, message string) error {
+	expected := errors.FieldError{Field: fmt.Sprintf("items[%d]", position-1), Message: message}
+	if !slices.Contains(s.fields, expected) {
+		return fmt.Errorf("expected %+v among the failed lines, got %+v", expected, s.fields)
+	}
+	return nil
+}
+
+func (s *OrderSteps) theResponseShouldReplayOrder(orderID int) error {
+	ctx := GetTestContext()
+	if ctx.response.Header.Get(authhttp.IdempotentReplayedHeader) != "true" || s.response == nil || s.response.ID != orderID {
+		return fmt.Errorf("expected order %d to be replayed, got %+v", orderID, s.response)
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (s *OrderSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^burger (\d+) can be ordered with stock (\d+)$`, s.burgerCanBeOrderedWithStock)
+	sc.Step(`^burger (\d+) is sold by another shop$`, s.burgerIsSoldByAnotherShop)
+	sc.Step(`^only (\d+) of burger (\d+) is left when the order is placed$`, s.onlyOfBurgerIsLeftWhenTheOrderIsPlaced)
+	sc.Step(`^option (\d+) has stock (\d+)$`, s.optionHasStock)
+	sc.Step(`^customer (\d+) placed an order with the idempotency key "([^"]*)" for (\d+) of burger (\d+) configured as '([^']*)'$`, s.customerPlacedAnOrderWithTheIdempotencyKeyFor)
+	sc.Step(`^a concurrent retry places the order first$`, s.aConcurrentRetryPlacesTheOrderFirst)
+	sc.Step(`^my cart to order holds (\d+) of burger (\d+) configured as '([^']*)'$`, s.myCartToOrderHoldsOfBurgerConfiguredAs)
+	sc.Step(`^my cart to order is empty$`, s.myCartToOrderIsEmpty)
+	sc.Step(`^order (\d+) of customer (\d+) bought (\d+) "([^"]*)" whose product was purged$`, s.orderOfCustomerBoughtWhoseProductWasPurged)
+	sc.Step(`^the shop has (\d+) orders$`, s.theShopHasOrders)
+	sc.Step(`^order (\d+) of customer (\d+) is "([^"]*)" with (\d+) of burger (\d+)$`, s.orderOfCustomerIsWithOfBurger)
+	sc.Step(`^the ordered burger came with option (\d+)$`, s.theOrderedBurgerCameWithOption)
+
+	// When steps
+	sc.Step(`^customer (\d+) orders (\d+) of burger (\d+) configured as '([^']*)'$`, s.customerOrdersOfBurgerConfiguredAs)
+	sc.Step(`^customer (\d+) orders (\d+) of burger (\d+) configured as '([^']*)' with the idempotency key "([^"]*)"$`, s.customerOrdersOfBurgerConfiguredAsWithTheIdempotencyKey)
+	sc.Step(`^I order my cart together with burger (\d+)$`, s.iOrderMyCartTogetherWithBurger)
+	sc.Step(`^I order my cart$`, s.iOrderMyCart)
+	sc.Step(`^customer (\d+) views order (\d+)$`, s.customerViewsOrder)
+	sc.Step(`^I list the orders of the shop with limit (\d+)$`, s.iListTheOrdersOfTheShopWithLimit)
+	sc.Step(`^I list my orders without signing in$`, s.iListMyOrdersWithoutSigningIn)
+	sc.Step(`^the shop moves order (\d+) to "([^"]*)"$`, s.theShopMovesOrderTo)
+	sc.Step(`^customer (\d+) moves order (\d+) to "([^"]*)"$`, s.customerMovesOrderTo)
+	sc.Step(`^an anonymous caller moves order (\d+) to "([^"]*)"$`, s.anAnonymousCallerMovesOrderTo)
+
+	// Then steps
+	sc.Step(`^the order should be pending with (\d+) items?$`, s.theOrderShouldBePendingWithItems)
+	sc.Step(`^the order total should be (\d+\.\d+)$`, s.theOrderTotalShouldBe)
+	sc.Step(`^order item (\d+) should snapshot "([^"]*)" at (\d+\.\d+) with options "([^"]*)"$`, s.orderItemShouldSnapshotAtWithOptions)
+	sc.Step(`^the order should be stored$`, s.theOrderShouldBeStored)
+	sc.Step(`^(\d+) orders should be listed with more to follow$`, s.ordersShouldBeListedWithMoreToFollow)
+	sc.Step(`^the checkout should leave burger (\d+) with stock (\d+)$`, s.theCheckoutShouldLeaveBurgerWithStock)
+	sc.Step(`^the checkout should leave option (\d+) with stock (\d+)$`, s.theCheckoutShouldLeaveOptionWithStock)
+	sc.Step(`^order line (\d+) should fail with "([^"]*)"$`, s.orderLineShouldFailWith)
+	sc.Step(`^the order should be "([^"]*)"$`, s.theOrderShouldBe)
+	sc.Step(`^the status change should be recorded$`, s.theOrderShouldBeStored)
+	sc.Step(`^the cancellation should return burger (\d+) to stock (\d+)$`, s.theCancellationShouldReturnBurgerToStock)
+	sc.Step(`^the cancellation should return option (\d+) to stock (\d+)$`, s.theCancellationShouldReturnOptionToStock)
+	sc.Step(`^the response should replay order (\d+)$`, s.theResponseShouldReplayOrder)
+}
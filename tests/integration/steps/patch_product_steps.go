@@ -0,0 +1,171 @@
+package steps
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+type PatchProductSteps struct {
+	productExists bool
+	patched       *models.Product
+}
+
+func NewPatchProductSteps() *PatchProductSteps {
+	return &PatchProductSteps{}
+}
+
+// ===== Given Steps =====
+
+func (p *PatchProductSteps) aStoredProductWithID(_ int) error {
+	p.productExists = true
+	return nil
+}
+
+func (p *PatchProductSteps) noStoredProductWithID(_ int) error {
+	p.productExists = false
+	return nil
+}
+
+// ===== When Steps =====
+
+func (p *PatchProductSteps) iSendAMergePatchForProduct(patch string, productID int) error {
+	return p.sendPatch(patch, productID, mergePatchContentType)
+}
+
+func (p *PatchProductSteps) iSendAPatchForProductWithContentType(patch string, productID int, contentType string) error {
+	return p.sendPatch(patch, productID, contentType)
+}
+
+func (p *PatchProductSteps) sendPatch(patch string, productID int, contentType string) error {
+	ctx := GetTestContext()
+
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	p.setupSQLExpectations(productID)
+
+	url := fmt.Sprintf("%s/products/%d", ctx.server.URL, productID)
+	req, err := http.NewRequest(http.MethodPatch, url, strings.NewReader(patch))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse["error"]
+		}
+		return nil
+	}
+
+	var product models.Product
+	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+		return err
+	}
+	p.patched = &product
+
+	return nil
+}
+
+// ===== SQL Mock Setup =====
+
+func (p *PatchProductSteps) storedProductRows(productID int, isActive bool) *sqlmock.Rows {
+	columns := []string{
+		"id", "name", "description", "price", "stock", "minimum_stock",
+		"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+		"category_id", "category_name", "category_description",
+		"images", "variants", "promotions", "time_zone",
+	}
+
+	images := `[{"id": 10, "url": "https://cdn.example.com/burger.jpg"}]`
+	variants := `[{"id": 20, "name": "Size", "order": 1, "selection_type": "single", "max_selections": 1,
+		"options": [{"id": 30, "name": "Large", "price": 2.5, "order": 1}]}]`
+
+	return sqlmock.NewRows(columns).
+		AddRow(productID, "Classic Burger", "Beef burger", 9.5, 10, 2, isActive, false, false, 0.0, time.Now(), 1,
+			1, "Burgers", "", images, variants, "[]", "")
+}
+
+func (p *PatchProductSteps) setupSQLExpectations(productID int) {
+	ctx := GetTestContext()
+
+	if !p.productExists {
+		ctx.mockSQLMock.ExpectQuery(`FROM products p(.+)WHERE p.id = \$1`).
+			WillReturnRows(sqlmock.NewRows(nil))
+		return
+	}
+
+	ctx.mockSQLMock.ExpectQuery(`FROM products p(.+)WHERE p.id = \$1`).
+		WillReturnRows(p.storedProductRows(productID, true))
+
+	// Only the is_active scenario reaches the stored procedure; images, variants and version go as NULL
+	ctx.mockSQLMock.ExpectExec("SELECT update_product").
+		WithArgs(productID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			false, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1, driver.Value(nil), driver.Value(nil), driver.Value(nil)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx.mockSQLMock.ExpectQuery(`FROM products p(.+)WHERE p.id = \$1`).
+		WillReturnRows(p.storedProductRows(productID, false))
+}
+
+// ===== Then Steps =====
+
+func (p *PatchProductSteps) thePatchedProductShouldNotBeActive() error {
+	if p.patched == nil {
+		return fmt.Errorf("expected patched product in response")
+	}
+	if p.patched.IsActive {
+		return fmt.Errorf("expected product to be inactive")
+	}
+	return nil
+}
+
+func (p *PatchProductSteps) theStoredImagesAndVariantsShouldBeLeftUntouched() error {
+	if err := GetTestContext().mockSQLMock.ExpectationsWereMet(); err != nil {
+		return err
+	}
+	if len(p.patched.Images) != 1 || len(p.patched.Variants) != 1 {
+		return fmt.Errorf("expected stored images and variants, got %d images and %d variants",
+			len(p.patched.Images), len(p.patched.Variants))
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (p *PatchProductSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^a stored product with id (\d+)$`, p.aStoredProductWithID)
+	sc.Step(`^no stored product with id (\d+)$`, p.noStoredProductWithID)
+
+	// When steps
+	sc.Step(`^I send a merge patch '([^']*)' for product (\d+)$`, p.iSendAMergePatchForProduct)
+	sc.Step(`^I send a patch '([^']*)' for product (\d+) with content type "([^"]*)"$`, p.iSendAPatchForProductWithContentType)
+
+	// Then steps
+	sc.Step(`^the patched product should not be active$`, p.thePatchedProductShouldNotBeActive)
+	sc.Step(`^the stored images and variants should be left untouched$`, p.theStoredImagesAndVariantsShouldBeLeftUntouched)
+}
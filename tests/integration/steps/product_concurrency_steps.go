@@ -0,0 +1,182 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+	"github.com/lib/pq"
+)
+
+const requireIfMatchEnv = "PRODUCT_REQUIRE_IF_MATCH"
+
+type ProductConcurrencySteps struct {
+	version              int
+	modifiedConcurrently bool
+}
+
+func NewProductConcurrencySteps() *ProductConcurrencySteps {
+	return &ProductConcurrencySteps{}
+}
+
+// ===== Given Steps =====
+
+func (c *ProductConcurrencySteps) aProductWithIDAtVersion(_ int, version int) error {
+	c.version = version
+	return nil
+}
+
+func (c *ProductConcurrencySteps) aProductWithIDAtVersionThatIsModifiedConcurrently(_ int, version int) error {
+	c.version = version
+	c.modifiedConcurrently = true
+	return nil
+}
+
+func (c *ProductConcurrencySteps) strictIfMatchModeIsEnabled() error {
+	// Read by the handler constructor, so it must be set before the app starts
+	return os.Setenv(requireIfMatchEnv, "true")
+}
+
+// ===== When Steps =====
+
+func (c *ProductConcurrencySteps) iGetProduct(productID int) error {
+	ctx := GetTestContext()
+	if err := c.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	ctx.mockSQLMock.ExpectQuery(`FROM products p(.+)WHERE p.id = \$1`).
+		WillReturnRows(c.productRows(productID, c.version))
+
+	return c.sendRequest(ctx, http.MethodGet, productID, "", nil)
+}
+
+func (c *ProductConcurrencySteps) iSendAMergePatchWithIfMatch(patch string, productID int, ifMatch string) error {
+	ctx := GetTestContext()
+	if err := c.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	if c.version > 0 {
+		ctx.mockSQLMock.ExpectQuery(`FROM products p(.+)WHERE p.id = \$1`).
+			WillReturnRows(c.productRows(productID, c.version))
+	}
+
+	// The stored procedure only runs when the If-Match version is still current
+	if c.version > 0 && fmt.Sprintf(`"%d"`, c.version) == ifMatch {
+		update := ctx.mockSQLMock.ExpectExec("SELECT update_product").
+			WithArgs(productID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+				sqlmock.AnyArg(), sqlmock.AnyArg(), c.version)
+
+		if c.modifiedConcurrently {
+			update.WillReturnError(&pq.Error{Code: "40001", Message: "product_version_mismatch"})
+		} else {
+			update.WillReturnResult(sqlmock.NewResult(0, 1))
+			ctx.mockSQLMock.ExpectQuery(`FROM products p(.+)WHERE p.id = \$1`).
+				WillReturnRows(c.productRows(productID, c.version+1))
+		}
+	}
+
+	return c.sendRequest(ctx, http.MethodPatch, productID, patch, map[string]string{"If-Match": ifMatch})
+}
+
+func (c *ProductConcurrencySteps) iSendAMergePatchWithoutIfMatch(patch string, productID int) error {
+	ctx := GetTestContext()
+	if err := c.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	return c.sendRequest(ctx, http.MethodPatch, productID, patch, nil)
+}
+
+func (c *ProductConcurrencySteps) setupTestApp(ctx *TestContext) error {
+	if ctx.app == nil {
+		return ctx.SetupProductTestApp()
+	}
+	return nil
+}
+
+func (c *ProductConcurrencySteps) productRows(productID, version int) *sqlmock.Rows {
+	columns := []string{
+		"id", "name", "description", "price", "stock", "minimum_stock",
+		"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+		"category_id", "category_name", "category_description",
+		"images", "variants", "promotions", "time_zone",
+	}
+
+	return sqlmock.NewRows(columns).
+		AddRow(productID, "Classic Burger", "Beef burger", 9.5, 10, 2, true, false, false, 0.0, time.Now(), version,
+			1, "Burgers", "", `[{"id": 10, "url": "https://cdn.example.com/burger.jpg"}]`, "[]", "[]", "")
+}
+
+func (c *ProductConcurrencySteps) sendRequest(ctx *TestContext, method string, productID int, body string, headers map[string]string) error {
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/products/%d", ctx.server.URL, productID), strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if method == http.MethodPatch {
+		req.Header.Set("Content-Type", mergePatchContentType)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	ctx.response = resp
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse["error"]
+		}
+	}
+
+	return nil
+}
+
+// ===== Then Steps =====
+
+func (c *ProductConcurrencySteps) theResponseShouldHaveETag(expected string) error {
+	ctx := GetTestContext()
+	if etag := ctx.response.Header.Get("ETag"); etag != expected {
+		return fmt.Errorf("expected ETag %s, got %s", expected, etag)
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (c *ProductConcurrencySteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^a product with id (\d+) at version (\d+)$`, c.aProductWithIDAtVersion)
+	sc.Step(`^a product with id (\d+) at version (\d+) that is modified concurrently$`, c.aProductWithIDAtVersionThatIsModifiedConcurrently)
+	sc.Step(`^strict If-Match mode is enabled$`, c.strictIfMatchModeIsEnabled)
+
+	// When steps
+	sc.Step(`^I get product (\d+)$`, c.iGetProduct)
+	sc.Step(`^I send a merge patch '([^']*)' for product (\d+) with If-Match "((?:[^"\\]|\\.)*)"$`, func(patch string, productID int, ifMatch string) error {
+		return c.iSendAMergePatchWithIfMatch(patch, productID, strings.ReplaceAll(ifMatch, `\"`, `"`))
+	})
+	sc.Step(`^I send a merge patch '([^']*)' for product (\d+) without If-Match$`, c.iSendAMergePatchWithoutIfMatch)
+
+	// Then steps
+	sc.Step(`^the response should have ETag "((?:[^"\\]|\\.)*)"$`, func(expected string) error {
+		return c.theResponseShouldHaveETag(strings.ReplaceAll(expected, `\"`, `"`))
+	})
+
+	sc.After(func(ctx context.Context, _ *godog.Scenario, err error) (context.Context, error) {
+		return ctx, os.Unsetenv(requireIfMatchEnv)
+	})
+}
@@ -0,0 +1,232 @@
+package steps
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+)
+
+var productImageColumns = []string{
+	"id", "name", "description", "price", "stock", "minimum_stock",
+	"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+	"category_id", "category_name", "category_description",
+	"images", "variants", "promotions", "time_zone",
+}
+
+type ProductImageOrderSteps struct {
+	imageIDs []int
+	exists   bool
+	product  *models.Product
+	listed   *contracts.PaginatedProductsResponse
+}
+
+func NewProductImageOrderSteps() *ProductImageOrderSteps {
+	return &ProductImageOrderSteps{}
+}
+
+// imagesJSON returns the images as the repository aggregates them, ordered by position
+func imagesJSON(images []models.ProductImage) string {
+	data, _ := json.Marshal(images)
+	return string(data)
+}
+
+// productRowsWithImages returns a stored product holding the images
+func productRowsWithImages(productID, version int, images []models.ProductImage) *sqlmock.Rows {
+	return sqlmock.NewRows(productImageColumns).
+		AddRow(productID, "Classic Burger", "Beef burger", 9.5, 10, 2, true, false, false, 0.0, time.Now(), version,
+			1, "Burgers", "", imagesJSON(images), "[]", "[]", "")
+}
+
+func parseImageIDs(list string) ([]int, error) {
+	var ids []int
+	for _, field := range strings.Split(list, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// ===== Given Steps =====
+
+func (s *ProductImageOrderSteps) productHasTheImages(_ int, list string) error {
+	ids, err := parseImageIDs(list)
+	if err != nil {
+		return err
+	}
+	s.imageIDs = ids
+	s.exists = true
+	return nil
+}
+
+// ===== When Steps =====
+
+func (s *ProductImageOrderSteps) iOrderTheImagesOfProductAs(productID int, list string) error {
+	return s.order(productID, list, 0)
+}
+
+func (s *ProductImageOrderSteps) iOrderTheImagesOfProductAsWithPrimaryImage(productID int, list string, primaryID int) error {
+	return s.order(productID, list, primaryID)
+}
+
+func (s *ProductImageOrderSteps) order(productID int, list string, primaryID int) error {
+	ctx := GetTestContext()
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	ids, err := parseImageIDs(list)
+	if err != nil {
+		return err
+	}
+	s.expectReorder(ctx, productID, ids, primaryID)
+
+	body, _ := json.Marshal(contracts.ProductImageOrderRequest{ImageIDs: ids, PrimaryImageID: primaryID})
+	url := fmt.Sprintf("%s/products/%d/images/order", ctx.server.URL, productID)
+	req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse["error"]
+		}
+		return nil
+	}
+
+	s.product = &models.Product{}
+	return json.NewDecoder(resp.Body).Decode(s.product)
+}
+
+// expectReorder mocks the reorder transaction and the product read back
+// Orders the domain rejects never reach the database, so unmet expectations are fine
+func (s *ProductImageOrderSteps) expectReorder(ctx *TestContext, productID int, ids []int, primaryID int) {
+	mock := ctx.mockSQLMock
+	mock.ExpectBegin()
+	if !s.exists {
+		mock.ExpectQuery("SELECT version").WillReturnRows(sqlmock.NewRows([]string{"version"}))
+		mock.ExpectRollback()
+		return
+	}
+
+	mock.ExpectQuery("SELECT version").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM product_images`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(s.imageIDs)))
+	if len(ids) != len(s.imageIDs) {
+		mock.ExpectRollback()
+		return
+	}
+	mock.ExpectExec("SET is_primary = false").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("FROM unnest").WillReturnResult(sqlmock.NewResult(0, int64(len(ids))))
+	mock.ExpectExec(`UPDATE products SET version`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	order := &models.ImageOrder{ImageIDs: ids, PrimaryImageID: primaryID}
+	mock.ExpectQuery(`FROM products p(.+)WHERE p.id = \$1`).
+		WillReturnRows(productRowsWithImages(productID, 2, order.Images()))
+}
+
+func (s *ProductImageOrderSteps) iListTheProductsOfShop(shopID int) error {
+	ctx := GetTestContext()
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	// The second image was chosen as primary
+	images := []models.ProductImage{{ID: s.imageIDs[0], Position: 0}}
+	for i, id := range s.imageIDs[1:] {
+		images = append(images, models.ProductImage{ID: id, Position: i + 1, IsPrimary: i == 0})
+	}
+	ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM products").
+		WillReturnRows(productRowsWithImages(1, 1, images))
+
+	resp, err := http.Get(fmt.Sprintf("%s/shops/%d/products", ctx.server.URL, shopID))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ctx.response = resp
+
+	s.listed = &contracts.PaginatedProductsResponse{}
+	return json.NewDecoder(resp.Body).Decode(s.listed)
+}
+
+// ===== Then Steps =====
+
+func (s *ProductImageOrderSteps) theImagesShouldBeOrderedAsWithPrimaryImage(list string, primaryID int) error {
+	ids, err := parseImageIDs(list)
+	if err != nil {
+		return err
+	}
+	if s.product == nil || len(s.product.Images) != len(ids) {
+		return fmt.Errorf("expected %d images in the response, got %+v", len(ids), s.product)
+	}
+
+	for i, image := range s.product.Images {
+		if image.ID != ids[i] || image.Position != i || image.IsPrimary != (image.ID == primaryID) {
+			return fmt.Errorf("expected images %v with primary %d, got %+v", ids, primaryID, s.product.Images)
+		}
+	}
+	return nil
+}
+
+func (s *ProductImageOrderSteps) theProductVersionShouldBe(version int) error {
+	ctx := GetTestContext()
+	if etag := ctx.response.Header.Get("ETag"); etag != fmt.Sprintf(`"%d"`, version) {
+		return fmt.Errorf("expected ETag %q, got %q", fmt.Sprintf(`"%d"`, version), etag)
+	}
+	return nil
+}
+
+func (s *ProductImageOrderSteps) theListedProductShouldExposePrimaryImage(imageID int) error {
+	if s.listed == nil || len(s.listed.Products) != 1 {
+		return fmt.Errorf("expected one listed product, got %+v", s.listed)
+	}
+	primary := s.listed.Products[0].PrimaryImage
+	if primary == nil || primary.ID != imageID {
+		return fmt.Errorf("expected primary image %d, got %+v", imageID, primary)
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (s *ProductImageOrderSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^product (\d+) has the images "([^"]*)"$`, s.productHasTheImages)
+
+	// When steps
+	sc.Step(`^I order the images of product (\d+) as "([^"]*)"$`, s.iOrderTheImagesOfProductAs)
+	sc.Step(`^I order the images of product (\d+) as "([^"]*)" with primary image (\d+)$`, s.iOrderTheImagesOfProductAsWithPrimaryImage)
+	sc.Step(`^I list the products of shop (\d+)$`, s.iListTheProductsOfShop)
+
+	// Then steps
+	sc.Step(`^the images should be ordered as "([^"]*)" with primary image (\d+)$`, s.theImagesShouldBeOrderedAsWithPrimaryImage)
+	sc.Step(`^the product version should be (\d+)$`, s.theProductVersionShouldBe)
+	sc.Step(`^the listed product should expose image (\d+) as its primary image$`, s.theListedProductShouldExposePrimaryImage)
+}
@@ -0,0 +1,240 @@
+package steps
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type ProductPromotionsSteps struct {
+	price        float64
+	promotions   string
+	locked       string // promotions found once the product is locked, when another request scheduled one first
+	affectedRows int64
+	product      *models.Product
+	promotion    *models.Promotion
+}
+
+func NewProductPromotionsSteps() *ProductPromotionsSteps {
+	return &ProductPromotionsSteps{}
+}
+
+// ===== Given Steps =====
+
+func (p *ProductPromotionsSteps) aProductPricedAtWithoutPromotions(_ int, price float64) error {
+	p.price = price
+	p.promotions = "[]"
+	return nil
+}
+
+func (p *ProductPromotionsSteps) aProductPricedAtWithThePromotion(_ int, price float64, promotion string) error {
+	p.price = price
+	p.promotions = "[" + promotion + "]"
+	return nil
+}
+
+func (p *ProductPromotionsSteps) anotherRequestSchedulesThePromotionFirst(promotion string) error {
+	p.locked = "[" + promotion + "]"
+	return nil
+}
+
+func (p *ProductPromotionsSteps) aScheduledPromotionForProduct(_, _ int) error {
+	p.affectedRows = 1
+	return nil
+}
+
+func (p *ProductPromotionsSteps) noScheduledPromotionForProduct(_, _ int) error {
+	p.affectedRows = 0
+	return nil
+}
+
+// ===== When Steps =====
+
+func (p *ProductPromotionsSteps) iScheduleThePromotionForProduct(body string, productID int) error {
+	ctx := GetTestContext()
+	if err := p.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	if p.promotions != "" {
+		ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM products").
+			WillReturnRows(p.productRows(productID))
+
+		locked := p.locked
+		if locked == "" {
+			locked = p.promotions
+		}
+		ctx.mockSQLMock.ExpectBegin()
+		ctx.mockSQLMock.ExpectQuery(`SELECT price\s+FROM products(.+)FOR UPDATE`).
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(p.price))
+		ctx.mockSQLMock.ExpectQuery("FROM product_promotions").
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows([]string{"promotions"}).AddRow(locked))
+		if p.locked != "" {
+			ctx.mockSQLMock.ExpectRollback()
+		} else {
+			ctx.mockSQLMock.ExpectQuery("INSERT INTO product_promotions").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+			ctx.mockSQLMock.ExpectExec(`UPDATE products\s+SET version = version \+ 1`).
+				WithArgs(productID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			ctx.mockSQLMock.ExpectCommit()
+		}
+	}
+
+	return p.sendRequest(ctx, http.MethodPost, fmt.Sprintf("/products/%d/promotions", productID), body)
+}
+
+func (p *ProductPromotionsSteps) iRequestProduct(productID int) error {
+	ctx := GetTestContext()
+	if err := p.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM products").
+		WillReturnRows(p.productRows(productID))
+
+	return p.sendRequest(ctx, http.MethodGet, fmt.Sprintf("/products/%d", productID), "")
+}
+
+func (p *ProductPromotionsSteps) iDeletePromotionOfProduct(promotionID, productID int) error {
+	ctx := GetTestContext()
+	if err := p.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	ctx.mockSQLMock.ExpectExec(`DELETE FROM product_promotions(.+)UPDATE products\s+SET version = version \+ 1`).
+		WithArgs(promotionID, productID).
+		WillReturnResult(sqlmock.NewResult(0, p.affectedRows))
+
+	return p.sendRequest(ctx, http.MethodDelete, fmt.Sprintf("/products/%d/promotions/%d", productID, promotionID), "")
+}
+
+func (p *ProductPromotionsSteps) productRows(productID int) *sqlmock.Rows {
+	columns := []string{
+		"id", "name", "description", "price", "stock", "minimum_stock",
+		"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+		"category_id", "category_name", "category_description",
+		"images", "variants", "promotions", "time_zone",
+	}
+
+	return sqlmock.NewRows(columns).
+		AddRow(productID, "Classic Burger", "Beef burger", p.price, 10, 2, true, false, false, 0.0, time.Now(), 1,
+			1, "Burgers", "", `[{"id": 10, "url": "https://cdn.example.com/burger.jpg"}]`, "[]", p.promotions, models.DefaultTimeZone)
+}
+
+func (p *ProductPromotionsSteps) setupTestApp(ctx *TestContext) error {
+	if ctx.app == nil {
+		return ctx.SetupProductTestApp()
+	}
+	return nil
+}
+
+func (p *ProductPromotionsSteps) sendRequest(ctx *TestContext, method, path, body string) error {
+	req, err := http.NewRequest(method, ctx.server.URL+path, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse["error"]
+		}
+		return nil
+	}
+
+	switch {
+	case method == http.MethodGet:
+		p.product = &models.Product{}
+		return json.NewDecoder(resp.Body).Decode(p.product)
+	case method == http.MethodPost:
+		p.promotion = &models.Promotion{}
+		return json.NewDecoder(resp.Body).Decode(p.promotion)
+	default:
+		var message map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&message); err == nil {
+			ctx.successMessage = message["message"]
+		}
+		return nil
+	}
+}
+
+// ===== Then Steps =====
+
+func (p *ProductPromotionsSteps) theScheduledPromotionShouldHaveID(expected int) error {
+	if p.promotion == nil || p.promotion.ID != expected {
+		return fmt.Errorf("expected scheduled promotion with id %d, got %+v", expected, p.promotion)
+	}
+	return nil
+}
+
+func (p *ProductPromotionsSteps) theProductVersionShouldBeBumped() error {
+	return GetTestContext().mockSQLMock.ExpectationsWereMet()
+}
+
+func (p *ProductPromotionsSteps) theProductActivePriceShouldBe(expected float64) error {
+	if p.product == nil {
+		return fmt.Errorf("expected a product in the response")
+	}
+	if p.product.ActivePrice.Cmp(models.MoneyFromFloat(expected)) != 0 {
+		return fmt.Errorf("expected active price %v, got %s", expected, p.product.ActivePrice)
+	}
+	return nil
+}
+
+func (p *ProductPromotionsSteps) theProductShouldReportWhenThePromotionEnds() error {
+	if p.product == nil || p.product.PromotionEndsAt == nil {
+		return fmt.Errorf("expected promotion_ends_at in the response")
+	}
+	return nil
+}
+
+func (p *ProductPromotionsSteps) theProductShouldNotReportAPromotionEnd() error {
+	if p.product == nil || p.product.PromotionEndsAt != nil {
+		return fmt.Errorf("expected no promotion_ends_at in the response")
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (p *ProductPromotionsSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^a product with id (\d+) priced at (\d+(?:\.\d+)?) without promotions$`, p.aProductPricedAtWithoutPromotions)
+	sc.Step(`^a product with id (\d+) priced at (\d+(?:\.\d+)?) with the promotion '([^']*)'$`, p.aProductPricedAtWithThePromotion)
+	sc.Step(`^another request schedules the promotion '([^']*)' first$`, p.anotherRequestSchedulesThePromotionFirst)
+	sc.Step(`^a scheduled promotion with id (\d+) for product (\d+)$`, p.aScheduledPromotionForProduct)
+	sc.Step(`^no scheduled promotion with id (\d+) for product (\d+)$`, p.noScheduledPromotionForProduct)
+
+	// When steps
+	sc.Step(`^I schedule the promotion '([^']*)' for product (\d+)$`, p.iScheduleThePromotionForProduct)
+	sc.Step(`^I request product (\d+)$`, p.iRequestProduct)
+	sc.Step(`^I delete promotion (\d+) of product (\d+)$`, p.iDeletePromotionOfProduct)
+
+	// Then steps
+	sc.Step(`^the scheduled promotion should have id (\d+)$`, p.theScheduledPromotionShouldHaveID)
+	sc.Step(`^the product version should be bumped$`, p.theProductVersionShouldBeBumped)
+	sc.Step(`^the product active price should be (\d+(?:\.\d+)?)$`, p.theProductActivePriceShouldBe)
+	sc.Step(`^the product should report when the promotion ends$`, p.theProductShouldReportWhenThePromotionEnds)
+	sc.Step(`^the product should not report a promotion end$`, p.theProductShouldNotReportAPromotionEnd)
+}
@@ -0,0 +1,146 @@
+package steps
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+// quotedProductVariants has a required single "Size" variant, a "Extras" variant up to 2 options
+// and a custom "Sauces" variant whose rules make the 2 cheapest sauces free
+const quotedProductVariants = `[
+	{"id": 1, "name": "Size", "order": 1, "selection_type": "single", "is_required": true, "options": [
+		{"id": 1, "name": "Simple", "price": 0, "order": 1, "is_available": true},
+		{"id": 2, "name": "Double", "price": 3, "order": 2, "is_available": true}
+	]},
+	{"id": 2, "name": "Extras", "order": 2, "selection_type": "multiple", "max_selections": 2, "options": [
+		{"id": 3, "name": "Bacon", "price": 1.5, "order": 1, "stock": 0, "is_available": true},
+		{"id": 4, "name": "Cheese", "price": 1, "order": 2, "is_available": true},
+		{"id": 5, "name": "Egg", "price": 1, "order": 3, "is_available": true}
+	]},
+	{"id": 3, "name": "Sauces", "order": 3, "selection_type": "custom", "options": [
+		{"id": 6, "name": "Ketchup", "price": 0.5, "order": 1},
+		{"id": 7, "name": "Mustard", "price": 0.5, "order": 2},
+		{"id": 8, "name": "Cheddar", "price": 2, "order": 3},
+		{"id": 9, "name": "Vegan mayo", "price": 1, "order": 4}
+	], "rules": [
+		{"type": "selection_count", "max": 3},
+		{"type": "free_selections", "free": 2},
+		{"type": "mutually_exclusive", "options": ["Cheddar", "Vegan mayo"]}
+	]}
+]`
+
+type ProductQuoteSteps struct {
+	isPromotional    bool
+	promotionalPrice float64
+	quote            *models.Quote
+}
+
+func NewProductQuoteSteps() *ProductQuoteSteps {
+	return &ProductQuoteSteps{}
+}
+
+// ===== Given Steps =====
+
+func (q *ProductQuoteSteps) aConfigurableBurgerWithID(_ int) error {
+	return nil
+}
+
+func (q *ProductQuoteSteps) aConfigurableBurgerWithIDOnPromotionAt(_ int, promotionalPrice float64) error {
+	q.isPromotional = true
+	q.promotionalPrice = promotionalPrice
+	return nil
+}
+
+// ===== When Steps =====
+
+func (q *ProductQuoteSteps) iRequestAQuoteForProduct(body string, productID int) error {
+	ctx := GetTestContext()
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	columns := []string{
+		"id", "name", "description", "price", "stock", "minimum_stock",
+		"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+		"category_id", "category_name", "category_description",
+		"images", "variants", "promotions", "time_zone",
+	}
+	ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM products").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(productID, "Classic Burger", "Beef burger", 10.0, 10, 2, true, false, q.isPromotional, q.promotionalPrice, time.Now(), 1,
+				1, "Burgers", "", `[{"id": 10, "url": "https://cdn.example.com/burger.jpg"}]`, quotedProductVariants, "[]", models.DefaultTimeZone))
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/products/%d/quote", ctx.server.URL, productID), strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse["error"]
+		}
+		return nil
+	}
+
+	q.quote = &models.Quote{}
+	return json.NewDecoder(resp.Body).Decode(q.quote)
+}
+
+// ===== Then Steps =====
+
+func (q *ProductQuoteSteps) theQuoteTotalShouldBe(expected string) error {
+	if q.quote == nil || q.quote.Total.String() != expected {
+		return fmt.Errorf("expected quote total %s, got %+v", expected, q.quote)
+	}
+	return nil
+}
+
+func (q *ProductQuoteSteps) theQuoteShouldHaveLines(expected int) error {
+	if q.quote == nil || len(q.quote.Lines) != expected {
+		return fmt.Errorf("expected %d quote lines, got %+v", expected, q.quote)
+	}
+	return nil
+}
+
+func (q *ProductQuoteSteps) theQuoteShouldUseThePromotionalPrice() error {
+	if q.quote == nil || !q.quote.IsPromotional {
+		return fmt.Errorf("expected the quote to use the promotional price, got %+v", q.quote)
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (q *ProductQuoteSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^a configurable burger with id (\d+)$`, q.aConfigurableBurgerWithID)
+	sc.Step(`^a configurable burger with id (\d+) on promotion at (\d+(?:\.\d+)?)$`, q.aConfigurableBurgerWithIDOnPromotionAt)
+
+	// When steps
+	sc.Step(`^I request a quote '([^']*)' for product (\d+)$`, q.iRequestAQuoteForProduct)
+
+	// Then steps
+	sc.Step(`^the quote total should be (\d+\.\d+)$`, q.theQuoteTotalShouldBe)
+	sc.Step(`^the quote should have (\d+) lines$`, q.theQuoteShouldHaveLines)
+	sc.Step(`^the quote should use the promotional price$`, q.theQuoteShouldUseThePromotionalPrice)
+}
@@ -2,18 +2,22 @@ package steps
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"hash/crc32"
 	"image"
 	"image/color"
 	"image/png"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"strings"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/cucumber/godog"
 
+	domainErrors "github.com/mlgaray/ecommerce_api/internal/core/errors"
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
 )
 
@@ -47,6 +51,26 @@ func createTestImage() []byte {
 	return buf.Bytes()
 }
 
+// Helper function to create a valid image padded past the 3MB limit (but < 13MB)
+// Images are streamed, so only a real image header reaches the size check
+func createOversizedImage() []byte {
+	image := createTestImage()
+	return append(image, make([]byte, 3*1024*1024+1024-len(image))...) // 3MB + 1KB
+}
+
+// Helper function to create a PNG that only declares its size, as a decompression bomb does
+func createImageHeader(width, height int) []byte {
+	chunk := []byte("IHDR")
+	chunk = binary.BigEndian.AppendUint32(chunk, uint32(width))
+	chunk = binary.BigEndian.AppendUint32(chunk, uint32(height))
+	chunk = append(chunk, 8, 6, 0, 0, 0)
+
+	content := []byte("\x89PNG\r\n\x1a\n")
+	content = binary.BigEndian.AppendUint32(content, uint32(len(chunk)-4))
+	content = append(content, chunk...)
+	return binary.BigEndian.AppendUint32(content, crc32.ChecksumIEEE(chunk))
+}
+
 // Helper function to create multipart form request
 func createMultipartRequest(product models.Product, shopID int, images [][]byte, imageType string) (*bytes.Buffer, string, error) {
 	body := &bytes.Buffer{}
@@ -107,7 +131,7 @@ func (p *ProductSteps) iHaveValidProductDataWithImages() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        10,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -129,7 +153,7 @@ func (p *ProductSteps) iHaveProductDataWithoutImages() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        10,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -150,7 +174,7 @@ func (p *ProductSteps) iHaveProductDataWithEmptyName() error {
 	ctx.requestBody = models.Product{
 		Name:         "",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        10,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -171,7 +195,7 @@ func (p *ProductSteps) iHaveProductDataWithEmptyDescription() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        10,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -192,7 +216,7 @@ func (p *ProductSteps) iHaveProductDataWithNegativePrice() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        -10.00,
+		Price:        models.MoneyFromFloat(-10.00),
 		Stock:        10,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -213,7 +237,7 @@ func (p *ProductSteps) iHaveProductDataWithNegativeStock() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        -5,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -234,7 +258,7 @@ func (p *ProductSteps) iHaveProductDataWithoutCategory() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        10,
 		MinimumStock: 5,
 		Category:     nil,
@@ -255,7 +279,7 @@ func (p *ProductSteps) iHaveProductDataWithInvalidShopID() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        10,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -276,20 +300,13 @@ func (p *ProductSteps) iHaveProductDataWithOversizedImage() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        10,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
 	}
 
-	// Create oversized image (> 3MB but < 13MB)
-	// Generate a buffer of 3MB + 1KB to ensure it exceeds the 3MB limit
-	oversizedImage := make([]byte, 3*1024*1024+1024) // 3MB + 1KB
-	for i := range oversizedImage {
-		oversizedImage[i] = byte(i % 256) // Fill with pattern data
-	}
-
-	ctx.productImages = [][]byte{oversizedImage}
+	ctx.productImages = [][]byte{createOversizedImage()}
 	if ctx.pathParams == nil {
 		ctx.pathParams = make(map[string]string)
 	}
@@ -304,7 +321,7 @@ func (p *ProductSteps) iHaveProductDataWithInvalidImageType() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        10,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -321,6 +338,27 @@ func (p *ProductSteps) iHaveProductDataWithInvalidImageType() error {
 	return nil
 }
 
+func (p *ProductSteps) iHaveProductDataWithAnImageDeclaringPixels(width, height int) error {
+	ctx := GetTestContext()
+
+	ctx.requestBody = models.Product{
+		Name:         "Test Product",
+		Description:  "Test Description",
+		Price:        models.MoneyFromFloat(99.99),
+		Stock:        10,
+		MinimumStock: 5,
+		Category:     &models.Category{ID: 1},
+	}
+
+	ctx.productImages = [][]byte{createImageHeader(width, height)}
+	if ctx.pathParams == nil {
+		ctx.pathParams = make(map[string]string)
+	}
+	ctx.pathParams["shop_id"] = "1"
+
+	return nil
+}
+
 func (p *ProductSteps) iSendACreateProductRequest() error {
 	ctx := GetTestContext()
 
@@ -396,9 +434,13 @@ func (p *ProductSteps) parseResponse(ctx *TestContext, resp *http.Response) erro
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		var errorResponse map[string]string
+		var errorResponse struct {
+			Error  string                    `json:"error"`
+			Fields []domainErrors.FieldError `json:"fields"`
+		}
 		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
-			ctx.errorMessage = errorResponse["error"]
+			ctx.errorMessage = errorResponse.Error
+			ctx.fieldErrors = errorResponse.Fields
 		}
 	} else {
 		var product models.Product
@@ -416,7 +458,7 @@ func (p *ProductSteps) iHaveProductDataWithNegativeMinimumStock() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        10,
 		MinimumStock: -5, // Negative minimum stock
 		Category:     &models.Category{ID: 1},
@@ -437,7 +479,7 @@ func (p *ProductSteps) iHaveProductDataWithMinimumStockButNoStock() error {
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        0, // No stock
 		MinimumStock: 5, // But has minimum stock
 		Category:     &models.Category{ID: 1},
@@ -458,7 +500,7 @@ func (p *ProductSteps) iHaveProductDataWithMinimumStockGreaterThanStock() error
 	ctx.requestBody = models.Product{
 		Name:         "Test Product",
 		Description:  "Test Description",
-		Price:        99.99,
+		Price:        models.MoneyFromFloat(99.99),
 		Stock:        10, // Stock is 10
 		MinimumStock: 20, // But minimum stock is 20
 		Category:     &models.Category{ID: 1},
@@ -479,12 +521,12 @@ func (p *ProductSteps) iHaveProductDataAsPromotionalWithoutPromotionalPrice() er
 	ctx.requestBody = models.Product{
 		Name:             "Test Product",
 		Description:      "Test Description",
-		Price:            99.99,
+		Price:            models.MoneyFromFloat(99.99),
 		Stock:            10,
 		MinimumStock:     5,
 		Category:         &models.Category{ID: 1},
-		IsPromotional:    true, // Is promotional
-		PromotionalPrice: 0,    // But no promotional price
+		IsPromotional:    true,                     // Is promotional
+		PromotionalPrice: models.MoneyFromFloat(0), // But no promotional price
 	}
 
 	ctx.productImages = [][]byte{createTestImage()}
@@ -502,12 +544,12 @@ func (p *ProductSteps) iHaveProductDataWithPromotionalPriceNotLowerThanPrice() e
 	ctx.requestBody = models.Product{
 		Name:             "Test Product",
 		Description:      "Test Description",
-		Price:            100.00, // Regular price
+		Price:            models.MoneyFromFloat(100.00), // Regular price
 		Stock:            10,
 		MinimumStock:     5,
 		Category:         &models.Category{ID: 1},
 		IsPromotional:    true,
-		PromotionalPrice: 120.00, // Promotional price is higher!
+		PromotionalPrice: models.MoneyFromFloat(120.00), // Promotional price is higher!
 	}
 
 	ctx.productImages = [][]byte{createTestImage()}
@@ -519,6 +561,46 @@ func (p *ProductSteps) iHaveProductDataWithPromotionalPriceNotLowerThanPrice() e
 	return nil
 }
 
+func (p *ProductSteps) iHaveProductDataWithInconsistentVariants() error {
+	ctx := GetTestContext()
+
+	ctx.requestBody = models.Product{
+		Name:        "Test Product",
+		Description: "Test Description",
+		Price:       models.MoneyFromFloat(99.99),
+		Stock:       10,
+		Category:    &models.Category{ID: 1},
+		Variants: []*models.Variant{
+			{Name: "Size", SelectionType: models.Single, MaxSelections: 2, Options: []*models.Option{
+				{Name: "Simple", Price: models.MoneyFromFloat(0), Order: 1},
+				{Name: "Double", Price: models.MoneyFromFloat(3), Order: 2},
+			}},
+			{Name: "Size", SelectionType: models.Multiple, Options: []*models.Option{
+				{Name: "Bacon", Price: models.MoneyFromFloat(1), Order: 1},
+				{Name: "Cheese", Price: models.MoneyFromFloat(1), Order: 1},
+			}},
+		},
+	}
+
+	ctx.productImages = [][]byte{createTestImage()}
+	if ctx.pathParams == nil {
+		ctx.pathParams = make(map[string]string)
+	}
+	ctx.pathParams["shop_id"] = "1"
+
+	return nil
+}
+
+func (p *ProductSteps) theFieldShouldReport(field, message string) error {
+	ctx := GetTestContext()
+	for _, fieldError := range ctx.fieldErrors {
+		if fieldError.Field == field && fieldError.Message == message {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected field %s to report %s, got %+v", field, message, ctx.fieldErrors)
+}
+
 func (p *ProductSteps) theProductShouldBeCreatedSuccessfully() error {
 	ctx := GetTestContext()
 	createdProduct, ok := ctx.responseBody.(*models.Product)
@@ -531,6 +613,68 @@ func (p *ProductSteps) theProductShouldBeCreatedSuccessfully() error {
 	return nil
 }
 
+func (p *ProductSteps) iRequestTheAsset(key string) error {
+	ctx := GetTestContext()
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	resp, err := http.Get(ctx.server.URL + "/assets/" + key)
+	if err != nil {
+		return err
+	}
+	ctx.response = resp
+	return p.parseResponse(ctx, resp)
+}
+
+func (p *ProductSteps) theProductImagesShouldBeServedFromTheAssetStorage() error {
+	ctx := GetTestContext()
+	createdProduct, ok := ctx.responseBody.(*models.Product)
+	if !ok || createdProduct == nil || len(createdProduct.Images) != len(ctx.productImages) {
+		return fmt.Errorf("expected %d product images, got: %+v", len(ctx.productImages), ctx.responseBody)
+	}
+
+	// Every rendition is scaled down from the original, which keeps the uploaded size
+	expectedSizes := map[string]int{models.OriginalRendition: testImageSize}
+	for _, spec := range testImageRenditions {
+		expectedSizes[spec.Name] = min(spec.MaxSize, testImageSize)
+	}
+
+	for _, productImage := range createdProduct.Images {
+		if productImage.URL != productImage.Renditions[models.OriginalRendition] {
+			return fmt.Errorf("expected image URL %s to be the original rendition, got %v", productImage.URL, productImage.Renditions)
+		}
+		if len(productImage.Renditions) != len(expectedSizes) {
+			return fmt.Errorf("expected renditions %v, got %v", expectedSizes, productImage.Renditions)
+		}
+
+		for name, size := range expectedSizes {
+			url, found := productImage.Renditions[name]
+			if !found || !strings.HasPrefix(url, "/assets/") {
+				return fmt.Errorf("expected rendition %s under /assets/, got %q", name, url)
+			}
+
+			resp, err := http.Get(ctx.server.URL + url)
+			if err != nil {
+				return err
+			}
+			config, _, err := image.DecodeConfig(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/jpeg" {
+				return fmt.Errorf("expected rendition %s to be served as image/jpeg, got %d %s", name, resp.StatusCode, resp.Header.Get("Content-Type"))
+			}
+			if err != nil || config.Width != size || config.Height != size {
+				return fmt.Errorf("expected rendition %s to be %dx%d, got %dx%d (%v)", name, size, size, config.Width, config.Height, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // RegisterSteps registers all step definitions
 func (p *ProductSteps) RegisterSteps(sc *godog.ScenarioContext) {
 	// Success scenarios
@@ -544,6 +688,7 @@ func (p *ProductSteps) RegisterSteps(sc *godog.ScenarioContext) {
 	sc.Step(`^I have product data with invalid shop_id$`, p.iHaveProductDataWithInvalidShopID)
 	sc.Step(`^I have product data with oversized image$`, p.iHaveProductDataWithOversizedImage)
 	sc.Step(`^I have product data with invalid image type$`, p.iHaveProductDataWithInvalidImageType)
+	sc.Step(`^I have product data with an image declaring (\d+)x(\d+) pixels$`, p.iHaveProductDataWithAnImageDeclaringPixels)
 
 	// Business validation scenarios
 	sc.Step(`^I have product data with negative price$`, p.iHaveProductDataWithNegativePrice)
@@ -553,10 +698,14 @@ func (p *ProductSteps) RegisterSteps(sc *godog.ScenarioContext) {
 	sc.Step(`^I have product data with minimum stock greater than stock$`, p.iHaveProductDataWithMinimumStockGreaterThanStock)
 	sc.Step(`^I have product data as promotional without promotional price$`, p.iHaveProductDataAsPromotionalWithoutPromotionalPrice)
 	sc.Step(`^I have product data with promotional price not lower than price$`, p.iHaveProductDataWithPromotionalPriceNotLowerThanPrice)
+	sc.Step(`^I have product data with inconsistent variants$`, p.iHaveProductDataWithInconsistentVariants)
 
 	// Action steps
 	sc.Step(`^I send a create product request$`, p.iSendACreateProductRequest)
+	sc.Step(`^I request the asset "([^"]*)"$`, p.iRequestTheAsset)
 
 	// Assertion steps
 	sc.Step(`^the product should be created successfully$`, p.theProductShouldBeCreatedSuccessfully)
+	sc.Step(`^the product images should be served from the asset storage$`, p.theProductImagesShouldBeServedFromTheAssetStorage)
+	sc.Step(`^the field "([^"]*)" should report "([^"]*)"$`, p.theFieldShouldReport)
 }
@@ -0,0 +1,198 @@
+package steps
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+)
+
+const (
+	scenarioSearchWithMatches    = "search-with-matches"
+	scenarioSearchWithoutMatches = "search-without-matches"
+)
+
+type SearchProductsSteps struct{}
+
+func NewSearchProductsSteps() *SearchProductsSteps {
+	return &SearchProductsSteps{}
+}
+
+// ===== Given Steps =====
+
+func (s *SearchProductsSteps) aShopHasProductsMatching(_ int, _ string) error {
+	ctx := GetTestContext()
+	ctx.scenario = scenarioSearchWithMatches
+	return nil
+}
+
+func (s *SearchProductsSteps) aShopHasNoProductsMatching(_ int, _ string) error {
+	ctx := GetTestContext()
+	ctx.scenario = scenarioSearchWithoutMatches
+	return nil
+}
+
+// ===== When Steps =====
+
+func (s *SearchProductsSteps) iSearchProductsOfShopFor(shopID int, query string) error {
+	return s.sendSearchRequest(shopID, query, "")
+}
+
+func (s *SearchProductsSteps) iSearchProductsOfShopForInLanguage(shopID int, query, lang string) error {
+	return s.sendSearchRequest(shopID, query, lang)
+}
+
+func (s *SearchProductsSteps) sendSearchRequest(shopID int, query, lang string) error {
+	ctx := GetTestContext()
+
+	// Setup test app if not already done
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	s.setupSearchSQLExpectations()
+
+	params := url.Values{}
+	params.Set("q", query)
+	if lang != "" {
+		params.Set("lang", lang)
+	}
+
+	resp, err := http.Get(ctx.server.URL + fmt.Sprintf("/shops/%d/products/search?%s", shopID, params.Encode()))
+	if err != nil {
+		return err
+	}
+
+	ctx.response = resp
+	s.parseResponse(ctx, resp)
+
+	return nil
+}
+
+func (s *SearchProductsSteps) parseResponse(ctx *TestContext, resp *http.Response) {
+	if resp.Body == nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse["error"]
+		}
+	} else {
+		var searchResponse contracts.ProductSearchResponse
+		if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err == nil {
+			ctx.responseBody = searchResponse
+		}
+	}
+}
+
+// ===== SQL Mock Setup =====
+
+func (s *SearchProductsSteps) setupSearchSQLExpectations() {
+	ctx := GetTestContext()
+
+	columns := []string{
+		"id", "name", "description", "price", "stock", "minimum_stock",
+		"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+		"category_id", "category_name", "category_description",
+		"images", "variants", "promotions", "time_zone",
+		"rank", "name_highlight", "description_highlight",
+	}
+
+	switch ctx.scenario {
+	case scenarioSearchWithMatches:
+		rows := sqlmock.NewRows(columns).
+			AddRow(3, "Classic Burger", "Beef burger", 9.5, 10, 0, true, false, false, 0.0, time.Now(), 1, 1, "Burgers", "", "[]", "[]", "[]", "",
+				0.75, "Classic \x02Burger\x03", "Beef \x02burger\x03").
+			AddRow(7, "Veggie Wrap", "Comes with a side of burger sauce", 8.0, 10, 0, true, false, false, 0.0, time.Now(), 1, 2, "Wraps", "", "[]", "[]", "[]", "",
+				0.12, "Veggie Wrap", "Comes with a side of \x02burger\x03 sauce")
+
+		ctx.mockSQLMock.ExpectQuery("WITH search_config (.+) FROM products").
+			WillReturnRows(rows)
+
+	case scenarioSearchWithoutMatches:
+		ctx.mockSQLMock.ExpectQuery("WITH search_config (.+) FROM products").
+			WillReturnRows(sqlmock.NewRows(columns))
+	}
+}
+
+// ===== Then Steps =====
+
+func (s *SearchProductsSteps) getSearchResponse() (contracts.ProductSearchResponse, error) {
+	ctx := GetTestContext()
+	searchResponse, ok := ctx.responseBody.(contracts.ProductSearchResponse)
+	if !ok {
+		return contracts.ProductSearchResponse{}, fmt.Errorf("expected ProductSearchResponse, got: %T", ctx.responseBody)
+	}
+	return searchResponse, nil
+}
+
+func (s *SearchProductsSteps) theSearchResultsShouldBeRankedByRelevance() error {
+	searchResponse, err := s.getSearchResponse()
+	if err != nil {
+		return err
+	}
+	if len(searchResponse.Results) == 0 {
+		return fmt.Errorf("expected search results, got none")
+	}
+	for i := 1; i < len(searchResponse.Results); i++ {
+		if searchResponse.Results[i].Rank > searchResponse.Results[i-1].Rank {
+			return fmt.Errorf("expected results in descending rank order, got %v after %v",
+				searchResponse.Results[i].Rank, searchResponse.Results[i-1].Rank)
+		}
+	}
+	return nil
+}
+
+func (s *SearchProductsSteps) theSearchResultsShouldContainHighlightedNames() error {
+	searchResponse, err := s.getSearchResponse()
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(searchResponse.Results[0].NameHighlight, "<mark>") {
+		return fmt.Errorf("expected highlighted name, got %q", searchResponse.Results[0].NameHighlight)
+	}
+	return nil
+}
+
+func (s *SearchProductsSteps) theSearchResultsShouldBeEmpty() error {
+	searchResponse, err := s.getSearchResponse()
+	if err != nil {
+		return err
+	}
+	if len(searchResponse.Results) != 0 {
+		return fmt.Errorf("expected no search results, got %d", len(searchResponse.Results))
+	}
+	if searchResponse.HasMore {
+		return fmt.Errorf("expected hasMore to be false, got true")
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (s *SearchProductsSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^a shop with ID (\d+) has products matching "([^"]*)"$`, s.aShopHasProductsMatching)
+	sc.Step(`^a shop with ID (\d+) has no products matching "([^"]*)"$`, s.aShopHasNoProductsMatching)
+
+	// When steps
+	sc.Step(`^I search products of shop (\d+) for "([^"]*)"$`, s.iSearchProductsOfShopFor)
+	sc.Step(`^I search products of shop (\d+) for "([^"]*)" in language "([^"]*)"$`, s.iSearchProductsOfShopForInLanguage)
+
+	// Then steps
+	sc.Step(`^the search results should be ranked by relevance$`, s.theSearchResultsShouldBeRankedByRelevance)
+	sc.Step(`^the search results should contain highlighted names$`, s.theSearchResultsShouldContainHighlightedNames)
+	sc.Step(`^the search results should be empty$`, s.theSearchResultsShouldBeEmpty)
+}
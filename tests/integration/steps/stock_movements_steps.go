@@ -0,0 +1,311 @@
+package steps
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+)
+
+type StockMovementsSteps struct {
+	stock          int
+	minimumStock   int
+	alreadyAlerted bool
+	lowStockCount  int
+	productMissing bool
+	lockProduct    bool
+	storedCount    int
+	movement       *models.StockMovement
+	movements      *contracts.StockMovementsResponse
+	report         *contracts.LowStockReportResponse
+}
+
+func NewStockMovementsSteps() *StockMovementsSteps {
+	return &StockMovementsSteps{}
+}
+
+// ===== Given Steps =====
+
+func (s *StockMovementsSteps) aProductWithIDAndStock(_, stock int) error {
+	s.stock = stock
+	s.lockProduct = true
+	return nil
+}
+
+func (s *StockMovementsSteps) aProductWithIDStockAndMinimumStock(id, stock, minimumStock int) error {
+	s.minimumStock = minimumStock
+	return s.aProductWithIDAndStock(id, stock)
+}
+
+func (s *StockMovementsSteps) aLowStockAlertWasAlreadySentForProduct(_ int) error {
+	s.alreadyAlerted = true
+	return nil
+}
+
+func (s *StockMovementsSteps) shopHasLowStockProducts(_, count int) error {
+	s.lowStockCount = count
+	return nil
+}
+
+func (s *StockMovementsSteps) noProductWithIDToAdjust(_ int) error {
+	s.productMissing = true
+	s.lockProduct = true
+	return nil
+}
+
+func (s *StockMovementsSteps) noProductWithIDToList(_ int) error {
+	s.productMissing = true
+	return nil
+}
+
+func (s *StockMovementsSteps) productHasStockMovements(_, count int) error {
+	s.storedCount = count
+	return nil
+}
+
+// ===== When Steps =====
+
+func (s *StockMovementsSteps) iRecordTheStockMovementForProduct(body string, productID int) error {
+	ctx := GetTestContext()
+	if err := s.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	// Only the staff of the product shop gets past the route to the stock lock
+	caller := ctx.caller(shopOwnerID)
+	if caller != 0 {
+		ctx.expectProductStaff(productID)
+	}
+	staff := caller == shopOwnerID && productID <= 90
+
+	if staff && s.lockProduct {
+		var request contracts.StockAdjustmentRequest
+		if err := json.Unmarshal([]byte(body), &request); err != nil {
+			return err
+		}
+
+		ctx.mockSQLMock.ExpectBegin()
+		lock := ctx.mockSQLMock.ExpectQuery("SELECT stock FROM products").WithArgs(productID)
+		switch {
+		case s.productMissing:
+			// Deleted after the staff check
+			lock.WillReturnError(sql.ErrNoRows)
+			ctx.mockSQLMock.ExpectRollback()
+		case s.stock+request.Quantity < 0:
+			lock.WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(s.stock))
+			ctx.mockSQLMock.ExpectRollback()
+		default:
+			lock.WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(s.stock))
+			ctx.mockSQLMock.ExpectExec("UPDATE products").
+				WithArgs(productID, s.stock+request.Quantity).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			ctx.mockSQLMock.ExpectQuery("INSERT INTO stock_movements").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, time.Now()))
+			ctx.mockSQLMock.ExpectCommit()
+
+			var alert *models.LowStockProduct
+			stockAfter := s.stock + request.Quantity
+			if stockAfter <= s.minimumStock && !s.alreadyAlerted {
+				alert = &models.LowStockProduct{ProductID: productID, ShopID: 1, Name: "Classic Burger", Stock: stockAfter, MinimumStock: s.minimumStock}
+			}
+			ctx.expectLowStockCheck(productID, alert)
+		}
+	}
+
+	return s.sendRequest(ctx, http.MethodPost, fmt.Sprintf("/products/%d/stock-movements", productID), body)
+}
+
+func (s *StockMovementsSteps) iRequestTheStockMovementsOfProductWithLimit(productID, limit int) error {
+	ctx := GetTestContext()
+	if err := s.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	ctx.mockSQLMock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM products`).
+		WithArgs(productID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(!s.productMissing))
+
+	if !s.productMissing {
+		rows := sqlmock.NewRows([]string{"id", "product_id", "reason", "quantity", "stock_after", "actor", "reference", "created_at"})
+		stock := s.storedCount * 5
+		for i := 0; i < s.storedCount; i++ {
+			rows.AddRow(s.storedCount-i, productID, "restock", 5, stock, "warehouse", "", time.Now())
+			stock -= 5
+		}
+		// Pages are capped at 100 movements
+		ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM stock_movements").
+			WithArgs(productID, 0, min(limit, 100)).
+			WillReturnRows(rows)
+	}
+
+	return s.sendRequest(ctx, http.MethodGet, fmt.Sprintf("/products/%d/stock-movements?limit=%d", productID, limit), "")
+}
+
+func (s *StockMovementsSteps) iRequestTheStockMovementsOfProductWithCursor(productID int, cursor string) error {
+	ctx := GetTestContext()
+	if err := s.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	return s.sendRequest(ctx, http.MethodGet, fmt.Sprintf("/products/%d/stock-movements?cursor=%s", productID, cursor), "")
+}
+
+func (s *StockMovementsSteps) iRequestTheLowStockReportOfShop(shopID int) error {
+	ctx := GetTestContext()
+	if err := s.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "shop_id", "name", "stock", "minimum_stock", "low_stock_alerted_at"})
+	for i := 1; i <= s.lowStockCount; i++ {
+		rows.AddRow(i, shopID, fmt.Sprintf("Product %d", i), i, 5, nil)
+	}
+	ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM products").
+		WithArgs(shopID).
+		WillReturnRows(rows)
+
+	return s.sendRequest(ctx, http.MethodGet, fmt.Sprintf("/shops/%d/inventory/low-stock", shopID), "")
+}
+
+func (s *StockMovementsSteps) setupTestApp(ctx *TestContext) error {
+	if ctx.app == nil {
+		return ctx.SetupProductTestApp()
+	}
+	return nil
+}
+
+func (s *StockMovementsSteps) sendRequest(ctx *TestContext, method, path, body string) error {
+	req, err := http.NewRequest(method, ctx.server.URL+path, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := ctx.authorize(req, shopOwnerID); err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage = errorResponse["error"]
+		}
+		return nil
+	}
+
+	if method == http.MethodPost {
+		s.movement = &models.StockMovement{}
+		return json.NewDecoder(resp.Body).Decode(s.movement)
+	}
+
+	if strings.HasPrefix(path, "/shops/") {
+		s.report = &contracts.LowStockReportResponse{}
+		return json.NewDecoder(resp.Body).Decode(s.report)
+	}
+
+	s.movements = &contracts.StockMovementsResponse{}
+	return json.NewDecoder(resp.Body).Decode(s.movements)
+}
+
+// ===== Then Steps =====
+
+func (s *StockMovementsSteps) theStockMovementShouldLeaveUnitsInStock(expected int) error {
+	if s.movement == nil || s.movement.StockAfter != expected {
+		return fmt.Errorf("expected stock after movement %d, got %+v", expected, s.movement)
+	}
+	return nil
+}
+
+func (s *StockMovementsSteps) theStockMovementShouldBeRecordedBy(actor string) error {
+	if s.movement == nil || s.movement.Actor != actor {
+		return fmt.Errorf("expected the stock movement to be recorded by %q, got %+v", actor, s.movement)
+	}
+	return nil
+}
+
+func (s *StockMovementsSteps) iShouldReceiveStockMovements(expected int) error {
+	if s.movements == nil || len(s.movements.Movements) != expected {
+		return fmt.Errorf("expected %d stock movements, got %+v", expected, s.movements)
+	}
+	return nil
+}
+
+func (s *StockMovementsSteps) theStockMovementsQueryShouldBeCapped() error {
+	return GetTestContext().mockSQLMock.ExpectationsWereMet()
+}
+
+func (s *StockMovementsSteps) theStockMovementsShouldHaveANextCursor() error {
+	if s.movements == nil || !s.movements.HasMore || s.movements.NextCursor == "" {
+		return fmt.Errorf("expected a next cursor, got %+v", s.movements)
+	}
+	return nil
+}
+
+func (s *StockMovementsSteps) aLowStockAlertShouldBeSentForProduct(productID int) error {
+	alerts := GetTestContext().notifier.sent(1)
+	if len(alerts) != 1 || alerts[0].ProductID != productID {
+		return fmt.Errorf("expected one low stock alert for product %d, got %+v", productID, alerts)
+	}
+	return nil
+}
+
+func (s *StockMovementsSteps) noLowStockAlertShouldBeSent() error {
+	// Alerts are only delivered after a successful claim, which the request already ran
+	if alerts := GetTestContext().notifier.sent(0); len(alerts) != 0 {
+		return fmt.Errorf("expected no low stock alert, got %+v", alerts)
+	}
+	return nil
+}
+
+func (s *StockMovementsSteps) theLowStockReportShouldContainProducts(expected int) error {
+	if s.report == nil || len(s.report.Products) != expected {
+		return fmt.Errorf("expected %d low stock products, got %+v", expected, s.report)
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (s *StockMovementsSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^a product with id (\d+) and stock (\d+)$`, s.aProductWithIDAndStock)
+	sc.Step(`^a product with id (\d+), stock (\d+) and minimum stock (\d+)$`, s.aProductWithIDStockAndMinimumStock)
+	sc.Step(`^a low stock alert was already sent for product (\d+)$`, s.aLowStockAlertWasAlreadySentForProduct)
+	sc.Step(`^shop (\d+) has (\d+) products below their minimum stock$`, s.shopHasLowStockProducts)
+	sc.Step(`^no product with id (\d+) to adjust$`, s.noProductWithIDToAdjust)
+	sc.Step(`^no product with id (\d+) to list$`, s.noProductWithIDToList)
+	sc.Step(`^product (\d+) has (\d+) stock movements$`, s.productHasStockMovements)
+
+	// When steps
+	sc.Step(`^I record the stock movement '([^']*)' for product (\d+)$`, s.iRecordTheStockMovementForProduct)
+	sc.Step(`^I request the stock movements of product (\d+) with limit (\d+)$`, s.iRequestTheStockMovementsOfProductWithLimit)
+	sc.Step(`^I request the stock movements of product (\d+) with cursor "([^"]*)"$`, s.iRequestTheStockMovementsOfProductWithCursor)
+	sc.Step(`^I request the low stock report of shop (\d+)$`, s.iRequestTheLowStockReportOfShop)
+
+	// Then steps
+	sc.Step(`^the stock movement should leave (\d+) units in stock$`, s.theStockMovementShouldLeaveUnitsInStock)
+	sc.Step(`^the stock movement should be recorded by "([^"]*)"$`, s.theStockMovementShouldBeRecordedBy)
+	sc.Step(`^I should receive (\d+) stock movements$`, s.iShouldReceiveStockMovements)
+	sc.Step(`^the stock movements should have a next cursor$`, s.theStockMovementsShouldHaveANextCursor)
+	sc.Step(`^at most 100 stock movements should be requested$`, s.theStockMovementsQueryShouldBeCapped)
+	sc.Step(`^a low stock alert should be sent for product (\d+)$`, s.aLowStockAlertShouldBeSentForProduct)
+	sc.Step(`^no low stock alert should be sent$`, s.noLowStockAlertShouldBeSent)
+	sc.Step(`^the low stock report should contain (\d+) products$`, s.theLowStockReportShouldContainProducts)
+}
@@ -5,18 +5,30 @@ import (
 	"database/sql"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gorilla/mux"
 	"go.uber.org/fx"
 
 	"github.com/mlgaray/ecommerce_api/internal/application/services"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/asset"
 	"github.com/mlgaray/ecommerce_api/internal/application/usecases/auth"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/cart"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/order"
 	"github.com/mlgaray/ecommerce_api/internal/application/usecases/product"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/stock"
+	"github.com/mlgaray/ecommerce_api/internal/application/usecases/upload"
+	domainErrors "github.com/mlgaray/ecommerce_api/internal/core/errors"
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
 	"github.com/mlgaray/ecommerce_api/internal/core/ports"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/assets"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/auth/jwt"
 	authhttp "github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/middleware"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/images"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/logs"
 	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/repositories/postgresql"
 )
@@ -30,6 +42,34 @@ func (m *mockDataBaseConnection) Connect() *sql.DB {
 	return m.db
 }
 
+// recordingNotifier implements ports.Notifier and keeps the alerts sent during a scenario
+// Alerts are delivered in the background, so readers wait for them with sent
+type recordingNotifier struct {
+	mu       sync.Mutex
+	lowStock []*models.LowStockProduct
+}
+
+func (n *recordingNotifier) NotifyLowStock(_ context.Context, product *models.LowStockProduct) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.lowStock = append(n.lowStock, product)
+	return nil
+}
+
+// sent waits up to a second for the expected number of alerts and returns the alerts delivered so far
+func (n *recordingNotifier) sent(expected int) []*models.LowStockProduct {
+	deadline := time.Now().Add(time.Second)
+	for {
+		n.mu.Lock()
+		alerts := append([]*models.LowStockProduct(nil), n.lowStock...)
+		n.mu.Unlock()
+		if len(alerts) >= expected || time.Now().After(deadline) {
+			return alerts
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 // TestContext contiene todo el estado compartido entre tests
 type TestContext struct {
 	// HTTP
@@ -46,6 +86,7 @@ type TestContext struct {
 	// Messages
 	successMessage string
 	errorMessage   string
+	fieldErrors    []domainErrors.FieldError
 
 	// Product-specific fields (para multipart/form-data)
 	productImages    [][]byte
@@ -54,11 +95,100 @@ type TestContext struct {
 	// Test control
 	scenario string
 
+	// Caller chosen by the scenario; otherwise each request signs in as its usual caller
+	callerSet bool
+	callerID  int // 0 is an anonymous caller
+
 	// SQL Mock
 	mockDB      *sql.DB
 	mockSQLMock sqlmock.Sqlmock
+
+	// Alerts
+	notifier *recordingNotifier
+
+	// Assets stored by the local storage during the scenario
+	assetsDir string
+	// S3-compatible fake used instead of the local storage when set
+	s3         *fakeS3Server
+	s3PartSize int
+}
+
+// expectLowStockCheck mocks the low-stock alert check that follows a stock change
+// A nil product means the stock is fine or the alert was already sent
+func (ctx *TestContext) expectLowStockCheck(productID int, product *models.LowStockProduct) {
+	ctx.mockSQLMock.ExpectExec("UPDATE products SET low_stock_alerted_at = NULL").
+		WithArgs(productID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"id", "shop_id", "name", "stock", "minimum_stock", "low_stock_alerted_at"})
+	if product != nil {
+		rows.AddRow(product.ProductID, product.ShopID, product.Name, product.Stock, product.MinimumStock, time.Now())
+	}
+	ctx.mockSQLMock.ExpectQuery("UPDATE products SET low_stock_alerted_at = now()").
+		WithArgs(productID).
+		WillReturnRows(rows)
+}
+
+// shopOwnerID is the user that owns shop 1 and runs it
+const shopOwnerID = 1
+
+// expectShopStaff answers the shop ownership check of the access service
+func (ctx *TestContext) expectShopStaff(shopID, userID int) {
+	ctx.mockSQLMock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs(shopID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(shopID == 1 && userID == shopOwnerID))
+}
+
+// expectProductStaff answers the product owner check of the access service; products
+// above 90 do not exist and the rest belong to the shop of shopOwnerID
+func (ctx *TestContext) expectProductStaff(productID int) {
+	rows := sqlmock.NewRows([]string{"user_id"})
+	if productID <= 90 {
+		rows.AddRow(shopOwnerID)
+	}
+	ctx.mockSQLMock.ExpectQuery(`SELECT s.user_id FROM products p JOIN shops s`).
+		WithArgs(productID).
+		WillReturnRows(rows)
+}
+
+// platformAdminID is a user granted the platform admin role
+const platformAdminID = 42
+
+// signIn sends the request with the bearer token of the given user
+func signIn(req *http.Request, userID int) error {
+	user := &models.User{ID: userID}
+	if userID == platformAdminID {
+		user.Roles = []*models.Role{{Name: models.PlatformAdminRole}}
+	}
+
+	token, err := jwt.NewTokenService().Generate(context.Background(), user)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// caller returns the caller chosen by the scenario, or defaultUserID; 0 is anonymous
+func (ctx *TestContext) caller(defaultUserID int) int {
+	if ctx.callerSet {
+		return ctx.callerID
+	}
+	return defaultUserID
+}
+
+// authorize signs the request in as the caller chosen by the scenario, or as defaultUserID
+func (ctx *TestContext) authorize(req *http.Request, defaultUserID int) error {
+	userID := ctx.caller(defaultUserID)
+	if userID == 0 {
+		return nil
+	}
+	return signIn(req, userID)
 }
 
+// testImageRenditions are small so the test images (100x100) are scaled down
+var testImageRenditions = []images.RenditionSpec{{Name: "thumb", MaxSize: 20}, {Name: "medium", MaxSize: 50}}
+
 // Global test context instance
 var testCtx *TestContext
 
@@ -81,11 +211,24 @@ func (ctx *TestContext) Reset() {
 	ctx.pathParams = nil
 	ctx.successMessage = ""
 	ctx.errorMessage = ""
+	ctx.fieldErrors = nil
 	ctx.productImages = nil
 	ctx.invalidImageType = false
 	ctx.scenario = ""
+	ctx.callerSet = false
+	ctx.callerID = 0
+	ctx.notifier = nil
 
 	// Close existing resources
+	if ctx.assetsDir != "" {
+		os.RemoveAll(ctx.assetsDir)
+		ctx.assetsDir = ""
+	}
+	if ctx.s3 != nil {
+		ctx.s3.Close()
+		ctx.s3 = nil
+	}
+	ctx.s3PartSize = 0
 	if ctx.mockDB != nil {
 		ctx.mockDB.Close()
 		ctx.mockDB = nil
@@ -170,6 +313,13 @@ func (ctx *TestContext) SetupProductTestApp() error {
 	}
 	ctx.mockDB = db
 	ctx.mockSQLMock = sqlMock
+	ctx.notifier = &recordingNotifier{}
+
+	// Setup a temporary local asset storage
+	ctx.assetsDir, err = os.MkdirTemp("", "ecommerce-assets-*")
+	if err != nil {
+		return err
+	}
 
 	// Create FX app with real services but mocked DB
 	ctx.app = fx.New(
@@ -182,29 +332,141 @@ func (ctx *TestContext) SetupProductTestApp() error {
 			// Provide product dependencies
 			fx.Annotate(services.NewProductService, fx.As(new(ports.ProductService))),
 			fx.Annotate(postgresql.NewProductRepository, fx.As(new(ports.ProductRepository))),
+			fx.Annotate(services.NewPromotionService, fx.As(new(ports.PromotionService))),
+			fx.Annotate(postgresql.NewPromotionRepository, fx.As(new(ports.PromotionRepository))),
+			fx.Annotate(services.NewStockService, fx.As(new(ports.StockService))),
+			fx.Annotate(postgresql.NewStockRepository, fx.As(new(ports.StockRepository))),
+			fx.Annotate(services.NewInventoryService, fx.As(new(ports.InventoryService))),
+			fx.Annotate(services.NewUploadService, fx.As(new(ports.UploadService))),
+			fx.Annotate(postgresql.NewUploadRepository, fx.As(new(ports.UploadRepository))),
+			fx.Annotate(services.NewAssetGCService, fx.As(new(ports.AssetGCService))),
+			fx.Annotate(postgresql.NewAssetReferenceRepository, fx.As(new(ports.AssetReferenceRepository))),
+			fx.Annotate(services.NewCartService, fx.As(new(ports.CartService))),
+			fx.Annotate(postgresql.NewCartRepository, fx.As(new(ports.CartRepository))),
+			fx.Annotate(services.NewOrderService, fx.As(new(ports.OrderService))),
+			fx.Annotate(postgresql.NewOrderRepository, fx.As(new(ports.OrderRepository))),
+			fx.Annotate(services.NewAccessService, fx.As(new(ports.AccessService))),
+			fx.Annotate(postgresql.NewShopRepository, fx.As(new(ports.ShopRepository))),
+			fx.Annotate(jwt.NewTokenService, fx.As(new(ports.TokenService))),
+			middleware.NewAuth,
+			func() ports.Notifier {
+				return ctx.notifier
+			},
+			func() ports.AssetService {
+				if ctx.s3 != nil {
+					// Images are proxied through /assets/ as a CDN would
+					return assets.NewS3AssetService(assets.S3Config{
+						Endpoint:        ctx.s3.URL(),
+						Bucket:          fakeS3Bucket,
+						Prefix:          fakeS3Prefix,
+						AccessKeyID:     fakeS3AccessKeyID,
+						SecretAccessKey: fakeS3SecretAccessKey,
+						BaseURL:         "/assets",
+						PartSize:        ctx.s3PartSize,
+					})
+				}
+				return assets.NewLocalAssetService(ctx.assetsDir, "/assets")
+			},
+			func() ports.ImageProcessor {
+				return images.NewImageProcessorWith(testImageRenditions, 2)
+			},
 
 			// Provide pagination service
 			fx.Annotate(
 				services.NewPaginationService[*models.Product],
 				fx.As(new(ports.PaginationService[*models.Product])),
 			),
+			fx.Annotate(
+				services.NewPaginationService[*models.ProductSearchResult],
+				fx.As(new(ports.PaginationService[*models.ProductSearchResult])),
+			),
+			fx.Annotate(
+				services.NewPaginationService[*models.StockMovement],
+				fx.As(new(ports.PaginationService[*models.StockMovement])),
+			),
+			fx.Annotate(
+				services.NewPaginationService[*models.Order],
+				fx.As(new(ports.PaginationService[*models.Order])),
+			),
 
 			// Provide use cases
 			fx.Annotate(product.NewCreateProductUseCase, fx.As(new(ports.CreateProductUseCase))),
 			fx.Annotate(product.NewGetAllByShopIDUseCase, fx.As(new(ports.GetAllByShopIDUseCase))),
 			fx.Annotate(product.NewGetByIDUseCase, fx.As(new(ports.GetByIDUseCase))),
 			fx.Annotate(product.NewUpdateProductUseCase, fx.As(new(ports.UpdateProductUseCase))),
+			fx.Annotate(product.NewPatchProductUseCase, fx.As(new(ports.PatchProductUseCase))),
+			fx.Annotate(product.NewSearchProductsUseCase, fx.As(new(ports.SearchProductsUseCase))),
+			fx.Annotate(product.NewDeleteProductUseCase, fx.As(new(ports.DeleteProductUseCase))),
+			fx.Annotate(product.NewRestoreProductUseCase, fx.As(new(ports.RestoreProductUseCase))),
+			fx.Annotate(product.NewPurgeProductsUseCase, fx.As(new(ports.PurgeProductsUseCase))),
+			fx.Annotate(product.NewQuoteProductUseCase, fx.As(new(ports.QuoteProductUseCase))),
+			fx.Annotate(product.NewReorderProductImagesUseCase, fx.As(new(ports.ReorderProductImagesUseCase))),
+			fx.Annotate(product.NewCreatePromotionUseCase, fx.As(new(ports.CreatePromotionUseCase))),
+			fx.Annotate(product.NewDeletePromotionUseCase, fx.As(new(ports.DeletePromotionUseCase))),
+			fx.Annotate(stock.NewAdjustStockUseCase, fx.As(new(ports.AdjustStockUseCase))),
+			fx.Annotate(stock.NewGetStockMovementsUseCase, fx.As(new(ports.GetStockMovementsUseCase))),
+			fx.Annotate(stock.NewGetLowStockReportUseCase, fx.As(new(ports.GetLowStockReportUseCase))),
+			fx.Annotate(upload.NewCreateUploadUseCase, fx.As(new(ports.CreateUploadUseCase))),
+			fx.Annotate(upload.NewFinalizeUploadUseCase, fx.As(new(ports.FinalizeUploadUseCase))),
+			fx.Annotate(upload.NewPurgeUploadsUseCase, fx.As(new(ports.PurgeUploadsUseCase))),
+			fx.Annotate(asset.NewCollectOrphanedAssetsUseCase, fx.As(new(ports.CollectOrphanedAssetsUseCase))),
+			fx.Annotate(cart.NewGetCartUseCase, fx.As(new(ports.GetCartUseCase))),
+			fx.Annotate(cart.NewAddCartLineUseCase, fx.As(new(ports.AddCartLineUseCase))),
+			fx.Annotate(cart.NewUpdateCartLineUseCase, fx.As(new(ports.UpdateCartLineUseCase))),
+			fx.Annotate(cart.NewRemoveCartLineUseCase, fx.As(new(ports.RemoveCartLineUseCase))),
+			fx.Annotate(cart.NewClearCartUseCase, fx.As(new(ports.ClearCartUseCase))),
+			fx.Annotate(cart.NewPurgeCartsUseCase, fx.As(new(ports.PurgeCartsUseCase))),
+			fx.Annotate(order.NewCreateOrderUseCase, fx.As(new(ports.CreateOrderUseCase))),
+			fx.Annotate(order.NewGetOrderUseCase, fx.As(new(ports.GetOrderUseCase))),
+			fx.Annotate(order.NewGetShopOrdersUseCase, fx.As(new(ports.GetShopOrdersUseCase))),
+			fx.Annotate(order.NewGetCustomerOrdersUseCase, fx.As(new(ports.GetCustomerOrdersUseCase))),
+			fx.Annotate(order.NewTransitionOrderUseCase, fx.As(new(ports.TransitionOrderUseCase))),
 
-			// Provide handler
+			// Provide handlers
 			authhttp.NewProductHandler,
+			authhttp.NewPromotionHandler,
+			authhttp.NewStockHandler,
+			authhttp.NewAssetHandler,
+			authhttp.NewUploadHandler,
+			authhttp.NewCartHandler,
+			authhttp.NewOrderHandler,
 		),
-		fx.Invoke(func(handler *authhttp.ProductHandler) {
+		fx.Invoke(func(handler *authhttp.ProductHandler, promotionHandler *authhttp.PromotionHandler, stockHandler *authhttp.StockHandler, assetHandler *authhttp.AssetHandler, uploadHandler *authhttp.UploadHandler, cartHandler *authhttp.CartHandler, orderHandler *authhttp.OrderHandler, auth *middleware.Auth) {
 			// Create HTTP router and server
 			router := mux.NewRouter()
+			router.Use(auth.Authenticate)
 			router.HandleFunc("/products", handler.Create).Methods("POST")
 			router.HandleFunc("/shops/{shop_id}/products", handler.GetAllByShopID).Methods("GET")
+			router.HandleFunc("/shops/{shop_id}/products/search", handler.Search).Methods("GET")
 			router.HandleFunc("/products/{product_id}", handler.GetByID).Methods("GET")
 			router.HandleFunc("/products/{product_id}", handler.Update).Methods("PUT")
+			router.HandleFunc("/products/{product_id}", handler.Patch).Methods("PATCH")
+			router.HandleFunc("/products/{product_id}", auth.RequireProductStaff(handler.Delete)).Methods("DELETE")
+			router.HandleFunc("/products/{product_id}/restore", auth.RequireProductStaff(handler.Restore)).Methods("POST")
+			router.HandleFunc("/products/{product_id}/quote", handler.Quote).Methods("POST")
+			router.HandleFunc("/products/{product_id}/images/order", handler.ReorderImages).Methods("PUT")
+			router.HandleFunc("/admin/products/purge", auth.RequirePlatformAdmin(handler.PurgeDeleted)).Methods("POST")
+			router.HandleFunc("/products/{product_id}/promotions", promotionHandler.Create).Methods("POST")
+			router.HandleFunc("/products/{product_id}/promotions/{promotion_id}", promotionHandler.Delete).Methods("DELETE")
+			router.HandleFunc("/products/{product_id}/stock-movements", auth.RequireProductStaff(stockHandler.AdjustStock)).Methods("POST")
+			router.HandleFunc("/products/{product_id}/stock-movements", stockHandler.GetMovements).Methods("GET")
+			router.HandleFunc("/shops/{shop_id}/inventory/low-stock", stockHandler.GetLowStockReport).Methods("GET")
+			router.HandleFunc("/assets/{key}", assetHandler.Serve).Methods("GET")
+			router.HandleFunc("/uploads", uploadHandler.Create).Methods("POST")
+			router.HandleFunc("/uploads/{upload_id}/finalize", uploadHandler.Finalize).Methods("POST")
+			router.HandleFunc("/admin/uploads/purge", auth.RequirePlatformAdmin(uploadHandler.PurgeExpired)).Methods("POST")
+			router.HandleFunc("/admin/assets/gc", auth.RequirePlatformAdmin(assetHandler.CollectOrphans)).Methods("POST")
+			router.HandleFunc("/shops/{shop_id}/cart", cartHandler.Get).Methods("GET")
+			router.HandleFunc("/shops/{shop_id}/cart", cartHandler.Clear).Methods("DELETE")
+			router.HandleFunc("/shops/{shop_id}/cart/lines", cartHandler.AddLine).Methods("POST")
+			router.HandleFunc("/shops/{shop_id}/cart/lines/{line_id}", cartHandler.UpdateLine).Methods("PATCH")
+			router.HandleFunc("/shops/{shop_id}/cart/lines/{line_id}", cartHandler.RemoveLine).Methods("DELETE")
+			router.HandleFunc("/admin/carts/purge", auth.RequirePlatformAdmin(cartHandler.PurgeExpired)).Methods("POST")
+			router.HandleFunc("/shops/{shop_id}/orders", orderHandler.Create).Methods("POST")
+			router.HandleFunc("/shops/{shop_id}/orders", auth.RequireShopStaff(orderHandler.GetShopOrders)).Methods("GET")
+			router.HandleFunc("/orders", auth.RequireUser(orderHandler.GetCustomerOrders)).Methods("GET")
+			router.HandleFunc("/orders/{order_id}", auth.RequireUser(orderHandler.GetByID)).Methods("GET")
+			router.HandleFunc("/orders/{order_id}/transitions", orderHandler.Transition).Methods("POST")
 
 			ctx.server = httptest.NewServer(router)
 		}),
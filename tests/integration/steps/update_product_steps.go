@@ -10,12 +10,15 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/cucumber/godog"
+	"github.com/lib/pq"
 
+	domainErrors "github.com/mlgaray/ecommerce_api/internal/core/errors"
 	"github.com/mlgaray/ecommerce_api/internal/core/models"
 )
 
 const (
-	validUpdateScenario = "valid-update"
+	validUpdateScenario         = "valid-update"
+	priceBelowPromotionScenario = "price-below-promotion"
 )
 
 type UpdateProductSteps struct{}
@@ -31,6 +34,13 @@ func (u *UpdateProductSteps) setupSQLExpectations() {
 		// Mock successful product update via stored procedure
 		ctx.mockSQLMock.ExpectExec("SELECT update_product").
 			WillReturnResult(sqlmock.NewResult(0, 1))
+		ctx.expectLowStockCheck(ctx.requestBody.(models.Product).ID, nil)
+	}
+
+	if ctx.scenario == priceBelowPromotionScenario {
+		// The stored procedure rejects the price once the product row is locked
+		ctx.mockSQLMock.ExpectExec("SELECT update_product").
+			WillReturnError(&pq.Error{Code: "23001", Message: "promotional_price_must_be_lower_than_regular_price"})
 	}
 }
 
@@ -42,7 +52,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndValidUpdateData(productID int
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -68,7 +78,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndKeepExistingImages(productID
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -96,7 +106,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndAddNewImages(productID int) e
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -122,7 +132,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndNoImages(productID int) error
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -146,7 +156,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndEmptyName(productID int) erro
 		ID:           productID,
 		Name:         "", // Empty name
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -171,7 +181,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndEmptyDescription(productID in
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "", // Empty description
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -196,7 +206,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndNoCategory(productID int) err
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     nil, // No category
@@ -221,7 +231,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndInvalidShopID(productID int)
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -246,7 +256,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndOversizedNewImage(productID i
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -255,13 +265,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndOversizedNewImage(productID i
 		},
 	}
 
-	// Create oversized image (> 3MB)
-	oversizedImage := make([]byte, 3*1024*1024+1024)
-	for i := range oversizedImage {
-		oversizedImage[i] = byte(i % 256)
-	}
-
-	ctx.productImages = [][]byte{oversizedImage}
+	ctx.productImages = [][]byte{createOversizedImage()}
 	if ctx.pathParams == nil {
 		ctx.pathParams = make(map[string]string)
 	}
@@ -278,7 +282,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndInvalidNewImageType(productID
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -305,7 +309,7 @@ func (u *UpdateProductSteps) iHaveAnInvalidProductID(invalidID string) error {
 	ctx.requestBody = models.Product{
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -331,7 +335,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndNegativePrice(productID int)
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        -10.00, // Negative price
+		Price:        models.MoneyFromFloat(-10.00), // Negative price
 		Stock:        20,
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -356,7 +360,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndNegativeStock(productID int)
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        -5, // Negative stock
 		MinimumStock: 5,
 		Category:     &models.Category{ID: 1},
@@ -381,7 +385,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAndNegativeMinimumStock(productI
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        20,
 		MinimumStock: -5, // Negative minimum stock
 		Category:     &models.Category{ID: 1},
@@ -406,7 +410,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDWithMinimumStockButNoStock(produ
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        0, // No stock
 		MinimumStock: 5, // But has minimum stock
 		Category:     &models.Category{ID: 1},
@@ -431,7 +435,7 @@ func (u *UpdateProductSteps) iHaveAProductWithIDWithMinimumStockGreaterThanStock
 		ID:           productID,
 		Name:         "Updated Product",
 		Description:  "Updated Description",
-		Price:        149.99,
+		Price:        models.MoneyFromFloat(149.99),
 		Stock:        10, // Stock is 10
 		MinimumStock: 20, // But minimum stock is 20
 		Category:     &models.Category{ID: 1},
@@ -456,12 +460,12 @@ func (u *UpdateProductSteps) iHaveAProductWithIDAsPromotionalWithoutPromotionalP
 		ID:               productID,
 		Name:             "Updated Product",
 		Description:      "Updated Description",
-		Price:            149.99,
+		Price:            models.MoneyFromFloat(149.99),
 		Stock:            20,
 		MinimumStock:     5,
 		Category:         &models.Category{ID: 1},
-		IsPromotional:    true, // Is promotional
-		PromotionalPrice: 0,    // But no promotional price
+		IsPromotional:    true,                     // Is promotional
+		PromotionalPrice: models.MoneyFromFloat(0), // But no promotional price
 		Images: []models.ProductImage{
 			{ID: 1, URL: "https://existing.com/image1.jpg"},
 		},
@@ -483,12 +487,12 @@ func (u *UpdateProductSteps) iHaveAProductWithIDWithPromotionalPriceNotLowerThan
 		ID:               productID,
 		Name:             "Updated Product",
 		Description:      "Updated Description",
-		Price:            100.00, // Regular price
+		Price:            models.MoneyFromFloat(100.00), // Regular price
 		Stock:            20,
 		MinimumStock:     5,
 		Category:         &models.Category{ID: 1},
 		IsPromotional:    true,
-		PromotionalPrice: 120.00, // Promotional price is higher!
+		PromotionalPrice: models.MoneyFromFloat(120.00), // Promotional price is higher!
 		Images: []models.ProductImage{
 			{ID: 1, URL: "https://existing.com/image1.jpg"},
 		},
@@ -503,6 +507,20 @@ func (u *UpdateProductSteps) iHaveAProductWithIDWithPromotionalPriceNotLowerThan
 	return nil
 }
 
+func (u *UpdateProductSteps) iHaveAProductWithIDPricedNotAboveAScheduledPromotion(productID int) error {
+	if err := u.iHaveAProductWithIDAndValidUpdateData(productID); err != nil {
+		return err
+	}
+
+	ctx := GetTestContext()
+	ctx.scenario = priceBelowPromotionScenario
+	product := ctx.requestBody.(models.Product)
+	product.Price = models.MoneyFromFloat(5.00) // A scheduled promotion sells it at 7.50
+	ctx.requestBody = product
+
+	return nil
+}
+
 func (u *UpdateProductSteps) iSendAnUpdateProductRequest() error {
 	ctx := GetTestContext()
 
@@ -625,9 +643,13 @@ func (u *UpdateProductSteps) parseResponse(ctx *TestContext, resp *http.Response
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		var errorResponse map[string]string
+		var errorResponse struct {
+			Error  string                    `json:"error"`
+			Fields []domainErrors.FieldError `json:"fields"`
+		}
 		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
-			ctx.errorMessage = errorResponse["error"]
+			ctx.errorMessage = errorResponse.Error
+			ctx.fieldErrors = errorResponse.Fields
 		}
 	} else {
 		var successResponse map[string]string
@@ -664,6 +686,7 @@ func (u *UpdateProductSteps) RegisterSteps(sc *godog.ScenarioContext) {
 	sc.Step(`^I have a product with id (\d+) with minimum stock greater than stock$`, u.iHaveAProductWithIDWithMinimumStockGreaterThanStock)
 	sc.Step(`^I have a product with id (\d+) as promotional without promotional price$`, u.iHaveAProductWithIDAsPromotionalWithoutPromotionalPrice)
 	sc.Step(`^I have a product with id (\d+) with promotional price not lower than price$`, u.iHaveAProductWithIDWithPromotionalPriceNotLowerThanPrice)
+	sc.Step(`^I have a product with id (\d+) priced not above a scheduled promotion$`, u.iHaveAProductWithIDPricedNotAboveAScheduledPromotion)
 
 	// Action steps
 	sc.Step(`^I send an update product request$`, u.iSendAnUpdateProductRequest)
@@ -0,0 +1,289 @@
+package steps
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+	"github.com/mlgaray/ecommerce_api/internal/infraestructure/adapters/http/contracts"
+)
+
+// testUploadID is the upload referenced by products in the scenarios that skip the upload itself
+const testUploadID = "0123456789abcdef0123456789abcdef"
+
+var uploadColumns = []string{"id", "status", "content_type", "size", "url", "renditions", "expires_at", "created_at"}
+
+type UploadSteps struct {
+	image   []byte
+	created *contracts.UploadCreateResponse
+	upload  *models.Upload
+	purged  *contracts.UploadPurgeResponse
+	expired int
+}
+
+func NewUploadSteps() *UploadSteps {
+	return &UploadSteps{}
+}
+
+// uploadRows returns the stored upload as the repository reads it
+func uploadRows(id string, status models.UploadStatus, size int, expiresAt time.Time) *sqlmock.Rows {
+	url, renditions := "", "{}"
+	if status != models.UploadAwaitingContent {
+		url = "/assets/" + strings.Repeat("a", 64) + ".jpg"
+		renditions = `{"original": "` + url + `"}`
+	}
+	return sqlmock.NewRows(uploadColumns).
+		AddRow(id, status, "image/png", size, url, []byte(renditions), expiresAt, time.Now())
+}
+
+// ===== Given Steps =====
+
+func (u *UploadSteps) iRequestedAnUploadURLForATestImage() error {
+	u.image = createTestImage()
+	if err := u.iRequestAnUploadURLForAnImageOfBytes("image/png", len(u.image)); err != nil {
+		return err
+	}
+	if u.created == nil {
+		return fmt.Errorf("expected an upload to be created, got status %d", GetTestContext().response.StatusCode)
+	}
+	return nil
+}
+
+func (u *UploadSteps) iHaveValidProductDataReferencingAFinalizedUpload() error {
+	ctx := GetTestContext()
+	if err := u.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	u.referenceUpload(ctx)
+	ctx.mockSQLMock.ExpectQuery("UPDATE uploads SET status").
+		WillReturnRows(uploadRows(testUploadID, models.UploadAttached, 2048, time.Now().Add(time.Hour)))
+	return nil
+}
+
+func (u *UploadSteps) iHaveValidProductDataReferencingAnExpiredUpload() error {
+	ctx := GetTestContext()
+	if err := u.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	// The claim skips the expired upload, which is then read to explain why
+	u.referenceUpload(ctx)
+	ctx.mockSQLMock.ExpectQuery("UPDATE uploads SET status").
+		WillReturnRows(sqlmock.NewRows(uploadColumns))
+	ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM uploads WHERE id").
+		WithArgs(testUploadID).
+		WillReturnRows(uploadRows(testUploadID, models.UploadPending, 2048, time.Now().Add(-time.Hour)))
+	return nil
+}
+
+// referenceUpload prepares a product whose only image is the test upload
+func (u *UploadSteps) referenceUpload(ctx *TestContext) {
+	ctx.scenario = validProductScenario
+	ctx.requestBody = models.Product{
+		Name:         "Test Product",
+		Description:  "Test Description",
+		Price:        models.MoneyFromFloat(99.99),
+		Stock:        10,
+		MinimumStock: 5,
+		Category:     &models.Category{ID: 1},
+		Images:       []models.ProductImage{{UploadID: testUploadID}},
+	}
+	ctx.productImages = nil
+	if ctx.pathParams == nil {
+		ctx.pathParams = make(map[string]string)
+	}
+	ctx.pathParams["shop_id"] = "1"
+}
+
+func (u *UploadSteps) thereAreExpiredUploads(count int) error {
+	u.expired = count
+	return nil
+}
+
+// ===== When Steps =====
+
+func (u *UploadSteps) iRequestAnUploadURLForAnImageOfBytes(contentType string, size int) error {
+	ctx := GetTestContext()
+	if err := u.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	ctx.mockSQLMock.ExpectExec("INSERT INTO uploads").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	body := fmt.Sprintf(`{"content_type": %q, "size": %d}`, contentType, size)
+	u.created = &contracts.UploadCreateResponse{}
+	if err := u.sendRequest(ctx, "/uploads", body, u.created, 0); err != nil {
+		return err
+	}
+	if ctx.response.StatusCode != http.StatusCreated {
+		u.created = nil
+	}
+	return nil
+}
+
+func (u *UploadSteps) iPutTheImageAtTheUploadURL() error {
+	req, err := http.NewRequest(u.created.Method, u.created.UploadURL, bytes.NewReader(u.image))
+	if err != nil {
+		return err
+	}
+	for name, value := range u.created.Headers {
+		if name != "Content-Length" {
+			req.Header.Set(name, value)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected the storage to accept the upload, got %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (u *UploadSteps) iFinalizeTheUpload() error {
+	ctx := GetTestContext()
+
+	ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM uploads WHERE id").
+		WithArgs(u.created.UploadID).
+		WillReturnRows(uploadRows(u.created.UploadID, models.UploadAwaitingContent, len(u.image), u.created.ExpiresAt))
+	ctx.mockSQLMock.ExpectExec("UPDATE uploads SET status").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	u.upload = &models.Upload{}
+	return u.sendRequest(ctx, "/uploads/"+u.created.UploadID+"/finalize", "", u.upload, 0)
+}
+
+func (u *UploadSteps) iPurgeTheExpiredUploads() error {
+	ctx := GetTestContext()
+	if err := u.setupTestApp(ctx); err != nil {
+		return err
+	}
+
+	rows := sqlmock.NewRows(uploadColumns)
+	for i := 0; i < u.expired; i++ {
+		id := fmt.Sprintf("%032x", i+1)
+		rows.AddRow(id, models.UploadAwaitingContent, "image/png", 2048, "", []byte("{}"), time.Now().Add(-time.Hour), time.Now())
+	}
+	if ctx.caller(platformAdminID) == platformAdminID {
+		ctx.mockSQLMock.ExpectQuery("DELETE FROM uploads").WillReturnRows(rows)
+	}
+
+	u.purged = &contracts.UploadPurgeResponse{}
+	return u.sendRequest(ctx, "/admin/uploads/purge", "", u.purged, platformAdminID)
+}
+
+func (u *UploadSteps) setupTestApp(ctx *TestContext) error {
+	if ctx.app == nil {
+		return ctx.SetupProductTestApp()
+	}
+	return nil
+}
+
+// sendRequest posts the body as the scenario caller, or defaultUserID, and decodes a successful response into result
+func (u *UploadSteps) sendRequest(ctx *TestContext, path, body string, result interface{}, defaultUserID int) error {
+	req, err := http.NewRequest(http.MethodPost, ctx.server.URL+path, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := ctx.authorize(req, defaultUserID); err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ctx.response = resp
+
+	if resp.StatusCode >= 400 {
+		var errorResponse map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+			ctx.errorMessage, _ = errorResponse["error"].(string)
+		}
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// ===== Then Steps =====
+
+func (u *UploadSteps) theUploadURLShouldBeSignedForTheBucket() error {
+	ctx := GetTestContext()
+	if u.created == nil {
+		return fmt.Errorf("expected an upload to be created")
+	}
+
+	stagingURL := ctx.s3.URL() + "/" + fakeS3Bucket + "/" + fakeS3Prefix + "/uploads/" + u.created.UploadID + "?"
+	if !strings.HasPrefix(u.created.UploadURL, stagingURL) || !strings.Contains(u.created.UploadURL, "X-Amz-Signature=") {
+		return fmt.Errorf("expected a signed URL under %s, got %s", stagingURL, u.created.UploadURL)
+	}
+	if u.created.Method != http.MethodPut || u.created.Headers["Content-Type"] != "image/png" {
+		return fmt.Errorf("expected a PUT of image/png, got %s %v", u.created.Method, u.created.Headers)
+	}
+	if !u.created.UploadExpiresAt.Before(u.created.ExpiresAt) {
+		return fmt.Errorf("expected the URL to expire before the upload, got %v and %v", u.created.UploadExpiresAt, u.created.ExpiresAt)
+	}
+	return nil
+}
+
+func (u *UploadSteps) theUploadShouldBePendingWithItsRenditions() error {
+	if u.upload == nil || u.upload.Status != models.UploadPending {
+		return fmt.Errorf("expected a pending upload, got %+v", u.upload)
+	}
+	if len(u.upload.Renditions) != len(testImageRenditions)+1 || u.upload.URL != u.upload.Renditions[models.OriginalRendition] {
+		return fmt.Errorf("expected the upload renditions with the original as URL, got %s %v", u.upload.URL, u.upload.Renditions)
+	}
+	return nil
+}
+
+func (u *UploadSteps) theStagingObjectShouldBeDeleted() error {
+	ctx := GetTestContext()
+	key := fakeS3Prefix + "/uploads/" + u.created.UploadID
+	if ctx.s3.object(key) != nil {
+		return fmt.Errorf("expected the staging object %s to be deleted", key)
+	}
+	return nil
+}
+
+func (u *UploadSteps) uploadsShouldBePurged(count int) error {
+	if u.purged == nil || u.purged.Purged != count {
+		return fmt.Errorf("expected %d purged uploads, got %+v", count, u.purged)
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (u *UploadSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^I requested an upload URL for a test image$`, u.iRequestedAnUploadURLForATestImage)
+	sc.Step(`^I have valid product data referencing a finalized upload$`, u.iHaveValidProductDataReferencingAFinalizedUpload)
+	sc.Step(`^I have valid product data referencing an expired upload$`, u.iHaveValidProductDataReferencingAnExpiredUpload)
+	sc.Step(`^there are (\d+) expired uploads$`, u.thereAreExpiredUploads)
+
+	// When steps
+	sc.Step(`^I request an upload URL for an? "([^"]*)" image of (\d+) bytes$`, u.iRequestAnUploadURLForAnImageOfBytes)
+	sc.Step(`^I put the image at the upload URL$`, u.iPutTheImageAtTheUploadURL)
+	sc.Step(`^I finalize the upload$`, u.iFinalizeTheUpload)
+	sc.Step(`^I purge the expired uploads$`, u.iPurgeTheExpiredUploads)
+
+	// Then steps
+	sc.Step(`^the upload URL should be signed for the bucket$`, u.theUploadURLShouldBeSignedForTheBucket)
+	sc.Step(`^the upload should be pending with its renditions$`, u.theUploadShouldBePendingWithItsRenditions)
+	sc.Step(`^the staging object should be deleted$`, u.theStagingObjectShouldBeDeleted)
+	sc.Step(`^(\d+) uploads should be purged$`, u.uploadsShouldBePurged)
+}
@@ -0,0 +1,131 @@
+package steps
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cucumber/godog"
+
+	"github.com/mlgaray/ecommerce_api/internal/core/models"
+)
+
+type VariantOptionsSteps struct {
+	options []map[string]interface{}
+	product *models.Product
+}
+
+func NewVariantOptionsSteps() *VariantOptionsSteps {
+	return &VariantOptionsSteps{}
+}
+
+// ===== Given Steps =====
+
+func (v *VariantOptionsSteps) aProductWhoseOptionHasStock(_ int, name string, stock int) error {
+	v.options = append(v.options, map[string]interface{}{"id": len(v.options) + 1, "name": name, "price": 1.5, "stock": stock, "is_available": true})
+	return nil
+}
+
+func (v *VariantOptionsSteps) aProductWhoseOptionIsNotStockTracked(_ int, name string) error {
+	v.options = append(v.options, map[string]interface{}{"id": len(v.options) + 1, "name": name, "price": 1.5, "is_available": true})
+	return nil
+}
+
+func (v *VariantOptionsSteps) aProductWhoseOptionIsSwitchedOff(_ int, name string) error {
+	v.options = append(v.options, map[string]interface{}{"id": len(v.options) + 1, "name": name, "price": 1.5, "is_available": false})
+	return nil
+}
+
+// ===== When Steps =====
+
+func (v *VariantOptionsSteps) iViewProductInTheStorefront(productID int) error {
+	ctx := GetTestContext()
+	if ctx.app == nil {
+		if err := ctx.SetupProductTestApp(); err != nil {
+			return err
+		}
+	}
+
+	variants, err := json.Marshal([]map[string]interface{}{
+		{"id": 1, "name": "Extras", "order": 1, "selection_type": "multiple", "max_selections": 3, "options": v.options},
+	})
+	if err != nil {
+		return err
+	}
+
+	columns := []string{
+		"id", "name", "description", "price", "stock", "minimum_stock",
+		"is_active", "is_highlighted", "is_promotional", "promotional_price", "created_at", "version",
+		"category_id", "category_name", "category_description",
+		"images", "variants", "promotions", "time_zone",
+	}
+	ctx.mockSQLMock.ExpectQuery("SELECT (.+) FROM products").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(productID, "Classic Burger", "Beef burger", 10.0, 10, 2, true, false, false, 0.0, time.Now(), 1,
+				1, "Burgers", "", `[{"id": 10, "url": "https://cdn.example.com/burger.jpg"}]`, string(variants), "[]", models.DefaultTimeZone))
+
+	resp, err := http.Get(fmt.Sprintf("%s/products/%d", ctx.server.URL, productID))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	ctx.response = resp
+	v.product = &models.Product{}
+	return json.NewDecoder(resp.Body).Decode(v.product)
+}
+
+// ===== Then Steps =====
+
+func (v *VariantOptionsSteps) findOption(name string) (*models.Option, error) {
+	if v.product != nil {
+		for _, variant := range v.product.Variants {
+			for _, option := range variant.Options {
+				if option.Name == name {
+					return option, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("option %q not found in the response", name)
+}
+
+func (v *VariantOptionsSteps) theOptionShouldBeFlaggedAsSoldOut(name string) error {
+	option, err := v.findOption(name)
+	if err != nil {
+		return err
+	}
+	if !option.SoldOut {
+		return fmt.Errorf("expected option %q to be sold out", name)
+	}
+	return nil
+}
+
+func (v *VariantOptionsSteps) theOptionShouldNotBeFlaggedAsSoldOut(name string) error {
+	option, err := v.findOption(name)
+	if err != nil {
+		return err
+	}
+	if option.SoldOut {
+		return fmt.Errorf("expected option %q to be selectable", name)
+	}
+	return nil
+}
+
+// ===== Register Steps =====
+
+func (v *VariantOptionsSteps) RegisterSteps(sc *godog.ScenarioContext) {
+	// Given steps
+	sc.Step(`^product (\d+) has the option "([^"]*)" with stock (\d+)$`, v.aProductWhoseOptionHasStock)
+	sc.Step(`^product (\d+) has the option "([^"]*)" without stock tracking$`, v.aProductWhoseOptionIsNotStockTracked)
+	sc.Step(`^product (\d+) has the option "([^"]*)" switched off$`, v.aProductWhoseOptionIsSwitchedOff)
+
+	// When steps
+	sc.Step(`^I view product (\d+) in the storefront$`, v.iViewProductInTheStorefront)
+
+	// Then steps
+	sc.Step(`^the option "([^"]*)" should be flagged as sold out$`, v.theOptionShouldBeFlaggedAsSoldOut)
+	sc.Step(`^the option "([^"]*)" should not be flagged as sold out$`, v.theOptionShouldNotBeFlaggedAsSoldOut)
+}